@@ -16,6 +16,11 @@ func (what *Threagile) initAnalyze() *Threagile {
 		Aliases: []string{"analyze", "analyse", "run", "analyse-model"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			what.processArgs(cmd, args)
+			what.processFailOnArgs(cmd)
+			if what.flags.printMergedValue {
+				return what.printMerged(cmd)
+			}
+
 			commands := what.readCommands()
 			progressReporter := DefaultProgressReporter{Verbose: what.config.GetVerbose()}
 
@@ -24,18 +29,38 @@ func (what *Threagile) initAnalyze() *Threagile {
 				return fmt.Errorf("failed to read and analyze model: %w", err)
 			}
 
-			err = report.Generate(what.config, r, commands, risks.GetBuiltInRiskRules(), progressReporter)
+			err = report.Generate(what.config, r, commands, r.BuiltinRiskRules, progressReporter)
 			if err != nil {
 				return fmt.Errorf("failed to generate reports: %w", err)
 			}
-			return nil
+
+			return what.checkRiskThreshold(cmd, r.ParsedModel)
 		},
 		CompletionOptions: cobra.CompletionOptions{
 			DisableDefaultCmd: true,
 		},
 	}
 
+	analyze.Flags().StringVar(&what.flags.FailOnValue, failOnFlagName, what.config.GetFailOn(), "fail with exit code "+fmt.Sprintf("%d", RiskThresholdExceededExitCode)+" if risks still at risk reach the threshold <severity>[:<status>,...], e.g. high or elevated:unchecked")
+	analyze.Flags().StringVar(&what.flags.BaselineFileValue, baselineFileFlagName, what.config.GetBaselineFile(), "risks JSON file (or model) of a previous run whose risks are ignored by --"+failOnFlagName)
+	analyze.Flags().BoolVar(&what.flags.printMergedValue, printMergedFlagName, false, "print the model with all includes and overlays merged (commented with where each element comes from) instead of analyzing it")
+
 	what.rootCmd.AddCommand(analyze)
 
 	return what
 }
+
+func (what *Threagile) printMerged(cmd *cobra.Command) error {
+	modelInput, err := model.ReadModelInput(what.config.GetInputFile(), what.config.GetVariant())
+	if err != nil {
+		return fmt.Errorf("failed to read model: %w", err)
+	}
+
+	mergedYaml, err := modelInput.MergedYaml()
+	if err != nil {
+		return err
+	}
+
+	_, err = cmd.OutOrStdout().Write(mergedYaml)
+	return err
+}
@@ -1,7 +1,5 @@
 package threagile
 
-type AttackerFocus struct {
-	Asset                 int // fibonacci sequence base index
-	ProcessedOrStoredData int // fibonacci sequence base index
-	TransferredData       int // fibonacci sequence base index
-}
+import "github.com/threagile/threagile/pkg/types"
+
+type AttackerFocus = types.AttackerFocus
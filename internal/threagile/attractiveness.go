@@ -1,8 +1,5 @@
 package threagile
 
-type Attractiveness struct {
-	Quantity        int           `json:"quantity,omitempty" yaml:"quantity"`
-	Confidentiality AttackerFocus `json:"confidentiality" yaml:"confidentiality"`
-	Integrity       AttackerFocus `json:"integrity" yaml:"integrity"`
-	Availability    AttackerFocus `json:"availability" yaml:"availability"`
-}
+import "github.com/threagile/threagile/pkg/types"
+
+type Attractiveness = types.Attractiveness
@@ -24,24 +24,28 @@ type Config struct {
 	VerboseValue        bool   `json:"Verbose,omitempty" yaml:"Verbose"`
 	InteractiveValue    bool   `json:"Interactive,omitempty" yaml:"Interactive"`
 
-	AppFolderValue    string `json:"AppFolder,omitempty" yaml:"AppFolder"`
-	PluginFolderValue string `json:"PluginFolder,omitempty" yaml:"PluginFolder"`
-	DataFolderValue   string `json:"DataFolder,omitempty" yaml:"DataFolder"`
-	OutputFolderValue string `json:"OutputFolder,omitempty" yaml:"OutputFolder"`
-	ServerFolderValue string `json:"ServerFolder,omitempty" yaml:"ServerFolder"`
-	TempFolderValue   string `json:"TempFolder,omitempty" yaml:"TempFolder"`
-	KeyFolderValue    string `json:"KeyFolder,omitempty" yaml:"KeyFolder"`
+	AppFolderValue         string `json:"AppFolder,omitempty" yaml:"AppFolder"`
+	PluginFolderValue      string `json:"PluginFolder,omitempty" yaml:"PluginFolder"`
+	ScriptRulesFolderValue string `json:"ScriptRulesFolder,omitempty" yaml:"ScriptRulesFolder"`
+	DataFolderValue        string `json:"DataFolder,omitempty" yaml:"DataFolder"`
+	OutputFolderValue      string `json:"OutputFolder,omitempty" yaml:"OutputFolder"`
+	ServerFolderValue      string `json:"ServerFolder,omitempty" yaml:"ServerFolder"`
+	TempFolderValue        string `json:"TempFolder,omitempty" yaml:"TempFolder"`
+	KeyFolderValue         string `json:"KeyFolder,omitempty" yaml:"KeyFolder"`
 
 	InputFileValue                   string `json:"InputFile,omitempty" yaml:"InputFile"`
 	ImportedInputFileValue           string `json:"ImportedInputFile,omitempty" yaml:"ImportedInputFile"`
+	VariantValue                     string `json:"Variant,omitempty" yaml:"Variant"`
 	DataFlowDiagramFilenamePNGValue  string `json:"DataFlowDiagramFilenamePNG,omitempty" yaml:"DataFlowDiagramFilenamePNG"`
 	DataAssetDiagramFilenamePNGValue string `json:"DataAssetDiagramFilenamePNG,omitempty" yaml:"DataAssetDiagramFilenamePNG"`
 	DataFlowDiagramFilenameDOTValue  string `json:"DataFlowDiagramFilenameDOT,omitempty" yaml:"DataFlowDiagramFilenameDOT"`
 	DataAssetDiagramFilenameDOTValue string `json:"DataAssetDiagramFilenameDOT,omitempty" yaml:"DataAssetDiagramFilenameDOT"`
 	ReportFilenameValue              string `json:"ReportFilename,omitempty" yaml:"ReportFilename"`
+	HtmlReportFilenameValue          string `json:"HtmlReportFilename,omitempty" yaml:"HtmlReportFilename"`
 	ExcelRisksFilenameValue          string `json:"ExcelRisksFilename,omitempty" yaml:"ExcelRisksFilename"`
 	ExcelTagsFilenameValue           string `json:"ExcelTagsFilename,omitempty" yaml:"ExcelTagsFilename"`
 	JsonRisksFilenameValue           string `json:"JsonRisksFilename,omitempty" yaml:"JsonRisksFilename"`
+	SarifRisksFilenameValue          string `json:"SarifRisksFilename,omitempty" yaml:"SarifRisksFilename"`
 	JsonTechnicalAssetsFilenameValue string `json:"JsonTechnicalAssetsFilename,omitempty" yaml:"JsonTechnicalAssetsFilename"`
 	JsonStatsFilenameValue           string `json:"JsonStatsFilename,omitempty" yaml:"JsonStatsFilename"`
 	TemplateFilenameValue            string `json:"TemplateFilename,omitempty" yaml:"TemplateFilename"`
@@ -54,6 +58,14 @@ type Config struct {
 	ExecuteModelMacroValue string          `json:"ExecuteModelMacro,omitempty" yaml:"ExecuteModelMacro"`
 	RiskExcelValue         RiskExcelConfig `json:"RiskExcel" yaml:"RiskExcel"`
 
+	FailOnValue       string `json:"FailOn,omitempty" yaml:"FailOn"`
+	BaselineFileValue string `json:"BaselineFile,omitempty" yaml:"BaselineFile"`
+	ErrorFormatValue  string `json:"ErrorFormat,omitempty" yaml:"ErrorFormat"`
+
+	LintChecksValue map[string]string `json:"LintChecks,omitempty" yaml:"LintChecks"`
+
+	DiagramFormatsValue []string `json:"DiagramFormats,omitempty" yaml:"DiagramFormats"`
+
 	ServerModeValue               bool `json:"ServerMode,omitempty" yaml:"ServerMode"`
 	ServerPortValue               int  `json:"ServerPort,omitempty" yaml:"ServerPort"`
 	DiagramDPIValue               int  `json:"DiagramDPI,omitempty" yaml:"DiagramDPI"`
@@ -61,6 +73,13 @@ type Config struct {
 	MaxGraphvizDPIValue           int  `json:"MaxGraphvizDPI,omitempty" yaml:"MaxGraphvizDPI"`
 	BackupHistoryFilesToKeepValue int  `json:"BackupHistoryFilesToKeep,omitempty" yaml:"BackupHistoryFilesToKeep"`
 
+	ServerWorkersValue        int    `json:"ServerWorkers,omitempty" yaml:"ServerWorkers"`
+	ServerJobTimeoutValue     int    `json:"ServerJobTimeout,omitempty" yaml:"ServerJobTimeout"`
+	ServerJobMemoryLimitValue int    `json:"ServerJobMemoryLimit,omitempty" yaml:"ServerJobMemoryLimit"`
+	ServerSubprocessValue     bool   `json:"ServerSubprocess,omitempty" yaml:"ServerSubprocess"`
+	ServerModelStoreValue     string `json:"ServerModelStore,omitempty" yaml:"ServerModelStore"`
+	ServerPersistTokensValue  bool   `json:"ServerPersistTokens,omitempty" yaml:"ServerPersistTokens"`
+
 	AddModelTitleValue              bool `json:"AddModelTitle,omitempty" yaml:"AddModelTitle"`
 	AddLegendValue                  bool `json:"AddLegend,omitempty" yaml:"AddLegend"`
 	KeepDiagramSourceFilesValue     bool `json:"KeepDiagramSourceFiles,omitempty" yaml:"KeepDiagramSourceFiles"`
@@ -69,12 +88,14 @@ type Config struct {
 	SkipDataFlowDiagramValue     bool `json:"SkipDataFlowDiagram,omitempty" yaml:"SkipDataFlowDiagram"`
 	SkipDataAssetDiagramValue    bool `json:"SkipDataAssetDiagram,omitempty" yaml:"SkipDataAssetDiagram"`
 	SkipRisksJSONValue           bool `json:"SkipRisksJSON,omitempty" yaml:"SkipRisksJSON"`
+	SkipRisksSARIFValue          bool `json:"SkipRisksSARIF,omitempty" yaml:"SkipRisksSARIF"`
 	SkipTechnicalAssetsJSONValue bool `json:"SkipTechnicalAssetsJSON,omitempty" yaml:"SkipTechnicalAssetsJSON"`
 	SkipStatsJSONValue           bool `json:"SkipStatsJSON,omitempty" yaml:"SkipStatsJSON"`
 	SkipRisksExcelValue          bool `json:"SkipRisksExcel,omitempty" yaml:"SkipRisksExcel"`
 	SkipTagsExcelValue           bool `json:"SkipTagsExcel,omitempty" yaml:"SkipTagsExcel"`
 	SkipReportPDFValue           bool `json:"SkipReportPDF,omitempty" yaml:"SkipReportPDF"`
 	SkipReportADOCValue          bool `json:"SkipReportADOC,omitempty" yaml:"SkipReportADOC"`
+	SkipReportHTMLValue          bool `json:"SkipReportHTML,omitempty" yaml:"SkipReportHTML"`
 
 	AttractivenessValue Attractiveness `json:"Attractiveness" yaml:"Attractiveness"`
 
@@ -87,6 +108,7 @@ type ConfigGetter interface {
 	GetInteractive() bool
 	GetAppFolder() string
 	GetPluginFolder() string
+	GetScriptRulesFolder() string
 	GetDataFolder() string
 	GetOutputFolder() string
 	GetServerFolder() string
@@ -94,14 +116,17 @@ type ConfigGetter interface {
 	GetKeyFolder() string
 	GetTechnologyFilename() string
 	GetInputFile() string
+	GetVariant() string
 	GetDataFlowDiagramFilenamePNG() string
 	GetDataAssetDiagramFilenamePNG() string
 	GetDataFlowDiagramFilenameDOT() string
 	GetDataAssetDiagramFilenameDOT() string
 	GetReportFilename() string
+	GetHtmlReportFilename() string
 	GetExcelRisksFilename() string
 	GetExcelTagsFilename() string
 	GetJsonRisksFilename() string
+	GetSarifRisksFilename() string
 	GetJsonTechnicalAssetsFilename() string
 	GetJsonStatsFilename() string
 	GetReportLogoImagePath() string
@@ -109,6 +134,10 @@ type ConfigGetter interface {
 	GetRiskRulePlugins() []string
 	GetSkipRiskRules() []string
 	GetExecuteModelMacro() string
+	GetFailOn() string
+	GetBaselineFile() string
+	GetErrorFormat() string
+	GetLintChecks() map[string]string
 	GetRiskExcelConfigHideColumns() []string
 	GetRiskExcelConfigSortByColumns() []string
 	GetRiskExcelConfigWidthOfColumns() map[string]float64
@@ -118,10 +147,17 @@ type ConfigGetter interface {
 	GetServerMode() bool
 	GetServerPort() int
 	GetDiagramDPI() int
+	GetDiagramFormats() []string
 	GetGraphvizDPI() int
 	GetMinGraphvizDPI() int
 	GetMaxGraphvizDPI() int
 	GetBackupHistoryFilesToKeep() int
+	GetServerWorkers() int
+	GetServerJobTimeout() int
+	GetServerJobMemoryLimit() int
+	GetServerSubprocess() bool
+	GetServerModelStore() string
+	GetServerPersistTokens() bool
 	GetAddModelTitle() bool
 	GetAddLegend() bool
 	GetKeepDiagramSourceFiles() bool
@@ -129,12 +165,14 @@ type ConfigGetter interface {
 	GetSkipDataFlowDiagram() bool
 	GetSkipDataAssetDiagram() bool
 	GetSkipRisksJSON() bool
+	GetSkipRisksSARIF() bool
 	GetSkipTechnicalAssetsJSON() bool
 	GetSkipStatsJSON() bool
 	GetSkipRisksExcel() bool
 	GetSkipTagsExcel() bool
 	GetSkipReportPDF() bool
 	GetSkipReportADOC() bool
+	GetSkipReportHTML() bool
 	GetAttractiveness() Attractiveness
 	GetReportConfiguration() report.ReportConfiguation
 	GetThreagileVersion() string
@@ -146,6 +184,7 @@ type ConfigSetter interface {
 	SetInteractive(interactive bool)
 	SetAppFolder(appFolder string)
 	SetPluginFolder(pluginFolder string)
+	SetScriptRulesFolder(scriptRulesFolder string)
 	SetOutputFolder(outputFolder string)
 	SetServerFolder(serverFolder string)
 	SetTempFolder(tempFolder string)
@@ -165,23 +204,27 @@ func (c *Config) Defaults(buildTimestamp string) *Config {
 		VerboseValue:        false,
 		InteractiveValue:    false,
 
-		AppFolderValue:    AppDir,
-		PluginFolderValue: PluginDir,
-		DataFolderValue:   DataDir,
-		OutputFolderValue: OutputDir,
-		ServerFolderValue: ServerDir,
-		TempFolderValue:   TempDir,
-		KeyFolderValue:    KeyDir,
+		AppFolderValue:         AppDir,
+		PluginFolderValue:      PluginDir,
+		ScriptRulesFolderValue: "",
+		DataFolderValue:        DataDir,
+		OutputFolderValue:      OutputDir,
+		ServerFolderValue:      ServerDir,
+		TempFolderValue:        TempDir,
+		KeyFolderValue:         KeyDir,
 
 		InputFileValue:                   InputFile,
+		VariantValue:                     "",
 		DataFlowDiagramFilenamePNGValue:  DataFlowDiagramFilenamePNG,
 		DataAssetDiagramFilenamePNGValue: DataAssetDiagramFilenamePNG,
 		DataFlowDiagramFilenameDOTValue:  DataFlowDiagramFilenameDOT,
 		DataAssetDiagramFilenameDOTValue: DataAssetDiagramFilenameDOT,
 		ReportFilenameValue:              ReportFilename,
+		HtmlReportFilenameValue:          HtmlReportFilename,
 		ExcelRisksFilenameValue:          ExcelRisksFilename,
 		ExcelTagsFilenameValue:           ExcelTagsFilename,
 		JsonRisksFilenameValue:           JsonRisksFilename,
+		SarifRisksFilenameValue:          SarifRisksFilename,
 		JsonTechnicalAssetsFilenameValue: JsonTechnicalAssetsFilename,
 		JsonStatsFilenameValue:           JsonStatsFilename,
 		TemplateFilenameValue:            TemplateFilename,
@@ -201,13 +244,27 @@ func (c *Config) Defaults(buildTimestamp string) *Config {
 			ColorText:          true,
 		},
 
+		FailOnValue:       "",
+		BaselineFileValue: "",
+		ErrorFormatValue:  report.DiagnosticsFormatText,
+
+		LintChecksValue: make(map[string]string),
+
 		ServerModeValue:               false,
 		DiagramDPIValue:               DefaultDiagramDPI,
+		DiagramFormatsValue:           []string{report.DiagramFormatPNG},
 		ServerPortValue:               DefaultServerPort,
 		GraphvizDPIValue:              DefaultGraphvizDPI,
 		MaxGraphvizDPIValue:           MaxGraphvizDPI,
 		BackupHistoryFilesToKeepValue: DefaultBackupHistoryFilesToKeep,
 
+		ServerWorkersValue:        DefaultServerWorkers,
+		ServerJobTimeoutValue:     DefaultServerJobTimeout,
+		ServerJobMemoryLimitValue: 0,
+		ServerSubprocessValue:     false,
+		ServerModelStoreValue:     DefaultServerModelStore,
+		ServerPersistTokensValue:  false,
+
 		AddModelTitleValue:              false,
 		AddLegendValue:                  false,
 		KeepDiagramSourceFilesValue:     false,
@@ -302,6 +359,14 @@ func (c *Config) Load(configFilename string) error {
 		errorList = append(errorList, pluginDirError)
 	}
 
+	if c.ScriptRulesFolderValue != "" {
+		c.ScriptRulesFolderValue = c.CleanPath(c.ScriptRulesFolderValue)
+		scriptRulesDirError := c.checkDir(c.ScriptRulesFolderValue, "script rules")
+		if scriptRulesDirError != nil {
+			errorList = append(errorList, scriptRulesDirError)
+		}
+	}
+
 	c.DataFolderValue = c.CleanPath(c.DataFolderValue)
 	dataDirError := c.checkDir(c.DataFolderValue, "data")
 	if dataDirError != nil {
@@ -359,6 +424,9 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("PluginFolder"):
 			c.PluginFolderValue = config.PluginFolderValue
 
+		case strings.ToLower("ScriptRulesFolder"):
+			c.ScriptRulesFolderValue = config.ScriptRulesFolderValue
+
 		case strings.ToLower("DataFolder"):
 			c.DataFolderValue = config.DataFolderValue
 
@@ -380,6 +448,9 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("ImportedInputFile"):
 			c.ImportedInputFileValue = config.ImportedInputFileValue
 
+		case strings.ToLower("Variant"):
+			c.VariantValue = config.VariantValue
+
 		case strings.ToLower("DataFlowDiagramFilenamePNG"):
 			c.DataFlowDiagramFilenamePNGValue = config.DataFlowDiagramFilenamePNGValue
 
@@ -395,6 +466,9 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("ReportFilename"):
 			c.ReportFilenameValue = config.ReportFilenameValue
 
+		case strings.ToLower("HtmlReportFilename"):
+			c.HtmlReportFilenameValue = config.HtmlReportFilenameValue
+
 		case strings.ToLower("ExcelRisksFilename"):
 			c.ExcelRisksFilenameValue = config.ExcelRisksFilenameValue
 
@@ -404,6 +478,9 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("JsonRisksFilename"):
 			c.JsonRisksFilenameValue = config.JsonRisksFilenameValue
 
+		case strings.ToLower("SarifRisksFilename"):
+			c.SarifRisksFilenameValue = config.SarifRisksFilenameValue
+
 		case strings.ToLower("JsonTechnicalAssetsFilename"):
 			c.JsonTechnicalAssetsFilenameValue = config.JsonTechnicalAssetsFilenameValue
 
@@ -431,6 +508,24 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("ExecuteModelMacro"):
 			c.ExecuteModelMacroValue = config.ExecuteModelMacroValue
 
+		case strings.ToLower("FailOn"):
+			c.FailOnValue = config.FailOnValue
+
+		case strings.ToLower("BaselineFile"):
+			c.BaselineFileValue = config.BaselineFileValue
+
+		case strings.ToLower("ErrorFormat"):
+			c.ErrorFormatValue = config.ErrorFormatValue
+
+		case strings.ToLower("LintChecks"):
+			if c.LintChecksValue == nil {
+				c.LintChecksValue = make(map[string]string)
+			}
+
+			for id, severity := range config.LintChecksValue {
+				c.LintChecksValue[id] = severity
+			}
+
 		case strings.ToLower("RiskExcel"):
 			configMap, mapOk := values[key].(map[string]any)
 			if !mapOk {
@@ -471,6 +566,9 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("DiagramDPI"):
 			c.DiagramDPIValue = config.DiagramDPIValue
 
+		case strings.ToLower("DiagramFormats"):
+			c.DiagramFormatsValue = config.DiagramFormatsValue
+
 		case strings.ToLower("ServerPort"):
 			c.ServerPortValue = config.ServerPortValue
 
@@ -483,6 +581,24 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("BackupHistoryFilesToKeep"):
 			c.BackupHistoryFilesToKeepValue = config.BackupHistoryFilesToKeepValue
 
+		case strings.ToLower("ServerWorkers"):
+			c.ServerWorkersValue = config.ServerWorkersValue
+
+		case strings.ToLower("ServerJobTimeout"):
+			c.ServerJobTimeoutValue = config.ServerJobTimeoutValue
+
+		case strings.ToLower("ServerJobMemoryLimit"):
+			c.ServerJobMemoryLimitValue = config.ServerJobMemoryLimitValue
+
+		case strings.ToLower("ServerSubprocess"):
+			c.ServerSubprocessValue = config.ServerSubprocessValue
+
+		case strings.ToLower("ServerModelStore"):
+			c.ServerModelStoreValue = config.ServerModelStoreValue
+
+		case strings.ToLower("ServerPersistTokens"):
+			c.ServerPersistTokensValue = config.ServerPersistTokensValue
+
 		case strings.ToLower("AddModelTitle"):
 			c.AddModelTitleValue = config.AddModelTitleValue
 
@@ -495,6 +611,39 @@ func (c *Config) Merge(config Config, values map[string]any) {
 		case strings.ToLower("IgnoreOrphanedRiskTracking"):
 			c.IgnoreOrphanedRiskTrackingValue = config.IgnoreOrphanedRiskTrackingValue
 
+		case strings.ToLower("SkipDataFlowDiagram"):
+			c.SkipDataFlowDiagramValue = config.SkipDataFlowDiagramValue
+
+		case strings.ToLower("SkipDataAssetDiagram"):
+			c.SkipDataAssetDiagramValue = config.SkipDataAssetDiagramValue
+
+		case strings.ToLower("SkipRisksJSON"):
+			c.SkipRisksJSONValue = config.SkipRisksJSONValue
+
+		case strings.ToLower("SkipRisksSARIF"):
+			c.SkipRisksSARIFValue = config.SkipRisksSARIFValue
+
+		case strings.ToLower("SkipTechnicalAssetsJSON"):
+			c.SkipTechnicalAssetsJSONValue = config.SkipTechnicalAssetsJSONValue
+
+		case strings.ToLower("SkipStatsJSON"):
+			c.SkipStatsJSONValue = config.SkipStatsJSONValue
+
+		case strings.ToLower("SkipRisksExcel"):
+			c.SkipRisksExcelValue = config.SkipRisksExcelValue
+
+		case strings.ToLower("SkipTagsExcel"):
+			c.SkipTagsExcelValue = config.SkipTagsExcelValue
+
+		case strings.ToLower("SkipReportPDF"):
+			c.SkipReportPDFValue = config.SkipReportPDFValue
+
+		case strings.ToLower("SkipReportADOC"):
+			c.SkipReportADOCValue = config.SkipReportADOCValue
+
+		case strings.ToLower("SkipReportHTML"):
+			c.SkipReportHTMLValue = config.SkipReportHTMLValue
+
 		case strings.ToLower("Attractiveness"):
 			c.AttractivenessValue = config.AttractivenessValue
 
@@ -603,6 +752,14 @@ func (c *Config) SetPluginFolder(pluginFolder string) {
 	c.PluginFolderValue = pluginFolder
 }
 
+func (c *Config) GetScriptRulesFolder() string {
+	return c.ScriptRulesFolderValue
+}
+
+func (c *Config) SetScriptRulesFolder(scriptRulesFolder string) {
+	c.ScriptRulesFolderValue = scriptRulesFolder
+}
+
 func (c *Config) GetDataFolder() string {
 	return c.DataFolderValue
 }
@@ -651,6 +808,10 @@ func (c *Config) SetInputFile(inputFile string) {
 	c.InputFileValue = inputFile
 }
 
+func (c *Config) GetVariant() string {
+	return c.VariantValue
+}
+
 func (c *Config) GetImportedInputFile() string {
 	return c.ImportedInputFileValue
 }
@@ -679,6 +840,10 @@ func (c *Config) GetReportFilename() string {
 	return c.ReportFilenameValue
 }
 
+func (c *Config) GetHtmlReportFilename() string {
+	return c.HtmlReportFilenameValue
+}
+
 func (c *Config) GetExcelRisksFilename() string {
 	return c.ExcelRisksFilenameValue
 }
@@ -691,6 +856,10 @@ func (c *Config) GetJsonRisksFilename() string {
 	return c.JsonRisksFilenameValue
 }
 
+func (c *Config) GetSarifRisksFilename() string {
+	return c.SarifRisksFilenameValue
+}
+
 func (c *Config) GetJsonTechnicalAssetsFilename() string {
 	return c.JsonTechnicalAssetsFilenameValue
 }
@@ -731,6 +900,22 @@ func (c *Config) GetExecuteModelMacro() string {
 	return c.ExecuteModelMacroValue
 }
 
+func (c *Config) GetFailOn() string {
+	return c.FailOnValue
+}
+
+func (c *Config) GetBaselineFile() string {
+	return c.BaselineFileValue
+}
+
+func (c *Config) GetErrorFormat() string {
+	return c.ErrorFormatValue
+}
+
+func (c *Config) GetLintChecks() map[string]string {
+	return c.LintChecksValue
+}
+
 func (c *Config) GetRiskExcelConfigHideColumns() []string {
 	return c.RiskExcelValue.HideColumns
 }
@@ -775,6 +960,10 @@ func (c *Config) GetDiagramDPI() int {
 	return c.DiagramDPIValue
 }
 
+func (c *Config) GetDiagramFormats() []string {
+	return c.DiagramFormatsValue
+}
+
 func (c *Config) SetDiagramDPI(diagramDPI int) {
 	c.DiagramDPIValue = diagramDPI
 }
@@ -795,6 +984,30 @@ func (c *Config) GetBackupHistoryFilesToKeep() int {
 	return c.BackupHistoryFilesToKeepValue
 }
 
+func (c *Config) GetServerWorkers() int {
+	return c.ServerWorkersValue
+}
+
+func (c *Config) GetServerJobTimeout() int {
+	return c.ServerJobTimeoutValue
+}
+
+func (c *Config) GetServerJobMemoryLimit() int {
+	return c.ServerJobMemoryLimitValue
+}
+
+func (c *Config) GetServerSubprocess() bool {
+	return c.ServerSubprocessValue
+}
+
+func (c *Config) GetServerModelStore() string {
+	return c.ServerModelStoreValue
+}
+
+func (c *Config) GetServerPersistTokens() bool {
+	return c.ServerPersistTokensValue
+}
+
 func (c *Config) GetAddModelTitle() bool {
 	return c.AddModelTitleValue
 }
@@ -827,6 +1040,10 @@ func (c *Config) GetSkipRisksJSON() bool {
 	return c.SkipRisksJSONValue
 }
 
+func (c *Config) GetSkipRisksSARIF() bool {
+	return c.SkipRisksSARIFValue
+}
+
 func (c *Config) GetSkipTechnicalAssetsJSON() bool {
 	return c.SkipTechnicalAssetsJSONValue
 }
@@ -851,6 +1068,10 @@ func (c *Config) GetSkipReportADOC() bool {
 	return c.SkipReportADOCValue
 }
 
+func (c *Config) GetSkipReportHTML() bool {
+	return c.SkipReportHTMLValue
+}
+
 func (c *Config) GetAttractiveness() Attractiveness {
 	return c.AttractivenessValue
 }
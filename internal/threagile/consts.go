@@ -13,9 +13,11 @@ const (
 
 	InputFile                   = "threagile.yaml"
 	ReportFilename              = "report.pdf"
+	HtmlReportFilename          = "report.html"
 	ExcelRisksFilename          = "risks.xlsx"
 	ExcelTagsFilename           = "tags.xlsx"
 	JsonRisksFilename           = "risks.json"
+	SarifRisksFilename          = "risks.sarif"
 	JsonTechnicalAssetsFilename = "technical-assets.json"
 	JsonStatsFilename           = "stats.json"
 	TemplateFilename            = "background.pdf"
@@ -30,6 +32,11 @@ const (
 	MinGraphvizDPI                  = 20
 	MaxGraphvizDPI                  = 300
 	DefaultBackupHistoryFilesToKeep = 50
+	DefaultServerWorkers            = 4
+	DefaultServerJobTimeout         = 300
+	DefaultServerModelStore         = "filesystem"
+
+	RiskThresholdExceededExitCode = 2
 )
 
 const (
@@ -45,7 +52,11 @@ const (
 	PrintLicenseCommand         = "print-license"
 
 	CreateCommand       = "create"
+	DiffCommand         = "diff"
 	ExplainCommand      = "explain"
+	LintCommand         = "lint"
+	MigrateCommand      = "migrate"
+	ValidateCommand     = "validate"
 	ListCommand         = "list"
 	PrintCommand        = "print"
 	QuitCommand         = "quit"
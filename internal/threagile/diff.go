@@ -0,0 +1,116 @@
+package threagile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/risks"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+func (what *Threagile) initDiff() *Threagile {
+	diff := &cobra.Command{
+		Use:   DiffCommand + " <old model or risks.json> <new model or risks.json>",
+		Short: "Compare two models or the risks of two analysis runs",
+		Long: "Compare two models or the risks of two analysis runs\n\n" +
+			"Model files are analyzed first and compared by their technical assets, communication links, data assets,\n" +
+			"trust boundaries and risks. If either file is a risks JSON file (" + JsonRisksFilename + "), only the risks are compared.",
+		Args: cobra.ExactArgs(2),
+		RunE: what.diff,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+	}
+
+	diff.Flags().StringVar(&what.flags.diffFormatValue, diffFormatFlagName, report.DiffFormatText, "output format: "+strings.Join(report.DiffFormats(), ", "))
+
+	what.rootCmd.AddCommand(diff)
+
+	return what
+}
+
+func (what *Threagile) diff(cmd *cobra.Command, args []string) error {
+	what.processArgs(cmd, args)
+
+	oldFilename, newFilename := args[0], args[1]
+
+	var diff *model.ModelDiff
+	if isRisksJSONFile(oldFilename) || isRisksJSONFile(newFilename) {
+		oldRisks, err := what.readRisks(oldFilename)
+		if err != nil {
+			return err
+		}
+
+		newRisks, err := what.readRisks(newFilename)
+		if err != nil {
+			return err
+		}
+
+		diff = &model.ModelDiff{Risks: model.DiffRisks(oldRisks, newRisks)}
+	} else {
+		oldModel, err := what.readAndAnalyzeModelFile(oldFilename)
+		if err != nil {
+			return err
+		}
+
+		newModel, err := what.readAndAnalyzeModelFile(newFilename)
+		if err != nil {
+			return err
+		}
+
+		diff, err = model.DiffModels(oldModel.ParsedModel, newModel.ParsedModel)
+		if err != nil {
+			return fmt.Errorf("failed to compare models: %w", err)
+		}
+	}
+
+	return report.WriteDiff(cmd.OutOrStdout(), diff, what.flags.diffFormatValue)
+}
+
+func isRisksJSONFile(filename string) bool {
+	return strings.EqualFold(filepath.Ext(filename), ".json")
+}
+
+// readRisks reads the risks of an analysis run (risks.json) or analyzes a model to get its risks
+func (what *Threagile) readRisks(filename string) ([]*types.Risk, error) {
+	if !isRisksJSONFile(filename) {
+		result, err := what.readAndAnalyzeModelFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		return result.ParsedModel.AllRisksWithCurrentStatus(), nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risks from %q: %w", filename, err)
+	}
+
+	allRisks := make([]*types.Risk, 0)
+	err = json.Unmarshal(data, &allRisks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse risks from %q: %w", filename, err)
+	}
+
+	return allRisks, nil
+}
+
+func (what *Threagile) readAndAnalyzeModelFile(filename string) (*model.ReadResult, error) {
+	config := *what.config
+	config.InputFileValue = filename
+	config.ImportedInputFileValue = ""
+
+	result, err := model.ReadAndAnalyzeModel(&config, risks.GetBuiltInRiskRules(), DefaultProgressReporter{Verbose: config.GetVerbose()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read and analyze model %q: %w", filename, err)
+	}
+
+	return result, nil
+}
@@ -61,10 +61,14 @@ func (what *Threagile) explainRisk(cmd *cobra.Command, args []string) error {
 		return runError
 	}
 
-	// todo: implement this
+	for _, riskId := range args {
+		explainError := result.ExplainRisk(what.config, riskId, cmd)
+		if explainError != nil {
+			return explainError
+		}
+	}
 
-	_ = result
-	return fmt.Errorf("not implemented yet")
+	return nil
 }
 
 func (what *Threagile) explainRules(cmd *cobra.Command, args []string) error {
@@ -81,6 +85,20 @@ func (what *Threagile) explainRules(cmd *cobra.Command, args []string) error {
 		cmd.Printf("%v: %v\n", rule.Category().ID, rule.Category().Description)
 	}
 	cmd.Println()
+	if len(what.config.GetScriptRulesFolder()) > 0 {
+		cmd.Println("----------------------")
+		cmd.Println("Script risk rules:")
+		cmd.Println("----------------------")
+		scriptRiskRules, loadError := risks.LoadScriptRiskRules(what.config.GetScriptRulesFolder())
+		if loadError != nil {
+			return loadError
+		}
+		for _, id := range sortedRiskRuleIds(types.RiskRules(scriptRiskRules)) {
+			rule := scriptRiskRules[id]
+			cmd.Printf("%v: %v\n", rule.Category().ID, rule.Category().Description)
+		}
+		cmd.Println()
+	}
 	cmd.Println("--------------------")
 	cmd.Println("Built-in risk rules:")
 	cmd.Println("--------------------")
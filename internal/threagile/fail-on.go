@@ -0,0 +1,80 @@
+package threagile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type riskThresholdError struct {
+	threshold *types.RiskThreshold
+	count     int
+}
+
+func (what *riskThresholdError) Error() string {
+	return fmt.Sprintf("%d risk(s) still at risk reaching the threshold %q", what.count, what.threshold.String())
+}
+
+// processFailOnArgs applies the --fail-on and --baseline flags, which only analyze-model has
+func (what *Threagile) processFailOnArgs(cmd *cobra.Command) {
+	if cmd.Flags().Changed(failOnFlagName) {
+		what.config.FailOnValue = what.flags.FailOnValue
+	}
+
+	if cmd.Flags().Changed(baselineFileFlagName) {
+		what.config.BaselineFileValue = what.config.CleanPath(what.flags.BaselineFileValue)
+	}
+}
+
+// checkRiskThreshold fails with a riskThresholdError if risks still at risk and not contained in the baseline reach the --fail-on threshold
+func (what *Threagile) checkRiskThreshold(cmd *cobra.Command, parsedModel *types.Model) error {
+	if len(strings.TrimSpace(what.config.GetFailOn())) == 0 {
+		return nil
+	}
+
+	threshold, parseError := types.ParseRiskThreshold(what.config.GetFailOn())
+	if parseError != nil {
+		return parseError
+	}
+
+	risks := parsedModel.AllRisksWithCurrentStatus()
+	ignored := 0
+	if len(what.config.GetBaselineFile()) > 0 {
+		baselineRisks, readError := what.readRisks(what.config.GetBaselineFile())
+		if readError != nil {
+			return fmt.Errorf("failed to read baseline: %w", readError)
+		}
+
+		baselineIds := make(map[string]bool)
+		for _, risk := range baselineRisks {
+			baselineIds[strings.ToLower(risk.SyntheticId)] = true
+		}
+
+		newRisks := make([]*types.Risk, 0)
+		for _, risk := range risks {
+			if baselineIds[strings.ToLower(risk.SyntheticId)] {
+				ignored++
+				continue
+			}
+			newRisks = append(newRisks, risk)
+		}
+		risks = newRisks
+	}
+
+	exceeding := threshold.Exceeding(risks)
+	if len(exceeding) == 0 {
+		if what.config.GetVerbose() {
+			cmd.Printf("No risks reaching the threshold %q (%d baseline risks ignored)\n", threshold.String(), ignored)
+		}
+		return nil
+	}
+
+	cmd.Printf("Risks reaching the threshold %q (%d baseline risks ignored):\n", threshold.String(), ignored)
+	for _, risk := range exceeding {
+		cmd.Printf("  [%v, %v] %v\n", risk.Severity.Title(), risk.RiskStatus.Title(), risk.SyntheticId)
+	}
+
+	return &riskThresholdError{threshold: threshold, count: len(exceeding)}
+}
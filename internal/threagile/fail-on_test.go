@@ -0,0 +1,127 @@
+package threagile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+func TestCheckRiskThreshold(t *testing.T) {
+	baselineFile := writeTestRisks(t, []*types.Risk{
+		{SyntheticId: "Known-High@Web-Server", Severity: types.HighSeverity},
+	})
+	parsedModel := &types.Model{
+		GeneratedRisksByCategory: map[string][]*types.Risk{
+			"some-category": {
+				{SyntheticId: "known-high@web-server", Severity: types.HighSeverity},
+				{SyntheticId: "new-elevated@web-server", Severity: types.ElevatedSeverity},
+				{SyntheticId: "new-high-mitigated@web-server", Severity: types.HighSeverity},
+			},
+		},
+		RiskTracking: map[string]*types.RiskTracking{
+			"new-high-mitigated@web-server": {Status: types.Mitigated},
+		},
+	}
+
+	// a risk only the baseline covers is ignored
+	what, output := newTestThreagile(t, "high", baselineFile)
+	assert.NoError(t, what.checkRiskThreshold(newTestCommand(output), parsedModel))
+	assert.Empty(t, output.String())
+
+	// as long as there is a baseline
+	what, output = newTestThreagile(t, "high", "")
+	err := what.checkRiskThreshold(newTestCommand(output), parsedModel)
+	assert.EqualError(t, err, `1 risk(s) still at risk reaching the threshold "high"`)
+	assert.Contains(t, output.String(), "[High, Unchecked] known-high@web-server")
+
+	// a new risk reaching the threshold fails with its own exit code
+	parsedModel.GeneratedRisksByCategory["some-category"] = append(parsedModel.GeneratedRisksByCategory["some-category"],
+		&types.Risk{SyntheticId: "new-critical@web-server", Severity: types.CriticalSeverity})
+	what, output = newTestThreagile(t, "high", baselineFile)
+	err = what.checkRiskThreshold(newTestCommand(output), parsedModel)
+	var thresholdError *riskThresholdError
+	if assert.ErrorAs(t, err, &thresholdError) {
+		assert.Equal(t, 1, thresholdError.count)
+	}
+	assert.Equal(t, RiskThresholdExceededExitCode, exitCode(fmt.Errorf("failed: %w", err)))
+	assert.Equal(t, "Risks reaching the threshold \"high\" (1 baseline risks ignored):\n  [Critical, Unchecked] new-critical@web-server\n", output.String())
+
+	what, output = newTestThreagile(t, "", baselineFile)
+	assert.NoError(t, what.checkRiskThreshold(newTestCommand(output), parsedModel))
+
+	what, output = newTestThreagile(t, "high", filepath.Join(t.TempDir(), "missing.json"))
+	err = what.checkRiskThreshold(newTestCommand(output), parsedModel)
+	assert.ErrorContains(t, err, "failed to read baseline")
+	assert.Equal(t, 1, exitCode(err))
+
+	what, output = newTestThreagile(t, "severe", "")
+	assert.Error(t, what.checkRiskThreshold(newTestCommand(output), parsedModel))
+	assert.Equal(t, 1, exitCode(errors.New("any other failure")))
+}
+
+func TestAnalyzeModelFailOn(t *testing.T) {
+	what, _ := newTestThreagile(t, "", "")
+	what.config.InputFileValue = filepath.Join("..", "..", "demo", "example", "threagile.yaml")
+	what.config.OutputFolderValue = t.TempDir()
+	what.config.TempFolderValue = t.TempDir()
+	what.config.SkipDataFlowDiagramValue = true
+	what.config.SkipDataAssetDiagramValue = true
+	what.config.SkipRisksExcelValue = true
+	what.config.SkipTagsExcelValue = true
+	what.config.SkipReportPDFValue = true
+	what.config.SkipReportADOCValue = true
+	what.config.SkipReportHTMLValue = true
+	risksFile := filepath.Join(what.config.OutputFolderValue, what.config.GetJsonRisksFilename())
+
+	what.rootCmd.SetArgs([]string{AnalyzeModelCommand, "--fail-on", "elevated"})
+	err := what.rootCmd.Execute()
+	assert.Equal(t, RiskThresholdExceededExitCode, exitCode(err), err)
+	assert.FileExists(t, risksFile)
+
+	// the risks of the previous run are all known
+	baselineFile := filepath.Join(t.TempDir(), "baseline.json")
+	risks, err := os.ReadFile(risksFile)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(baselineFile, risks, 0600))
+	what.rootCmd.SetArgs([]string{AnalyzeModelCommand, "--fail-on", "elevated", "--baseline", baselineFile})
+	assert.NoError(t, what.rootCmd.Execute())
+
+	// the threshold is checked by analyze-model only
+	what.rootCmd.SetArgs([]string{ListTypesCommand, "--fail-on", "elevated"})
+	assert.ErrorContains(t, what.rootCmd.Execute(), "unknown flag: --fail-on")
+}
+
+func newTestThreagile(t *testing.T, failOn string, baselineFile string) (*Threagile, *bytes.Buffer) {
+	what := new(Threagile).Init("")
+	what.config.FailOnValue = failOn
+	what.config.BaselineFileValue = baselineFile
+	what.config.AppFolderValue = t.TempDir()
+
+	output := new(bytes.Buffer)
+	what.rootCmd.SetOut(output)
+	what.rootCmd.SetErr(output)
+	return what, output
+}
+
+func newTestCommand(output *bytes.Buffer) *cobra.Command {
+	cmd := new(cobra.Command)
+	cmd.SetOut(output)
+	return cmd
+}
+
+func writeTestRisks(t *testing.T, risks []*types.Risk) string {
+	data, err := json.Marshal(risks)
+	require.NoError(t, err)
+	filename := filepath.Join(t.TempDir(), "risks.json")
+	require.NoError(t, os.WriteFile(filename, data, 0600))
+	return filename
+}
@@ -13,24 +13,28 @@ const (
 	interactiveFlagName      = "interactive"
 	interactiveFlagShorthand = "i"
 
-	appDirFlagName    = "app-dir"
-	pluginDirFlagName = "plugin-dir"
-	dataDirFlagName   = "data-dir"
-	outputFlagName    = "output"
-	serverDirFlagName = "server-dir"
-	tempDirFlagName   = "temp-dir"
-	keyDirFlagName    = "key-dir"
+	appDirFlagName         = "app-dir"
+	pluginDirFlagName      = "plugin-dir"
+	scriptRulesDirFlagName = "script-rules-dir"
+	dataDirFlagName        = "data-dir"
+	outputFlagName         = "output"
+	serverDirFlagName      = "server-dir"
+	tempDirFlagName        = "temp-dir"
+	keyDirFlagName         = "key-dir"
 
 	inputFileFlagName               = "model"
 	importedFileFlagName            = "imported-model"
+	variantFlagName                 = "variant"
 	dataFlowDiagramPNGFileFlagName  = "data-flow-diagram-png"
 	dataAssetDiagramPNGFileFlagName = "data-asset-diagram-png"
 	dataFlowDiagramDOTFileFlagName  = "data-flow-diagram-dot"
 	dataAssetDiagramDOTFileFlagName = "data-asset-diagram-dot"
 	reportFileFlagName              = "report"
+	htmlReportFileFlagName          = "report-html"
 	risksExcelFileFlagName          = "risks-excel"
 	tagsExcelFileFlagName           = "tags-excel"
 	risksJsonFileFlagName           = "risks-json"
+	risksSarifFileFlagName          = "risks-sarif"
 	technicalAssetsJsonFileFlagName = "technical-assets-json"
 	statsJsonFileFlagName           = "stats-json"
 	templateFileNameFlagName        = "background"
@@ -40,12 +44,31 @@ const (
 	customRiskRulesPluginFlagName = "custom-risk-rules-plugin"
 	skipRiskRulesFlagName         = "skip-risk-rules"
 	executeModelMacroFlagName     = "execute-model-macro"
+	failOnFlagName                = "fail-on"
+	baselineFileFlagName          = "baseline"
+	errorFormatFlagName           = "error-format"
+	printMergedFlagName           = "print-merged"
+
+	diffFormatFlagName     = "format"
+	lintFormatFlagName     = "format"
+	skipLintChecksFlagName = "skip-lint-checks"
+	migrateFromFlagName    = "from"
+	migrateTargetFlagName  = "target"
+	migrateDryRunFlagName  = "dry-run"
+	migrateToFlagName      = "to"
 
 	serverModeFlagName               = "server-mode"
 	serverPortFlagName               = "server-port"
 	diagramDpiFlagName               = "diagram-dpi"
+	diagramFormatsFlagName           = "diagram-formats"
 	graphvizDpiFlagName              = "graphviz-dpi"
 	backupHistoryFilesToKeepFlagName = "backup-history-files-to-keep"
+	serverWorkersFlagName            = "server-workers"
+	serverJobTimeoutFlagName         = "server-job-timeout"
+	serverJobMemoryLimitFlagName     = "server-job-memory-limit"
+	serverSubprocessFlagName         = "server-subprocess"
+	serverModelStoreFlagName         = "server-model-store"
+	serverPersistTokensFlagName      = "server-persist-tokens"
 
 	addModelTitleFlagName              = "add-model-title"
 	keepDiagramSourceFilesFlagName     = "keep-diagram-source-files"
@@ -54,12 +77,14 @@ const (
 	skipDataFlowDiagramFlagName     = "skip-data-flow-diagram"
 	skipDataAssetDiagramFlagName    = "skip-data-asset-diagram"
 	skipRisksJSONFlagName           = "skip-risks-json"
+	skipRisksSARIFFlagName          = "skip-risks-sarif"
 	skipTechnicalAssetsJSONFlagName = "skip-technical-assets-json"
 	skipStatsJSONFlagName           = "skip-stats-json"
 	skipRisksExcelFlagName          = "skip-risks-excel"
 	skipTagsExcelFlagName           = "skip-tags-excel"
 	skipReportPDFFlagName           = "skip-report-pdf"
 	skipReportADOCFlagName          = "skip-report-adoc"
+	skipReportHTMLFlagName          = "skip-report-html"
 
 	generateDataFlowDiagramFlagName     = "generate-data-flow-diagram"
 	generateDataAssetDiagramFlagName    = "generate-data-asset-diagram"
@@ -78,6 +103,15 @@ type Flags struct {
 	configFlag           string
 	riskRulePluginsValue string
 	skipRiskRulesValue   string
+	diffFormatValue      string
+	lintFormatValue      string
+	printMergedValue     bool
+	skipLintChecksValue  string
+	diagramFormatsValue  string
+	migrateFromValue     string
+	migrateToValue       string
+	migrateTargetValue   string
+	migrateDryRunValue   bool
 
 	generateDataFlowDiagramFlag     bool // deprecated
 	generateDataAssetDiagramFlag    bool // deprecated
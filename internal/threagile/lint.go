@@ -0,0 +1,90 @@
+package threagile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/lint"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/risks"
+)
+
+type lintError struct {
+	count int
+}
+
+func (what *lintError) Error() string {
+	return fmt.Sprintf("model lint found %d error(s)", what.count)
+}
+
+func (what *Threagile) initLint() *Threagile {
+	checks := new(strings.Builder)
+	for _, rule := range lint.ListBuiltInRules() {
+		details := rule.GetRuleDetails()
+		_, _ = fmt.Fprintf(checks, "  %v (%v): %v\n", details.ID, details.DefaultSeverity, details.Description)
+	}
+
+	lintCmd := &cobra.Command{
+		Use:     LintCommand,
+		Aliases: []string{ValidateCommand},
+		Short:   "Check the model for hygiene issues",
+		Long: "Check the model for hygiene issues\n\n" +
+			"Beyond the errors stopping the analysis, the model is checked by the following rules (default severity in parentheses):\n\n" +
+			checks.String() + "\n" +
+			"The severity of each check can be changed (or the check turned off) in the config file via \"LintChecks\", e.g.\n" +
+			"{\"LintChecks\": {\"missing-owner\": \"error\", \"unused-tag\": \"off\"}}. The command fails if any finding is an error.",
+		RunE: what.lint,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+	}
+
+	lintCmd.Flags().StringVar(&what.flags.lintFormatValue, lintFormatFlagName, report.LintFormatText, "output format: "+strings.Join(report.LintFormats(), ", "))
+	lintCmd.Flags().StringVar(&what.flags.skipLintChecksValue, skipLintChecksFlagName, "", "comma-separated list of lint checks (by their ID) to skip")
+
+	what.rootCmd.AddCommand(lintCmd)
+
+	return what
+}
+
+func (what *Threagile) lint(cmd *cobra.Command, args []string) error {
+	what.processArgs(cmd, args)
+
+	// orphaned risk tracking is reported as a finding rather than failing the analysis
+	config := *what.config
+	config.IgnoreOrphanedRiskTrackingValue = true
+	config.ImportedInputFileValue = ""
+
+	result, err := model.ReadAndAnalyzeModel(&config, risks.GetBuiltInRiskRules(), DefaultProgressReporter{Verbose: config.GetVerbose()})
+	if err != nil {
+		return fmt.Errorf("failed to read and analyze model: %w", err)
+	}
+
+	severities := make(map[string]string)
+	for id, severity := range config.GetLintChecks() {
+		severities[id] = severity
+	}
+	for _, id := range strings.Split(what.flags.skipLintChecksValue, ",") {
+		if len(strings.TrimSpace(id)) > 0 {
+			severities[strings.TrimSpace(id)] = string(lint.SeverityOff)
+		}
+	}
+
+	findings, err := lint.Run(result.ModelInput, result.ParsedModel, lint.ListBuiltInRules(), severities)
+	if err != nil {
+		return err
+	}
+
+	err = report.WriteLintFindings(cmd.OutOrStdout(), findings, what.flags.lintFormatValue)
+	if err != nil {
+		return err
+	}
+
+	if count := lint.CountBySeverity(findings, lint.SeverityError); count > 0 {
+		return &lintError{count: count}
+	}
+
+	return nil
+}
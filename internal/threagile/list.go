@@ -2,6 +2,8 @@ package threagile
 
 import (
 	"fmt"
+	"sort"
+
 	"github.com/spf13/cobra"
 	"github.com/threagile/threagile/pkg/macros"
 	"github.com/threagile/threagile/pkg/model"
@@ -27,6 +29,20 @@ func (what *Threagile) initList() *Threagile {
 				cmd.Println(id, "-->", customRule.Category().Title, "--> with tags:", customRule.SupportedTags())
 			}
 			cmd.Println()
+			if len(what.config.GetScriptRulesFolder()) > 0 {
+				cmd.Println("----------------------")
+				cmd.Println("Script risk rules:")
+				cmd.Println("----------------------")
+				scriptRiskRules, loadError := risks.LoadScriptRiskRules(what.config.GetScriptRulesFolder())
+				if loadError != nil {
+					return loadError
+				}
+				for _, id := range sortedRiskRuleIds(types.RiskRules(scriptRiskRules)) {
+					scriptRule := scriptRiskRules[id]
+					cmd.Println(id, "-->", scriptRule.Category().Title, "--> with tags:", scriptRule.SupportedTags())
+				}
+				cmd.Println()
+			}
 			cmd.Println("--------------------")
 			cmd.Println("Built-in risk rules:")
 			cmd.Println("--------------------")
@@ -86,3 +102,14 @@ func (what *Threagile) initList() *Threagile {
 
 	return what
 }
+
+// sortedRiskRuleIds returns the ids of the rules sorted, so the rules are always listed in the same order
+func sortedRiskRuleIds(rules types.RiskRules) []string {
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
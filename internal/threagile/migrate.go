@@ -0,0 +1,93 @@
+package threagile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/input"
+)
+
+func (what *Threagile) initMigrate() *Threagile {
+	migrate := &cobra.Command{
+		Use:   MigrateCommand + " [model files]",
+		Short: "Upgrade model files to the current schema version",
+		Long: "Upgrade model files to the current schema version (" + strconv.Itoa(input.SchemaVersion) + ")\n\n" +
+			"Renamed fields, changed enum values and moved structures of older model files are upgraded in place\n" +
+			"(or written to --" + migrateTargetFlagName + "), keeping their comments. Without model files the --" + inputFileFlagName + " file is migrated.\n" +
+			"Includes and overlays are separate files, pass them as well to migrate them.\n\n" +
+			"Older model files are migrated in memory whenever they are read, so migrating the files themselves is optional.",
+		RunE: what.migrate,
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd: true,
+		},
+	}
+
+	migrate.Flags().StringVar(&what.flags.migrateTargetValue, migrateTargetFlagName, "", "file to write the migrated model to instead of changing it in place (for a single model file)")
+	migrate.Flags().BoolVar(&what.flags.migrateDryRunValue, migrateDryRunFlagName, false, "only print the changes without writing any file")
+
+	what.rootCmd.AddCommand(migrate)
+
+	return what
+}
+
+func (what *Threagile) migrate(cmd *cobra.Command, args []string) error {
+	what.processArgs(cmd, args)
+
+	filenames := args
+	if len(filenames) == 0 {
+		filenames = []string{what.config.GetInputFile()}
+	}
+
+	if len(what.flags.migrateTargetValue) > 0 && len(filenames) > 1 {
+		return fmt.Errorf("--%v can only be used to migrate a single model file", migrateTargetFlagName)
+	}
+
+	for _, filename := range filenames {
+		modelYaml, err := os.ReadFile(filepath.Clean(filename))
+		if err != nil {
+			return fmt.Errorf("unable to read model file: %w", err)
+		}
+
+		migratedYaml, changes, err := input.MigrateModelYaml(modelYaml)
+		if err != nil {
+			return fmt.Errorf("unable to migrate %q: %w", filename, err)
+		}
+
+		if len(changes) == 0 {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%v: already at schema version %v\n", filename, input.SchemaVersion)
+		} else {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%v:\n", filename)
+			for _, change := range changes {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %v\n", change)
+			}
+		}
+
+		if what.flags.migrateDryRunValue {
+			continue
+		}
+
+		target := what.flags.migrateTargetValue
+		if len(target) == 0 {
+			if len(changes) == 0 {
+				continue
+			}
+
+			target = filename
+		}
+
+		mode := os.FileMode(0600)
+		if info, statErr := os.Stat(filename); statErr == nil {
+			mode = info.Mode().Perm()
+		}
+
+		err = os.WriteFile(filepath.Clean(target), migratedYaml, mode)
+		if err != nil {
+			return fmt.Errorf("unable to write migrated model file: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -65,6 +65,7 @@ func (what *Threagile) initFlags() *Threagile {
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.AppFolderValue, appDirFlagName, what.config.GetAppFolder(), "app folder")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.PluginFolderValue, pluginDirFlagName, what.config.GetPluginFolder(), "plugin directory")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.ScriptRulesFolderValue, scriptRulesDirFlagName, what.config.GetScriptRulesFolder(), "directory with additional script risk rules (*.yaml)")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.DataFolderValue, dataDirFlagName, what.config.GetDataFolder(), "data directory")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.OutputFolderValue, outputFlagName, what.config.GetOutputFolder(), "output directory")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.TempFolderValue, tempDirFlagName, what.config.GetTempFolder(), "temporary folder location")
@@ -72,14 +73,17 @@ func (what *Threagile) initFlags() *Threagile {
 
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.InputFileValue, inputFileFlagName, what.config.GetInputFile(), "input model yaml file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.ImportedInputFileValue, importedFileFlagName, what.config.GetImportedInputFile(), "imported input model yaml file")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.VariantValue, variantFlagName, what.config.GetVariant(), "variant of the model whose overlays are applied, e.g. prod")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.DataFlowDiagramFilenamePNGValue, dataFlowDiagramPNGFileFlagName, what.config.GetDataFlowDiagramFilenamePNG(), "data flow diagram PNG file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.DataAssetDiagramFilenamePNGValue, dataAssetDiagramPNGFileFlagName, what.config.GetDataAssetDiagramFilenamePNG(), "data asset diagram PNG file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.DataFlowDiagramFilenameDOTValue, dataFlowDiagramDOTFileFlagName, what.config.GetDataFlowDiagramFilenameDOT(), "data flow diagram DOT file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.DataAssetDiagramFilenameDOTValue, dataAssetDiagramDOTFileFlagName, what.config.GetDataAssetDiagramFilenameDOT(), "data asset diagram DOT file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.ReportFilenameValue, reportFileFlagName, what.config.GetReportFilename(), "report file")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.HtmlReportFilenameValue, htmlReportFileFlagName, what.config.GetHtmlReportFilename(), "HTML report file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.ExcelRisksFilenameValue, risksExcelFileFlagName, what.config.GetExcelRisksFilename(), "risks Excel file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.ExcelTagsFilenameValue, tagsExcelFileFlagName, what.config.GetExcelTagsFilename(), "tags Excel file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.JsonRisksFilenameValue, risksJsonFileFlagName, what.config.GetJsonRisksFilename(), "risks JSON file")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.SarifRisksFilenameValue, risksSarifFileFlagName, what.config.GetSarifRisksFilename(), "risks SARIF file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.JsonTechnicalAssetsFilenameValue, technicalAssetsJsonFileFlagName, what.config.GetJsonTechnicalAssetsFilename(), "technical assets JSON file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.JsonStatsFilenameValue, statsJsonFileFlagName, what.config.GetJsonStatsFilename(), "stats JSON file")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.TemplateFilenameValue, templateFileNameFlagName, what.config.GetTemplateFilename(), "template pdf file")
@@ -89,12 +93,14 @@ func (what *Threagile) initFlags() *Threagile {
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.riskRulePluginsValue, customRiskRulesPluginFlagName, strings.Join(what.config.GetRiskRulePlugins(), ","), "comma-separated list of plugins file names with custom risk rules to load")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.skipRiskRulesValue, skipRiskRulesFlagName, strings.Join(what.config.GetSkipRiskRules(), ","), "comma-separated list of risk rules (by their ID) to skip")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.ExecuteModelMacroValue, executeModelMacroFlagName, what.config.GetExecuteModelMacro(), "macro to execute")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.ErrorFormatValue, errorFormatFlagName, what.config.GetErrorFormat(), "output format of the problems found in the model: "+strings.Join(report.DiagnosticsFormats(), ", "))
 
 	// RiskExcelValue not available as flags
 
 	what.rootCmd.PersistentFlags().IntVar(&what.flags.ServerPortValue, serverPortFlagName, what.config.GetServerPort(), "server port")
 	what.rootCmd.PersistentFlags().StringVar(&what.flags.ServerFolderValue, serverDirFlagName, what.config.GetDataFolder(), "base folder for server mode (default: "+DataDir+")")
 	what.rootCmd.PersistentFlags().IntVar(&what.flags.DiagramDPIValue, diagramDpiFlagName, what.config.GetDiagramDPI(), "DPI used to render: maximum is "+fmt.Sprintf("%d", what.config.GetMaxGraphvizDPI())+"")
+	what.rootCmd.PersistentFlags().StringVar(&what.flags.diagramFormatsValue, diagramFormatsFlagName, strings.Join(what.config.GetDiagramFormats(), ","), "comma-separated list of formats to render the diagrams in ("+strings.Join(report.DiagramFormats(), ", ")+")")
 	// MaxGraphvizDPIValue not available as flags
 	what.rootCmd.PersistentFlags().IntVar(&what.flags.BackupHistoryFilesToKeepValue, backupHistoryFilesToKeepFlagName, what.config.GetBackupHistoryFilesToKeep(), "number of backup history files to keep")
 
@@ -105,12 +111,14 @@ func (what *Threagile) initFlags() *Threagile {
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipDataFlowDiagramValue, skipDataFlowDiagramFlagName, what.config.GetSkipDataFlowDiagram(), "skip generating data flow diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipDataAssetDiagramValue, skipDataAssetDiagramFlagName, what.config.GetSkipDataAssetDiagram(), "skip generating data asset diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipRisksJSONValue, skipRisksJSONFlagName, what.config.GetSkipRisksJSON(), "skip generating risks json")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipRisksSARIFValue, skipRisksSARIFFlagName, what.config.GetSkipRisksSARIF(), "skip generating risks sarif")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipTechnicalAssetsJSONValue, skipTechnicalAssetsJSONFlagName, what.config.GetSkipTechnicalAssetsJSON(), "skip generating technical assets json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipStatsJSONValue, skipStatsJSONFlagName, what.config.GetSkipStatsJSON(), "skip generating stats json")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipRisksExcelValue, skipRisksExcelFlagName, what.config.GetSkipRisksExcel(), "skip generating risks excel")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipTagsExcelValue, skipTagsExcelFlagName, what.config.GetSkipTagsExcel(), "skip generating tags excel")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipReportPDFValue, skipReportPDFFlagName, what.config.GetSkipReportPDF(), "skip generating report pdf, including diagrams")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipReportADOCValue, skipReportADOCFlagName, what.config.GetSkipReportADOC(), "skip generating report adoc, including diagrams")
+	what.rootCmd.PersistentFlags().BoolVar(&what.flags.SkipReportHTMLValue, skipReportHTMLFlagName, what.config.GetSkipReportHTML(), "skip generating report html, including the data flow diagram")
 
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataFlowDiagramFlag, generateDataFlowDiagramFlagName, !what.config.GetSkipDataFlowDiagram(), "(deprecated) generate generating data flow diagram")
 	what.rootCmd.PersistentFlags().BoolVar(&what.flags.generateDataAssetDiagramFlag, generateDataAssetDiagramFlagName, !what.config.GetSkipDataAssetDiagram(), "(deprecated) generate generating data asset diagram")
@@ -253,15 +261,17 @@ func (what *Threagile) usage(cmd *cobra.Command) string {
 
 func (what *Threagile) readCommands() *report.GenerateCommands {
 	commands := new(report.GenerateCommands).Defaults()
-	commands.DataFlowDiagram = !what.flags.SkipDataFlowDiagramValue
-	commands.DataAssetDiagram = !what.flags.SkipDataAssetDiagramValue
-	commands.RisksJSON = !what.flags.SkipRisksJSONValue
-	commands.StatsJSON = !what.flags.SkipStatsJSONValue
-	commands.TechnicalAssetsJSON = !what.flags.SkipTechnicalAssetsJSONValue
-	commands.RisksExcel = !what.flags.SkipRisksExcelValue
-	commands.TagsExcel = !what.flags.SkipTagsExcelValue
-	commands.ReportPDF = !what.flags.SkipReportPDFValue
-	commands.ReportADOC = !what.flags.SkipReportADOCValue
+	commands.DataFlowDiagram = !what.config.GetSkipDataFlowDiagram()
+	commands.DataAssetDiagram = !what.config.GetSkipDataAssetDiagram()
+	commands.RisksJSON = !what.config.GetSkipRisksJSON()
+	commands.RisksSARIF = !what.config.GetSkipRisksSARIF()
+	commands.StatsJSON = !what.config.GetSkipStatsJSON()
+	commands.TechnicalAssetsJSON = !what.config.GetSkipTechnicalAssetsJSON()
+	commands.RisksExcel = !what.config.GetSkipRisksExcel()
+	commands.TagsExcel = !what.config.GetSkipTagsExcel()
+	commands.ReportPDF = !what.config.GetSkipReportPDF()
+	commands.ReportADOC = !what.config.GetSkipReportADOC()
+	commands.ReportHTML = !what.config.GetSkipReportHTML()
 	return commands
 }
 
@@ -271,6 +281,8 @@ func (what *Threagile) processSystemArgs(cmd *cobra.Command) *Threagile {
 }
 
 func (what *Threagile) processArgs(cmd *cobra.Command, args []string) bool {
+	// the flags of the commands are unknown here, they must not stop parsing the persistent flags following them
+	cmd.PersistentFlags().ParseErrorsWhitelist.UnknownFlags = true
 	_ = cmd.PersistentFlags().Parse(args)
 
 	if what.isFlagOverridden(cmd, configFlagName) {
@@ -297,6 +309,10 @@ func (what *Threagile) processArgs(cmd *cobra.Command, args []string) bool {
 		what.config.PluginFolderValue = what.config.CleanPath(what.flags.PluginFolderValue)
 	}
 
+	if what.isFlagOverridden(cmd, scriptRulesDirFlagName) {
+		what.config.ScriptRulesFolderValue = what.config.CleanPath(what.flags.ScriptRulesFolderValue)
+	}
+
 	if what.isFlagOverridden(cmd, dataDirFlagName) {
 		what.config.DataFolderValue = what.config.CleanPath(what.flags.DataFolderValue)
 	}
@@ -325,6 +341,10 @@ func (what *Threagile) processArgs(cmd *cobra.Command, args []string) bool {
 		what.config.InputFileValue = what.config.CleanPath(what.flags.InputFileValue)
 	}
 
+	if what.isFlagOverridden(cmd, variantFlagName) {
+		what.config.VariantValue = what.flags.VariantValue
+	}
+
 	if what.isFlagOverridden(cmd, dataFlowDiagramPNGFileFlagName) {
 		what.config.DataFlowDiagramFilenamePNGValue = what.config.CleanPath(what.flags.DataFlowDiagramFilenamePNGValue)
 	}
@@ -345,6 +365,10 @@ func (what *Threagile) processArgs(cmd *cobra.Command, args []string) bool {
 		what.config.ReportFilenameValue = what.config.CleanPath(what.flags.ReportFilenameValue)
 	}
 
+	if what.isFlagOverridden(cmd, htmlReportFileFlagName) {
+		what.config.HtmlReportFilenameValue = what.config.CleanPath(what.flags.HtmlReportFilenameValue)
+	}
+
 	if what.isFlagOverridden(cmd, risksExcelFileFlagName) {
 		what.config.ExcelRisksFilenameValue = what.config.CleanPath(what.flags.ExcelRisksFilenameValue)
 	}
@@ -357,6 +381,10 @@ func (what *Threagile) processArgs(cmd *cobra.Command, args []string) bool {
 		what.config.JsonRisksFilenameValue = what.config.CleanPath(what.flags.JsonRisksFilenameValue)
 	}
 
+	if what.isFlagOverridden(cmd, risksSarifFileFlagName) {
+		what.config.SarifRisksFilenameValue = what.config.CleanPath(what.flags.SarifRisksFilenameValue)
+	}
+
 	if what.isFlagOverridden(cmd, technicalAssetsJsonFileFlagName) {
 		what.config.JsonTechnicalAssetsFilenameValue = what.config.CleanPath(what.flags.JsonTechnicalAssetsFilenameValue)
 	}
@@ -389,6 +417,10 @@ func (what *Threagile) processArgs(cmd *cobra.Command, args []string) bool {
 		what.config.ExecuteModelMacroValue = what.flags.ExecuteModelMacroValue
 	}
 
+	if what.isFlagOverridden(cmd, errorFormatFlagName) {
+		what.config.ErrorFormatValue = what.flags.ErrorFormatValue
+	}
+
 	// RiskExcelValue not available as flags
 
 	if what.isFlagOverridden(cmd, serverModeFlagName) {
@@ -399,10 +431,38 @@ func (what *Threagile) processArgs(cmd *cobra.Command, args []string) bool {
 		what.config.ServerPortValue = what.flags.ServerPortValue
 	}
 
+	if what.isFlagOverridden(cmd, serverWorkersFlagName) {
+		what.config.ServerWorkersValue = what.flags.ServerWorkersValue
+	}
+
+	if what.isFlagOverridden(cmd, serverJobTimeoutFlagName) {
+		what.config.ServerJobTimeoutValue = what.flags.ServerJobTimeoutValue
+	}
+
+	if what.isFlagOverridden(cmd, serverJobMemoryLimitFlagName) {
+		what.config.ServerJobMemoryLimitValue = what.flags.ServerJobMemoryLimitValue
+	}
+
+	if what.isFlagOverridden(cmd, serverSubprocessFlagName) {
+		what.config.ServerSubprocessValue = what.flags.ServerSubprocessValue
+	}
+
+	if what.isFlagOverridden(cmd, serverModelStoreFlagName) {
+		what.config.ServerModelStoreValue = what.flags.ServerModelStoreValue
+	}
+
+	if what.isFlagOverridden(cmd, serverPersistTokensFlagName) {
+		what.config.ServerPersistTokensValue = what.flags.ServerPersistTokensValue
+	}
+
 	if what.isFlagOverridden(cmd, diagramDpiFlagName) {
 		what.config.DiagramDPIValue = what.flags.DiagramDPIValue
 	}
 
+	if what.isFlagOverridden(cmd, diagramFormatsFlagName) {
+		what.config.DiagramFormatsValue = strings.Split(what.flags.diagramFormatsValue, ",")
+	}
+
 	if what.isFlagOverridden(cmd, graphvizDpiFlagName) {
 		what.config.GraphvizDPIValue = what.flags.GraphvizDPIValue
 	}
@@ -437,6 +497,10 @@ func (what *Threagile) processArgs(cmd *cobra.Command, args []string) bool {
 		what.config.SkipRisksJSONValue = what.flags.SkipRisksJSONValue
 	}
 
+	if what.isFlagOverridden(cmd, skipRisksSARIFFlagName) {
+		what.config.SkipRisksSARIFValue = what.flags.SkipRisksSARIFValue
+	}
+
 	if what.isFlagOverridden(cmd, skipTechnicalAssetsJSONFlagName) {
 		what.config.SkipTechnicalAssetsJSONValue = what.flags.SkipTechnicalAssetsJSONValue
 	}
@@ -461,6 +525,10 @@ func (what *Threagile) processArgs(cmd *cobra.Command, args []string) bool {
 		what.config.SkipReportADOCValue = what.flags.SkipReportADOCValue
 	}
 
+	if what.isFlagOverridden(cmd, skipReportHTMLFlagName) {
+		what.config.SkipReportHTMLValue = what.flags.SkipReportHTMLValue
+	}
+
 	if what.isFlagOverridden(cmd, generateDataFlowDiagramFlagName) {
 		what.config.SkipDataFlowDiagramValue = !what.flags.generateDataFlowDiagramFlag
 	}
@@ -1,6 +1,8 @@
 package threagile
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/threagile/threagile/pkg/risks"
 	"github.com/threagile/threagile/pkg/server"
@@ -18,6 +20,28 @@ func (what *Threagile) initServer() *Threagile {
 
 	serverCmd.PersistentFlags().IntVar(&what.flags.ServerPortValue, serverPortFlagName, what.config.GetServerPort(), "server port")
 	serverCmd.PersistentFlags().StringVar(&what.flags.ServerFolderValue, serverDirFlagName, what.config.GetDataFolder(), "base folder for server mode (default: "+DataDir+")")
+	serverCmd.PersistentFlags().IntVar(&what.flags.ServerWorkersValue, serverWorkersFlagName, what.config.GetServerWorkers(), "number of analyses run at the same time")
+	serverCmd.PersistentFlags().IntVar(&what.flags.ServerJobTimeoutValue, serverJobTimeoutFlagName, what.config.GetServerJobTimeout(), "seconds after which an analysis is aborted (0 for no timeout)")
+	serverCmd.PersistentFlags().IntVar(&what.flags.ServerJobMemoryLimitValue, serverJobMemoryLimitFlagName, what.config.GetServerJobMemoryLimit(), "MiB of memory an analysis may use before it is aborted (0 for no limit)")
+	serverCmd.PersistentFlags().BoolVar(&what.flags.ServerSubprocessValue, serverSubprocessFlagName, what.config.GetServerSubprocess(), "run each analysis in a separate process")
+	serverCmd.PersistentFlags().StringVar(&what.flags.ServerModelStoreValue, serverModelStoreFlagName, what.config.GetServerModelStore(), "store of the models: "+server.ModelStoreFilesystem+" or "+server.ModelStoreBolt)
+	serverCmd.PersistentFlags().BoolVar(&what.flags.ServerPersistTokensValue, serverPersistTokensFlagName, what.config.GetServerPersistTokens(), "keep the tokens across server restarts")
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate-models",
+		Short: "Copy all models of the server along with their history from one model store to another",
+		Long: "Copy all models of the server along with their history from one model store to another\n\n" +
+			"The server must not be running while migrating. Afterwards start it with the new store (" + serverModelStoreFlagName + ").",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// the server flags are the persistent ones of the server command
+			what.processArgs(cmd.Parent(), args)
+			return what.migrateModels(cmd)
+		},
+	}
+	migrateCmd.Flags().StringVar(&what.flags.migrateFromValue, migrateFromFlagName, server.ModelStoreFilesystem, "store to copy the models from")
+	migrateCmd.Flags().StringVar(&what.flags.migrateToValue, migrateToFlagName, server.ModelStoreBolt, "store to copy the models to")
+	serverCmd.AddCommand(migrateCmd)
 
 	what.rootCmd.AddCommand(serverCmd)
 
@@ -31,6 +55,31 @@ func (what *Threagile) runServer() error {
 		return serverError
 	}
 
-	server.RunServer(what.config, risks.GetBuiltInRiskRules())
+	return server.RunServer(what.config, risks.GetBuiltInRiskRules())
+}
+
+func (what *Threagile) migrateModels(cmd *cobra.Command) error {
+	if what.flags.migrateFromValue == what.flags.migrateToValue {
+		return fmt.Errorf("unable to migrate models: source and target store are the same (%v)", what.flags.migrateFromValue)
+	}
+
+	from, err := server.OpenModelStore(what.flags.migrateFromValue, what.config)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = from.Close() }()
+
+	to, err := server.OpenModelStore(what.flags.migrateToValue, what.config)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = to.Close() }()
+
+	count, err := server.MigrateModels(from, to)
+	if err != nil {
+		return fmt.Errorf("unable to migrate models (%d migrated so far): %w", count, err)
+	}
+
+	cmd.Printf("migrated %d models from %v to %v\n", count, what.flags.migrateFromValue, what.flags.migrateToValue)
 	return nil
 }
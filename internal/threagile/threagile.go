@@ -1,9 +1,13 @@
 package threagile
 
 import (
+	"errors"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/types"
 )
 
 type Threagile struct {
@@ -16,8 +20,14 @@ type Threagile struct {
 func (what *Threagile) Execute() {
 	err := what.rootCmd.Execute()
 	if err != nil {
-		what.rootCmd.Println(err)
-		os.Exit(1)
+		var diagnostics types.Diagnostics
+		if errors.As(err, &diagnostics) {
+			what.printDiagnostics(diagnostics)
+		} else {
+			what.rootCmd.Println(err)
+		}
+
+		os.Exit(exitCode(err))
 	}
 
 	if what.config.GetServerMode() {
@@ -28,7 +38,32 @@ func (what *Threagile) Execute() {
 	}
 }
 
+// exitCode tells apart risks reaching the --fail-on threshold from any other failure
+func exitCode(err error) int {
+	var thresholdError *riskThresholdError
+	if errors.As(err, &thresholdError) {
+		return RiskThresholdExceededExitCode
+	}
+
+	return 1
+}
+
 func (what *Threagile) Init(buildTimestamp string) *Threagile {
 	what.buildTimestamp = buildTimestamp
-	return what.initRoot().initImport().initAnalyze().initCreate().initExecute().initExplain().initList().initPrint().initQuit().initServer().initVersion().processSystemArgs(what.rootCmd)
+	return what.initRoot().initImport().initAnalyze().initCreate().initDiff().initExecute().initExplain().initLint().initList().initMigrate().initPrint().initQuit().initServer().initVersion().processSystemArgs(what.rootCmd)
+}
+
+// printDiagnostics prints all problems found in the model, either as summary or as JSON (on stdout) for tooling
+func (what *Threagile) printDiagnostics(diagnostics types.Diagnostics) {
+	writer := what.rootCmd.ErrOrStderr()
+	if strings.EqualFold(what.config.GetErrorFormat(), report.DiagnosticsFormatJSON) {
+		writer = what.rootCmd.OutOrStdout()
+	} else {
+		what.rootCmd.PrintErrln("The model has errors:")
+	}
+
+	err := report.WriteDiagnostics(writer, diagnostics, what.config.GetErrorFormat())
+	if err != nil {
+		what.rootCmd.PrintErrln(err)
+	}
 }
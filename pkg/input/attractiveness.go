@@ -0,0 +1,36 @@
+package input
+
+import "fmt"
+
+type Attractiveness struct {
+	Quantity              int                    `yaml:"quantity,omitempty" json:"quantity,omitempty"`
+	Confidentiality       AttackerFocus          `yaml:"confidentiality,omitempty" json:"confidentiality,omitempty"`
+	Integrity             AttackerFocus          `yaml:"integrity,omitempty" json:"integrity,omitempty"`
+	Availability          AttackerFocus          `yaml:"availability,omitempty" json:"availability,omitempty"`
+	TechnologyMultipliers []TechnologyMultiplier `yaml:"technology_multipliers,omitempty" json:"technology_multipliers,omitempty"`
+	DatastoreMultiplier   float64                `yaml:"datastore_multiplier,omitempty" json:"datastore_multiplier,omitempty"`
+	MultiTenantMultiplier float64                `yaml:"multi_tenant_multiplier,omitempty" json:"multi_tenant_multiplier,omitempty"`
+}
+
+type AttackerFocus struct {
+	Asset                 int `yaml:"asset,omitempty" json:"asset,omitempty"`
+	ProcessedOrStoredData int `yaml:"processed_or_stored_data,omitempty" json:"processed_or_stored_data,omitempty"`
+	TransferredData       int `yaml:"transferred_data,omitempty" json:"transferred_data,omitempty"`
+}
+
+type TechnologyMultiplier struct {
+	Attributes []string `yaml:"attributes,omitempty" json:"attributes,omitempty"`
+	Multiplier float64  `yaml:"multiplier,omitempty" json:"multiplier,omitempty"`
+}
+
+func (what *Attractiveness) Merge(other *Attractiveness) (*Attractiveness, error) {
+	if what == nil {
+		return other, nil
+	}
+
+	if other == nil {
+		return what, nil
+	}
+
+	return what, fmt.Errorf("attractiveness is defined more than once")
+}
@@ -1,19 +1,24 @@
 package input
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/types"
+)
 
 type DataAsset struct {
-	ID                     string   `yaml:"id,omitempty" json:"id,omitempty"`
-	Description            string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Usage                  string   `yaml:"usage,omitempty" json:"usage,omitempty"`
-	Tags                   []string `yaml:"tags,omitempty" json:"tags,omitempty"`
-	Origin                 string   `yaml:"origin,omitempty" json:"origin,omitempty"`
-	Owner                  string   `yaml:"owner,omitempty" json:"owner,omitempty"`
-	Quantity               string   `yaml:"quantity,omitempty" json:"quantity,omitempty"`
-	Confidentiality        string   `yaml:"confidentiality,omitempty" json:"confidentiality,omitempty"`
-	Integrity              string   `yaml:"integrity,omitempty" json:"integrity,omitempty"`
-	Availability           string   `yaml:"availability,omitempty" json:"availability,omitempty"`
-	JustificationCiaRating string   `yaml:"justification_cia_rating,omitempty" json:"justification_cia_rating,omitempty"`
+	ID                     string                `yaml:"id,omitempty" json:"id,omitempty"`
+	Description            string                `yaml:"description,omitempty" json:"description,omitempty"`
+	Usage                  string                `yaml:"usage,omitempty" json:"usage,omitempty"`
+	Tags                   []string              `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Origin                 string                `yaml:"origin,omitempty" json:"origin,omitempty"`
+	Owner                  string                `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Quantity               string                `yaml:"quantity,omitempty" json:"quantity,omitempty"`
+	Confidentiality        string                `yaml:"confidentiality,omitempty" json:"confidentiality,omitempty"`
+	Integrity              string                `yaml:"integrity,omitempty" json:"integrity,omitempty"`
+	Availability           string                `yaml:"availability,omitempty" json:"availability,omitempty"`
+	JustificationCiaRating string                `yaml:"justification_cia_rating,omitempty" json:"justification_cia_rating,omitempty"`
+	SourcePosition         *types.SourcePosition `yaml:"-" json:"-"`
 }
 
 func (what *DataAsset) Merge(other DataAsset) error {
@@ -0,0 +1,126 @@
+package input
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// resolveInclude returns the model files of an include relative to dir in merge order;
+// literal is false for directories and globs, whose files are found rather than named
+func resolveInclude(dir string, include string) ([]string, bool, error) {
+	includePath := filepath.Join(dir, include)
+	if strings.ContainsAny(include, "*?[") {
+		matches, globError := filepath.Glob(includePath)
+		if globError != nil {
+			return nil, false, fmt.Errorf("invalid include pattern %q: %w", include, globError)
+		}
+
+		files := make([]string, 0)
+		for _, match := range matches {
+			matchFiles, walkError := modelFiles(match)
+			if walkError != nil {
+				return nil, false, walkError
+			}
+
+			files = append(files, matchFiles...)
+		}
+
+		return files, false, nil
+	}
+
+	info, statError := os.Stat(includePath)
+	if statError != nil || !info.IsDir() {
+		return []string{includePath}, true, nil
+	}
+
+	files, walkError := modelFiles(includePath)
+	return files, false, walkError
+}
+
+// modelFiles returns the path itself if it is a file, else the yaml files below the directory sorted by path,
+// skipping hidden files and directories
+func modelFiles(path string) ([]string, error) {
+	files := make([]string, 0)
+	walkError := filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filePath != path && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		extension := strings.ToLower(filepath.Ext(filePath))
+		if filePath == path || extension == ".yaml" || extension == ".yml" {
+			files = append(files, filePath)
+		}
+
+		return nil
+	})
+	if walkError != nil {
+		return nil, fmt.Errorf("unable to list model files of %q: %w", path, walkError)
+	}
+
+	return files, nil
+}
+
+func includeKey(path string) string {
+	absolutePath, absError := filepath.Abs(path)
+	if absError != nil {
+		return filepath.Clean(path)
+	}
+
+	return absolutePath
+}
+
+// startFile records that the file is being read in the merge mode, it returns false if the file was read in that mode before;
+// a file included by the model is still applied as an overlay of a variant
+func (what *expansionScope) startFile(path string, mode MergeMode) bool {
+	key := includeKey(path)
+	if what.loadedFiles[mode][key] {
+		return false
+	}
+
+	if what.loadedFiles[mode] == nil {
+		what.loadedFiles[mode] = make(map[string]bool)
+	}
+
+	what.loadedFiles[mode][key] = true
+	what.includeStack = append(what.includeStack, path)
+	return true
+}
+
+func (what *expansionScope) endFile() {
+	if len(what.includeStack) > 0 {
+		what.includeStack = what.includeStack[:len(what.includeStack)-1]
+	}
+}
+
+func (what *expansionScope) isIncluding(path string) bool {
+	key := includeKey(path)
+	return slices.ContainsFunc(what.includeStack, func(including string) bool {
+		return includeKey(including) == key
+	})
+}
+
+// includeCycle describes the chain of includes from the file including path the first time up to path
+func (what *expansionScope) includeCycle(path string) string {
+	key := includeKey(path)
+	start := slices.IndexFunc(what.includeStack, func(including string) bool {
+		return includeKey(including) == key
+	})
+
+	return strings.Join(append(slices.Clone(what.includeStack[start:]), path), " -> ")
+}
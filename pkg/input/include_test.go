@@ -0,0 +1,170 @@
+package input
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveInclude(t *testing.T) {
+	dir := writeTestModelFiles(t, map[string]string{
+		"model.d/b.yaml":             "",
+		"model.d/a.YML":              "",
+		"model.d/notes.txt":          "",
+		"model.d/.draft.yaml":        "",
+		"model.d/.git/config.yaml":   "",
+		"model.d/teams/c.yaml":       "",
+		"model.d/teams/.old/d.yaml":  "",
+		"teams/orders/assets.yaml":   "",
+		"teams/billing/assets.yaml":  "",
+		"teams/billing/servers.yaml": "",
+	})
+	relative := func(files []string) []string {
+		result := make([]string, 0)
+		for _, file := range files {
+			path, relError := filepath.Rel(dir, file)
+			require.NoError(t, relError)
+			result = append(result, filepath.ToSlash(path))
+		}
+		return result
+	}
+
+	// directories are searched recursively for model files in the order of their paths, skipping hidden ones
+	files, literal, err := resolveInclude(dir, "model.d")
+	require.NoError(t, err)
+	assert.False(t, literal)
+	assert.Equal(t, []string{"model.d/a.YML", "model.d/b.yaml", "model.d/teams/c.yaml"}, relative(files))
+
+	files, literal, err = resolveInclude(dir, "teams/*/assets.yaml")
+	require.NoError(t, err)
+	assert.False(t, literal)
+	assert.Equal(t, []string{"teams/billing/assets.yaml", "teams/orders/assets.yaml"}, relative(files))
+
+	// files are taken as they are, even if missing or hidden
+	for _, include := range []string{"model.d/notes.txt", "model.d/.draft.yaml", "missing.yaml"} {
+		files, literal, err = resolveInclude(dir, include)
+		require.NoError(t, err)
+		assert.True(t, literal, include)
+		assert.Equal(t, []string{include}, relative(files))
+	}
+
+	files, _, err = resolveInclude(dir, "unknown/*.yaml")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	_, _, err = resolveInclude(dir, "teams/[")
+	assert.ErrorContains(t, err, `invalid include pattern "teams/["`)
+}
+
+func TestIncludes(t *testing.T) {
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml": `title: Shop
+includes:
+  - model.d
+  - model.d/assets.yaml
+  - "*.yaml"
+`,
+		"model.d/assets.yaml": `technical_assets:
+  Web:
+    id: web
+`,
+		"model.d/data/orders.yaml": `data_assets:
+  Orders:
+    id: orders
+`,
+		"model.d/.draft.yaml": `technical_assets:
+  Draft:
+    id: draft
+`,
+	})
+
+	// each file is merged once, and the glob skips the model file including it
+	model := loadTestModel(t, dir)
+	assert.Equal(t, "Shop", model.Title)
+	assert.Len(t, model.TechnicalAssets, 1)
+	assert.Contains(t, model.TechnicalAssets, "Web")
+	assert.Len(t, model.DataAssets, 1)
+	if assert.NotNil(t, model.DataAssets["Orders"].SourcePosition) {
+		assert.Equal(t, filepath.Join(dir, "model.d", "data", "orders.yaml"), model.DataAssets["Orders"].SourcePosition.File)
+	}
+}
+
+func TestIncludeCycle(t *testing.T) {
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml":         "title: Shop\nincludes:\n  - parts/assets.yaml\n",
+		"parts/assets.yaml":      "includes:\n  - boundaries.yaml\n",
+		"parts/boundaries.yaml":  "includes:\n  - ../threagile.yaml\n",
+		"direct/threagile.yaml":  "title: Shop\nincludes:\n  - threagile.yaml\n",
+		"globbed/threagile.yaml": "title: Shop\nincludes:\n  - parts\n",
+		"globbed/parts/a.yaml":   "includes:\n  - ../*.yaml\n",
+	})
+
+	err := new(Model).Defaults().Load(filepath.Join(dir, "threagile.yaml"))
+	assert.Contains(t, testModelError(err, dir), "include cycle: threagile.yaml -> parts/assets.yaml -> parts/boundaries.yaml -> threagile.yaml")
+
+	err = new(Model).Defaults().Load(filepath.Join(dir, "direct", "threagile.yaml"))
+	assert.Contains(t, testModelError(err, dir), "include cycle: direct/threagile.yaml -> direct/threagile.yaml")
+
+	// directories and globs skip the files being included instead
+	model := loadTestModel(t, filepath.Join(dir, "globbed"))
+	assert.Equal(t, "Shop", model.Title)
+}
+
+func TestOverlayMatchedByInclude(t *testing.T) {
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml": `title: Shop
+includes:
+  - model.d
+variants:
+  prod:
+    - model.d/prod.yaml
+`,
+		"model.d/prod.yaml": `technical_assets:
+  Web:
+    id: web
+    internet: true
+`,
+	})
+
+	// the overlay merged as include is still applied as overlay of the variant
+	model := loadTestModel(t, dir)
+	web := model.TechnicalAssets["Web"]
+	web.Internet = false
+	model.TechnicalAssets["Web"] = web
+	require.NoError(t, model.ApplyVariant(dir, "prod"))
+	assert.True(t, model.TechnicalAssets["Web"].Internet)
+}
+
+func TestMergedYaml(t *testing.T) {
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml": `title: Shop
+includes:
+  - assets.yaml
+variants:
+  prod:
+    - prod.yaml
+`,
+		"assets.yaml": "technical_assets:\n  Web:\n    id: web\n",
+		"prod.yaml":   "title: Shop (prod)\n",
+	})
+
+	model := loadTestModel(t, dir)
+	require.NoError(t, model.ApplyVariant(dir, "prod"))
+	mergedYaml, err := model.MergedYaml()
+	require.NoError(t, err)
+
+	merged := string(mergedYaml)
+	assert.Contains(t, merged, "title: Shop (prod)")
+	assert.Contains(t, merged, "Web: # "+filepath.Join(dir, "assets.yaml")+":2:3")
+	assert.NotContains(t, merged, "includes:")
+	assert.NotContains(t, merged, "variants:")
+
+	// the merged model is a model of its own
+	mergedDir := writeTestModelFiles(t, map[string]string{"threagile.yaml": merged})
+	mergedModel := loadTestModel(t, mergedDir)
+	assert.Equal(t, "Shop (prod)", mergedModel.Title)
+	assert.Contains(t, mergedModel.TechnicalAssets, "Web")
+	assert.Empty(t, mergedModel.Variants)
+}
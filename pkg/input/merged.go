@@ -0,0 +1,81 @@
+package input
+
+import (
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// MergedYaml marshals the model with all includes and overlays merged,
+// each element commented with the file and line it was defined at;
+// the includes and variants are left out, as they are merged already
+func (model *Model) MergedYaml() ([]byte, error) {
+	var root yaml.Node
+	encodeError := root.Encode(model)
+	if encodeError != nil {
+		return nil, fmt.Errorf("unable to encode merged model: %w", encodeError)
+	}
+
+	removeMappingEntry(&root, "includes")
+	removeMappingEntry(&root, "variants")
+
+	forEachMappingEntry(mappingValue(&root, "data_assets"), func(key *yaml.Node, _ *yaml.Node) {
+		setSourceComment(key, model.DataAssets[key.Value].SourcePosition)
+	})
+
+	forEachMappingEntry(mappingValue(&root, "technical_assets"), func(key *yaml.Node, value *yaml.Node) {
+		asset := model.TechnicalAssets[key.Value]
+		setSourceComment(key, asset.SourcePosition)
+		forEachMappingEntry(mappingValue(value, "communication_links"), func(linkKey *yaml.Node, _ *yaml.Node) {
+			setSourceComment(linkKey, asset.CommunicationLinks[linkKey.Value].SourcePosition)
+		})
+	})
+
+	forEachMappingEntry(mappingValue(&root, "trust_boundaries"), func(key *yaml.Node, _ *yaml.Node) {
+		setSourceComment(key, model.TrustBoundaries[key.Value].SourcePosition)
+	})
+
+	forEachMappingEntry(mappingValue(&root, "shared_runtimes"), func(key *yaml.Node, _ *yaml.Node) {
+		setSourceComment(key, model.SharedRuntimes[key.Value].SourcePosition)
+	})
+
+	forEachMappingEntry(mappingValue(&root, "risk_tracking"), func(key *yaml.Node, _ *yaml.Node) {
+		setSourceComment(key, model.RiskTracking[key.Value].SourcePosition)
+	})
+
+	categories := mappingValue(&root, "custom_risk_categories")
+	if categories != nil && categories.Kind == yaml.SequenceNode {
+		for i, categoryNode := range categories.Content {
+			if i < len(model.CustomRiskCategories) && model.CustomRiskCategories[i] != nil {
+				setSourceComment(categoryNode, model.CustomRiskCategories[i].SourcePosition)
+			}
+		}
+	}
+
+	mergedYaml, marshalError := yaml.Marshal(&root)
+	if marshalError != nil {
+		return nil, fmt.Errorf("unable to marshal merged model: %w", marshalError)
+	}
+
+	return mergedYaml, nil
+}
+
+func removeMappingEntry(node *yaml.Node, key string) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func setSourceComment(node *yaml.Node, position *types.SourcePosition) {
+	if position != nil {
+		node.LineComment = position.String()
+	}
+}
@@ -0,0 +1,221 @@
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the version of the model file format read and written by this version of Threagile;
+// model files without a schema_version are of version 1, the format of the first Threagile releases
+const SchemaVersion = 2
+
+const (
+	schemaVersionKey            = "schema_version"
+	individualRiskCategoriesKey = "individual_risk_categories"
+	customRiskCategoriesKey     = "custom_risk_categories"
+)
+
+// migration upgrades the root node of a model file to version and returns the changes it made
+type migration struct {
+	version int
+	apply   func(root *yaml.Node) []string
+}
+
+// migrations are applied in order to model files of a lower schema version, they have to leave
+// model files untouched which already are in the format of their version
+var migrations = []migration{
+	{version: 2, apply: migrateIndividualRiskCategories},
+	{version: 2, apply: migrateEndUserEnumValues},
+}
+
+// MigrateModelYaml upgrades the yaml of a model file to SchemaVersion, keeping its comments,
+// and returns the migrated yaml along with the changes made; yaml without changes is returned as is
+func MigrateModelYaml(modelYaml []byte) ([]byte, []string, error) {
+	var document yaml.Node
+	unmarshalError := yaml.Unmarshal(modelYaml, &document)
+	if unmarshalError != nil {
+		return nil, nil, fmt.Errorf("unable to parse model yaml: %w", unmarshalError)
+	}
+
+	if len(document.Content) == 0 {
+		return modelYaml, nil, nil
+	}
+
+	changes, migrateError := migrate(document.Content[0])
+	if migrateError != nil {
+		return nil, nil, migrateError
+	}
+
+	if len(changes) == 0 {
+		return modelYaml, nil, nil
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	encodeError := encoder.Encode(&document)
+	if encodeError != nil {
+		return nil, nil, fmt.Errorf("unable to write migrated model yaml: %w", encodeError)
+	}
+
+	closeError := encoder.Close()
+	if closeError != nil {
+		return nil, nil, fmt.Errorf("unable to write migrated model yaml: %w", closeError)
+	}
+
+	return buffer.Bytes(), changes, nil
+}
+
+// migrate upgrades the root node of a model file to SchemaVersion
+func migrate(root *yaml.Node) ([]string, error) {
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	version := 1
+	versionNode := mappingValue(root, schemaVersionKey)
+	if versionNode != nil {
+		var versionError error
+		version, versionError = strconv.Atoi(versionNode.Value)
+		if versionError != nil || version < 1 {
+			return nil, fmt.Errorf("line %v: invalid schema version %q", versionNode.Line, versionNode.Value)
+		}
+	}
+
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("model schema version %v is newer than version %v supported by this version of threagile", version, SchemaVersion)
+	}
+
+	if version == SchemaVersion {
+		return nil, nil
+	}
+
+	changes := make([]string, 0)
+	for _, step := range migrations {
+		if step.version > version {
+			changes = append(changes, step.apply(root)...)
+		}
+	}
+
+	setSchemaVersion(root, versionNode)
+	changes = append(changes, fmt.Sprintf("schema version changed from %v to %v", version, SchemaVersion))
+
+	return changes, nil
+}
+
+// setSchemaVersion sets the schema version of the model file, a missing one is added next to the threagile version
+func setSchemaVersion(root *yaml.Node, versionNode *yaml.Node) {
+	if versionNode != nil {
+		versionNode.Kind = yaml.ScalarNode
+		versionNode.Tag = "!!int"
+		versionNode.Style = 0
+		versionNode.Value = strconv.Itoa(SchemaVersion)
+		return
+	}
+
+	index := 0
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "threagile_version" {
+			index = i + 2
+			break
+		}
+	}
+
+	entry := []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: schemaVersionKey},
+		{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(SchemaVersion)},
+	}
+
+	root.Content = append(root.Content[:index], append(entry, root.Content[index:]...)...)
+}
+
+// migrateIndividualRiskCategories turns the individual risk categories, a map by title,
+// into the list of custom risk categories with the title as a field of each category
+func migrateIndividualRiskCategories(root *yaml.Node) []string {
+	changes := make([]string, 0)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], resolveAlias(root.Content[i+1])
+		if key.Value != individualRiskCategoriesKey {
+			continue
+		}
+
+		categories := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		forEachMappingEntry(value, func(title *yaml.Node, category *yaml.Node) {
+			if category.Kind == yaml.AliasNode {
+				category = copyNode(category)
+			}
+
+			if category.Kind != yaml.MappingNode {
+				category = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+
+			titleEntry := []*yaml.Node{
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: "title"},
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: title.Value, LineComment: title.LineComment},
+			}
+
+			category.HeadComment = title.HeadComment
+			category.Content = append(titleEntry, category.Content...)
+			categories.Content = append(categories.Content, category)
+		})
+
+		existing := resolveAlias(mappingValue(root, customRiskCategoriesKey))
+		if existing != nil && existing.Kind == yaml.SequenceNode {
+			existing.Content = append(existing.Content, categories.Content...)
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+			changes = append(changes, fmt.Sprintf("line %v: %v moved to %v", key.Line, individualRiskCategoriesKey, customRiskCategoriesKey))
+			return changes
+		}
+
+		if len(categories.Content) == 0 {
+			categories.Style = yaml.FlowStyle
+		}
+
+		key.Value = customRiskCategoriesKey
+		root.Content[i+1] = categories
+		changes = append(changes, fmt.Sprintf("line %v: %v renamed to %v", key.Line, individualRiskCategoriesKey, customRiskCategoriesKey))
+		return changes
+	}
+
+	return changes
+}
+
+// migrateEndUserEnumValues renames the enum values spelled enduser to end-user
+func migrateEndUserEnumValues(root *yaml.Node) []string {
+	changes := make([]string, 0)
+	forEachTechnicalAssetNode(root, func(asset *yaml.Node) {
+		changes = append(changes, renameEnumValue(asset, "encryption", "data-with-enduser-individual-key", "data-with-end-user-individual-key")...)
+		forEachMappingEntry(resolveAlias(mappingValue(asset, "communication_links")), func(_ *yaml.Node, link *yaml.Node) {
+			changes = append(changes, renameEnumValue(resolveAlias(link), "authorization", "enduser-identity-propagation", "end-user-identity-propagation")...)
+		})
+	})
+
+	return changes
+}
+
+// forEachTechnicalAssetNode calls handle for the technical assets of the model and of its templates
+func forEachTechnicalAssetNode(root *yaml.Node, handle func(asset *yaml.Node)) {
+	assets := []*yaml.Node{resolveAlias(mappingValue(root, "technical_assets"))}
+	forEachMappingEntry(resolveAlias(mappingValue(root, templatesKey)), func(_ *yaml.Node, template *yaml.Node) {
+		assets = append(assets, resolveAlias(mappingValue(resolveAlias(template), "technical_assets")))
+	})
+
+	for _, assetMap := range assets {
+		forEachMappingEntry(assetMap, func(_ *yaml.Node, asset *yaml.Node) {
+			handle(resolveAlias(asset))
+		})
+	}
+}
+
+func renameEnumValue(node *yaml.Node, key string, from string, to string) []string {
+	value := resolveAlias(mappingValue(node, key))
+	if value == nil || value.Kind != yaml.ScalarNode || value.Value != from {
+		return nil
+	}
+
+	value.Value = to
+	return []string{fmt.Sprintf("line %v: %v value %q renamed to %q", value.Line, key, from, to)}
+}
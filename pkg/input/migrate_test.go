@@ -0,0 +1,101 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateModelYaml(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		expected string
+		changes  int
+	}{
+		"individual risk categories": {
+			input: `threagile_version: 1.0.0
+title: Model
+individual_risk_categories:
+  # kept comment
+  Some Risk:
+    id: some-risk
+`,
+			expected: `threagile_version: 1.0.0
+schema_version: 2
+title: Model
+custom_risk_categories:
+  # kept comment
+  - title: Some Risk
+    id: some-risk
+`,
+			changes: 2,
+		},
+		"individual risk categories appended to custom risk categories": {
+			input: `custom_risk_categories:
+  - title: First
+    id: first
+individual_risk_categories:
+  Second:
+    id: second
+`,
+			expected: `schema_version: 2
+custom_risk_categories:
+  - title: First
+    id: first
+  - title: Second
+    id: second
+`,
+			changes: 2,
+		},
+		"end user enum values": {
+			input: `schema_version: 1
+technical_assets:
+  Web App:
+    encryption: data-with-enduser-individual-key
+    communication_links:
+      Database:
+        authorization: enduser-identity-propagation # propagated
+templates:
+  service:
+    technical_assets:
+      Service ${name}:
+        encryption: data-with-enduser-individual-key
+`,
+			expected: `schema_version: 2
+technical_assets:
+  Web App:
+    encryption: data-with-end-user-individual-key
+    communication_links:
+      Database:
+        authorization: end-user-identity-propagation # propagated
+templates:
+  service:
+    technical_assets:
+      Service ${name}:
+        encryption: data-with-end-user-individual-key
+`,
+			changes: 4,
+		},
+		"current version": {
+			input:    "schema_version: 2\ntitle:   Model\n",
+			expected: "schema_version: 2\ntitle:   Model\n",
+			changes:  0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			migrated, changes, err := MigrateModelYaml([]byte(test.input))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(migrated))
+			assert.Len(t, changes, test.changes)
+		})
+	}
+}
+
+func TestMigrateModelYamlOfNewerVersion(t *testing.T) {
+	_, _, err := MigrateModelYaml([]byte("schema_version: 3\n"))
+	assert.Error(t, err)
+}
@@ -6,23 +6,25 @@ package input
 
 import (
 	"fmt"
-	"log"
-	"os"
 	"path/filepath"
 	"slices"
 	"sort"
 	"strings"
 
 	"github.com/mpvl/unique"
-
-	"gopkg.in/yaml.v3"
 )
 
 // === Model Type Stuff ======================================
 
 type Model struct { // TODO: Eventually remove this and directly use ParsedModelRoot? But then the error messages for model errors are not quite as good anymore...
 	ThreagileVersion                              string                    `yaml:"threagile_version,omitempty" json:"threagile_version,omitempty"`
+	SchemaVersion                                 int                       `yaml:"schema_version,omitempty" json:"schema_version,omitempty"`
 	Includes                                      []string                  `yaml:"includes,omitempty" json:"includes,omitempty"`
+	Variables                                     map[string]string         `yaml:"variables,omitempty" json:"variables,omitempty"`
+	Templates                                     map[string]Template       `yaml:"templates,omitempty" json:"templates,omitempty"`
+	TemplateInstances                             []TemplateInstance        `yaml:"template_instances,omitempty" json:"template_instances,omitempty"`
+	Variants                                      map[string][]string       `yaml:"variants,omitempty" json:"variants,omitempty"`
+	Remove                                        *Removals                 `yaml:"remove,omitempty" json:"remove,omitempty"`
 	Title                                         string                    `yaml:"title,omitempty" json:"title,omitempty"`
 	Author                                        Author                    `yaml:"author,omitempty" json:"author,omitempty"`
 	Contributors                                  []Author                  `yaml:"contributors,omitempty" json:"contributors,omitempty"`
@@ -42,6 +44,7 @@ type Model struct { // TODO: Eventually remove this and directly use ParsedModel
 	SharedRuntimes                                map[string]SharedRuntime  `yaml:"shared_runtimes,omitempty" json:"shared_runtimes,omitempty"`
 	CustomRiskCategories                          RiskCategories            `yaml:"custom_risk_categories,omitempty" json:"custom_risk_categories,omitempty"`
 	RiskTracking                                  map[string]RiskTracking   `yaml:"risk_tracking,omitempty" json:"risk_tracking,omitempty"`
+	Attractiveness                                *Attractiveness           `yaml:"attractiveness,omitempty" json:"attractiveness,omitempty"`
 	DiagramTweakNodesep                           int                       `yaml:"diagram_tweak_nodesep,omitempty" json:"diagram_tweak_nodesep,omitempty"`
 	DiagramTweakRanksep                           int                       `yaml:"diagram_tweak_ranksep,omitempty" json:"diagram_tweak_ranksep,omitempty"`
 	DiagramTweakEdgeLayout                        string                    `yaml:"diagram_tweak_edge_layout,omitempty" json:"diagram_tweak_edge_layout,omitempty"`
@@ -49,6 +52,12 @@ type Model struct { // TODO: Eventually remove this and directly use ParsedModel
 	DiagramTweakLayoutLeftToRight                 bool                      `yaml:"diagram_tweak_layout_left_to_right,omitempty" json:"diagram_tweak_layout_left_to_right,omitempty"`
 	DiagramTweakInvisibleConnectionsBetweenAssets []string                  `yaml:"diagram_tweak_invisible_connections_between_assets,omitempty" json:"diagram_tweak_invisible_connections_between_assets,omitempty"`
 	DiagramTweakSameRankAssets                    []string                  `yaml:"diagram_tweak_same_rank_assets,omitempty" json:"diagram_tweak_same_rank_assets,omitempty"`
+
+	// Variant is the variant applied by ApplyVariant
+	Variant string `yaml:"-" json:"-"`
+
+	// variables and templates of the files loaded so far; variables, templates and template instances are expanded while loading and not kept in the model
+	expansion *expansionScope
 }
 
 func (model *Model) Defaults() *Model {
@@ -68,55 +77,116 @@ func (model *Model) Defaults() *Model {
 }
 
 func (model *Model) Load(inputFilename string) error {
-	modelYaml, readError := os.ReadFile(filepath.Clean(inputFilename))
+	model.expansion = newExpansionScope()
+	model.expansion.startFile(inputFilename, MergeStrict)
+	root, readError := model.expansion.readModelFile(inputFilename)
 	if readError != nil {
-		log.Fatal("Unable to read model file: ", readError)
+		return readError
+	}
+
+	if root == nil {
+		return nil
 	}
 
-	unmarshalError := yaml.Unmarshal(modelYaml, &model)
+	unmarshalError := root.Decode(model)
 	if unmarshalError != nil {
-		log.Fatal("Unable to parse model yaml: ", unmarshalError)
+		return fmt.Errorf("unable to parse model yaml of %q: %w", inputFilename, unmarshalError)
 	}
 
+	if model.Remove != nil {
+		return fmt.Errorf("unable to load model yaml of %q: remove is only supported by the overlays of variants", inputFilename)
+	}
+
+	model.setSourcePositions(inputFilename, root)
+
 	for _, includeFile := range model.Includes {
-		mergeError := model.Merge(filepath.Dir(inputFilename), includeFile)
+		mergeError := model.Merge(filepath.Dir(inputFilename), includeFile, MergeStrict)
+		if mergeError != nil {
+			return fmt.Errorf("unable to merge model include %q: %w", includeFile, mergeError)
+		}
+	}
+
+	return nil
+}
+
+// Merge merges the model files of an include relative to dir into the model;
+// the include is a file, a directory whose model files are merged recursively or a glob
+func (model *Model) Merge(dir string, includeFilename string, mode MergeMode) error {
+	if model.expansion == nil {
+		model.expansion = newExpansionScope()
+		defer func() { model.expansion = nil }()
+	}
+
+	includePaths, literal, resolveError := resolveInclude(dir, includeFilename)
+	if resolveError != nil {
+		return resolveError
+	}
+
+	for _, includePath := range includePaths {
+		mergeError := model.mergeFile(includePath, mode, literal)
 		if mergeError != nil {
-			log.Fatalf("Unable to merge model include %q: %v", includeFile, mergeError)
+			if literal {
+				return mergeError
+			}
+
+			return fmt.Errorf("failed to merge %q: %w", includePath, mergeError)
 		}
 	}
 
 	return nil
 }
 
-func (model *Model) Merge(dir string, includeFilename string) error {
-	modelYaml, readError := os.ReadFile(filepath.Clean(filepath.Join(dir, includeFilename)))
+func (model *Model) mergeFile(includePath string, mode MergeMode, literal bool) error {
+	if model.expansion.isIncluding(includePath) {
+		if !literal {
+			return nil
+		}
+
+		return fmt.Errorf("include cycle: %v", model.expansion.includeCycle(includePath))
+	}
+
+	if !model.expansion.startFile(includePath, mode) {
+		return nil
+	}
+	defer model.expansion.endFile()
+
+	root, readError := model.expansion.readModelFile(includePath)
 	if readError != nil {
-		return fmt.Errorf("unable to read model file: %w", readError)
+		return readError
 	}
 
-	var fileStructure map[string]any
-	unmarshalStructureError := yaml.Unmarshal(modelYaml, &fileStructure)
-	if unmarshalStructureError != nil {
-		return fmt.Errorf("unable to parse model structure: %w", unmarshalStructureError)
+	if root == nil {
+		return nil
 	}
 
 	var includedModel Model
-	unmarshalError := yaml.Unmarshal(modelYaml, &includedModel)
+	unmarshalError := root.Decode(&includedModel)
 	if unmarshalError != nil {
-		return fmt.Errorf("unable to parse model yaml: %w", unmarshalError)
+		return fmt.Errorf("unable to parse model yaml of %q: %w", includePath, unmarshalError)
+	}
+
+	includedModel.setSourcePositions(includePath, root)
+	if mode == MergeOverride {
+		return model.override(includePath, root, &includedModel)
 	}
 
 	var mergeError error
-	for item := range fileStructure {
-		switch strings.ToLower(item) {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch strings.ToLower(root.Content[i].Value) {
 		case strings.ToLower("includes"):
 			for _, includeFile := range includedModel.Includes {
-				mergeError = model.Merge(filepath.Join(dir, filepath.Dir(includeFilename)), includeFile)
+				mergeError = model.Merge(filepath.Dir(includePath), includeFile, MergeStrict)
 				if mergeError != nil {
 					return fmt.Errorf("failed to merge model include %q: %w", includeFile, mergeError)
 				}
 			}
 
+		case strings.ToLower("variants"):
+			return fmt.Errorf("variants can only be declared by the model file, not by include %q", includePath)
+
+		case strings.ToLower("remove"):
+			return fmt.Errorf("remove is only supported by the overlays of variants, not by include %q", includePath)
+
 		case strings.ToLower("threagile_version"):
 			model.ThreagileVersion, mergeError = new(Strings).MergeSingleton(model.ThreagileVersion, includedModel.ThreagileVersion)
 			if mergeError != nil {
@@ -231,6 +301,12 @@ func (model *Model) Merge(dir string, includeFilename string) error {
 				return fmt.Errorf("failed to merge risk tracking: %w", mergeError)
 			}
 
+		case strings.ToLower("attractiveness"):
+			model.Attractiveness, mergeError = model.Attractiveness.Merge(includedModel.Attractiveness)
+			if mergeError != nil {
+				return fmt.Errorf("failed to merge attractiveness: %w", mergeError)
+			}
+
 		case "diagram_tweak_nodesep":
 			model.DiagramTweakNodesep = includedModel.DiagramTweakNodesep
 
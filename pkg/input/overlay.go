@@ -0,0 +1,354 @@
+package input
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergeMode decides how Merge treats values which are defined by both the model and the merged file
+type MergeMode int
+
+const (
+	// MergeStrict fails on conflicting values, it is used for includes
+	MergeStrict MergeMode = iota
+
+	// MergeOverride lets the merged file change, add and remove elements, it is used for the overlays of variants
+	MergeOverride
+)
+
+// Removals are the elements an overlay removes from the model, referenced by their titles
+// (custom risk categories by their ids); communication links are listed per title of their technical asset
+type Removals struct {
+	DataAssets           []string            `yaml:"data_assets,omitempty" json:"data_assets,omitempty"`
+	TechnicalAssets      []string            `yaml:"technical_assets,omitempty" json:"technical_assets,omitempty"`
+	CommunicationLinks   map[string][]string `yaml:"communication_links,omitempty" json:"communication_links,omitempty"`
+	TrustBoundaries      []string            `yaml:"trust_boundaries,omitempty" json:"trust_boundaries,omitempty"`
+	SharedRuntimes       []string            `yaml:"shared_runtimes,omitempty" json:"shared_runtimes,omitempty"`
+	CustomRiskCategories []string            `yaml:"custom_risk_categories,omitempty" json:"custom_risk_categories,omitempty"`
+	RiskTracking         []string            `yaml:"risk_tracking,omitempty" json:"risk_tracking,omitempty"`
+}
+
+// ApplyVariant merges the overlay files of the variant into the model, dir is the directory of the model file
+func (model *Model) ApplyVariant(dir string, variant string) error {
+	if len(variant) == 0 {
+		return nil
+	}
+
+	overlays, ok := model.Variants[variant]
+	if !ok {
+		variants := make([]string, 0, len(model.Variants))
+		for name := range model.Variants {
+			variants = append(variants, name)
+		}
+		sort.Strings(variants)
+
+		return fmt.Errorf("unknown variant %q, the model defines %v", variant, variants)
+	}
+
+	for _, overlay := range overlays {
+		mergeError := model.Merge(dir, overlay, MergeOverride)
+		if mergeError != nil {
+			return fmt.Errorf("unable to apply overlay %q of variant %q: %w", overlay, variant, mergeError)
+		}
+	}
+
+	model.Variant = variant
+	return nil
+}
+
+// override patches the model with an overlay: fields set by the overlay replace the ones of the model,
+// elements are patched field by field (communication links one by one) or added if the model does not have them
+func (model *Model) override(overlayPath string, root *yaml.Node, overlay *Model) error {
+	if overlay.Remove != nil {
+		removeError := model.remove(overlay.Remove)
+		if removeError != nil {
+			return removeError
+		}
+	}
+
+	fields := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	var overrideError error
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch strings.ToLower(key.Value) {
+		case "includes", "remove", schemaVersionKey:
+
+		case "variants":
+			return fmt.Errorf("variants can only be declared by the model file")
+
+		case "data_assets":
+			model.DataAssets, overrideError = overrideMap(model.DataAssets, overlay.DataAssets, value)
+			if overrideError != nil {
+				return fmt.Errorf("failed to override data assets: %w", overrideError)
+			}
+
+		case "technical_assets":
+			overrideError = model.overrideTechnicalAssets(overlay.TechnicalAssets, value)
+			if overrideError != nil {
+				return fmt.Errorf("failed to override technical assets: %w", overrideError)
+			}
+
+		case "trust_boundaries":
+			model.TrustBoundaries, overrideError = overrideMap(model.TrustBoundaries, overlay.TrustBoundaries, value)
+			if overrideError != nil {
+				return fmt.Errorf("failed to override trust boundaries: %w", overrideError)
+			}
+
+		case "shared_runtimes":
+			model.SharedRuntimes, overrideError = overrideMap(model.SharedRuntimes, overlay.SharedRuntimes, value)
+			if overrideError != nil {
+				return fmt.Errorf("failed to override shared runtimes: %w", overrideError)
+			}
+
+		case "risk_tracking":
+			model.RiskTracking, overrideError = overrideMap(model.RiskTracking, overlay.RiskTracking, value)
+			if overrideError != nil {
+				return fmt.Errorf("failed to override risk tracking: %w", overrideError)
+			}
+
+		case "custom_risk_categories":
+			overrideError = model.overrideRiskCategories(overlay.CustomRiskCategories, value)
+			if overrideError != nil {
+				return fmt.Errorf("failed to override risk categories: %w", overrideError)
+			}
+
+		default:
+			fields.Content = append(fields.Content, key, value)
+		}
+	}
+
+	// decoding into the existing model only replaces what the overlay sets
+	decodeError := fields.Decode(model)
+	if decodeError != nil {
+		return fmt.Errorf("failed to override model: %w", decodeError)
+	}
+
+	for _, includeFile := range overlay.Includes {
+		mergeError := model.Merge(filepath.Dir(overlayPath), includeFile, MergeOverride)
+		if mergeError != nil {
+			return fmt.Errorf("failed to merge overlay include %q: %w", includeFile, mergeError)
+		}
+	}
+
+	return nil
+}
+
+// overrideMap patches the elements with the ones of the overlay node, overlayElements are those elements fully decoded
+func overrideMap[T any](elements map[string]T, overlayElements map[string]T, node *yaml.Node) (map[string]T, error) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return elements, nil
+	}
+
+	if elements == nil {
+		elements = make(map[string]T)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		title := node.Content[i].Value
+		item, ok := elements[title]
+		if !ok {
+			elements[title] = overlayElements[title]
+			continue
+		}
+
+		decodeError := node.Content[i+1].Decode(&item)
+		if decodeError != nil {
+			return elements, fmt.Errorf("failed to override %q: %w", title, decodeError)
+		}
+
+		elements[title] = item
+	}
+
+	return elements, nil
+}
+
+func (model *Model) overrideTechnicalAssets(overlayAssets map[string]TechnicalAsset, node *yaml.Node) error {
+	if model.TechnicalAssets == nil {
+		model.TechnicalAssets = make(map[string]TechnicalAsset)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		title, value := node.Content[i].Value, node.Content[i+1]
+		item, ok := model.TechnicalAssets[title]
+		if !ok {
+			model.TechnicalAssets[title] = overlayAssets[title]
+			continue
+		}
+
+		fields := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		var links *yaml.Node
+		forEachMappingEntry(value, func(key *yaml.Node, fieldValue *yaml.Node) {
+			if key.Value == "communication_links" {
+				links = fieldValue
+			} else {
+				fields.Content = append(fields.Content, key, fieldValue)
+			}
+		})
+
+		decodeError := fields.Decode(&item)
+		if decodeError != nil {
+			return fmt.Errorf("failed to override %q: %w", title, decodeError)
+		}
+
+		var linksError error
+		item.CommunicationLinks, linksError = overrideMap(item.CommunicationLinks, overlayAssets[title].CommunicationLinks, links)
+		if linksError != nil {
+			return fmt.Errorf("failed to override communication links of %q: %w", title, linksError)
+		}
+
+		model.TechnicalAssets[title] = item
+	}
+
+	return nil
+}
+
+func (model *Model) overrideRiskCategories(overlayCategories RiskCategories, node *yaml.Node) error {
+	if node.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	for i, categoryNode := range node.Content {
+		if i >= len(overlayCategories) || overlayCategories[i] == nil {
+			break
+		}
+
+		index := slices.IndexFunc(model.CustomRiskCategories, func(category *RiskCategory) bool {
+			return category != nil && category.ID == overlayCategories[i].ID
+		})
+		if index < 0 {
+			model.CustomRiskCategories = append(model.CustomRiskCategories, overlayCategories[i])
+			continue
+		}
+
+		category := *model.CustomRiskCategories[index]
+		decodeError := categoryNode.Decode(&category)
+		if decodeError != nil {
+			return fmt.Errorf("failed to override %q: %w", category.ID, decodeError)
+		}
+
+		model.CustomRiskCategories[index] = &category
+	}
+
+	return nil
+}
+
+// remove deletes the elements and the references of other elements to removed assets and trust boundaries
+func (model *Model) remove(removals *Removals) error {
+	removedIds := make([]string, 0)
+	for _, title := range removals.DataAssets {
+		item, ok := model.DataAssets[title]
+		if !ok {
+			return fmt.Errorf("unable to remove unknown data asset %q", title)
+		}
+
+		removedIds = append(removedIds, item.ID)
+		delete(model.DataAssets, title)
+	}
+
+	for _, title := range removals.TechnicalAssets {
+		item, ok := model.TechnicalAssets[title]
+		if !ok {
+			return fmt.Errorf("unable to remove unknown technical asset %q", title)
+		}
+
+		removedIds = append(removedIds, item.ID)
+		delete(model.TechnicalAssets, title)
+	}
+
+	for assetTitle, linkTitles := range removals.CommunicationLinks {
+		item, ok := model.TechnicalAssets[assetTitle]
+		if !ok {
+			return fmt.Errorf("unable to remove communication links of unknown technical asset %q", assetTitle)
+		}
+
+		for _, linkTitle := range linkTitles {
+			if _, linkOk := item.CommunicationLinks[linkTitle]; !linkOk {
+				return fmt.Errorf("unable to remove unknown communication link %q of technical asset %q", linkTitle, assetTitle)
+			}
+
+			delete(item.CommunicationLinks, linkTitle)
+		}
+	}
+
+	for _, title := range removals.TrustBoundaries {
+		item, ok := model.TrustBoundaries[title]
+		if !ok {
+			return fmt.Errorf("unable to remove unknown trust boundary %q", title)
+		}
+
+		removedIds = append(removedIds, item.ID)
+		delete(model.TrustBoundaries, title)
+	}
+
+	for _, title := range removals.SharedRuntimes {
+		if _, ok := model.SharedRuntimes[title]; !ok {
+			return fmt.Errorf("unable to remove unknown shared runtime %q", title)
+		}
+
+		delete(model.SharedRuntimes, title)
+	}
+
+	for _, id := range removals.CustomRiskCategories {
+		index := slices.IndexFunc(model.CustomRiskCategories, func(category *RiskCategory) bool {
+			return category != nil && category.ID == id
+		})
+		if index < 0 {
+			return fmt.Errorf("unable to remove unknown custom risk category %q", id)
+		}
+
+		model.CustomRiskCategories = slices.Delete(model.CustomRiskCategories, index, index+1)
+	}
+
+	for _, id := range removals.RiskTracking {
+		if _, ok := model.RiskTracking[id]; !ok {
+			return fmt.Errorf("unable to remove unknown risk tracking %q", id)
+		}
+
+		delete(model.RiskTracking, id)
+	}
+
+	model.removeReferences(removedIds)
+	return nil
+}
+
+func (model *Model) removeReferences(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	isRemoved := func(id string) bool {
+		return slices.Contains(ids, id)
+	}
+
+	for title, asset := range model.TechnicalAssets {
+		asset.DataAssetsProcessed = slices.DeleteFunc(asset.DataAssetsProcessed, isRemoved)
+		asset.DataAssetsStored = slices.DeleteFunc(asset.DataAssetsStored, isRemoved)
+		for linkTitle, link := range asset.CommunicationLinks {
+			if isRemoved(link.Target) {
+				delete(asset.CommunicationLinks, linkTitle)
+				continue
+			}
+
+			link.DataAssetsSent = slices.DeleteFunc(link.DataAssetsSent, isRemoved)
+			link.DataAssetsReceived = slices.DeleteFunc(link.DataAssetsReceived, isRemoved)
+			asset.CommunicationLinks[linkTitle] = link
+		}
+		model.TechnicalAssets[title] = asset
+	}
+
+	for title, boundary := range model.TrustBoundaries {
+		boundary.TechnicalAssetsInside = slices.DeleteFunc(boundary.TechnicalAssetsInside, isRemoved)
+		boundary.TrustBoundariesNested = slices.DeleteFunc(boundary.TrustBoundariesNested, isRemoved)
+		model.TrustBoundaries[title] = boundary
+	}
+
+	for title, runtime := range model.SharedRuntimes {
+		runtime.TechnicalAssetsRunning = slices.DeleteFunc(runtime.TechnicalAssetsRunning, isRemoved)
+		model.SharedRuntimes[title] = runtime
+	}
+}
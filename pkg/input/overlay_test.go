@@ -0,0 +1,174 @@
+package input
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testVariantModelYaml = `title: Shop
+data_assets:
+  Orders:
+    id: orders
+  Secrets:
+    id: secrets
+technical_assets:
+  Web:
+    id: web
+    internet: true
+    owner: Web Team
+    data_assets_processed: [orders, secrets]
+    communication_links:
+      Database Access:
+        target: db
+        protocol: jdbc
+        data_assets_sent: [orders]
+      Cache Access:
+        target: cache
+        protocol: http
+  Database:
+    id: db
+    data_assets_stored: [orders]
+  Cache:
+    id: cache
+    data_assets_stored: [secrets]
+trust_boundaries:
+  Network:
+    id: network
+    technical_assets_inside: [web, db, cache]
+    trust_boundaries_nested: [inner]
+  Inner:
+    id: inner
+shared_runtimes:
+  Cluster:
+    id: cluster
+    technical_assets_running: [db, cache]
+risk_tracking:
+  missing-vault:
+    status: accepted
+variants:
+  prod:
+    - overlays/prod.yaml
+  test:
+    - overlays/test.yaml
+`
+
+func TestApplyVariant(t *testing.T) {
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml": testVariantModelYaml,
+		"overlays/prod.yaml": `title: Shop (prod)
+technical_assets:
+  Web:
+    internet: false
+    communication_links:
+      Database Access:
+        protocol: jdbc-encrypted
+      Audit Log:
+        target: db
+        protocol: https
+  Audit:
+    id: audit
+remove:
+  technical_assets: [Cache]
+  data_assets: [Secrets]
+  trust_boundaries: [Inner]
+  risk_tracking: [missing-vault]
+`,
+	})
+
+	model := loadTestModel(t, dir)
+	require.NoError(t, model.ApplyVariant(dir, "prod"))
+	assert.Equal(t, "prod", model.Variant)
+	assert.Equal(t, "Shop (prod)", model.Title)
+
+	// the fields set by the overlay replace the ones of the model, even when set to false
+	web := model.TechnicalAssets["Web"]
+	assert.False(t, web.Internet)
+	assert.Equal(t, "web", web.ID)
+	assert.Equal(t, "Web Team", web.Owner)
+
+	// communication links are patched one by one
+	databaseAccess := web.CommunicationLinks["Database Access"]
+	assert.Equal(t, "jdbc-encrypted", databaseAccess.Protocol)
+	assert.Equal(t, "db", databaseAccess.Target)
+	assert.Equal(t, []string{"orders"}, databaseAccess.DataAssetsSent)
+	assert.Equal(t, "https", web.CommunicationLinks["Audit Log"].Protocol)
+	assert.Contains(t, model.TechnicalAssets, "Audit")
+
+	// removing an asset removes the references to it as well
+	assert.NotContains(t, model.TechnicalAssets, "Cache")
+	assert.NotContains(t, web.CommunicationLinks, "Cache Access")
+	assert.Equal(t, []string{"web", "db"}, model.TrustBoundaries["Network"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"db"}, model.SharedRuntimes["Cluster"].TechnicalAssetsRunning)
+	assert.NotContains(t, model.DataAssets, "Secrets")
+	assert.Equal(t, []string{"orders"}, web.DataAssetsProcessed)
+	assert.NotContains(t, model.TrustBoundaries, "Inner")
+	assert.Empty(t, model.TrustBoundaries["Network"].TrustBoundariesNested)
+	assert.Empty(t, model.RiskTracking)
+
+	// without a variant the model stays as it is
+	model = loadTestModel(t, dir)
+	require.NoError(t, model.ApplyVariant(dir, ""))
+	assert.Empty(t, model.Variant)
+	assert.True(t, model.TechnicalAssets["Web"].Internet)
+	assert.Contains(t, model.TechnicalAssets, "Cache")
+}
+
+func TestApplyVariantFailures(t *testing.T) {
+	tests := map[string]struct {
+		variant string
+		overlay string
+		error   string
+	}{
+		"unknown variant": {
+			variant: "staging",
+			error:   `unknown variant "staging", the model defines [prod test]`,
+		},
+		"unknown technical asset": {
+			variant: "test",
+			overlay: "remove:\n  technical_assets: [Queue]\n",
+			error:   `unable to remove unknown technical asset "Queue"`,
+		},
+		"unknown communication link": {
+			variant: "test",
+			overlay: "remove:\n  communication_links:\n    Web: [Queue Access]\n",
+			error:   `unable to remove unknown communication link "Queue Access" of technical asset "Web"`,
+		},
+		"communication link of unknown technical asset": {
+			variant: "test",
+			overlay: "remove:\n  communication_links:\n    Queue: [Queue Access]\n",
+			error:   `unable to remove communication links of unknown technical asset "Queue"`,
+		},
+		"unknown risk tracking": {
+			variant: "test",
+			overlay: "remove:\n  risk_tracking: [missing-waf]\n",
+			error:   `unable to remove unknown risk tracking "missing-waf"`,
+		},
+		"variants in overlay": {
+			variant: "test",
+			overlay: "variants:\n  other:\n    - other.yaml\n",
+			error:   "variants can only be declared by the model file",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeTestModelFiles(t, map[string]string{
+				"threagile.yaml":     testVariantModelYaml,
+				"overlays/test.yaml": test.overlay,
+			})
+			model := loadTestModel(t, dir)
+			err := model.ApplyVariant(dir, test.variant)
+			assert.ErrorContains(t, err, test.error)
+			assert.Empty(t, model.Variant)
+		})
+	}
+
+	// remove is only for overlays
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml": "title: Shop\nremove:\n  technical_assets: [Web]\n",
+	})
+	err := new(Model).Defaults().Load(filepath.Join(dir, "threagile.yaml"))
+	assert.ErrorContains(t, err, "remove is only supported by the overlays of variants")
+}
@@ -3,6 +3,8 @@ package input
 import (
 	"fmt"
 	"strings"
+
+	"github.com/threagile/threagile/pkg/types"
 )
 
 type RiskCategory struct {
@@ -23,6 +25,7 @@ type RiskCategory struct {
 	ModelFailurePossibleReason bool                      `yaml:"model_failure_possible_reason,omitempty" json:"model_failure_possible_reason,omitempty"`
 	CWE                        int                       `yaml:"cwe,omitempty" json:"cwe,omitempty"`
 	RisksIdentified            map[string]RiskIdentified `yaml:"risks_identified,omitempty" json:"risks_identified,omitempty"`
+	SourcePosition             *types.SourcePosition     `yaml:"-" json:"-"`
 }
 
 type RiskCategories []*RiskCategory
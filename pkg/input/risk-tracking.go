@@ -1,13 +1,18 @@
 package input
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/types"
+)
 
 type RiskTracking struct {
-	Status        string `yaml:"status,omitempty" json:"status,omitempty"`
-	Justification string `yaml:"justification,omitempty" json:"justification,omitempty"`
-	Ticket        string `yaml:"ticket,omitempty" json:"ticket,omitempty"`
-	Date          string `yaml:"date,omitempty" json:"date,omitempty"`
-	CheckedBy     string `yaml:"checked_by,omitempty" json:"checked_by,omitempty"`
+	Status         string                `yaml:"status,omitempty" json:"status,omitempty"`
+	Justification  string                `yaml:"justification,omitempty" json:"justification,omitempty"`
+	Ticket         string                `yaml:"ticket,omitempty" json:"ticket,omitempty"`
+	Date           string                `yaml:"date,omitempty" json:"date,omitempty"`
+	CheckedBy      string                `yaml:"checked_by,omitempty" json:"checked_by,omitempty"`
+	SourcePosition *types.SourcePosition `yaml:"-" json:"-"`
 }
 
 func (what *RiskTracking) Merge(other RiskTracking) error {
@@ -1,18 +1,23 @@
 package input
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/types"
+)
 
 type RiskIdentified struct {
-	Severity                      string   `yaml:"severity,omitempty" json:"severity,omitempty"`
-	ExploitationLikelihood        string   `yaml:"exploitation_likelihood,omitempty" json:"exploitation_likelihood,omitempty"`
-	ExploitationImpact            string   `yaml:"exploitation_impact,omitempty" json:"exploitation_impact,omitempty"`
-	DataBreachProbability         string   `yaml:"data_breach_probability,omitempty" json:"data_breach_probability,omitempty"`
-	DataBreachTechnicalAssets     []string `yaml:"data_breach_technical_assets,omitempty" json:"data_breach_technical_assets,omitempty"`
-	MostRelevantDataAsset         string   `yaml:"most_relevant_data_asset,omitempty" json:"most_relevant_data_asset,omitempty"`
-	MostRelevantTechnicalAsset    string   `yaml:"most_relevant_technical_asset,omitempty" json:"most_relevant_technical_asset,omitempty"`
-	MostRelevantCommunicationLink string   `yaml:"most_relevant_communication_link,omitempty" json:"most_relevant_communication_link,omitempty"`
-	MostRelevantTrustBoundary     string   `yaml:"most_relevant_trust_boundary,omitempty" json:"most_relevant_trust_boundary,omitempty"`
-	MostRelevantSharedRuntime     string   `yaml:"most_relevant_shared_runtime,omitempty" json:"most_relevant_shared_runtime,omitempty"`
+	Severity                      string                `yaml:"severity,omitempty" json:"severity,omitempty"`
+	ExploitationLikelihood        string                `yaml:"exploitation_likelihood,omitempty" json:"exploitation_likelihood,omitempty"`
+	ExploitationImpact            string                `yaml:"exploitation_impact,omitempty" json:"exploitation_impact,omitempty"`
+	DataBreachProbability         string                `yaml:"data_breach_probability,omitempty" json:"data_breach_probability,omitempty"`
+	DataBreachTechnicalAssets     []string              `yaml:"data_breach_technical_assets,omitempty" json:"data_breach_technical_assets,omitempty"`
+	MostRelevantDataAsset         string                `yaml:"most_relevant_data_asset,omitempty" json:"most_relevant_data_asset,omitempty"`
+	MostRelevantTechnicalAsset    string                `yaml:"most_relevant_technical_asset,omitempty" json:"most_relevant_technical_asset,omitempty"`
+	MostRelevantCommunicationLink string                `yaml:"most_relevant_communication_link,omitempty" json:"most_relevant_communication_link,omitempty"`
+	MostRelevantTrustBoundary     string                `yaml:"most_relevant_trust_boundary,omitempty" json:"most_relevant_trust_boundary,omitempty"`
+	MostRelevantSharedRuntime     string                `yaml:"most_relevant_shared_runtime,omitempty" json:"most_relevant_shared_runtime,omitempty"`
+	SourcePosition                *types.SourcePosition `yaml:"-" json:"-"`
 }
 
 func (what *RiskIdentified) Merge(other RiskIdentified) error {
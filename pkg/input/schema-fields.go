@@ -0,0 +1,284 @@
+package input
+
+import (
+	"sort"
+
+	"github.com/threagile/threagile/pkg/types"
+)
+
+// schemaField documents a field of the input structs in the JSON schema of model files
+type schemaField struct {
+	description string
+
+	// enum returns the values allowed for a string field or for the strings of a list
+	enum func() []string
+
+	format   string
+	required bool
+
+	// notNull rejects empty values, which otherwise are allowed as they are read as the zero value
+	notNull bool
+
+	// scalars allows numbers and booleans besides strings, for the values of variables and template parameters
+	scalars bool
+
+	// loose leaves the values of a map undescribed, for template assets whose fields may still be ${parameters}
+	loose bool
+}
+
+// schemaFields documents the fields of the input structs by struct name and yaml key, every field has to be documented
+var schemaFields = map[string]map[string]schemaField{
+	"Model": {
+		"includes":                           {description: "Include other yaml files into the model"},
+		"variables":                          {description: "Variables which can be used as ${name} in the strings of the model", scalars: true},
+		"templates":                          {description: "Templates of technical and data assets which are instantiated with parameters"},
+		"template_instances":                 {description: "Instances of templates"},
+		"variants":                           {description: "Variants of the model with the overlay files applied for them, selected by the variant flag"},
+		"remove":                             {description: "Elements an overlay of a variant removes from the model"},
+		"threagile_version":                  {description: "Version of the Threagile toolkit", required: true, notNull: true},
+		"schema_version":                     {description: "Version of the model file format, older model files are upgraded by the migrate command"},
+		"title":                              {description: "Title of the model", required: true, notNull: true},
+		"date":                               {description: "Date of the model", format: "date"},
+		"author":                             {description: "Author of the model", required: true, notNull: true},
+		"contributors":                       {description: "Contributors to the model"},
+		"management_summary_comment":         {description: "Individual management summary for the report"},
+		"business_criticality":               {description: "Business criticality of the target", enum: criticalityValues, required: true, notNull: true},
+		"application_description":            {description: "General description of the application, its purpose and functionality.", notNull: true},
+		"business_overview":                  {description: "Individual business overview for the report", notNull: true},
+		"technical_overview":                 {description: "Individual technical overview for the report", notNull: true},
+		"questions":                          {description: "Custom questions for the report"},
+		"abuse_cases":                        {description: "Custom abuse cases for the report"},
+		"security_requirements":              {description: "Custom security requirements for the report"},
+		"tags_available":                     {description: "Tags are used to add custom metadata to model elements, enabling filtering, classification, and the creation of tailored risk rules. They help provide context and drive more precise, organization-specific threat modeling.", required: true},
+		"data_assets":                        {description: "Data assets represent types of data processed, stored, or transmitted in the system, such as personal data, credentials, or logs—along with their sensitivity, confidentiality, and integrity requirements. They help assess the impact of risks based on the value of the data involved.", required: true, notNull: true},
+		"technical_assets":                   {description: "Any hardware, software, or system component that supports the processing, storage, or transmission of data, such as servers, applications, databases, or network devices.", required: true, notNull: true},
+		"trust_boundaries":                   {description: "Trust boundaries", notNull: true},
+		"shared_runtimes":                    {description: "Shared runtimes", required: true, notNull: true},
+		"custom_risk_categories":             {description: "Custom risk categories with the risks identified for them"},
+		"risk_tracking":                      {description: "Risk tracking"},
+		"attractiveness":                     {description: "Weighting profile of the RAA (relative attacker attractiveness) calculation, zero values keep the defaults"},
+		"diagram_tweak_suppress_edge_labels": {description: "Diagram tweak suppress edge labels"},
+		"diagram_tweak_layout_left_to_right": {description: "Diagram tweak layout left to right"},
+		"diagram_tweak_edge_layout":          {description: "Diagram tweak edge layout", enum: edgeLayoutValues},
+		"diagram_tweak_nodesep":              {description: "Diagram tweak nodesep"},
+		"diagram_tweak_ranksep":              {description: "Diagram tweak ranksep"},
+		"diagram_tweak_invisible_connections_between_assets": {description: "Diagram tweak invisible connections between assets"},
+		"diagram_tweak_same_rank_assets":                     {description: "Diagram tweak same rank assets"},
+	},
+	"Author": {
+		"name":     {description: "Name", required: true},
+		"contact":  {description: "Contact info"},
+		"homepage": {description: "Homepage"},
+	},
+	"Overview": {
+		"description": {description: "Description for the report"},
+		"images":      {description: "Images for the report, each mapping an image file to its caption"},
+	},
+	"DataAsset": {
+		"id":                       {description: "A unique identifier for the data asset.", required: true, notNull: true},
+		"description":              {description: "A description for the data asset.", required: true},
+		"usage":                    {description: "Describes how the data is handled — typically as business, or devops, to indicate its role in the system and influence certain risk evaluations, such as inappropriate access or exposure.", enum: usageValues, required: true, notNull: true},
+		"tags":                     {description: "Tags"},
+		"origin":                   {description: "Specifies where the data originally comes from — such as client, server, external, or another source — to help assess trust levels, data flow risks, and whether sensitive data enters from untrusted sources."},
+		"owner":                    {description: "The person or team responsible for the data asset's management, security, and compliance, ensuring accountability for protecting and maintaining the asset."},
+		"quantity":                 {description: "Describes the approximate amount of data for a data asset, helping to gauge the potential impact of data-related risks. The values like very-few, few, many, and very-many represent increasing scales of data volume, allowing the model to differentiate risk severity based on how much data could be affected.", enum: quantityValues, required: true, notNull: true},
+		"confidentiality":          {description: "Refers to the level of protection required to keep data secret and prevent unauthorized access. It is used to assess the potential impact if sensitive information is exposed.", enum: confidentialityValues, required: true, notNull: true},
+		"integrity":                {description: "Refers to the importance of keeping data accurate, complete, and unaltered by unauthorized parties, helping to evaluate the impact if data is tampered with or corrupted.", enum: criticalityValues, required: true, notNull: true},
+		"availability":             {description: "Measures how critical it is for data to be accessible and operational when needed, guiding the assessment of risks related to downtime or loss of service.", enum: criticalityValues, required: true, notNull: true},
+		"justification_cia_rating": {description: "Justification of the rating"},
+	},
+	"TechnicalAsset": {
+		"id":                         {description: "A unique identifier for the technical asset.", required: true, notNull: true},
+		"description":                {description: "A description for the technical asset.", required: true},
+		"type":                       {description: "Defines the role or function in the architecture, such as application, database, load-balancer, client-system, or external-entity, which helps determine how it interacts with other assets and what risks apply to it.", enum: technicalAssetTypeValues, required: true, notNull: true},
+		"usage":                      {description: "Indicates whether it primarily serves business functions or devops purposes, helping to assess risks based on its role and exposure in the system.", enum: usageValues, required: true, notNull: true},
+		"used_as_client_by_human":    {description: "Indicates whether a technical asset is directly used by a human, such as a web browser or mobile app. This affects risk evaluation related to user interaction, like spoofing or social engineering.", required: true, notNull: true},
+		"out_of_scope":               {description: "Marks a technical asset as outside the scope of the threat model. This means the asset is shown in diagrams but is not analyzed for risks, helping focus attention on relevant parts of the system.", required: true, notNull: true},
+		"justification_out_of_scope": {description: "Justification of out of scope"},
+		"size":                       {description: "Reflects its relative complexity or capacity, with values like component, system etc. This helps estimate the asset's importance and the potential impact if it is compromised.", enum: technicalAssetSizeValues, required: true, notNull: true},
+		"technology":                 {description: "Technology (deprecated, use 'technologies' instead)", enum: technologyValues, notNull: true},
+		"technologies":               {description: "List of technologies used for the asset", enum: technologyValues},
+		"tags":                       {description: "Custom labels used to categorize or describe assets, such as cloud, internal, public-facing, or third-party. They support filtering, documentation, and custom risk rules tailored to your environment."},
+		"internet":                   {description: "Set to true if a technical asset is accessible from the public internet. This increases its exposure and affects the severity and likelihood of certain risks, such as unauthorized access or denial of service.", required: true, notNull: true},
+		"machine":                    {description: "Describes the type of environment the technical asset runs on, such as virtual, container, physical, or serverless. This helps assess risks related to deployment, isolation, and infrastructure.", enum: technicalAssetMachineValues, required: true, notNull: true},
+		"encryption":                 {description: "Specifies whether and how data handled by a technical asset is protected using encryption. It helps evaluate the risk of data exposure by indicating if encryption is applied for data at rest, in transit, or both.", enum: encryptionStyleValues, required: true, notNull: true},
+		"owner":                      {description: "Refers to the person, team, or organizational unit responsible for managing and securing a technical asset, ensuring accountability for its protection and compliance.", required: true},
+		"confidentiality":            {description: "Defines how important it is to keep asset information secret and protected from unauthorized access, guiding risk assessments related to data leaks or exposure.", enum: confidentialityValues, required: true, notNull: true},
+		"integrity":                  {description: "Refers to the importance of maintaining the accuracy and trustworthiness of the system component by preventing unauthorized modification or corruption.", enum: criticalityValues, required: true, notNull: true},
+		"availability":               {description: "Indicates how critical it is for the technical asset to be accessible and operational when needed, helping assess risks related to downtime or service interruptions.", enum: criticalityValues, required: true, notNull: true},
+		"justification_cia_rating":   {description: "Justification of the rating"},
+		"multi_tenant":               {description: "Whether the technical asset supports multiple tenants or customers sharing the same instance, affecting risk related to data isolation and access control.", required: true, notNull: true},
+		"redundant":                  {description: "Specifies whether the technical asset has redundancy (e.g., backup systems or failover), which impacts availability and resilience risk assessments.", required: true, notNull: true},
+		"custom_developed_parts":     {description: "Marks if the asset contains custom-developed code or components, which may introduce unique security risks compared to off-the-shelf software.", required: true, notNull: true},
+		"data_assets_processed":      {description: "All data assets stored or sent or received via a communication link (be it as a source or a target) are implicitly also processed and do not need to be listed here.", required: true},
+		"data_assets_stored":         {description: "Lists data assets that the technical asset stores persistently, important for confidentiality, integrity, and availability risks.", required: true},
+		"data_formats_accepted":      {description: "Specifies the types or formats of data the asset can accept (e.g., JSON, XML), useful for input validation and injection risk analysis.", enum: dataFormatValues, required: true},
+		"diagram_tweak_order":        {description: "A numeric value used to control the layering or order of technical assets in generated diagrams, helping improve visual clarity (affects left to right positioning).", notNull: true},
+		"communication_links":        {description: "Defines connections between technical assets for data or control flow, essential for modeling trust boundaries and attack paths.", required: true},
+	},
+	"CommunicationLink": {
+		"target":                   {description: "Target", required: true, notNull: true},
+		"description":              {description: "Description", required: true},
+		"protocol":                 {description: "Protocol", enum: protocolValues, required: true, notNull: true},
+		"authentication":           {description: "Authentication", enum: authenticationValues, required: true, notNull: true},
+		"authorization":            {description: "Authorization", enum: authorizationValues, required: true, notNull: true},
+		"tags":                     {description: "Tags"},
+		"vpn":                      {description: "VPN", required: true, notNull: true},
+		"ip_filtered":              {description: "IP filtered", required: true, notNull: true},
+		"readonly":                 {description: "readonly", required: true, notNull: true},
+		"usage":                    {description: "Usage", enum: usageValues, required: true, notNull: true},
+		"data_assets_sent":         {description: "Data assets sent"},
+		"data_assets_received":     {description: "Data assets received"},
+		"diagram_tweak_weight":     {description: "diagram tweak weight", notNull: true},
+		"diagram_tweak_constraint": {description: "diagram tweak constraint", notNull: true},
+	},
+	"TrustBoundary": {
+		"id":                      {description: "ID", required: true, notNull: true},
+		"description":             {description: "Description", required: true},
+		"type":                    {description: "Type", enum: trustBoundaryTypeValues, required: true, notNull: true},
+		"tags":                    {description: "Tags"},
+		"technical_assets_inside": {description: "Technical assets inside", required: true},
+		"trust_boundaries_nested": {description: "Trust boundaries nested", required: true},
+	},
+	"SharedRuntime": {
+		"id":                       {description: "ID", required: true, notNull: true},
+		"description":              {description: "Description", required: true},
+		"tags":                     {description: "Tags"},
+		"technical_assets_running": {description: "Technical assets running", required: true},
+	},
+	"RiskCategory": {
+		"title":                         {description: "Title", required: true, notNull: true},
+		"id":                            {description: "ID", required: true, notNull: true},
+		"description":                   {description: "Description", required: true},
+		"impact":                        {description: "Impact", required: true, notNull: true},
+		"asvs":                          {description: "ASVS", required: true, notNull: true},
+		"cheat_sheet":                   {description: "Cheat sheet", required: true, notNull: true},
+		"action":                        {description: "Action", required: true, notNull: true},
+		"mitigation":                    {description: "Mitigation", required: true, notNull: true},
+		"check":                         {description: "Check", required: true, notNull: true},
+		"function":                      {description: "Function", enum: riskFunctionValues, required: true, notNull: true},
+		"stride":                        {description: "STRIDE", enum: strideValues, required: true, notNull: true},
+		"detection_logic":               {description: "Detection logic", required: true, notNull: true},
+		"risk_assessment":               {description: "Risk assessment", required: true, notNull: true},
+		"false_positives":               {description: "False positives", required: true, notNull: true},
+		"model_failure_possible_reason": {description: "Model failure possible reason", required: true, notNull: true},
+		"cwe":                           {description: "CWE", required: true, notNull: true},
+		"risks_identified":              {description: "Risks identified", required: true, notNull: true},
+	},
+	"RiskIdentified": {
+		"severity":                         {description: "Severity", enum: riskSeverityValues, notNull: true},
+		"exploitation_likelihood":          {description: "Exploitation likelihood", enum: riskExploitationLikelihoodValues, notNull: true},
+		"exploitation_impact":              {description: "Exploitation impact", enum: riskExploitationImpactValues, notNull: true},
+		"data_breach_probability":          {description: "Data breach probability", enum: dataBreachProbabilityValues, notNull: true},
+		"data_breach_technical_assets":     {description: "Data breach technical assets"},
+		"most_relevant_data_asset":         {description: "Most relevant data asset"},
+		"most_relevant_technical_asset":    {description: "Most relevant technical asset"},
+		"most_relevant_communication_link": {description: "Most relevant communication link"},
+		"most_relevant_trust_boundary":     {description: "Most relevant trust boundary"},
+		"most_relevant_shared_runtime":     {description: "Most relevant shared runtime"},
+	},
+	"RiskTracking": {
+		"status":        {description: "Status", enum: riskStatusValues, required: true, notNull: true},
+		"justification": {description: "Justification", required: true},
+		"ticket":        {description: "Ticket", required: true},
+		"date":          {description: "Date", format: "date", required: true},
+		"checked_by":    {description: "Checked by", required: true},
+	},
+	"Attractiveness": {
+		"quantity":                {description: "Fibonacci sequence base index for the quantity of data assets"},
+		"confidentiality":         {description: "Weights of the confidentiality rating"},
+		"integrity":               {description: "Weights of the integrity rating"},
+		"availability":            {description: "Weights of the availability rating"},
+		"technology_multipliers":  {description: "Multipliers for technical assets having any of the technology attributes (the first match wins, checked before the defaults)"},
+		"datastore_multiplier":    {description: "Multiplier for datastores not matching any technology multiplier"},
+		"multi_tenant_multiplier": {description: "Multiplier for multi-tenant technical assets"},
+	},
+	"AttackerFocus": {
+		"asset":                    {description: "Fibonacci sequence base index for the rating of the technical asset itself"},
+		"processed_or_stored_data": {description: "Fibonacci sequence base index for the rating of processed or stored data assets"},
+		"transferred_data":         {description: "Fibonacci sequence base index for the rating of transferred data assets"},
+	},
+	"TechnologyMultiplier": {
+		"attributes": {description: "Technology attributes", required: true, notNull: true},
+		"multiplier": {description: "Multiplier", required: true, notNull: true},
+	},
+	"Template": {
+		"parameters":       {description: "Parameters an instance has to set"},
+		"defaults":         {description: "Parameters an instance may set, with their default values", scalars: true},
+		"data_assets":      {description: "Data assets created by each instance", loose: true},
+		"technical_assets": {description: "Technical assets created by each instance", loose: true},
+	},
+	"TemplateInstance": {
+		"template":   {description: "Name of the template", required: true, notNull: true},
+		"parameters": {description: "Values of the template parameters", scalars: true},
+	},
+	"Removals": {
+		"data_assets":            {description: "Titles of the data assets to remove"},
+		"technical_assets":       {description: "Titles of the technical assets to remove"},
+		"communication_links":    {description: "Titles of the communication links to remove by title of their technical asset"},
+		"trust_boundaries":       {description: "Titles of the trust boundaries to remove"},
+		"shared_runtimes":        {description: "Titles of the shared runtimes to remove"},
+		"custom_risk_categories": {description: "Ids of the custom risk categories to remove"},
+		"risk_tracking":          {description: "Risk tracking entries to remove"},
+	},
+}
+
+// schemaAnyOfRequired lists the fields of which a struct needs at least one
+var schemaAnyOfRequired = map[string][]string{
+	"TechnicalAsset": {"technology", "technologies"},
+}
+
+var (
+	authenticationValues             = typeValues(types.AuthenticationTypeDescription[:])
+	authorizationValues              = typeValues(types.AuthorizationTypeDescription[:])
+	confidentialityValues            = typeValues(types.ConfidentialityTypeDescription[:])
+	criticalityValues                = typeValues(types.CriticalityTypeDescription[:])
+	dataBreachProbabilityValues      = typeValues(types.DataBreachProbabilityTypeDescription[:])
+	dataFormatValues                 = typeValues(types.DataFormatTypeDescription[:])
+	encryptionStyleValues            = typeValues(types.EncryptionStyleTypeDescription[:])
+	protocolValues                   = typeValues(types.ProtocolTypeDescription[:])
+	quantityValues                   = typeValues(types.QuantityTypeDescription[:])
+	riskExploitationImpactValues     = typeValues(types.RiskExploitationImpactTypeDescription[:])
+	riskExploitationLikelihoodValues = typeValues(types.RiskExploitationLikelihoodTypeDescription[:])
+	riskFunctionValues               = typeValues(types.RiskFunctionTypeDescription[:])
+	riskSeverityValues               = typeValues(types.RiskSeverityTypeDescription[:])
+	riskStatusValues                 = typeValues(types.RiskStatusTypeDescription[:])
+	strideValues                     = typeValues(types.StrideTypeDescription[:])
+	technicalAssetMachineValues      = typeValues(types.TechnicalAssetMachineTypeDescription[:])
+	technicalAssetSizeValues         = typeValues(types.TechnicalAssetSizeDescription[:])
+	technicalAssetTypeValues         = typeValues(types.TechnicalAssetTypeDescription[:])
+	trustBoundaryTypeValues          = typeValues(types.TrustBoundaryTypeDescription[:])
+	usageValues                      = typeValues(types.UsageTypeDescription[:])
+)
+
+func typeValues(descriptions []types.TypeDescription) func() []string {
+	return func() []string {
+		values := make([]string, 0, len(descriptions))
+		for _, description := range descriptions {
+			values = append(values, description.Name)
+		}
+
+		return values
+	}
+}
+
+// technologyValues are the names of the default technologies, additional technologies of the config are not known to the schema
+func technologyValues() []string {
+	technologies := make(types.TechnologyMap)
+	if technologies.LoadDefault() != nil {
+		return nil
+	}
+
+	values := make([]string, 0, len(technologies))
+	for name := range technologies {
+		values = append(values, name)
+	}
+
+	sort.Strings(values)
+	return values
+}
+
+// edgeLayoutValues are the graphviz splines supported by diagram_tweak_edge_layout
+func edgeLayoutValues() []string {
+	return []string{"", "ortho", "spline", "polyline", "false", "curved"}
+}
@@ -0,0 +1,252 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JSONSchema generates the JSON schema of model files (support/schema.json) from the input structs,
+// so editors validate and complete model files by the fields Threagile actually reads
+func JSONSchema() ([]byte, error) {
+	generator := &schemaGenerator{documented: make(map[string]bool)}
+
+	schema := new(jsonObject).
+		set("$schema", "https://json-schema.org/draft-07/schema#").
+		set("id", "https://threagile.io/schema.json").
+		set("title", "Threagile").
+		set("description", "Agile Threat Modeling").
+		set("type", "object")
+
+	structError := generator.structSchema(schema, reflect.TypeOf(Model{}))
+	if structError != nil {
+		return nil, structError
+	}
+
+	undocumented := make([]string, 0)
+	for structName, fields := range schemaFields {
+		for name := range fields {
+			if !generator.documented[structName+"."+name] {
+				undocumented = append(undocumented, structName+"."+name)
+			}
+		}
+	}
+
+	if len(undocumented) > 0 {
+		sort.Strings(undocumented)
+		return nil, fmt.Errorf("schema fields documented for unknown fields: %v", strings.Join(undocumented, ", "))
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	encodeError := encoder.Encode(schema)
+	if encodeError != nil {
+		return nil, fmt.Errorf("unable to write schema: %w", encodeError)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+type schemaGenerator struct {
+	// documented records the schema fields used, by struct name and yaml key
+	documented map[string]bool
+}
+
+func (what *schemaGenerator) structSchema(schema *jsonObject, structType reflect.Type) error {
+	fields, ok := schemaFields[structType.Name()]
+	if !ok {
+		return fmt.Errorf("no schema fields documented for %v", structType.Name())
+	}
+
+	properties := new(jsonObject)
+	required := make([]string, 0)
+	for i := 0; i < structType.NumField(); i++ {
+		name := yamlFieldName(structType.Field(i))
+		if len(name) == 0 {
+			continue
+		}
+
+		field, fieldOk := fields[name]
+		if !fieldOk {
+			return fmt.Errorf("no schema field documented for %v.%v", structType.Name(), name)
+		}
+
+		what.documented[structType.Name()+"."+name] = true
+
+		property := new(jsonObject)
+		if len(field.description) > 0 {
+			property.set("description", field.description)
+		}
+
+		typeError := what.typeSchema(property, structType.Field(i).Type, field, !field.notNull)
+		if typeError != nil {
+			return fmt.Errorf("%v.%v: %w", structType.Name(), name, typeError)
+		}
+
+		properties.set(name, property)
+		if field.required {
+			required = append(required, name)
+		}
+	}
+
+	schema.set("properties", properties)
+	if len(required) > 0 {
+		schema.set("required", required)
+	}
+
+	if anyOf, anyOfOk := schemaAnyOfRequired[structType.Name()]; anyOfOk {
+		alternatives := make([]*jsonObject, 0, len(anyOf))
+		for _, name := range anyOf {
+			alternatives = append(alternatives, new(jsonObject).set("required", []string{name}))
+		}
+
+		schema.set("anyOf", alternatives)
+	}
+
+	return nil
+}
+
+func (what *schemaGenerator) typeSchema(schema *jsonObject, valueType reflect.Type, field schemaField, nullable bool) error {
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+
+	// the values of lists and maps inherit what describes the strings in them
+	itemField := schemaField{enum: field.enum, format: field.format, scalars: field.scalars}
+
+	switch valueType.Kind() {
+	case reflect.String:
+		if field.scalars {
+			schema.set("type", []string{"string", "number", "boolean"})
+		} else {
+			schema.set("type", jsonType("string", nullable))
+		}
+
+		if len(field.format) > 0 {
+			schema.set("format", field.format)
+		}
+
+		if field.enum != nil {
+			schema.set("enum", field.enum())
+		}
+
+	case reflect.Bool:
+		schema.set("type", jsonType("boolean", nullable))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		schema.set("type", jsonType("integer", nullable))
+
+	case reflect.Float32, reflect.Float64:
+		schema.set("type", jsonType("number", nullable))
+
+	case reflect.Slice:
+		schema.set("type", jsonType("array", nullable))
+		if valueType.Elem().Kind() == reflect.String {
+			schema.set("uniqueItems", true)
+		}
+
+		items := new(jsonObject)
+		itemsError := what.typeSchema(items, valueType.Elem(), itemField, false)
+		if itemsError != nil {
+			return itemsError
+		}
+
+		schema.set("items", items)
+
+	case reflect.Map:
+		schema.set("type", jsonType("object", nullable))
+		if field.loose {
+			return nil
+		}
+
+		values := new(jsonObject)
+		valuesError := what.typeSchema(values, valueType.Elem(), itemField, false)
+		if valuesError != nil {
+			return valuesError
+		}
+
+		schema.set("additionalProperties", values)
+
+	case reflect.Struct:
+		schema.set("type", jsonType("object", nullable))
+		return what.structSchema(schema, valueType)
+
+	default:
+		return fmt.Errorf("unsupported type %v", valueType)
+	}
+
+	return nil
+}
+
+// yamlFieldName is the key of a struct field in model files, empty for fields not read from model files
+func yamlFieldName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+func jsonType(name string, nullable bool) any {
+	if nullable {
+		return []string{name, "null"}
+	}
+
+	return name
+}
+
+// jsonObject is a JSON object keeping its keys in the order they are set
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (what *jsonObject) set(key string, value any) *jsonObject {
+	if what.values == nil {
+		what.values = make(map[string]any)
+	}
+
+	if _, ok := what.values[key]; !ok {
+		what.keys = append(what.keys, key)
+	}
+
+	what.values[key] = value
+	return what
+}
+
+func (what *jsonObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	buffer.WriteByte('{')
+	for i, key := range what.keys {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		keyError := encoder.Encode(key)
+		if keyError != nil {
+			return nil, keyError
+		}
+
+		buffer.WriteByte(':')
+		valueError := encoder.Encode(what.values[key])
+		if valueError != nil {
+			return nil, valueError
+		}
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
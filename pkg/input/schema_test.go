@@ -0,0 +1,28 @@
+package input
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateSchema = flag.Bool("update-schema", false, "write the generated JSON schema to support/schema.json")
+
+const schemaFilename = "../../support/schema.json"
+
+func TestSchemaIsUpToDate(t *testing.T) {
+	schema, err := JSONSchema()
+	require.NoError(t, err)
+
+	if *updateSchema {
+		require.NoError(t, os.WriteFile(schemaFilename, schema, 0600))
+		return
+	}
+
+	current, err := os.ReadFile(schemaFilename)
+	require.NoError(t, err)
+	assert.Equal(t, string(schema), string(current), "support/schema.json is outdated, regenerate it with: make schema")
+}
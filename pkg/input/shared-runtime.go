@@ -1,12 +1,17 @@
 package input
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/types"
+)
 
 type SharedRuntime struct {
-	ID                     string   `yaml:"id,omitempty" json:"id,omitempty"`
-	Description            string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Tags                   []string `yaml:"tags,omitempty" json:"tag,omitempty"`
-	TechnicalAssetsRunning []string `yaml:"technical_assets_running,omitempty" json:"technical_assets_running,omitempty"`
+	ID                     string                `yaml:"id,omitempty" json:"id,omitempty"`
+	Description            string                `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags                   []string              `yaml:"tags,omitempty" json:"tag,omitempty"`
+	TechnicalAssetsRunning []string              `yaml:"technical_assets_running,omitempty" json:"technical_assets_running,omitempty"`
+	SourcePosition         *types.SourcePosition `yaml:"-" json:"-"`
 }
 
 func (what *SharedRuntime) Merge(other SharedRuntime) error {
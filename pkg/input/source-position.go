@@ -0,0 +1,104 @@
+package input
+
+import (
+	"github.com/threagile/threagile/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// setSourcePositions records where the elements of a model file freshly decoded from root are defined
+func (model *Model) setSourcePositions(filename string, root *yaml.Node) {
+	forEachMappingEntry(mappingValue(root, "data_assets"), func(key *yaml.Node, _ *yaml.Node) {
+		if item, ok := model.DataAssets[key.Value]; ok {
+			item.SourcePosition = newSourcePosition(filename, key)
+			model.DataAssets[key.Value] = item
+		}
+	})
+
+	forEachMappingEntry(mappingValue(root, "technical_assets"), func(key *yaml.Node, value *yaml.Node) {
+		item, ok := model.TechnicalAssets[key.Value]
+		if !ok {
+			return
+		}
+
+		item.SourcePosition = newSourcePosition(filename, key)
+		forEachMappingEntry(mappingValue(value, "communication_links"), func(linkKey *yaml.Node, _ *yaml.Node) {
+			if link, linkOk := item.CommunicationLinks[linkKey.Value]; linkOk {
+				link.SourcePosition = newSourcePosition(filename, linkKey)
+				item.CommunicationLinks[linkKey.Value] = link
+			}
+		})
+		model.TechnicalAssets[key.Value] = item
+	})
+
+	forEachMappingEntry(mappingValue(root, "trust_boundaries"), func(key *yaml.Node, _ *yaml.Node) {
+		if item, ok := model.TrustBoundaries[key.Value]; ok {
+			item.SourcePosition = newSourcePosition(filename, key)
+			model.TrustBoundaries[key.Value] = item
+		}
+	})
+
+	forEachMappingEntry(mappingValue(root, "shared_runtimes"), func(key *yaml.Node, _ *yaml.Node) {
+		if item, ok := model.SharedRuntimes[key.Value]; ok {
+			item.SourcePosition = newSourcePosition(filename, key)
+			model.SharedRuntimes[key.Value] = item
+		}
+	})
+
+	forEachMappingEntry(mappingValue(root, "risk_tracking"), func(key *yaml.Node, _ *yaml.Node) {
+		if item, ok := model.RiskTracking[key.Value]; ok {
+			item.SourcePosition = newSourcePosition(filename, key)
+			model.RiskTracking[key.Value] = item
+		}
+	})
+
+	categories := mappingValue(root, "custom_risk_categories")
+	if categories != nil && categories.Kind == yaml.SequenceNode {
+		for i, categoryNode := range categories.Content {
+			if i >= len(model.CustomRiskCategories) || model.CustomRiskCategories[i] == nil {
+				break
+			}
+
+			category := model.CustomRiskCategories[i]
+			category.SourcePosition = newSourcePosition(filename, categoryNode)
+			forEachMappingEntry(mappingValue(categoryNode, "risks_identified"), func(key *yaml.Node, _ *yaml.Node) {
+				if risk, ok := category.RisksIdentified[key.Value]; ok {
+					risk.SourcePosition = newSourcePosition(filename, key)
+					category.RisksIdentified[key.Value] = risk
+				}
+			})
+		}
+	}
+
+}
+
+func newSourcePosition(filename string, node *yaml.Node) *types.SourcePosition {
+	return &types.SourcePosition{
+		File:   filename,
+		Line:   node.Line,
+		Column: node.Column,
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func forEachMappingEntry(node *yaml.Node, handle func(key *yaml.Node, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		handle(node.Content[i], node.Content[i+1])
+	}
+}
@@ -1,6 +1,10 @@
 package input
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/types"
+)
 
 type TechnicalAsset struct {
 	ID                      string                       `yaml:"id,omitempty" json:"id,omitempty"`
@@ -30,6 +34,7 @@ type TechnicalAsset struct {
 	DataFormatsAccepted     []string                     `yaml:"data_formats_accepted,omitempty" json:"data_formats_accepted,omitempty"`
 	DiagramTweakOrder       int                          `yaml:"diagram_tweak_order,omitempty" json:"diagram_tweak_order,omitempty"`
 	CommunicationLinks      map[string]CommunicationLink `yaml:"communication_links,omitempty" json:"communication_links,omitempty"`
+	SourcePosition          *types.SourcePosition        `yaml:"-" json:"-"`
 }
 
 func (what *TechnicalAsset) Merge(other TechnicalAsset) error {
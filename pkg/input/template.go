@@ -0,0 +1,433 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a reusable shape of technical assets (with their communication links) and data assets,
+// instantiated by TemplateInstance with the template parameters substituted as ${name}
+type Template struct {
+	Parameters      []string                  `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+	Defaults        map[string]string         `yaml:"defaults,omitempty" json:"defaults,omitempty"`
+	DataAssets      map[string]DataAsset      `yaml:"data_assets,omitempty" json:"data_assets,omitempty"`
+	TechnicalAssets map[string]TechnicalAsset `yaml:"technical_assets,omitempty" json:"technical_assets,omitempty"`
+}
+
+type TemplateInstance struct {
+	Template   string            `yaml:"template,omitempty" json:"template,omitempty"`
+	Parameters map[string]string `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+}
+
+const (
+	variablesKey         = "variables"
+	templatesKey         = "templates"
+	templateInstancesKey = "template_instances"
+)
+
+// variableReference matches ${name} and the escaped form $${ which stands for a literal ${
+var variableReference = regexp.MustCompile(`\$\$\{|\$\{([^}]*)}`)
+
+type scopedVariable struct {
+	value    string
+	position *types.SourcePosition
+}
+
+type modelTemplate struct {
+	name     string
+	position *types.SourcePosition
+	template Template
+	node     *yaml.Node
+}
+
+// expansionScope holds the variables and templates of all model files read so far,
+// so the includes of a model can use what the files including them define,
+// and the files read so far per merge mode as well as the chain of files currently being included
+type expansionScope struct {
+	variables    map[string]scopedVariable
+	templates    map[string]*modelTemplate
+	loadedFiles  map[MergeMode]map[string]bool
+	includeStack []string
+}
+
+func newExpansionScope() *expansionScope {
+	return &expansionScope{
+		variables:   make(map[string]scopedVariable),
+		templates:   make(map[string]*modelTemplate),
+		loadedFiles: make(map[MergeMode]map[string]bool),
+	}
+}
+
+// readModelFile reads a model file into its root node migrated to the current schema version,
+// with variables substituted and templates instantiated
+func (what *expansionScope) readModelFile(filename string) (*yaml.Node, error) {
+	modelYaml, readError := os.ReadFile(filepath.Clean(filename))
+	if readError != nil {
+		return nil, fmt.Errorf("unable to read model file: %w", readError)
+	}
+
+	var document yaml.Node
+	unmarshalError := yaml.Unmarshal(modelYaml, &document)
+	if unmarshalError != nil {
+		return nil, fmt.Errorf("unable to parse model yaml of %q: %w", filename, unmarshalError)
+	}
+
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	root := document.Content[0]
+	_, migrateError := migrate(root)
+	if migrateError != nil {
+		return nil, fmt.Errorf("unable to migrate model yaml of %q: %w", filename, migrateError)
+	}
+
+	expandError := what.expand(filename, root)
+	if expandError != nil {
+		return nil, fmt.Errorf("unable to expand model yaml of %q: %w", filename, expandError)
+	}
+
+	return root, nil
+}
+
+func (what *expansionScope) expand(filename string, root *yaml.Node) error {
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	variablesError := what.addVariables(filename, mappingValue(root, variablesKey))
+	if variablesError != nil {
+		return variablesError
+	}
+
+	templatesError := what.addTemplates(filename, mappingValue(root, templatesKey))
+	if templatesError != nil {
+		return templatesError
+	}
+
+	// models without variables and templates are taken literally, so a ${ in a model written before variables existed stays as is
+	for i := 0; i+1 < len(root.Content) && (len(what.variables) > 0 || len(what.templates) > 0); i += 2 {
+		switch root.Content[i].Value {
+		case variablesKey, templatesKey:
+			continue
+		}
+
+		substituteError := substituteVariables(root.Content[i+1], func(name string, node *yaml.Node) (string, error) {
+			variable, ok := what.variables[name]
+			if !ok {
+				return "", newSourcePosition(filename, node).Errorf("unknown variable %q", name)
+			}
+
+			return variable.value, nil
+		})
+		if substituteError != nil {
+			return substituteError
+		}
+	}
+
+	instancesError := what.instantiateTemplates(filename, root)
+	if instancesError != nil {
+		return instancesError
+	}
+
+	removeMappingEntries(root, variablesKey, templatesKey, templateInstancesKey)
+	return nil
+}
+
+func (what *expansionScope) addVariables(filename string, variables *yaml.Node) error {
+	if variables == nil {
+		return nil
+	}
+
+	if variables.Kind != yaml.MappingNode {
+		return newSourcePosition(filename, variables).Errorf("variables must be a mapping of names to values")
+	}
+
+	for i := 0; i+1 < len(variables.Content); i += 2 {
+		key, value := variables.Content[i], resolveAlias(variables.Content[i+1])
+		if value.Kind != yaml.ScalarNode {
+			return newSourcePosition(filename, value).Errorf("value of variable %q must be a scalar", key.Value)
+		}
+
+		position := newSourcePosition(filename, key)
+		if existing, ok := what.variables[key.Value]; ok && existing.value != value.Value {
+			return position.Errorf("variable %q is already defined at %v with a different value", key.Value, existing.position)
+		}
+
+		what.variables[key.Value] = scopedVariable{value: value.Value, position: position}
+	}
+
+	return nil
+}
+
+func (what *expansionScope) addTemplates(filename string, templates *yaml.Node) error {
+	if templates == nil {
+		return nil
+	}
+
+	if templates.Kind != yaml.MappingNode {
+		return newSourcePosition(filename, templates).Errorf("templates must be a mapping of names to templates")
+	}
+
+	for i := 0; i+1 < len(templates.Content); i += 2 {
+		key, value := templates.Content[i], resolveAlias(templates.Content[i+1])
+		position := newSourcePosition(filename, key)
+		if existing, ok := what.templates[key.Value]; ok {
+			return position.Errorf("template %q is already defined at %v", key.Value, existing.position)
+		}
+
+		if value.Kind != yaml.MappingNode {
+			return position.Errorf("template %q must be a mapping", key.Value)
+		}
+
+		newTemplate := &modelTemplate{name: key.Value, position: position, node: value}
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			field, fieldValue := value.Content[j], value.Content[j+1]
+			var decodeError error
+			switch field.Value {
+			case "parameters":
+				decodeError = fieldValue.Decode(&newTemplate.template.Parameters)
+
+			case "defaults":
+				decodeError = fieldValue.Decode(&newTemplate.template.Defaults)
+
+			case "data_assets", "technical_assets":
+				if resolveAlias(fieldValue).Kind != yaml.MappingNode {
+					decodeError = fmt.Errorf("%v must be a mapping", field.Value)
+				}
+
+			default:
+				decodeError = fmt.Errorf("unknown field %q, expected one of parameters, defaults, data_assets or technical_assets", field.Value)
+			}
+
+			if decodeError != nil {
+				return newSourcePosition(filename, field).Errorf("invalid template %q: %w", key.Value, decodeError)
+			}
+		}
+
+		what.templates[key.Value] = newTemplate
+	}
+
+	return nil
+}
+
+func (what *expansionScope) instantiateTemplates(filename string, root *yaml.Node) error {
+	instances := mappingValue(root, templateInstancesKey)
+	if instances == nil {
+		return nil
+	}
+
+	if instances.Kind != yaml.SequenceNode {
+		return newSourcePosition(filename, instances).Errorf("template instances must be a list")
+	}
+
+	for _, instanceNode := range instances.Content {
+		instancePosition := newSourcePosition(filename, instanceNode)
+		var instance TemplateInstance
+		decodeError := instanceNode.Decode(&instance)
+		if decodeError != nil {
+			return instancePosition.Errorf("invalid template instance: %w", decodeError)
+		}
+
+		instantiateError := what.instantiateTemplate(root, instance, instanceNode, instancePosition)
+		if instantiateError != nil {
+			return instantiateError
+		}
+	}
+
+	return nil
+}
+
+func (what *expansionScope) instantiateTemplate(root *yaml.Node, instance TemplateInstance, instanceNode *yaml.Node, instancePosition *types.SourcePosition) error {
+	template, ok := what.templates[instance.Template]
+	if !ok {
+		return instancePosition.Errorf("unknown template %q", instance.Template)
+	}
+
+	parameters, parametersError := template.parameters(instance)
+	if parametersError != nil {
+		return instancePosition.Errorf("instance of template %q defined at %v: %w", template.name, template.position, parametersError)
+	}
+
+	lookup := func(name string, node *yaml.Node) (string, error) {
+		if value, isParameter := parameters[name]; isParameter {
+			return value, nil
+		}
+
+		if variable, isVariable := what.variables[name]; isVariable {
+			return variable.value, nil
+		}
+
+		return "", newSourcePosition(template.position.File, node).Errorf("unknown parameter or variable %q in template %q instantiated at %v", name, template.name, instancePosition)
+	}
+
+	for _, section := range []string{"data_assets", "technical_assets"} {
+		entries := resolveAlias(mappingValue(template.node, section))
+		if entries == nil {
+			continue
+		}
+
+		target := mappingValue(root, section)
+		if target == nil {
+			target = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: section}, target)
+		}
+
+		for i := 0; i+1 < len(entries.Content); i += 2 {
+			key, value := copyNode(entries.Content[i]), copyNode(entries.Content[i+1])
+			for _, node := range []*yaml.Node{key, value} {
+				substituteError := substituteVariables(node, lookup)
+				if substituteError != nil {
+					return substituteError
+				}
+			}
+
+			if mappingValue(target, key.Value) != nil {
+				return instancePosition.Errorf("instance of template %q defines %v %q (at %v) which is already defined", template.name, strings.TrimSuffix(strings.ReplaceAll(section, "_", " "), "s"), key.Value, newSourcePosition(template.position.File, entries.Content[i]))
+			}
+
+			// generated elements are reported at the instance creating them
+			moveNode(key, instanceNode)
+			moveNode(value, instanceNode)
+			target.Content = append(target.Content, key, value)
+		}
+	}
+
+	return nil
+}
+
+// parameters are the defaults of the template overridden by the parameters of the instance
+func (what *modelTemplate) parameters(instance TemplateInstance) (map[string]string, error) {
+	parameters := make(map[string]string)
+	for name, value := range what.template.Defaults {
+		parameters[name] = value
+	}
+
+	unknown := make([]string, 0)
+	for name, value := range instance.Parameters {
+		_, hasDefault := what.template.Defaults[name]
+		if !hasDefault && !slices.Contains(what.template.Parameters, name) {
+			unknown = append(unknown, name)
+		}
+
+		parameters[name] = value
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown parameters %v", unknown)
+	}
+
+	missing := make([]string, 0)
+	for _, name := range what.template.Parameters {
+		if _, ok := parameters[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing parameters %v", missing)
+	}
+
+	return parameters, nil
+}
+
+// substituteVariables replaces all ${name} references in the scalars below node
+func substituteVariables(node *yaml.Node, lookup func(name string, node *yaml.Node) (string, error)) error {
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "${") {
+			return nil
+		}
+
+		var lookupError error
+		node.Value = variableReference.ReplaceAllStringFunc(node.Value, func(reference string) string {
+			if reference == "$${" {
+				return "${"
+			}
+
+			name := strings.TrimSpace(reference[2 : len(reference)-1])
+			value, err := lookup(name, node)
+			if err != nil && lookupError == nil {
+				lookupError = err
+			}
+
+			return value
+		})
+
+		if lookupError != nil {
+			return lookupError
+		}
+
+		// let plain scalars resolve their type again, so ${port} may become a number
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+
+	case yaml.MappingNode, yaml.SequenceNode, yaml.DocumentNode:
+		for _, child := range node.Content {
+			err := substituteVariables(child, lookup)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// copyNode deep copies node, replacing aliases by copies of their anchors
+func copyNode(node *yaml.Node) *yaml.Node {
+	node = resolveAlias(node)
+	if node == nil {
+		return nil
+	}
+
+	nodeCopy := *node
+	nodeCopy.Anchor = ""
+	nodeCopy.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		nodeCopy.Content[i] = copyNode(child)
+	}
+
+	return &nodeCopy
+}
+
+func moveNode(node *yaml.Node, position *yaml.Node) {
+	node.Line = position.Line
+	node.Column = position.Column
+	for _, child := range node.Content {
+		moveNode(child, position)
+	}
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	return node
+}
+
+func removeMappingEntries(node *yaml.Node, keys ...string) {
+	content := make([]*yaml.Node, 0, len(node.Content))
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if !slices.Contains(keys, node.Content[i].Value) {
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+	}
+
+	node.Content = content
+}
@@ -0,0 +1,257 @@
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariables(t *testing.T) {
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml": `variables:
+  owner: Company ABC
+  env: prod
+title: Shop in ${ env }
+technical_assets:
+  Backend:
+    id: backend
+    owner: ${owner}
+    description: Backend of ${owner} in ${env}, configured by $${HOME} and $${env}
+    tags:
+      - ${env}
+includes:
+  - included.yaml
+`,
+		"included.yaml": `variables:
+  env: prod
+  region: eu
+data_assets:
+  Orders:
+    id: orders
+    description: Orders of ${owner} in ${region}
+`,
+	})
+
+	model := loadTestModel(t, dir)
+	assert.Equal(t, "Shop in prod", model.Title)
+	backend := model.TechnicalAssets["Backend"]
+	assert.Equal(t, "Company ABC", backend.Owner)
+	assert.Equal(t, "Backend of Company ABC in prod, configured by ${HOME} and ${env}", backend.Description)
+	assert.Equal(t, []string{"prod"}, backend.Tags)
+	// the variables of the including file are known to the included one
+	assert.Equal(t, "Orders of Company ABC in eu", model.DataAssets["Orders"].Description)
+	// and they are not part of the model
+	assert.Empty(t, model.Variables)
+}
+
+func TestVariablesFailures(t *testing.T) {
+	tests := map[string]struct {
+		files map[string]string
+		error string
+	}{
+		"unknown variable": {
+			files: map[string]string{"threagile.yaml": "variables:\n  env: prod\ntitle: Shop in ${stage}\n"},
+			error: `threagile.yaml:3:8: unknown variable "stage"`,
+		},
+		"non-scalar value": {
+			files: map[string]string{"threagile.yaml": "variables:\n  env: [prod]\n"},
+			error: `value of variable "env" must be a scalar`,
+		},
+		"redefined with a different value": {
+			files: map[string]string{
+				"threagile.yaml": "variables:\n  env: prod\nincludes:\n  - included.yaml\n",
+				"included.yaml":  "variables:\n  env: test\n",
+			},
+			error: `variable "env" is already defined at`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeTestModelFiles(t, test.files)
+			err := new(Model).Defaults().Load(filepath.Join(dir, "threagile.yaml"))
+			assert.ErrorContains(t, err, test.error)
+		})
+	}
+}
+
+func TestModelWithoutVariablesIsLiteral(t *testing.T) {
+	// models written before variables existed may contain ${ anywhere
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml": `title: Shop
+technical_assets:
+  Backend:
+    id: backend
+    description: Reads ${HOME} and $${PATH}
+includes:
+  - included.yaml
+`,
+		"included.yaml": `data_assets:
+  Orders:
+    id: orders
+    description: Orders of ${owner}
+`,
+	})
+
+	model := loadTestModel(t, dir)
+	assert.Equal(t, "Reads ${HOME} and $${PATH}", model.TechnicalAssets["Backend"].Description)
+	assert.Equal(t, "Orders of ${owner}", model.DataAssets["Orders"].Description)
+}
+
+const testTemplatesYaml = `variables:
+  owner: Company ABC
+  order: "9"
+templates:
+  rest-service:
+    parameters: [name, id]
+    defaults:
+      redundant: "false"
+      order: "1"
+    data_assets:
+      ${name} Data:
+        id: ${id}-data
+        description: Data of ${name}
+    technical_assets:
+      ${name} Service:
+        id: ${id}
+        owner: ${owner}
+        redundant: ${redundant}
+        diagram_tweak_order: ${order}
+        justification_cia_rating: "${order}"
+        communication_links:
+          Database Access:
+            target: ${id}-db
+            data_assets_sent:
+              - ${id}-data
+      ${name} Database:
+        id: ${id}-db
+        owner: ${owner}
+`
+
+func TestTemplates(t *testing.T) {
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml": testTemplatesYaml + `template_instances:
+  - template: rest-service
+    parameters:
+      name: Orders
+      id: orders
+  - template: rest-service
+    parameters:
+      name: Billing
+      id: billing
+      redundant: "true"
+      order: "2"
+`,
+	})
+
+	model := loadTestModel(t, dir)
+	assert.Len(t, model.TechnicalAssets, 4)
+	assert.Len(t, model.DataAssets, 2)
+	assert.Empty(t, model.Templates)
+	assert.Empty(t, model.TemplateInstances)
+
+	orders := model.TechnicalAssets["Orders Service"]
+	assert.Equal(t, "orders", orders.ID)
+	assert.Equal(t, "Company ABC", orders.Owner)
+	// the defaults apply unless the instance sets the parameter
+	assert.False(t, orders.Redundant)
+	assert.Equal(t, "orders-db", orders.CommunicationLinks["Database Access"].Target)
+	assert.Equal(t, []string{"orders-data"}, orders.CommunicationLinks["Database Access"].DataAssetsSent)
+	assert.Equal(t, "Data of Orders", model.DataAssets["Orders Data"].Description)
+
+	// plain scalars resolve their type after the substitution, quoted ones stay strings
+	billing := model.TechnicalAssets["Billing Service"]
+	assert.True(t, billing.Redundant)
+	assert.Equal(t, 2, billing.DiagramTweakOrder)
+	assert.Equal(t, "2", billing.JustificationCiaRating)
+
+	// the parameters win over the variables of the same name
+	assert.Equal(t, 1, orders.DiagramTweakOrder)
+	assert.Equal(t, "Company ABC", model.TechnicalAssets["Billing Database"].Owner)
+
+	// the generated elements are reported at the instance creating them
+	if assert.NotNil(t, billing.SourcePosition) {
+		assert.Equal(t, 34, billing.SourcePosition.Line)
+	}
+}
+
+func TestTemplatesFailures(t *testing.T) {
+	tests := map[string]struct {
+		instances string
+		error     string
+	}{
+		"missing parameter": {
+			instances: "  - template: rest-service\n    parameters:\n      name: Orders\n",
+			error:     `threagile.yaml:30:5: instance of template "rest-service" defined at threagile.yaml:5:3: missing parameters [id]`,
+		},
+		"unknown parameter": {
+			instances: "  - template: rest-service\n    parameters:\n      name: Orders\n      id: orders\n      size: large\n",
+			error:     `threagile.yaml:30:5: instance of template "rest-service" defined at threagile.yaml:5:3: unknown parameters [size]`,
+		},
+		"unknown template": {
+			instances: "  - template: soap-service\n",
+			error:     `threagile.yaml:30:5: unknown template "soap-service"`,
+		},
+		"duplicate element": {
+			instances: "  - template: rest-service\n    parameters:\n      name: Orders\n      id: orders\n  - template: rest-service\n    parameters:\n      name: Orders\n      id: orders-2\n",
+			error:     `threagile.yaml:34:5: instance of template "rest-service" defines data asset "Orders Data" (at threagile.yaml:11:7) which is already defined`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := writeTestModelFiles(t, map[string]string{
+				"threagile.yaml": testTemplatesYaml + "template_instances:\n" + test.instances,
+			})
+			err := new(Model).Defaults().Load(filepath.Join(dir, "threagile.yaml"))
+			assert.Contains(t, testModelError(err, dir), test.error)
+		})
+	}
+
+	// an unknown variable in a template points at the template and names the instance
+	dir := writeTestModelFiles(t, map[string]string{
+		"threagile.yaml": `templates:
+  service:
+    parameters: [id]
+    technical_assets:
+      ${id}:
+        id: ${id}
+        owner: ${team}
+template_instances:
+  - template: service
+    parameters:
+      id: orders
+`,
+	})
+	err := new(Model).Defaults().Load(filepath.Join(dir, "threagile.yaml"))
+	assert.Contains(t, testModelError(err, dir), `threagile.yaml:7:16: unknown parameter or variable "team" in template "service" instantiated at threagile.yaml:9:5`)
+}
+
+// writeTestModelFiles writes the model files by their path relative to the directory returned
+func writeTestModelFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	return dir
+}
+
+// testModelError is the message of the error with the paths made relative to the model directory
+func testModelError(err error, dir string) string {
+	if err == nil {
+		return ""
+	}
+
+	return strings.ReplaceAll(err.Error(), dir+string(filepath.Separator), "")
+}
+
+func loadTestModel(t *testing.T, dir string) *Model {
+	model := new(Model).Defaults()
+	require.NoError(t, model.Load(filepath.Join(dir, "threagile.yaml")))
+	return model
+}
@@ -1,14 +1,19 @@
 package input
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/threagile/threagile/pkg/types"
+)
 
 type TrustBoundary struct {
-	ID                    string   `yaml:"id,omitempty" json:"id,omitempty"`
-	Description           string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Type                  string   `yaml:"type,omitempty" json:"type,omitempty"`
-	Tags                  []string `yaml:"tags,omitempty" json:"tags,omitempty"`
-	TechnicalAssetsInside []string `yaml:"technical_assets_inside,omitempty" json:"technical_assets_inside,omitempty"`
-	TrustBoundariesNested []string `yaml:"trust_boundaries_nested,omitempty" json:"trust_boundaries_nested,omitempty"`
+	ID                    string                `yaml:"id,omitempty" json:"id,omitempty"`
+	Description           string                `yaml:"description,omitempty" json:"description,omitempty"`
+	Type                  string                `yaml:"type,omitempty" json:"type,omitempty"`
+	Tags                  []string              `yaml:"tags,omitempty" json:"tags,omitempty"`
+	TechnicalAssetsInside []string              `yaml:"technical_assets_inside,omitempty" json:"technical_assets_inside,omitempty"`
+	TrustBoundariesNested []string              `yaml:"trust_boundaries_nested,omitempty" json:"trust_boundaries_nested,omitempty"`
+	SourcePosition        *types.SourcePosition `yaml:"-" json:"-"`
 }
 
 func (what *TrustBoundary) Merge(other TrustBoundary) error {
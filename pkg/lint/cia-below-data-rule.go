@@ -0,0 +1,58 @@
+package lint
+
+import (
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type ciaBelowDataRule struct {
+}
+
+func newCiaBelowData() *ciaBelowDataRule {
+	return &ciaBelowDataRule{}
+}
+
+func (*ciaBelowDataRule) GetRuleDetails() RuleDetails {
+	return RuleDetails{
+		ID:    "cia-below-data",
+		Title: "CIA Rating Below Data",
+		Description: "The CIA rating of a technical asset should not be lower than the rating of the data it processes or stores. " +
+			"The analysis raises it implicitly, which hides the omission in the model.",
+		DefaultSeverity: SeverityWarning,
+	}
+}
+
+func (*ciaBelowDataRule) Check(modelInput *input.Model, parsedModel *types.Model) []*Finding {
+	assetsById := inputTechnicalAssetsById(modelInput)
+
+	findings := make([]*Finding, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		inputAsset, ok := assetsById[id]
+		if !ok {
+			continue
+		}
+
+		// rate the asset as written in the model, not as raised by the analysis
+		declared := *parsedModel.TechnicalAssets[id]
+		declared.Confidentiality, _ = types.ParseConfidentiality(inputAsset.Confidentiality)
+		declared.Integrity, _ = types.ParseCriticality(inputAsset.Integrity)
+		declared.Availability, _ = types.ParseCriticality(inputAsset.Availability)
+
+		if highest := parsedModel.HighestTechnicalAssetConfidentiality(&declared); highest > declared.Confidentiality {
+			findings = append(findings, newFinding(id, declared.SourcePosition, "technical asset %q is rated confidentiality %q but handles data rated %q",
+				declared.Title, declared.Confidentiality.String(), highest.String()))
+		}
+
+		if highest := parsedModel.HighestIntegrity(&declared); highest > declared.Integrity {
+			findings = append(findings, newFinding(id, declared.SourcePosition, "technical asset %q is rated integrity %q but handles data rated %q",
+				declared.Title, declared.Integrity.String(), highest.String()))
+		}
+
+		if highest := parsedModel.HighestAvailability(&declared); highest > declared.Availability {
+			findings = append(findings, newFinding(id, declared.SourcePosition, "technical asset %q is rated availability %q but handles data rated %q",
+				declared.Title, declared.Availability.String(), highest.String()))
+		}
+	}
+
+	return findings
+}
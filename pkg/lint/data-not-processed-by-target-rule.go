@@ -0,0 +1,51 @@
+package lint
+
+import (
+	"slices"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type dataNotProcessedByTargetRule struct {
+}
+
+func newDataNotProcessedByTarget() *dataNotProcessedByTargetRule {
+	return &dataNotProcessedByTargetRule{}
+}
+
+func (*dataNotProcessedByTargetRule) GetRuleDetails() RuleDetails {
+	return RuleDetails{
+		ID:    "data-not-processed-by-target",
+		Title: "Data Not Processed By Target",
+		Description: "Data assets sent over a communication link should be listed as processed or stored by the link target. " +
+			"The analysis adds them implicitly, which hides the omission in the model.",
+		DefaultSeverity: SeverityWarning,
+	}
+}
+
+func (*dataNotProcessedByTargetRule) Check(modelInput *input.Model, _ *types.Model) []*Finding {
+	assetsById := inputTechnicalAssetsById(modelInput)
+
+	findings := make([]*Finding, 0)
+	for _, sourceId := range sortedKeys(assetsById) {
+		source := assetsById[sourceId]
+		for _, linkTitle := range sortedKeys(source.CommunicationLinks) {
+			link := source.CommunicationLinks[linkTitle]
+			target, ok := assetsById[link.Target]
+			if !ok || target.ID == sourceId {
+				continue
+			}
+
+			for _, dataId := range link.DataAssetsSent {
+				if !slices.Contains(target.DataAssetsProcessed, dataId) && !slices.Contains(target.DataAssetsStored, dataId) {
+					findings = append(findings, newFinding(sourceId+">"+linkTitle, link.SourcePosition,
+						"communication link %q of technical asset %q sends data asset %q which is not processed by its target %q",
+						linkTitle, sourceId, dataId, target.ID))
+				}
+			}
+		}
+	}
+
+	return findings
+}
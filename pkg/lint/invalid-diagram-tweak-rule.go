@@ -0,0 +1,70 @@
+package lint
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type invalidDiagramTweakRule struct {
+}
+
+func newInvalidDiagramTweak() *invalidDiagramTweakRule {
+	return &invalidDiagramTweakRule{}
+}
+
+func (*invalidDiagramTweakRule) GetRuleDetails() RuleDetails {
+	return RuleDetails{
+		ID:    "invalid-diagram-tweak",
+		Title: "Invalid Diagram Tweak",
+		Description: "The diagram_tweak_* settings should reference existing technical assets (same-rank assets outside of trust boundaries) " +
+			"and use a known edge layout, otherwise rendering the data-flow diagram fails.",
+		DefaultSeverity: SeverityError,
+	}
+}
+
+func (*invalidDiagramTweakRule) Check(_ *input.Model, parsedModel *types.Model) []*Finding {
+	findings := make([]*Finding, 0)
+
+	edgeLayouts := []string{"spline", "polyline", "ortho", "curved", "false"}
+	if len(parsedModel.DiagramTweakEdgeLayout) > 0 && !slices.Contains(edgeLayouts, parsedModel.DiagramTweakEdgeLayout) {
+		findings = append(findings, newFinding("diagram_tweak_edge_layout", nil, "unknown diagram_tweak_edge_layout %q (%v)",
+			parsedModel.DiagramTweakEdgeLayout, strings.Join(edgeLayouts, ", ")))
+	}
+
+	for _, connection := range parsedModel.DiagramTweakInvisibleConnectionsBetweenAssets {
+		assetIds := strings.Split(connection, ":")
+		if len(assetIds) != 2 {
+			findings = append(findings, newFinding("diagram_tweak_invisible_connections_between_assets", nil,
+				"invisible connection %q is not of the form <source asset id>:<target asset id>", connection))
+			continue
+		}
+
+		for _, id := range assetIds {
+			if _, ok := parsedModel.TechnicalAssets[id]; !ok {
+				findings = append(findings, newFinding("diagram_tweak_invisible_connections_between_assets", nil,
+					"invisible connection %q references unknown technical asset %q", connection, id))
+			}
+		}
+	}
+
+	for _, sameRank := range parsedModel.DiagramTweakSameRankAssets {
+		for _, id := range strings.Split(sameRank, ":") {
+			asset, ok := parsedModel.TechnicalAssets[id]
+			if !ok {
+				findings = append(findings, newFinding("diagram_tweak_same_rank_assets", nil,
+					"same rank assets %q reference unknown technical asset %q", sameRank, id))
+				continue
+			}
+
+			if len(parsedModel.GetTechnicalAssetTrustBoundaryId(asset)) > 0 {
+				findings = append(findings, newFinding("diagram_tweak_same_rank_assets", nil,
+					"same rank assets %q reference technical asset %q inside a trust boundary", sameRank, id))
+			}
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,171 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+// Rule is a model hygiene check; unlike parse errors its findings do not prevent the analysis
+type Rule interface {
+	GetRuleDetails() RuleDetails
+	Check(modelInput *input.Model, parsedModel *types.Model) []*Finding
+}
+
+type RuleDetails struct {
+	ID              string
+	Title           string
+	Description     string
+	DefaultSeverity Severity
+}
+
+type Finding struct {
+	RuleId         string                `json:"rule_id" yaml:"rule_id"`
+	Severity       Severity              `json:"severity" yaml:"severity"`
+	ElementId      string                `json:"element_id,omitempty" yaml:"element_id,omitempty"`
+	Message        string                `json:"message" yaml:"message"`
+	SourcePosition *types.SourcePosition `json:"source_position,omitempty" yaml:"source_position,omitempty"`
+}
+
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+func ParseSeverity(value string) (Severity, error) {
+	for _, candidate := range []Severity{SeverityOff, SeverityInfo, SeverityWarning, SeverityError} {
+		if strings.EqualFold(strings.TrimSpace(value), string(candidate)) {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown lint severity %q (off, info, warning, error)", value)
+}
+
+func ListBuiltInRules() []Rule {
+	return []Rule{
+		newTechnicalAssetWithoutLinks(),
+		newUnusedDataAsset(),
+		newDataNotProcessedByTarget(),
+		newCiaBelowData(),
+		newMissingOwner(),
+		newMissingJustification(),
+		newUnusedTag(),
+		newOrphanedRiskTracking(),
+		newInvalidDiagramTweak(),
+	}
+}
+
+// Run checks the model with all rules not turned off; severities maps rule ids to a severity overriding the rule's default
+func Run(modelInput *input.Model, parsedModel *types.Model, rules []Rule, severities map[string]string) ([]*Finding, error) {
+	rulesById := make(map[string]Rule)
+	for _, rule := range rules {
+		rulesById[rule.GetRuleDetails().ID] = rule
+	}
+
+	configured := make(map[string]Severity)
+	for id, value := range severities {
+		if _, ok := rulesById[id]; !ok {
+			return nil, fmt.Errorf("unknown lint check %q", id)
+		}
+
+		severity, err := ParseSeverity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid severity of lint check %q: %w", id, err)
+		}
+
+		configured[id] = severity
+	}
+
+	findings := make([]*Finding, 0)
+	for _, rule := range rules {
+		details := rule.GetRuleDetails()
+		severity, ok := configured[details.ID]
+		if !ok {
+			severity = details.DefaultSeverity
+		}
+
+		if severity == SeverityOff {
+			continue
+		}
+
+		for _, finding := range rule.Check(modelInput, parsedModel) {
+			finding.RuleId = details.ID
+			finding.Severity = severity
+			findings = append(findings, finding)
+		}
+	}
+
+	SortFindings(findings)
+	return findings, nil
+}
+
+// SortFindings orders findings by their position in the model, then by rule and element
+func SortFindings(findings []*Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		left, right := findings[i].SourcePosition, findings[j].SourcePosition
+		if left != nil && right != nil {
+			if left.File != right.File {
+				return left.File < right.File
+			}
+
+			if left.Line != right.Line {
+				return left.Line < right.Line
+			}
+		} else if left != nil || right != nil {
+			return left != nil
+		}
+
+		if findings[i].RuleId != findings[j].RuleId {
+			return findings[i].RuleId < findings[j].RuleId
+		}
+
+		return findings[i].ElementId < findings[j].ElementId
+	})
+}
+
+func CountBySeverity(findings []*Finding, severity Severity) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+func newFinding(elementId string, position *types.SourcePosition, format string, args ...any) *Finding {
+	return &Finding{
+		ElementId:      elementId,
+		Message:        fmt.Sprintf(format, args...),
+		SourcePosition: position,
+	}
+}
+
+// inputTechnicalAssetsById maps the technical assets as written in the model by their id
+func inputTechnicalAssetsById(modelInput *input.Model) map[string]input.TechnicalAsset {
+	result := make(map[string]input.TechnicalAsset)
+	for _, asset := range modelInput.TechnicalAssets {
+		result[asset.ID] = asset
+	}
+
+	return result
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+func TestRunAppliesConfiguredSeverities(t *testing.T) {
+	parsedModel := &types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"lonely": {Id: "lonely", Title: "Lonely", Owner: "someone"},
+		},
+		DataAssets:      map[string]*types.DataAsset{},
+		TagsAvailable:   []string{"unused"},
+		RiskTracking:    map[string]*types.RiskTracking{},
+		TrustBoundaries: map[string]*types.TrustBoundary{},
+	}
+
+	findings, err := Run(new(input.Model).Defaults(), parsedModel, ListBuiltInRules(), map[string]string{
+		"technical-asset-without-links": "error",
+		"unused-tag":                    "off",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "technical-asset-without-links", findings[0].RuleId)
+	assert.Equal(t, SeverityError, findings[0].Severity)
+	assert.Equal(t, "lonely", findings[0].ElementId)
+}
+
+func TestRunRejectsUnknownChecksAndSeverities(t *testing.T) {
+	_, err := Run(new(input.Model).Defaults(), &types.Model{}, ListBuiltInRules(), map[string]string{"no-such-check": "error"})
+	assert.Error(t, err)
+
+	_, err = Run(new(input.Model).Defaults(), &types.Model{}, ListBuiltInRules(), map[string]string{"unused-tag": "fatal"})
+	assert.Error(t, err)
+}
+
+func TestDataNotProcessedByTarget(t *testing.T) {
+	linkPosition := &types.SourcePosition{File: "threagile.yaml", Line: 10, Column: 7}
+	modelInput := new(input.Model).Defaults()
+	modelInput.TechnicalAssets["Client"] = input.TechnicalAsset{
+		ID: "client",
+		CommunicationLinks: map[string]input.CommunicationLink{
+			"Upload": {Target: "server", DataAssetsSent: []string{"declared", "undeclared"}, SourcePosition: linkPosition},
+		},
+	}
+	modelInput.TechnicalAssets["Server"] = input.TechnicalAsset{
+		ID:                  "server",
+		DataAssetsProcessed: []string{"declared"},
+	}
+
+	findings := newDataNotProcessedByTarget().Check(modelInput, nil)
+
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "client>Upload", findings[0].ElementId)
+	assert.Equal(t, linkPosition, findings[0].SourcePosition)
+	assert.Contains(t, findings[0].Message, `"undeclared"`)
+}
+
+func TestCiaBelowDataUsesDeclaredRating(t *testing.T) {
+	modelInput := new(input.Model).Defaults()
+	modelInput.TechnicalAssets["Server"] = input.TechnicalAsset{
+		ID:              "server",
+		Confidentiality: types.Internal.String(),
+		Integrity:       types.Critical.String(),
+		Availability:    types.Critical.String(),
+	}
+
+	parsedModel := &types.Model{
+		DataAssets: map[string]*types.DataAsset{
+			"secret": {Id: "secret", Confidentiality: types.StrictlyConfidential, Integrity: types.Critical, Availability: types.Critical},
+		},
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			// as raised by the analysis
+			"server": {Id: "server", Title: "Server", Confidentiality: types.StrictlyConfidential, Integrity: types.Critical, Availability: types.Critical, DataAssetsProcessed: []string{"secret"}},
+		},
+	}
+
+	findings := newCiaBelowData().Check(modelInput, parsedModel)
+
+	assert.Len(t, findings, 1)
+	assert.Contains(t, findings[0].Message, "confidentiality")
+}
+
+func TestOrphanedRiskTracking(t *testing.T) {
+	modelInput := new(input.Model).Defaults()
+	modelInput.RiskTracking["some-risk@*"] = input.RiskTracking{Status: "accepted"}
+	modelInput.RiskTracking["gone@server"] = input.RiskTracking{Status: "accepted"}
+
+	parsedModel := &types.Model{
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{
+			"some-risk@client": {SyntheticId: "some-risk@client"},
+		},
+	}
+
+	findings := newOrphanedRiskTracking().Check(modelInput, parsedModel)
+
+	assert.Len(t, findings, 1)
+	assert.Equal(t, "gone@server", findings[0].ElementId)
+}
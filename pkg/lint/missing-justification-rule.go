@@ -0,0 +1,66 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type missingJustificationRule struct {
+}
+
+func newMissingJustification() *missingJustificationRule {
+	return &missingJustificationRule{}
+}
+
+func (*missingJustificationRule) GetRuleDetails() RuleDetails {
+	return RuleDetails{
+		ID:    "missing-justification",
+		Title: "Missing Justification",
+		Description: "Out-of-scope technical assets, high CIA ratings (confidential or critical and above) " +
+			"and risk tracking entries other than unchecked should be justified.",
+		DefaultSeverity: SeverityWarning,
+	}
+}
+
+func (*missingJustificationRule) Check(_ *input.Model, parsedModel *types.Model) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		asset := parsedModel.TechnicalAssets[id]
+		if asset.OutOfScope {
+			if isBlank(asset.JustificationOutOfScope) {
+				findings = append(findings, newFinding(id, asset.SourcePosition, "technical asset %q is out of scope without justification", asset.Title))
+			}
+			continue
+		}
+
+		if isHighlyRated(asset.Confidentiality, asset.Integrity, asset.Availability) && isBlank(asset.JustificationCiaRating) {
+			findings = append(findings, newFinding(id, asset.SourcePosition, "technical asset %q has a high CIA rating without justification", asset.Title))
+		}
+	}
+
+	for _, id := range sortedKeys(parsedModel.DataAssets) {
+		dataAsset := parsedModel.DataAssets[id]
+		if isHighlyRated(dataAsset.Confidentiality, dataAsset.Integrity, dataAsset.Availability) && isBlank(dataAsset.JustificationCiaRating) {
+			findings = append(findings, newFinding(id, dataAsset.SourcePosition, "data asset %q has a high CIA rating without justification", dataAsset.Title))
+		}
+	}
+
+	for _, id := range sortedKeys(parsedModel.RiskTracking) {
+		tracking := parsedModel.RiskTracking[id]
+		if tracking.Status != types.Unchecked && isBlank(tracking.Justification) {
+			findings = append(findings, newFinding(id, tracking.SourcePosition, "risk tracking %q with status %q has no justification", id, tracking.Status.String()))
+		}
+	}
+
+	return findings
+}
+
+func isHighlyRated(confidentiality types.Confidentiality, integrity types.Criticality, availability types.Criticality) bool {
+	return confidentiality >= types.Confidential || integrity >= types.Critical || availability >= types.Critical
+}
+
+func isBlank(value string) bool {
+	return len(strings.TrimSpace(value)) == 0
+}
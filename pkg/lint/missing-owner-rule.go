@@ -0,0 +1,43 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type missingOwnerRule struct {
+}
+
+func newMissingOwner() *missingOwnerRule {
+	return &missingOwnerRule{}
+}
+
+func (*missingOwnerRule) GetRuleDetails() RuleDetails {
+	return RuleDetails{
+		ID:              "missing-owner",
+		Title:           "Missing Owner",
+		Description:     "In-scope technical assets and data assets should name their owner.",
+		DefaultSeverity: SeverityWarning,
+	}
+}
+
+func (*missingOwnerRule) Check(_ *input.Model, parsedModel *types.Model) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		asset := parsedModel.TechnicalAssets[id]
+		if !asset.OutOfScope && len(strings.TrimSpace(asset.Owner)) == 0 {
+			findings = append(findings, newFinding(id, asset.SourcePosition, "technical asset %q has no owner", asset.Title))
+		}
+	}
+
+	for _, id := range sortedKeys(parsedModel.DataAssets) {
+		dataAsset := parsedModel.DataAssets[id]
+		if len(strings.TrimSpace(dataAsset.Owner)) == 0 {
+			findings = append(findings, newFinding(id, dataAsset.SourcePosition, "data asset %q has no owner", dataAsset.Title))
+		}
+	}
+
+	return findings
+}
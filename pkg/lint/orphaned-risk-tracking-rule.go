@@ -0,0 +1,33 @@
+package lint
+
+import (
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type orphanedRiskTrackingRule struct {
+}
+
+func newOrphanedRiskTracking() *orphanedRiskTrackingRule {
+	return &orphanedRiskTrackingRule{}
+}
+
+func (*orphanedRiskTrackingRule) GetRuleDetails() RuleDetails {
+	return RuleDetails{
+		ID:              "orphaned-risk-tracking",
+		Title:           "Orphaned Risk Tracking",
+		Description:     "Risk tracking entries should match at least one generated risk (wildcards included).",
+		DefaultSeverity: SeverityWarning,
+	}
+}
+
+func (*orphanedRiskTrackingRule) Check(modelInput *input.Model, parsedModel *types.Model) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, id := range sortedKeys(modelInput.RiskTracking) {
+		if !parsedModel.RiskTrackingMatchesAnyRisk(id) {
+			findings = append(findings, newFinding(id, modelInput.RiskTracking[id].SourcePosition, "risk tracking %q does not match any risk", id))
+		}
+	}
+
+	return findings
+}
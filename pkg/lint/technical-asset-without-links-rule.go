@@ -0,0 +1,38 @@
+package lint
+
+import (
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type technicalAssetWithoutLinksRule struct {
+}
+
+func newTechnicalAssetWithoutLinks() *technicalAssetWithoutLinksRule {
+	return &technicalAssetWithoutLinksRule{}
+}
+
+func (*technicalAssetWithoutLinksRule) GetRuleDetails() RuleDetails {
+	return RuleDetails{
+		ID:              "technical-asset-without-links",
+		Title:           "Technical Asset Without Links",
+		Description:     "In-scope technical assets should have at least one incoming or outgoing communication link.",
+		DefaultSeverity: SeverityWarning,
+	}
+}
+
+func (*technicalAssetWithoutLinksRule) Check(_ *input.Model, parsedModel *types.Model) []*Finding {
+	findings := make([]*Finding, 0)
+	for _, id := range parsedModel.SortedTechnicalAssetIDs() {
+		asset := parsedModel.TechnicalAssets[id]
+		if asset.OutOfScope {
+			continue
+		}
+
+		if len(asset.CommunicationLinks) == 0 && len(parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId[id]) == 0 {
+			findings = append(findings, newFinding(id, asset.SourcePosition, "technical asset %q has no communication links", asset.Title))
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type unusedDataAssetRule struct {
+}
+
+func newUnusedDataAsset() *unusedDataAssetRule {
+	return &unusedDataAssetRule{}
+}
+
+func (*unusedDataAssetRule) GetRuleDetails() RuleDetails {
+	return RuleDetails{
+		ID:              "unused-data-asset",
+		Title:           "Unused Data Asset",
+		Description:     "Data assets should be processed or stored by at least one technical asset.",
+		DefaultSeverity: SeverityWarning,
+	}
+}
+
+func (*unusedDataAssetRule) Check(_ *input.Model, parsedModel *types.Model) []*Finding {
+	used := make(map[string]bool)
+	for _, asset := range parsedModel.TechnicalAssets {
+		for _, dataId := range asset.DataAssetsProcessed {
+			used[dataId] = true
+		}
+		for _, dataId := range asset.DataAssetsStored {
+			used[dataId] = true
+		}
+	}
+
+	findings := make([]*Finding, 0)
+	for _, id := range sortedKeys(parsedModel.DataAssets) {
+		if !used[id] {
+			dataAsset := parsedModel.DataAssets[id]
+			findings = append(findings, newFinding(id, dataAsset.SourcePosition, "data asset %q is neither processed nor stored by any technical asset", dataAsset.Title))
+		}
+	}
+
+	return findings
+}
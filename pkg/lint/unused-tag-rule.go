@@ -0,0 +1,37 @@
+package lint
+
+import (
+	"slices"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type unusedTagRule struct {
+}
+
+func newUnusedTag() *unusedTagRule {
+	return &unusedTagRule{}
+}
+
+func (*unusedTagRule) GetRuleDetails() RuleDetails {
+	return RuleDetails{
+		ID:              "unused-tag",
+		Title:           "Unused Tag",
+		Description:     "Tags listed in tags_available should be used by at least one model element (see the remove-unused-tags macro).",
+		DefaultSeverity: SeverityInfo,
+	}
+}
+
+func (*unusedTagRule) Check(_ *input.Model, parsedModel *types.Model) []*Finding {
+	used := parsedModel.TagsActuallyUsed()
+
+	findings := make([]*Finding, 0)
+	for _, tag := range parsedModel.TagsAvailable {
+		if !slices.Contains(used, tag) {
+			findings = append(findings, newFinding(tag, nil, "tag %q is not used by any model element", tag))
+		}
+	}
+
+	return findings
+}
@@ -320,6 +320,9 @@ func (m *AddVaultMacro) applyChange(modelInput *input.Model, parsedModel *types.
 			clientAssetTitle := parsedModel.TechnicalAssets[clientID].Title
 			if !dryRun {
 				client := modelInput.TechnicalAssets[clientAssetTitle]
+				if client.CommunicationLinks == nil {
+					client.CommunicationLinks = make(map[string]input.CommunicationLink)
+				}
 				client.CommunicationLinks["Vault Access ("+clientID+")"] = clientAccessCommLink
 				modelInput.TechnicalAssets[clientAssetTitle] = client
 			}
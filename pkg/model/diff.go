@@ -0,0 +1,234 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/threagile/threagile/pkg/types"
+)
+
+// ModelDiff is the semantic difference between two analyzed models (or two sets of risks)
+type ModelDiff struct {
+	TechnicalAssets    *ElementDiff `json:"technical_assets,omitempty" yaml:"technical_assets,omitempty"`
+	CommunicationLinks *ElementDiff `json:"communication_links,omitempty" yaml:"communication_links,omitempty"`
+	DataAssets         *ElementDiff `json:"data_assets,omitempty" yaml:"data_assets,omitempty"`
+	TrustBoundaries    *ElementDiff `json:"trust_boundaries,omitempty" yaml:"trust_boundaries,omitempty"`
+	Risks              *RiskDiff    `json:"risks" yaml:"risks"`
+}
+
+type ElementDiff struct {
+	Added   []string          `json:"added,omitempty" yaml:"added,omitempty"`
+	Removed []string          `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Changed []*ChangedElement `json:"changed,omitempty" yaml:"changed,omitempty"`
+}
+
+// ChangedElement names a model element present in both models and the (json) fields that differ
+type ChangedElement struct {
+	Id     string   `json:"id" yaml:"id"`
+	Fields []string `json:"fields" yaml:"fields"`
+}
+
+// RiskDiff is keyed by the synthetic risk id
+type RiskDiff struct {
+	Added    []*types.Risk  `json:"added,omitempty" yaml:"added,omitempty"`
+	Resolved []*types.Risk  `json:"resolved,omitempty" yaml:"resolved,omitempty"`
+	Changed  []*ChangedRisk `json:"changed,omitempty" yaml:"changed,omitempty"`
+}
+
+// ChangedRisk is a risk present in both models with a different severity or status
+type ChangedRisk struct {
+	SyntheticId string             `json:"synthetic_id" yaml:"synthetic_id"`
+	Title       string             `json:"title" yaml:"title"`
+	OldSeverity types.RiskSeverity `json:"old_severity" yaml:"old_severity"`
+	NewSeverity types.RiskSeverity `json:"new_severity" yaml:"new_severity"`
+	OldStatus   types.RiskStatus   `json:"old_status" yaml:"old_status"`
+	NewStatus   types.RiskStatus   `json:"new_status" yaml:"new_status"`
+}
+
+// fields which are the result of the analysis or are compared separately
+var ignoredDiffFields = map[string]bool{
+	"raa":                 true,
+	"raa_breakdown":       true,
+	"communication_links": true,
+}
+
+func DiffModels(oldModel *types.Model, newModel *types.Model) (*ModelDiff, error) {
+	technicalAssets, err := diffElements(oldModel.TechnicalAssets, newModel.TechnicalAssets)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compare technical assets: %w", err)
+	}
+
+	communicationLinks, err := diffElements(oldModel.CommunicationLinks, newModel.CommunicationLinks)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compare communication links: %w", err)
+	}
+
+	dataAssets, err := diffElements(oldModel.DataAssets, newModel.DataAssets)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compare data assets: %w", err)
+	}
+
+	trustBoundaries, err := diffElements(oldModel.TrustBoundaries, newModel.TrustBoundaries)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compare trust boundaries: %w", err)
+	}
+
+	return &ModelDiff{
+		TechnicalAssets:    technicalAssets,
+		CommunicationLinks: communicationLinks,
+		DataAssets:         dataAssets,
+		TrustBoundaries:    trustBoundaries,
+		Risks:              DiffRisks(oldModel.AllRisksWithCurrentStatus(), newModel.AllRisksWithCurrentStatus()),
+	}, nil
+}
+
+func DiffRisks(oldRisks []*types.Risk, newRisks []*types.Risk) *RiskDiff {
+	oldRisksById := make(map[string]*types.Risk)
+	for _, risk := range oldRisks {
+		oldRisksById[risk.SyntheticId] = risk
+	}
+
+	newRisksById := make(map[string]*types.Risk)
+	for _, risk := range newRisks {
+		newRisksById[risk.SyntheticId] = risk
+	}
+
+	result := new(RiskDiff)
+	for _, id := range sortedKeys(newRisksById) {
+		newRisk := newRisksById[id]
+		oldRisk, ok := oldRisksById[id]
+		if !ok {
+			result.Added = append(result.Added, newRisk)
+			continue
+		}
+
+		if oldRisk.Severity != newRisk.Severity || oldRisk.RiskStatus != newRisk.RiskStatus {
+			result.Changed = append(result.Changed, &ChangedRisk{
+				SyntheticId: id,
+				Title:       newRisk.Title,
+				OldSeverity: oldRisk.Severity,
+				NewSeverity: newRisk.Severity,
+				OldStatus:   oldRisk.RiskStatus,
+				NewStatus:   newRisk.RiskStatus,
+			})
+		}
+	}
+
+	for _, id := range sortedKeys(oldRisksById) {
+		if _, ok := newRisksById[id]; !ok {
+			result.Resolved = append(result.Resolved, oldRisksById[id])
+		}
+	}
+
+	types.SortByRiskSeverity(result.Added)
+	types.SortByRiskSeverity(result.Resolved)
+
+	return result
+}
+
+func (what *ModelDiff) IsEmpty() bool {
+	for _, elements := range []*ElementDiff{what.TechnicalAssets, what.CommunicationLinks, what.DataAssets, what.TrustBoundaries} {
+		if !elements.IsEmpty() {
+			return false
+		}
+	}
+
+	return what.Risks.IsEmpty()
+}
+
+func (what *ElementDiff) IsEmpty() bool {
+	return what == nil || len(what.Added)+len(what.Removed)+len(what.Changed) == 0
+}
+
+func (what *RiskDiff) IsEmpty() bool {
+	return what == nil || len(what.Added)+len(what.Resolved)+len(what.Changed) == 0
+}
+
+func diffElements[T any](oldElements map[string]T, newElements map[string]T) (*ElementDiff, error) {
+	result := new(ElementDiff)
+	for _, id := range sortedKeys(newElements) {
+		oldElement, ok := oldElements[id]
+		if !ok {
+			result.Added = append(result.Added, id)
+			continue
+		}
+
+		fields, err := changedFields(oldElement, newElements[id])
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", id, err)
+		}
+
+		if len(fields) > 0 {
+			result.Changed = append(result.Changed, &ChangedElement{Id: id, Fields: fields})
+		}
+	}
+
+	for _, id := range sortedKeys(oldElements) {
+		if _, ok := newElements[id]; !ok {
+			result.Removed = append(result.Removed, id)
+		}
+	}
+
+	return result, nil
+}
+
+// changedFields compares the json representation of both elements to get the names as used in the model
+func changedFields(oldElement any, newElement any) ([]string, error) {
+	oldFields, err := toFields(oldElement)
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := toFields(newElement)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for name := range oldFields {
+		names[name] = true
+	}
+	for name := range newFields {
+		names[name] = true
+	}
+
+	result := make([]string, 0)
+	for _, name := range sortedKeys(names) {
+		if ignoredDiffFields[name] {
+			continue
+		}
+
+		if !reflect.DeepEqual(oldFields[name], newFields[name]) {
+			result = append(result, name)
+		}
+	}
+
+	return result, nil
+}
+
+func toFields(element any) (map[string]any, error) {
+	data, err := json.Marshal(element)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]any)
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
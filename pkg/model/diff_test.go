@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+func TestDiffRisks(t *testing.T) {
+	oldRisks := []*types.Risk{
+		{SyntheticId: "kept", Severity: types.MediumSeverity},
+		{SyntheticId: "resolved", Severity: types.HighSeverity},
+		{SyntheticId: "escalated", Severity: types.MediumSeverity},
+		{SyntheticId: "accepted", Severity: types.LowSeverity},
+	}
+	newRisks := []*types.Risk{
+		{SyntheticId: "kept", Severity: types.MediumSeverity},
+		{SyntheticId: "added", Severity: types.CriticalSeverity},
+		{SyntheticId: "escalated", Severity: types.HighSeverity},
+		{SyntheticId: "accepted", Severity: types.LowSeverity, RiskStatus: types.Accepted},
+	}
+
+	diff := DiffRisks(oldRisks, newRisks)
+
+	assert.Equal(t, []*types.Risk{newRisks[1]}, diff.Added)
+	assert.Equal(t, []*types.Risk{oldRisks[1]}, diff.Resolved)
+	assert.Equal(t, []*ChangedRisk{
+		{SyntheticId: "accepted", OldSeverity: types.LowSeverity, NewSeverity: types.LowSeverity, OldStatus: types.Unchecked, NewStatus: types.Accepted},
+		{SyntheticId: "escalated", OldSeverity: types.MediumSeverity, NewSeverity: types.HighSeverity, OldStatus: types.Unchecked, NewStatus: types.Unchecked},
+	}, diff.Changed)
+}
+
+func TestDiffModels(t *testing.T) {
+	oldModel := &types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"kept":    {Id: "kept", Title: "Kept", RAA: 10},
+			"changed": {Id: "changed", Title: "Changed", Encryption: types.NoneEncryption},
+			"removed": {Id: "removed"},
+		},
+		DataAssets: map[string]*types.DataAsset{
+			"data": {Id: "data", Confidentiality: types.Internal},
+		},
+	}
+	newModel := &types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"kept":    {Id: "kept", Title: "Kept", RAA: 20},
+			"changed": {Id: "changed", Title: "Changed (renamed)", Encryption: types.Transparent},
+			"added":   {Id: "added"},
+		},
+		DataAssets: map[string]*types.DataAsset{
+			"data": {Id: "data", Confidentiality: types.Internal},
+		},
+	}
+
+	diff, err := DiffModels(oldModel, newModel)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &ElementDiff{
+		Added:   []string{"added"},
+		Removed: []string{"removed"},
+		Changed: []*ChangedElement{{Id: "changed", Fields: []string{"encryption", "title"}}},
+	}, diff.TechnicalAssets)
+	assert.True(t, diff.DataAssets.IsEmpty())
+	assert.True(t, diff.Risks.IsEmpty())
+	assert.False(t, diff.IsEmpty())
+}
@@ -0,0 +1,162 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type explainRiskConfig interface {
+	GetVerbose() bool
+}
+
+type explainRiskReporter interface {
+	Println(a ...any)
+	Printf(format string, a ...any)
+}
+
+func (what ReadResult) ExplainRisk(cfg explainRiskConfig, risk string, reporter explainRiskReporter) error {
+	if what.ParsedModel == nil {
+		return fmt.Errorf("no model loaded")
+	}
+
+	matchingRisks := what.ParsedModel.FindRisks(risk)
+	if len(matchingRisks) == 0 {
+		return fmt.Errorf("no risk found matching synthetic risk id %q", risk)
+	}
+
+	for n, matchingRisk := range matchingRisks {
+		if n > 0 {
+			reporter.Println()
+			reporter.Println("----------------------")
+			reporter.Println()
+		}
+
+		what.explainRisk(cfg, matchingRisk, reporter)
+	}
+
+	return nil
+}
+
+func (what ReadResult) explainRisk(cfg explainRiskConfig, risk *types.Risk, reporter explainRiskReporter) {
+	parsedModel := what.ParsedModel
+
+	reporter.Printf("Risk: %v\n", risk.SyntheticId)
+	reporter.Printf("Title: %v\n", risk.Title)
+
+	category := parsedModel.GetRiskCategory(risk.CategoryId)
+	if category != nil {
+		reporter.Printf("Category: %v (%v)\n", category.Title, category.ID)
+		reporter.Printf("  Function: %v, STRIDE: %v, CWE: %v\n", category.Function.Title(), category.STRIDE.Title(), category.CWE)
+		if cfg.GetVerbose() {
+			reporter.Printf("  Description: %v\n", category.Description)
+			reporter.Printf("  Detection logic: %v\n", category.DetectionLogic)
+			reporter.Printf("  Mitigation: %v\n", category.Mitigation)
+		}
+	} else {
+		reporter.Printf("Category: %v (unknown risk category)\n", risk.CategoryId)
+	}
+
+	reporter.Println()
+	reporter.Println("Matched model elements:")
+	what.explainMatchedElements(risk, reporter)
+
+	reporter.Println()
+	reporter.Println("Why it was flagged:")
+	explainLines(risk.RiskExplanation, "no explanation trail recorded by the risk rule", reporter)
+
+	reporter.Println()
+	reporter.Println("Rating:")
+	reporter.Printf("  Exploitation likelihood: %v (weight %v)\n", risk.ExploitationLikelihood.Title(), risk.ExploitationLikelihood.Weight())
+	reporter.Printf("  Exploitation impact: %v (weight %v)\n", risk.ExploitationImpact.Title(), risk.ExploitationImpact.Weight())
+	reporter.Printf("  Data breach probability: %v\n", risk.DataBreachProbability.Title())
+	explainLines(risk.RatingExplanation, "no rating trail recorded by the risk rule", reporter)
+
+	reporter.Println()
+	reporter.Println("Severity:")
+	calculatedSeverity := types.CalculateSeverity(risk.ExploitationLikelihood, risk.ExploitationImpact)
+	reporter.Printf("  %v x %v = %v => %v\n", risk.ExploitationLikelihood.Weight(), risk.ExploitationImpact.Weight(),
+		risk.ExploitationLikelihood.Weight()*risk.ExploitationImpact.Weight(), calculatedSeverity.Title())
+	reporter.Println("  (<= 1: low, <= 3: medium, <= 8: elevated, <= 12: high, otherwise critical)")
+	if calculatedSeverity != risk.Severity {
+		reporter.Printf("  Severity has been set to %v by the risk rule\n", risk.Severity.Title())
+	}
+
+	reporter.Println()
+	reporter.Println("Risk tracking:")
+	tracking := parsedModel.GetRiskTracking(risk)
+	if tracking == nil {
+		reporter.Printf("  no risk tracking entry, status is %v\n", types.Unchecked.Title())
+		return
+	}
+
+	reporter.Printf("  Status: %v\n", tracking.Status.Title())
+	if strings.Contains(tracking.TrackingKey, "*") {
+		reporter.Printf("  Tracked by wildcard: %v\n", tracking.TrackingKey)
+	} else if len(tracking.TrackingKey) > 0 {
+		reporter.Printf("  Tracked as: %v\n", tracking.TrackingKey)
+	}
+	if len(tracking.Justification) > 0 {
+		reporter.Printf("  Justification: %v\n", tracking.Justification)
+	}
+	if len(tracking.Ticket) > 0 {
+		reporter.Printf("  Ticket: %v\n", tracking.Ticket)
+	}
+	if len(tracking.CheckedBy) > 0 {
+		reporter.Printf("  Checked by: %v\n", tracking.CheckedBy)
+	}
+	if !tracking.Date.IsZero() {
+		reporter.Printf("  Date: %v\n", tracking.Date.Format("2006-01-02"))
+	}
+}
+
+func (what ReadResult) explainMatchedElements(risk *types.Risk, reporter explainRiskReporter) {
+	parsedModel := what.ParsedModel
+	found := false
+
+	if asset, ok := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]; ok {
+		reporter.Printf("  Technical asset: %v (%v)\n", asset.Title, asset.Id)
+		found = true
+	}
+
+	if link, ok := parsedModel.CommunicationLinks[risk.MostRelevantCommunicationLinkId]; ok {
+		reporter.Printf("  Communication link: %v (%v) from %v to %v\n", link.Title, link.Id, link.SourceId, link.TargetId)
+		found = true
+	}
+
+	if dataAsset, ok := parsedModel.DataAssets[risk.MostRelevantDataAssetId]; ok {
+		reporter.Printf("  Data asset: %v (%v)\n", dataAsset.Title, dataAsset.Id)
+		found = true
+	}
+
+	if trustBoundary, ok := parsedModel.TrustBoundaries[risk.MostRelevantTrustBoundaryId]; ok {
+		reporter.Printf("  Trust boundary: %v (%v)\n", trustBoundary.Title, trustBoundary.Id)
+		found = true
+	}
+
+	if sharedRuntime, ok := parsedModel.SharedRuntimes[risk.MostRelevantSharedRuntimeId]; ok {
+		reporter.Printf("  Shared runtime: %v (%v)\n", sharedRuntime.Title, sharedRuntime.Id)
+		found = true
+	}
+
+	if len(risk.DataBreachTechnicalAssetIDs) > 0 {
+		reporter.Printf("  Data breach technical assets: %v\n", strings.Join(risk.DataBreachTechnicalAssetIDs, ", "))
+		found = true
+	}
+
+	if !found {
+		reporter.Println("  none")
+	}
+}
+
+func explainLines(lines []string, fallback string, reporter explainRiskReporter) {
+	if len(lines) == 0 {
+		reporter.Printf("  %v\n", fallback)
+		return
+	}
+
+	for _, line := range lines {
+		reporter.Printf("  %v\n", line)
+	}
+}
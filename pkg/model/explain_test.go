@@ -0,0 +1,140 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+func TestExplainRisk(t *testing.T) {
+	result := createExplainReadResult(t)
+
+	var reporter explainReporter
+	err := result.ExplainRisk(&explainConfig{}, "some-category@exact-asset", &reporter)
+
+	assert.NoError(t, err)
+	output := reporter.String()
+	assert.Contains(t, output, "Risk: some-category@exact-asset")
+	assert.Contains(t, output, "Category: Some Category (some-category)")
+	assert.Contains(t, output, "  Technical asset: Exact Asset (exact-asset)")
+	assert.Contains(t, output, "  flagged because of the rule")
+	assert.Contains(t, output, "  3 x 2 = 6 => Elevated")
+	assert.Contains(t, output, "  Status: Mitigated")
+	assert.Contains(t, output, "  Tracked as: some-category@exact-asset")
+	assert.Contains(t, output, "  Justification: fixed")
+	assert.NotContains(t, output, "Description:")
+}
+
+func TestExplainRiskTrackedByWildcard(t *testing.T) {
+	result := createExplainReadResult(t)
+
+	var reporter explainReporter
+	err := result.ExplainRisk(&explainConfig{verbose: true}, "some-category@wildcard-asset", &reporter)
+
+	assert.NoError(t, err)
+	output := reporter.String()
+	assert.Contains(t, output, "  Status: Accepted")
+	assert.Contains(t, output, "  Tracked by wildcard: some-category@*")
+	assert.Contains(t, output, "  Description: some description")
+}
+
+func TestExplainRiskMatchingSeveralRisks(t *testing.T) {
+	result := createExplainReadResult(t)
+
+	var reporter explainReporter
+	err := result.ExplainRisk(&explainConfig{}, "some-category@*", &reporter)
+
+	assert.NoError(t, err)
+	output := reporter.String()
+	assert.Less(t, strings.Index(output, "Risk: some-category@exact-asset"), strings.Index(output, "----------------------"))
+	assert.Less(t, strings.Index(output, "----------------------"), strings.Index(output, "Risk: some-category@wildcard-asset"))
+}
+
+func TestExplainRiskNotFound(t *testing.T) {
+	result := createExplainReadResult(t)
+
+	var reporter explainReporter
+	err := result.ExplainRisk(&explainConfig{}, "other-category@exact-asset", &reporter)
+
+	assert.EqualError(t, err, `no risk found matching synthetic risk id "other-category@exact-asset"`)
+}
+
+func createExplainReadResult(t *testing.T) ReadResult {
+	newRisk := func(assetId string) *types.Risk {
+		return &types.Risk{
+			CategoryId:                   "some-category",
+			Title:                        "Some Risk at " + assetId,
+			SyntheticId:                  "some-category@" + assetId,
+			MostRelevantTechnicalAssetId: assetId,
+			ExploitationLikelihood:       types.VeryLikely,
+			ExploitationImpact:           types.MediumImpact,
+			Severity:                     types.ElevatedSeverity,
+			RiskExplanation:              []string{"flagged because of the rule"},
+		}
+	}
+
+	exactRisk, wildcardRisk := newRisk("exact-asset"), newRisk("wildcard-asset")
+	parsedModel := &types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"exact-asset":    {Id: "exact-asset", Title: "Exact Asset"},
+			"wildcard-asset": {Id: "wildcard-asset", Title: "Wildcard Asset"},
+		},
+		CustomRiskCategories: []*types.RiskCategory{
+			{ID: "some-category", Title: "Some Category", Description: "some description"},
+		},
+		GeneratedRisksBySyntheticId: map[string]*types.Risk{
+			exactRisk.SyntheticId:    exactRisk,
+			wildcardRisk.SyntheticId: wildcardRisk,
+		},
+		RiskTracking: map[string]*types.RiskTracking{
+			"some-category@exact-asset": {
+				SyntheticRiskId: "some-category@exact-asset",
+				Status:          types.Mitigated,
+				Justification:   "fixed",
+				Date:            types.Date{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+				TrackingKey:     "some-category@exact-asset",
+			},
+			"some-category@*": {
+				SyntheticRiskId: "some-category@*",
+				Status:          types.Accepted,
+				TrackingKey:     "some-category@*",
+			},
+		},
+	}
+
+	assert.NoError(t, parsedModel.ApplyWildcardRiskTrackingEvaluation(false, &explainReporter{}))
+
+	return ReadResult{ParsedModel: parsedModel}
+}
+
+type explainConfig struct {
+	verbose bool
+}
+
+func (what *explainConfig) GetVerbose() bool {
+	return what.verbose
+}
+
+// explainReporter records the explanation, and serves as progress reporter of the wildcard risk tracking
+type explainReporter struct {
+	strings.Builder
+}
+
+func (what *explainReporter) Println(a ...any) {
+	_, _ = fmt.Fprintln(what, a...)
+}
+
+func (what *explainReporter) Printf(format string, a ...any) {
+	_, _ = fmt.Fprintf(what, format, a...)
+}
+
+func (what *explainReporter) Info(_ ...any)             {}
+func (what *explainReporter) Warn(_ ...any)             {}
+func (what *explainReporter) Error(_ ...any)            {}
+func (what *explainReporter) Infof(_ string, _ ...any)  {}
+func (what *explainReporter) Warnf(_ string, _ ...any)  {}
+func (what *explainReporter) Errorf(_ string, _ ...any) {}
@@ -16,6 +16,9 @@ type technologyMapConfigReader interface {
 	GetTechnologyFilename() string
 }
 
+// ParseModel converts the model input into the model to analyze; it checks the whole model rather than stopping at the
+// first problem, so the error returned is either the types.Diagnostics of all problems found (as soon as one of them is an
+// error) or a technical one; the warnings found in a model parsed nevertheless are kept in its Warnings
 func ParseModel(config technologyMapConfigReader, modelInput *input.Model, builtinRiskRules types.RiskRules, customRiskRules types.RiskRules) (*types.Model, error) {
 	technologies := make(types.TechnologyMap)
 	technologiesLoadError := technologies.LoadWithConfig(config, "technologies.yaml")
@@ -25,9 +28,11 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 	technologies.PropagateAttributes()
 
+	diagnostics := new(diagnosticsCollector)
+
 	businessCriticality, err := types.ParseCriticality(modelInput.BusinessCriticality)
 	if err != nil {
-		return nil, fmt.Errorf("unknown 'business_criticality' value of application: %v", modelInput.BusinessCriticality)
+		diagnostics.add(types.ModelElement, "", "business_criticality", nil, "unknown 'business_criticality' value of application: %v", modelInput.BusinessCriticality)
 	}
 
 	reportDate := time.Now()
@@ -35,13 +40,14 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 		var parseError error
 		reportDate, parseError = time.Parse("2006-01-02", modelInput.Date)
 		if parseError != nil {
-			return nil, fmt.Errorf("unable to parse 'date' value of model file (expected format: '2006-01-02')")
+			diagnostics.add(types.ModelElement, "", "date", nil, "unable to parse 'date' value of model file (expected format: '2006-01-02')")
 		}
 	}
 
 	parsedModel := types.Model{
 		ThreagileVersion:               modelInput.ThreagileVersion,
 		Title:                          modelInput.Title,
+		Variant:                        modelInput.Variant,
 		Author:                         convertAuthor(modelInput.Author),
 		Contributors:                   convertAuthors(modelInput.Contributors),
 		Date:                           types.Date{Time: reportDate},
@@ -63,6 +69,17 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 		DiagramTweakSameRankAssets:                    modelInput.DiagramTweakSameRankAssets,
 	}
 
+	if modelInput.Attractiveness != nil {
+		parsedModel.Attractiveness = convertAttractiveness(modelInput.Attractiveness)
+		for i, multiplier := range modelInput.Attractiveness.TechnologyMultipliers {
+			for _, attribute := range multiplier.Attributes {
+				if !technologies.HasAttribute(attribute) {
+					diagnostics.add(types.ModelElement, "", "attractiveness", nil, "unknown technology attribute %q in technology multiplier %v of attractiveness", attribute, i+1)
+				}
+			}
+		}
+	}
+
 	parsedModel.CommunicationLinks = make(map[string]*types.CommunicationLink)
 	parsedModel.AllSupportedTags = make(map[string]bool)
 	parsedModel.IncomingTechnicalCommunicationLinksMappedByTargetId = make(map[string][]*types.CommunicationLink)
@@ -84,36 +101,31 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 		usage, err := types.ParseUsage(asset.Usage)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'usage' value of data asset %q: %v", title, asset.Usage)
+			diagnostics.add(types.DataAssetElement, id, "usage", asset.SourcePosition, "unknown 'usage' value of data asset %q: %v", title, asset.Usage)
 		}
 		quantity, err := types.ParseQuantity(asset.Quantity)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'quantity' value of data asset %q: %v", title, asset.Quantity)
+			diagnostics.add(types.DataAssetElement, id, "quantity", asset.SourcePosition, "unknown 'quantity' value of data asset %q: %v", title, asset.Quantity)
 		}
 		confidentiality, err := types.ParseConfidentiality(asset.Confidentiality)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'confidentiality' value of data asset %q: %v", title, asset.Confidentiality)
+			diagnostics.add(types.DataAssetElement, id, "confidentiality", asset.SourcePosition, "unknown 'confidentiality' value of data asset %q: %v", title, asset.Confidentiality)
 		}
 		integrity, err := types.ParseCriticality(asset.Integrity)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'integrity' value of data asset %q: %v", title, asset.Integrity)
+			diagnostics.add(types.DataAssetElement, id, "integrity", asset.SourcePosition, "unknown 'integrity' value of data asset %q: %v", title, asset.Integrity)
 		}
 		availability, err := types.ParseCriticality(asset.Availability)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'availability' value of data asset %q: %v", title, asset.Availability)
+			diagnostics.add(types.DataAssetElement, id, "availability", asset.SourcePosition, "unknown 'availability' value of data asset %q: %v", title, asset.Availability)
 		}
 
-		err = checkIdSyntax(id)
-		if err != nil {
-			return nil, err
-		}
+		diagnostics.addError(types.DataAssetElement, id, "id", asset.SourcePosition, checkIdSyntax(id))
 		if _, exists := parsedModel.DataAssets[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
-		}
-		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(asset.Tags), "data asset '"+title+"'")
-		if err != nil {
-			return nil, err
+			diagnostics.add(types.DataAssetElement, id, "id", asset.SourcePosition, "duplicate id used: %v", id)
+			continue
 		}
+		tags := diagnostics.checkTags(&parsedModel, lowerCaseAndTrim(asset.Tags), "data asset '"+title+"'", types.DataAssetElement, id, asset.SourcePosition)
 		parsedModel.DataAssets[id] = &types.DataAsset{
 			Id:                     id,
 			Title:                  title,
@@ -127,6 +139,7 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			Integrity:              integrity,
 			Availability:           availability,
 			JustificationCiaRating: fmt.Sprintf("%v", asset.JustificationCiaRating),
+			SourcePosition:         asset.SourcePosition,
 		}
 	}
 
@@ -137,9 +150,11 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 		usage, err := types.ParseUsage(asset.Usage)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'usage' value of technical asset %q: %v", title, asset.Usage)
+			diagnostics.add(types.TechnicalAssetElement, id, "usage", asset.SourcePosition, "unknown 'usage' value of technical asset %q: %v", title, asset.Usage)
 		}
 
+		diagnostics.warnDuplicates(asset.DataAssetsStored, types.TechnicalAssetElement, id, "data_assets_stored", asset.SourcePosition)
+		diagnostics.warnDuplicates(asset.DataAssetsProcessed, types.TechnicalAssetElement, id, "data_assets_processed", asset.SourcePosition)
 		var dataAssetsStored = make([]string, 0)
 		if asset.DataAssetsStored != nil {
 			for _, parsedStoredAssets := range asset.DataAssetsStored {
@@ -150,7 +165,8 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 				err := parsedModel.CheckDataAssetTargetExists(referencedAsset, fmt.Sprintf("technical asset %q", title))
 				if err != nil {
-					return nil, err
+					diagnostics.addError(types.TechnicalAssetElement, id, "data_assets_stored", asset.SourcePosition, err)
+					continue
 				}
 				dataAssetsStored = append(dataAssetsStored, referencedAsset)
 			}
@@ -166,7 +182,8 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 				err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "technical asset '"+title+"'")
 				if err != nil {
-					return nil, err
+					diagnostics.addError(types.TechnicalAssetElement, id, "data_assets_processed", asset.SourcePosition, err)
+					continue
 				}
 				dataAssetsProcessed = append(dataAssetsProcessed, referencedAsset)
 			}
@@ -174,11 +191,11 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 		technicalAssetType, err := types.ParseTechnicalAssetType(asset.Type)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'type' value of technical asset %q: %v", title, asset.Type)
+			diagnostics.add(types.TechnicalAssetElement, id, "type", asset.SourcePosition, "unknown 'type' value of technical asset %q: %v", title, asset.Type)
 		}
 		technicalAssetSize, err := types.ParseTechnicalAssetSize(asset.Size)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'size' value of technical asset %q: %v", title, asset.Size)
+			diagnostics.add(types.TechnicalAssetElement, id, "size", asset.SourcePosition, "unknown 'size' value of technical asset %q: %v", title, asset.Size)
 		}
 
 		technicalAssetTechnologies := make([]*types.Technology, 0)
@@ -190,7 +207,8 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 		for _, technologyName := range allTechnologies {
 			technicalAssetTechnology := technologies.Get(technologyName)
 			if technicalAssetTechnology == nil {
-				return nil, fmt.Errorf("unknown 'technology' value of technical asset %q: %v", title, asset.Technology)
+				diagnostics.add(types.TechnicalAssetElement, id, "technology", asset.SourcePosition, "unknown 'technology' value of technical asset %q: %v", title, technologyName)
+				continue
 			}
 
 			technicalAssetTechnologies = append(technicalAssetTechnologies, technicalAssetTechnology)
@@ -198,23 +216,23 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 		encryption, err := types.ParseEncryptionStyle(asset.Encryption)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'encryption' value of technical asset %q: %v", title, asset.Encryption)
+			diagnostics.add(types.TechnicalAssetElement, id, "encryption", asset.SourcePosition, "unknown 'encryption' value of technical asset %q: %v", title, asset.Encryption)
 		}
 		technicalAssetMachine, err := types.ParseTechnicalAssetMachine(asset.Machine)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'machine' value of technical asset %q: %v", title, asset.Machine)
+			diagnostics.add(types.TechnicalAssetElement, id, "machine", asset.SourcePosition, "unknown 'machine' value of technical asset %q: %v", title, asset.Machine)
 		}
 		confidentiality, err := types.ParseConfidentiality(asset.Confidentiality)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'confidentiality' value of technical asset %q: %v", title, asset.Confidentiality)
+			diagnostics.add(types.TechnicalAssetElement, id, "confidentiality", asset.SourcePosition, "unknown 'confidentiality' value of technical asset %q: %v", title, asset.Confidentiality)
 		}
 		integrity, err := types.ParseCriticality(asset.Integrity)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'integrity' value of technical asset %q: %v", title, asset.Integrity)
+			diagnostics.add(types.TechnicalAssetElement, id, "integrity", asset.SourcePosition, "unknown 'integrity' value of technical asset %q: %v", title, asset.Integrity)
 		}
 		availability, err := types.ParseCriticality(asset.Availability)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'availability' value of technical asset %q: %v", title, asset.Availability)
+			diagnostics.add(types.TechnicalAssetElement, id, "availability", asset.SourcePosition, "unknown 'availability' value of technical asset %q: %v", title, asset.Availability)
 		}
 
 		dataFormatsAccepted := make([]types.DataFormat, 0)
@@ -222,7 +240,8 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			for _, dataFormatName := range asset.DataFormatsAccepted {
 				dataFormat, err := types.ParseDataFormat(dataFormatName)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'data_formats_accepted' value of technical asset %q: %v", title, dataFormatName)
+					diagnostics.add(types.TechnicalAssetElement, id, "data_formats_accepted", asset.SourcePosition, "unknown 'data_formats_accepted' value of technical asset %q: %v", title, dataFormatName)
+					continue
 				}
 				dataFormatsAccepted = append(dataFormatsAccepted, dataFormat)
 			}
@@ -235,21 +254,30 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 				var dataAssetsSent []string
 				var dataAssetsReceived []string
 
+				dataFlowTitle := fmt.Sprintf("%v", commLinkTitle)
+				commLinkId, err := CreateDataFlowId(id, dataFlowTitle)
+				if err != nil {
+					diagnostics.addError(types.CommunicationLinkElement, id+">"+dataFlowTitle, "", commLink.SourcePosition, err)
+					continue
+				}
+				diagnostics.warnDuplicates(commLink.DataAssetsSent, types.CommunicationLinkElement, commLinkId, "data_assets_sent", commLink.SourcePosition)
+				diagnostics.warnDuplicates(commLink.DataAssetsReceived, types.CommunicationLinkElement, commLinkId, "data_assets_received", commLink.SourcePosition)
+
 				authentication, err := types.ParseAuthentication(commLink.Authentication)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'authentication' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authentication)
+					diagnostics.add(types.CommunicationLinkElement, commLinkId, "authentication", commLink.SourcePosition, "unknown 'authentication' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authentication)
 				}
 				authorization, err := types.ParseAuthorization(commLink.Authorization)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'authorization' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authorization)
+					diagnostics.add(types.CommunicationLinkElement, commLinkId, "authorization", commLink.SourcePosition, "unknown 'authorization' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Authorization)
 				}
 				usage, err := types.ParseUsage(commLink.Usage)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'usage' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Usage)
+					diagnostics.add(types.CommunicationLinkElement, commLinkId, "usage", commLink.SourcePosition, "unknown 'usage' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Usage)
 				}
 				protocol, err := types.ParseProtocol(commLink.Protocol)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'protocol' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Protocol)
+					diagnostics.add(types.CommunicationLinkElement, commLinkId, "protocol", commLink.SourcePosition, "unknown 'protocol' value of technical asset %q communication link %q: %v", title, commLinkTitle, commLink.Protocol)
 				}
 
 				if commLink.DataAssetsSent != nil {
@@ -258,7 +286,8 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 						if !contains(dataAssetsSent, referencedAsset) {
 							err := parsedModel.CheckDataAssetTargetExists(referencedAsset, fmt.Sprintf("communication link %q of technical asset %q", commLinkTitle, title))
 							if err != nil {
-								return nil, err
+								diagnostics.addError(types.CommunicationLinkElement, commLinkId, "data_assets_sent", commLink.SourcePosition, err)
+								continue
 							}
 
 							dataAssetsSent = append(dataAssetsSent, referencedAsset)
@@ -278,7 +307,8 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 						err := parsedModel.CheckDataAssetTargetExists(referencedAsset, "communication link '"+commLinkTitle+"' of technical asset '"+title+"'")
 						if err != nil {
-							return nil, err
+							diagnostics.addError(types.CommunicationLinkElement, commLinkId, "data_assets_received", commLink.SourcePosition, err)
+							continue
 						}
 						dataAssetsReceived = append(dataAssetsReceived, referencedAsset)
 
@@ -292,15 +322,7 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 					weight = commLink.DiagramTweakWeight
 				}
 
-				dataFlowTitle := fmt.Sprintf("%v", commLinkTitle)
-				commLinkId, err := createDataFlowId(id, dataFlowTitle)
-				if err != nil {
-					return nil, err
-				}
-				tags, err := parsedModel.CheckTags(lowerCaseAndTrim(commLink.Tags), "communication link '"+commLinkTitle+"' of technical asset '"+title+"'")
-				if err != nil {
-					return nil, err
-				}
+				tags := diagnostics.checkTags(&parsedModel, lowerCaseAndTrim(commLink.Tags), "communication link '"+commLinkTitle+"' of technical asset '"+title+"'", types.CommunicationLinkElement, commLinkId, commLink.SourcePosition)
 				commLink := &types.CommunicationLink{
 					Id:                     commLinkId,
 					SourceId:               id,
@@ -319,6 +341,7 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 					DataAssetsReceived:     dataAssetsReceived,
 					DiagramTweakWeight:     weight,
 					DiagramTweakConstraint: !commLink.DiagramTweakConstraint,
+					SourcePosition:         commLink.SourcePosition,
 				}
 				communicationLinks = append(communicationLinks, commLink)
 				// track all comm links
@@ -329,17 +352,12 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			}
 		}
 
-		err = checkIdSyntax(id)
-		if err != nil {
-			return nil, err
-		}
+		diagnostics.addError(types.TechnicalAssetElement, id, "id", asset.SourcePosition, checkIdSyntax(id))
 		if _, exists := parsedModel.TechnicalAssets[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
-		}
-		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(asset.Tags), fmt.Sprintf("technical asset %q", title))
-		if err != nil {
-			return nil, err
+			diagnostics.add(types.TechnicalAssetElement, id, "id", asset.SourcePosition, "duplicate id used: %v", id)
+			continue
 		}
+		tags := diagnostics.checkTags(&parsedModel, lowerCaseAndTrim(asset.Tags), fmt.Sprintf("technical asset %q", title), types.TechnicalAssetElement, id, asset.SourcePosition)
 		parsedModel.TechnicalAssets[id] = &types.TechnicalAsset{
 			Id:                      id,
 			Usage:                   usage,
@@ -368,6 +386,7 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			DataFormatsAccepted:     dataFormatsAccepted,
 			CommunicationLinks:      communicationLinks,
 			DiagramTweakOrder:       asset.DiagramTweakOrder,
+			SourcePosition:          asset.SourcePosition,
 		}
 	}
 
@@ -399,7 +418,8 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			}
 			targetTechAsset := parsedModel.TechnicalAssets[commLink.TargetId]
 			if targetTechAsset == nil {
-				return nil, fmt.Errorf("missing target technical asset %q for communication link: %q", commLink.TargetId, commLink.Title)
+				diagnostics.add(types.CommunicationLinkElement, commLink.Id, "target", commLink.SourcePosition, "missing target technical asset %q for communication link: %q", commLink.TargetId, commLink.Title)
+				continue
 			}
 			dataAssetsProcessedByTarget := targetTechAsset.DataAssetsProcessed
 			for _, dataAssetSent := range commLink.DataAssetsSent {
@@ -425,19 +445,19 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 		var technicalAssetsInside = make([]string, 0)
 		if boundary.TechnicalAssetsInside != nil {
-			parsedInsideAssets := boundary.TechnicalAssetsInside
-			technicalAssetsInside = make([]string, len(parsedInsideAssets))
-			for i, parsedInsideAsset := range parsedInsideAssets {
-				technicalAssetsInside[i] = strings.ToLower(parsedInsideAsset)
-				_, found := parsedModel.TechnicalAssets[technicalAssetsInside[i]]
+			for _, parsedInsideAsset := range boundary.TechnicalAssetsInside {
+				insideAsset := strings.ToLower(parsedInsideAsset)
+				_, found := parsedModel.TechnicalAssets[insideAsset]
 				if !found {
-					return nil, fmt.Errorf("missing referenced technical asset %q at trust boundary %q", technicalAssetsInside[i], title)
+					diagnostics.add(types.TrustBoundaryElement, id, "technical_assets_inside", boundary.SourcePosition, "missing referenced technical asset %q at trust boundary %q", insideAsset, title)
+					continue
 				}
-				if checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[technicalAssetsInside[i]] {
-					return nil, fmt.Errorf("referenced technical asset %q at trust boundary %q is modeled in multiple trust boundaries", technicalAssetsInside[i], title)
+				if checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[insideAsset] {
+					diagnostics.add(types.TrustBoundaryElement, id, "technical_assets_inside", boundary.SourcePosition, "referenced technical asset %q at trust boundary %q is modeled in multiple trust boundaries", insideAsset, title)
+					continue
 				}
-				checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[technicalAssetsInside[i]] = true
-				//fmt.Println("asset "+technicalAssetsInside[i]+" at i="+strconv.Itoa(i))
+				checklistToAvoidAssetBeingModeledInMultipleTrustBoundaries[insideAsset] = true
+				technicalAssetsInside = append(technicalAssetsInside, insideAsset)
 			}
 		}
 
@@ -452,12 +472,9 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 
 		trustBoundaryType, err := types.ParseTrustBoundary(boundary.Type)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'type' of trust boundary %q: %v", title, boundary.Type)
-		}
-		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(boundary.Tags), fmt.Sprintf("trust boundary %q", title))
-		if err != nil {
-			return nil, err
+			diagnostics.add(types.TrustBoundaryElement, id, "type", boundary.SourcePosition, "unknown 'type' of trust boundary %q: %v", title, boundary.Type)
 		}
+		tags := diagnostics.checkTags(&parsedModel, lowerCaseAndTrim(boundary.Tags), fmt.Sprintf("trust boundary %q", title), types.TrustBoundaryElement, id, boundary.SourcePosition)
 		trustBoundary := &types.TrustBoundary{
 			Id:                    id,
 			Title:                 title, //fmt.Sprintf("%v", boundary["title"]),
@@ -466,13 +483,12 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			Tags:                  tags,
 			TechnicalAssetsInside: technicalAssetsInside,
 			TrustBoundariesNested: trustBoundariesNested,
+			SourcePosition:        boundary.SourcePosition,
 		}
-		err = checkIdSyntax(id)
-		if err != nil {
-			return nil, err
-		}
+		diagnostics.addError(types.TrustBoundaryElement, id, "id", boundary.SourcePosition, checkIdSyntax(id))
 		if _, exists := parsedModel.TrustBoundaries[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			diagnostics.add(types.TrustBoundaryElement, id, "id", boundary.SourcePosition, "duplicate id used: %v", id)
+			continue
 		}
 		parsedModel.TrustBoundaries[id] = trustBoundary
 		for _, technicalAsset := range trustBoundary.TechnicalAssetsInside {
@@ -480,9 +496,12 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			//fmt.Println("Asset "+technicalAsset+" is directly in trust boundary "+trustBoundary.ID)
 		}
 	}
-	err = parsedModel.CheckNestedTrustBoundariesExisting()
-	if err != nil {
-		return nil, err
+	for _, trustBoundary := range parsedModel.TrustBoundaries {
+		for _, nestedId := range trustBoundary.TrustBoundariesNested {
+			if _, ok := parsedModel.TrustBoundaries[nestedId]; !ok {
+				diagnostics.add(types.TrustBoundaryElement, trustBoundary.Id, "trust_boundaries_nested", trustBoundary.SourcePosition, "missing referenced nested trust boundary: %v", nestedId)
+			}
+		}
 	}
 
 	// Shared Runtime ===============================================================================
@@ -490,36 +509,36 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 	for title, inputRuntime := range modelInput.SharedRuntimes {
 		id := fmt.Sprintf("%v", inputRuntime.ID)
 
+		diagnostics.warnDuplicates(inputRuntime.TechnicalAssetsRunning, types.SharedRuntimeElement, id, "technical_assets_running", inputRuntime.SourcePosition)
 		var technicalAssetsRunning = make([]string, 0)
 		if inputRuntime.TechnicalAssetsRunning != nil {
 			parsedRunningAssets := inputRuntime.TechnicalAssetsRunning
-			technicalAssetsRunning = make([]string, len(parsedRunningAssets))
-			for i, parsedRunningAsset := range parsedRunningAssets {
+			for _, parsedRunningAsset := range parsedRunningAssets {
 				assetId := fmt.Sprintf("%v", parsedRunningAsset)
+				if contains(technicalAssetsRunning, assetId) {
+					continue
+				}
 				err := parsedModel.CheckTechnicalAssetExists(assetId, "shared runtime '"+title+"'", false)
 				if err != nil {
-					return nil, err
+					diagnostics.addError(types.SharedRuntimeElement, id, "technical_assets_running", inputRuntime.SourcePosition, err)
+					continue
 				}
-				technicalAssetsRunning[i] = assetId
+				technicalAssetsRunning = append(technicalAssetsRunning, assetId)
 			}
 		}
-		tags, err := parsedModel.CheckTags(lowerCaseAndTrim(inputRuntime.Tags), "shared runtime '"+title+"'")
-		if err != nil {
-			return nil, err
-		}
+		tags := diagnostics.checkTags(&parsedModel, lowerCaseAndTrim(inputRuntime.Tags), "shared runtime '"+title+"'", types.SharedRuntimeElement, id, inputRuntime.SourcePosition)
 		sharedRuntime := &types.SharedRuntime{
 			Id:                     id,
 			Title:                  title, //fmt.Sprintf("%v", boundary["title"]),
 			Description:            withDefault(fmt.Sprintf("%v", inputRuntime.Description), title),
 			Tags:                   tags,
 			TechnicalAssetsRunning: technicalAssetsRunning,
+			SourcePosition:         inputRuntime.SourcePosition,
 		}
-		err = checkIdSyntax(id)
-		if err != nil {
-			return nil, err
-		}
+		diagnostics.addError(types.SharedRuntimeElement, id, "id", inputRuntime.SourcePosition, checkIdSyntax(id))
 		if _, exists := parsedModel.SharedRuntimes[id]; exists {
-			return nil, fmt.Errorf("duplicate id used: %v", id)
+			diagnostics.add(types.SharedRuntimeElement, id, "id", inputRuntime.SourcePosition, "duplicate id used: %v", id)
+			continue
 		}
 		parsedModel.SharedRuntimes[id] = sharedRuntime
 	}
@@ -536,12 +555,12 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 	for _, customRiskCategoryCategory := range modelInput.CustomRiskCategories {
 		function, err := types.ParseRiskFunction(customRiskCategoryCategory.Function)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'function' value of individual risk category %q: %v", customRiskCategoryCategory.Title, customRiskCategoryCategory.Function)
+			diagnostics.add(types.CustomRiskCategoryElement, customRiskCategoryCategory.ID, "function", customRiskCategoryCategory.SourcePosition, "unknown 'function' value of individual risk category %q: %v", customRiskCategoryCategory.Title, customRiskCategoryCategory.Function)
 		}
 
 		stride, err := types.ParseSTRIDE(customRiskCategoryCategory.STRIDE)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'stride' value of individual risk category  %q: %v", customRiskCategoryCategory.Title, customRiskCategoryCategory.STRIDE)
+			diagnostics.add(types.CustomRiskCategoryElement, customRiskCategoryCategory.ID, "stride", customRiskCategoryCategory.SourcePosition, "unknown 'stride' value of individual risk category  %q: %v", customRiskCategoryCategory.Title, customRiskCategoryCategory.STRIDE)
 		}
 
 		cat := &types.RiskCategory{
@@ -567,13 +586,11 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			cat.Description = customRiskCategoryCategory.Title
 		}
 
-		err = checkIdSyntax(customRiskCategoryCategory.ID)
-		if err != nil {
-			return nil, err
-		}
+		diagnostics.addError(types.CustomRiskCategoryElement, customRiskCategoryCategory.ID, "id", customRiskCategoryCategory.SourcePosition, checkIdSyntax(customRiskCategoryCategory.ID))
 
 		if !parsedModel.CustomRiskCategories.Add(cat) {
-			return nil, fmt.Errorf("duplicate id used: %v", customRiskCategoryCategory.ID)
+			diagnostics.add(types.CustomRiskCategoryElement, customRiskCategoryCategory.ID, "id", customRiskCategoryCategory.SourcePosition, "duplicate id used: %v", customRiskCategoryCategory.ID)
+			continue
 		}
 
 		// NOW THE INDIVIDUAL RISK INSTANCES:
@@ -585,22 +602,22 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 				var dataBreachTechnicalAssetIDs []string
 				severity, err := types.ParseRiskSeverity(individualRiskInstance.Severity)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'severity' value of individual risk instance %q: %v", title, individualRiskInstance.Severity)
+					diagnostics.add(types.IndividualRiskElement, title, "severity", individualRiskInstance.SourcePosition, "unknown 'severity' value of individual risk instance %q: %v", title, individualRiskInstance.Severity)
 				}
 				exploitationLikelihood, err := types.ParseRiskExploitationLikelihood(individualRiskInstance.ExploitationLikelihood)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'exploitation_likelihood' value of individual risk instance %q: %v", title, individualRiskInstance.ExploitationLikelihood)
+					diagnostics.add(types.IndividualRiskElement, title, "exploitation_likelihood", individualRiskInstance.SourcePosition, "unknown 'exploitation_likelihood' value of individual risk instance %q: %v", title, individualRiskInstance.ExploitationLikelihood)
 				}
 				exploitationImpact, err := types.ParseRiskExploitationImpact(individualRiskInstance.ExploitationImpact)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'exploitation_impact' value of individual risk instance %q: %v", title, individualRiskInstance.ExploitationImpact)
+					diagnostics.add(types.IndividualRiskElement, title, "exploitation_impact", individualRiskInstance.SourcePosition, "unknown 'exploitation_impact' value of individual risk instance %q: %v", title, individualRiskInstance.ExploitationImpact)
 				}
 
 				if len(individualRiskInstance.MostRelevantDataAsset) > 0 {
 					mostRelevantDataAssetId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantDataAsset)
 					err := parsedModel.CheckDataAssetTargetExists(mostRelevantDataAssetId, fmt.Sprintf("individual risk %q", title))
 					if err != nil {
-						return nil, err
+						diagnostics.addError(types.IndividualRiskElement, title, "most_relevant_data_asset", individualRiskInstance.SourcePosition, err)
 					}
 				}
 
@@ -608,7 +625,7 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 					mostRelevantTechnicalAssetId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantTechnicalAsset)
 					err := parsedModel.CheckTechnicalAssetExists(mostRelevantTechnicalAssetId, fmt.Sprintf("individual risk %q", title), false)
 					if err != nil {
-						return nil, err
+						diagnostics.addError(types.IndividualRiskElement, title, "most_relevant_technical_asset", individualRiskInstance.SourcePosition, err)
 					}
 				}
 
@@ -616,7 +633,7 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 					mostRelevantCommunicationLinkId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantCommunicationLink)
 					err := parsedModel.CheckCommunicationLinkExists(mostRelevantCommunicationLinkId, fmt.Sprintf("individual risk %q", title))
 					if err != nil {
-						return nil, err
+						diagnostics.addError(types.IndividualRiskElement, title, "most_relevant_communication_link", individualRiskInstance.SourcePosition, err)
 					}
 				}
 
@@ -624,7 +641,7 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 					mostRelevantTrustBoundaryId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantTrustBoundary)
 					err := parsedModel.CheckTrustBoundaryExists(mostRelevantTrustBoundaryId, fmt.Sprintf("individual risk %q", title))
 					if err != nil {
-						return nil, err
+						diagnostics.addError(types.IndividualRiskElement, title, "most_relevant_trust_boundary", individualRiskInstance.SourcePosition, err)
 					}
 				}
 
@@ -632,13 +649,13 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 					mostRelevantSharedRuntimeId = fmt.Sprintf("%v", individualRiskInstance.MostRelevantSharedRuntime)
 					err := parsedModel.CheckSharedRuntimeExists(mostRelevantSharedRuntimeId, fmt.Sprintf("individual risk %q", title))
 					if err != nil {
-						return nil, err
+						diagnostics.addError(types.IndividualRiskElement, title, "most_relevant_shared_runtime", individualRiskInstance.SourcePosition, err)
 					}
 				}
 
 				dataBreachProbability, err = types.ParseDataBreachProbability(individualRiskInstance.DataBreachProbability)
 				if err != nil {
-					return nil, fmt.Errorf("unknown 'data_breach_probability' value of individual risk instance %q: %v", title, individualRiskInstance.DataBreachProbability)
+					diagnostics.add(types.IndividualRiskElement, title, "data_breach_probability", individualRiskInstance.SourcePosition, "unknown 'data_breach_probability' value of individual risk instance %q: %v", title, individualRiskInstance.DataBreachProbability)
 				}
 
 				if individualRiskInstance.DataBreachTechnicalAssets != nil {
@@ -647,7 +664,7 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 						assetId := fmt.Sprintf("%v", parsedReferencedAsset)
 						err := parsedModel.CheckTechnicalAssetExists(assetId, fmt.Sprintf("data breach technical assets of individual risk %q", title), false)
 						if err != nil {
-							return nil, err
+							diagnostics.addError(types.IndividualRiskElement, title, "data_breach_technical_assets", individualRiskInstance.SourcePosition, err)
 						}
 						dataBreachTechnicalAssetIDs[i] = assetId
 					}
@@ -667,6 +684,7 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 					MostRelevantSharedRuntimeId:     mostRelevantSharedRuntimeId,
 					DataBreachProbability:           dataBreachProbability,
 					DataBreachTechnicalAssetIDs:     dataBreachTechnicalAssetIDs,
+					SourcePosition:                  individualRiskInstance.SourcePosition,
 				})
 			}
 		}
@@ -683,13 +701,13 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			var parseError error
 			date, parseError = time.Parse("2006-01-02", riskTracking.Date)
 			if parseError != nil {
-				return nil, fmt.Errorf("unable to parse 'date' of risk tracking %q: %v", syntheticRiskId, riskTracking.Date)
+				diagnostics.add(types.RiskTrackingElement, syntheticRiskId, "date", riskTracking.SourcePosition, "unable to parse 'date' of risk tracking %q: %v", syntheticRiskId, riskTracking.Date)
 			}
 		}
 
 		status, err := types.ParseRiskStatus(riskTracking.Status)
 		if err != nil {
-			return nil, fmt.Errorf("unknown 'status' value of risk tracking %q: %v", syntheticRiskId, riskTracking.Status)
+			diagnostics.add(types.RiskTrackingElement, syntheticRiskId, "status", riskTracking.SourcePosition, "unknown 'status' value of risk tracking %q: %v", syntheticRiskId, riskTracking.Status)
 		}
 
 		tracking := &types.RiskTracking{
@@ -699,20 +717,19 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 			Ticket:          ticket,
 			Date:            types.Date{Time: date},
 			Status:          status,
+			SourcePosition:  riskTracking.SourcePosition,
+			TrackingKey:     syntheticRiskId,
 		}
 
 		parsedModel.RiskTracking[syntheticRiskId] = tracking
 	}
 
-	// ====================== model consistency check (linking)
-	for _, technicalAsset := range parsedModel.TechnicalAssets {
-		for _, commLink := range technicalAsset.CommunicationLinks {
-			err := parsedModel.CheckTechnicalAssetExists(commLink.TargetId, "communication link '"+commLink.Title+"' of technical asset '"+technicalAsset.Title+"'", false)
-			if err != nil {
-				return nil, err
-			}
-		}
+	// the targets of the communication links are checked along with the data assets they implicitly process (see above)
+	diagnostics.diagnostics.Sort()
+	if diagnostics.diagnostics.Count(types.DiagnosticError) > 0 {
+		return nil, diagnostics.diagnostics
 	}
+	parsedModel.Warnings = diagnostics.diagnostics
 
 	/*
 		data, _ := json.MarshalIndent(parsedModel, "", "  ")
@@ -736,6 +753,62 @@ func ParseModel(config technologyMapConfigReader, modelInput *input.Model, built
 	return &parsedModel, nil
 }
 
+// diagnosticsCollector collects the problems found while parsing the model, for all of them to be reported at once
+type diagnosticsCollector struct {
+	diagnostics types.Diagnostics
+}
+
+func (what *diagnosticsCollector) add(elementKind string, elementId string, field string, position *types.SourcePosition, format string, args ...any) {
+	what.addWithSeverity(types.DiagnosticError, elementKind, elementId, field, position, format, args...)
+}
+
+// warn reports a problem the model can be parsed despite, so it doesn't fail the parsing
+func (what *diagnosticsCollector) warn(elementKind string, elementId string, field string, position *types.SourcePosition, format string, args ...any) {
+	what.addWithSeverity(types.DiagnosticWarning, elementKind, elementId, field, position, format, args...)
+}
+
+func (what *diagnosticsCollector) addWithSeverity(severity types.DiagnosticSeverity, elementKind string, elementId string, field string, position *types.SourcePosition, format string, args ...any) {
+	what.diagnostics = append(what.diagnostics, &types.Diagnostic{
+		Severity:       severity,
+		ElementKind:    elementKind,
+		ElementId:      elementId,
+		Field:          field,
+		Message:        fmt.Sprintf(format, args...),
+		SourcePosition: position,
+	})
+}
+
+func (what *diagnosticsCollector) addError(elementKind string, elementId string, field string, position *types.SourcePosition, err error) {
+	if err != nil {
+		what.add(elementKind, elementId, field, position, "%v", err)
+	}
+}
+
+// warnDuplicates reports the references listed more than once in the field, which count only once
+func (what *diagnosticsCollector) warnDuplicates(references []string, elementKind string, elementId string, field string, position *types.SourcePosition) {
+	listed := make(map[string]int)
+	for _, reference := range references {
+		listed[reference]++
+		if listed[reference] == 2 {
+			what.warn(elementKind, elementId, field, position, "%q is listed more than once in '%v'", reference, field)
+		}
+	}
+}
+
+// checkTags returns the tags of the element found in the overall tag list, reporting the others
+func (what *diagnosticsCollector) checkTags(parsedModel *types.Model, tags []string, where string, elementKind string, elementId string, position *types.SourcePosition) []string {
+	tagsUsed := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		err := parsedModel.CheckTagExists(tag, where)
+		if err != nil {
+			what.addError(elementKind, elementId, "tags", position, err)
+			continue
+		}
+		tagsUsed = append(tagsUsed, tag)
+	}
+	return tagsUsed
+}
+
 func convertAuthor(author input.Author) *types.Author {
 	return &types.Author{
 		Name:     author.Name,
@@ -752,6 +825,35 @@ func convertAuthors(authors []input.Author) []*types.Author {
 	return result
 }
 
+func convertAttractiveness(attractiveness *input.Attractiveness) *types.Attractiveness {
+	result := &types.Attractiveness{
+		Quantity:              attractiveness.Quantity,
+		Confidentiality:       convertAttackerFocus(attractiveness.Confidentiality),
+		Integrity:             convertAttackerFocus(attractiveness.Integrity),
+		Availability:          convertAttackerFocus(attractiveness.Availability),
+		TechnologyMultipliers: make([]types.TechnologyMultiplier, len(attractiveness.TechnologyMultipliers)),
+		DatastoreMultiplier:   attractiveness.DatastoreMultiplier,
+		MultiTenantMultiplier: attractiveness.MultiTenantMultiplier,
+	}
+
+	for i, multiplier := range attractiveness.TechnologyMultipliers {
+		result.TechnologyMultipliers[i] = types.TechnologyMultiplier{
+			Attributes: multiplier.Attributes,
+			Multiplier: multiplier.Multiplier,
+		}
+	}
+
+	return result
+}
+
+func convertAttackerFocus(focus input.AttackerFocus) types.AttackerFocus {
+	return types.AttackerFocus{
+		Asset:                 focus.Asset,
+		ProcessedOrStoredData: focus.ProcessedOrStoredData,
+		TransferredData:       focus.TransferredData,
+	}
+}
+
 func checkIdSyntax(id string) error {
 	validIdSyntax := regexp.MustCompile(`^[a-zA-Z0-9\-]+$`)
 	if !validIdSyntax.MatchString(id) {
@@ -760,7 +862,8 @@ func checkIdSyntax(id string) error {
 	return nil
 }
 
-func createDataFlowId(sourceAssetId, title string) (string, error) {
+// CreateDataFlowId derives the id of a communication link from the id of its source asset and its title
+func CreateDataFlowId(sourceAssetId, title string) (string, error) {
 	reg, err := regexp.Compile("[^A-Za-z0-9]+")
 	if err != nil {
 		return "", err
@@ -5,6 +5,7 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package model
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/google/uuid"
@@ -149,6 +150,174 @@ func TestInferAvailability_ExpectHighestAvailability(t *testing.T) {
 	assert.Equal(t, types.Operational, parsedModel.TechnicalAssets[taWithArchiveAvailabilityDataAsset.ID].Availability)
 }
 
+func TestParseModelKeepsSourcePositions(t *testing.T) {
+	ta := make(map[string]input.TechnicalAsset)
+	da := make(map[string]input.DataAsset)
+
+	dataAsset := createDataAsset(types.Internal, types.Operational, types.Operational)
+	dataAsset.SourcePosition = &types.SourcePosition{File: "include.yaml", Line: 3, Column: 3}
+	da[dataAsset.ID] = dataAsset
+
+	parsedModel, err := ParseModel(&mockConfig{}, createInputModel(ta, da), make(types.RiskRules), make(types.RiskRules))
+
+	assert.NoError(t, err)
+	assert.Equal(t, dataAsset.SourcePosition, parsedModel.DataAssets[dataAsset.ID].SourcePosition)
+}
+
+func TestParseModelErrorHasSourcePosition(t *testing.T) {
+	ta := make(map[string]input.TechnicalAsset)
+	da := make(map[string]input.DataAsset)
+
+	technicalAsset := createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	technicalAsset.Size = "huge"
+	technicalAsset.SourcePosition = &types.SourcePosition{File: "threagile.yaml", Line: 42, Column: 3}
+	ta[technicalAsset.ID] = technicalAsset
+
+	_, err := ParseModel(&mockConfig{}, createInputModel(ta, da), make(types.RiskRules), make(types.RiskRules))
+
+	var sourceError *types.SourceError
+	assert.ErrorAs(t, err, &sourceError)
+	assert.Equal(t, *technicalAsset.SourcePosition, sourceError.Position)
+	assert.Contains(t, err.Error(), "threagile.yaml:42:3: unknown 'size' value")
+}
+
+func TestParseModelCollectsAllErrors(t *testing.T) {
+	ta := make(map[string]input.TechnicalAsset)
+	da := make(map[string]input.DataAsset)
+
+	dataAsset := createDataAsset(types.Internal, types.Operational, types.Operational)
+	dataAsset.Quantity = "plenty"
+	dataAsset.SourcePosition = &types.SourcePosition{File: "threagile.yaml", Line: 10, Column: 3}
+	da[dataAsset.ID] = dataAsset
+
+	technicalAsset := createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	technicalAsset.Size = "huge"
+	technicalAsset.DataAssetsStored = []string{dataAsset.ID, "missing-data-asset"}
+	technicalAsset.CommunicationLinks = map[string]input.CommunicationLink{
+		"Some Link": {
+			Target:         "missing-target",
+			Protocol:       "https",
+			Authentication: "none",
+			Authorization:  "none",
+			Usage:          "business",
+			SourcePosition: &types.SourcePosition{File: "threagile.yaml", Line: 30, Column: 7},
+		},
+	}
+	technicalAsset.SourcePosition = &types.SourcePosition{File: "threagile.yaml", Line: 20, Column: 3}
+	ta[technicalAsset.ID] = technicalAsset
+
+	modelInput := createInputModel(ta, da)
+	modelInput.TrustBoundaries = map[string]input.TrustBoundary{
+		"Some Boundary": {
+			ID:                    "some-boundary",
+			Type:                  "network-on-prem",
+			TechnicalAssetsInside: []string{technicalAsset.ID},
+			TrustBoundariesNested: []string{"missing-boundary"},
+			SourcePosition:        &types.SourcePosition{File: "threagile.yaml", Line: 40, Column: 3},
+		},
+	}
+
+	_, err := ParseModel(&mockConfig{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+
+	var diagnostics types.Diagnostics
+	assert.ErrorAs(t, err, &diagnostics)
+	assert.Equal(t, 5, diagnostics.Count(types.DiagnosticError))
+
+	fields := make([]string, 0)
+	for _, diagnostic := range diagnostics {
+		fields = append(fields, diagnostic.ElementKind+"."+diagnostic.Field)
+	}
+	// sorted by position, the valid data asset reference of the technical asset leaves no trace
+	assert.Equal(t, []string{
+		"data_asset.quantity",
+		"technical_asset.data_assets_stored",
+		"technical_asset.size",
+		"communication_link.target",
+		"trust_boundary.trust_boundaries_nested",
+	}, fields)
+	assert.Equal(t, technicalAsset.ID+">some-link", diagnostics[3].ElementId)
+}
+
+func TestParseModelWarnings(t *testing.T) {
+	ta := make(map[string]input.TechnicalAsset)
+	da := make(map[string]input.DataAsset)
+
+	dataAsset := createDataAsset(types.Internal, types.Operational, types.Operational)
+	da[dataAsset.ID] = dataAsset
+
+	// listed once as stored and once as processed is fine, listed twice in the same list is not
+	technicalAsset := createTechnicalAsset(types.Internal, types.Operational, types.Operational)
+	technicalAsset.DataAssetsStored = []string{dataAsset.ID}
+	technicalAsset.DataAssetsProcessed = []string{dataAsset.ID, dataAsset.ID, dataAsset.ID}
+	technicalAsset.SourcePosition = &types.SourcePosition{File: "threagile.yaml", Line: 20, Column: 3}
+	ta[technicalAsset.ID] = technicalAsset
+
+	modelInput := createInputModel(ta, da)
+	modelInput.SharedRuntimes = map[string]input.SharedRuntime{
+		"Some Runtime": {
+			ID:                     "some-runtime",
+			TechnicalAssetsRunning: []string{technicalAsset.ID, technicalAsset.ID},
+			SourcePosition:         &types.SourcePosition{File: "threagile.yaml", Line: 40, Column: 3},
+		},
+	}
+
+	parsedModel, err := ParseModel(&mockConfig{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{dataAsset.ID}, parsedModel.TechnicalAssets[technicalAsset.ID].DataAssetsProcessed)
+	assert.Equal(t, []string{technicalAsset.ID}, parsedModel.SharedRuntimes["some-runtime"].TechnicalAssetsRunning)
+	if assert.Len(t, parsedModel.Warnings, 2) {
+		assert.Equal(t, types.DiagnosticWarning, parsedModel.Warnings[0].Severity)
+		assert.Equal(t, "data_assets_processed", parsedModel.Warnings[0].Field)
+		assert.Equal(t, fmt.Sprintf("threagile.yaml:20:3: %q is listed more than once in 'data_assets_processed'", dataAsset.ID), parsedModel.Warnings[0].Error())
+		assert.Equal(t, types.SharedRuntimeElement, parsedModel.Warnings[1].ElementKind)
+		assert.Equal(t, "technical_assets_running", parsedModel.Warnings[1].Field)
+	}
+
+	// along with an error, the warnings are reported as well
+	technicalAsset.Size = "huge"
+	ta[technicalAsset.ID] = technicalAsset
+
+	_, err = ParseModel(&mockConfig{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+
+	var diagnostics types.Diagnostics
+	assert.ErrorAs(t, err, &diagnostics)
+	assert.Equal(t, 1, diagnostics.Count(types.DiagnosticError))
+	assert.Equal(t, 2, diagnostics.Count(types.DiagnosticWarning))
+}
+
+func TestParseModelUnknownAttractivenessAttribute(t *testing.T) {
+	modelInput := createInputModel(make(map[string]input.TechnicalAsset), make(map[string]input.DataAsset))
+	modelInput.Attractiveness = &input.Attractiveness{
+		TechnologyMultipliers: []input.TechnologyMultiplier{
+			{Attributes: []string{"vault", "high_value_target"}, Multiplier: 2},
+			{Attributes: []string{"load-balancr"}, Multiplier: 0.5},
+		},
+	}
+
+	_, err := ParseModel(&mockConfig{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+
+	var diagnostics types.Diagnostics
+	assert.ErrorAs(t, err, &diagnostics)
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, "attractiveness", diagnostics[0].Field)
+	assert.Equal(t, `unknown technology attribute "load-balancr" in technology multiplier 2 of attractiveness`, diagnostics[0].Message)
+}
+
+func TestDefaultAttractivenessAttributesAreKnown(t *testing.T) {
+	technologies := make(types.TechnologyMap)
+	assert.NoError(t, technologies.LoadDefault())
+	technologies.PropagateAttributes()
+
+	for _, multiplier := range types.DefaultAttractiveness().TechnologyMultipliers {
+		for _, attribute := range multiplier.Attributes {
+			assert.True(t, technologies.HasAttribute(attribute), attribute)
+		}
+	}
+}
+
 func createInputModel(technicalAssets map[string]input.TechnicalAsset, dataAssets map[string]input.DataAsset) *input.Model {
 	return &input.Model{
 		TechnicalAssets: technicalAssets,
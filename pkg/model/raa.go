@@ -6,13 +6,20 @@ import (
 	"github.com/threagile/threagile/pkg/types"
 )
 
-func applyRAA(input *types.Model, progressReporter types.ProgressReporter) string {
+func applyRAA(input *types.Model, attractiveness types.Attractiveness, progressReporter types.ProgressReporter) string {
 	progressReporter.Infof("Applying RAA calculation")
 
+	profile := types.DefaultAttractiveness().Merge(attractiveness)
+	if input.Attractiveness != nil {
+		profile = profile.Merge(*input.Attractiveness)
+	}
+
+	calculator := newRAACalculator(input, profile)
 	for techAssetID, techAsset := range input.TechnicalAssets {
-		aa := calculateAttackerAttractiveness(input, techAsset)
-		aa += calculatePivotingNeighbourEffectAdjustment(input, techAsset)
-		techAsset.RAA = calculateRelativeAttackerAttractiveness(input, aa)
+		breakdown := calculator.breakdowns[techAssetID]
+		breakdown.PivotingAdjustment = calculator.calculatePivotingNeighbourEffectAdjustment(techAsset)
+		techAsset.RAA = calculator.calculateRelativeAttackerAttractiveness(breakdown.Score + breakdown.PivotingAdjustment)
+		techAsset.RAABreakdown = breakdown
 		input.TechnicalAssets[techAssetID] = techAsset
 	}
 	// return intro text (for reporting etc., can be short summary-like)
@@ -25,36 +32,54 @@ func applyRAA(input *types.Model, progressReporter types.ProgressReporter) strin
 		"attacker-attractive technical assets:"
 }
 
-// set the concrete value in relation to the minimum and maximum of all
-func calculateRelativeAttackerAttractiveness(input *types.Model, attractiveness float64) float64 {
-	var attackerAttractivenessMinimum, attackerAttractivenessMaximum, spread float64 = 0, 0, 0
-	if attackerAttractivenessMinimum == 0 || attackerAttractivenessMaximum == 0 {
-		attackerAttractivenessMinimum, attackerAttractivenessMaximum = 9223372036854775807, -9223372036854775808
-		// determine (only one time required) the min/max of all
-		// first create them in memory (see the link replacement below for nested trust boundaries) - otherwise in Go ranging over map is random order
-		// range over them in sorted (hence re-producible) way:
-		keys := make([]string, 0)
-		for k := range input.TechnicalAssets {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for _, key := range keys {
-			techAsset := input.TechnicalAssets[key]
-			if calculateAttackerAttractiveness(input, techAsset) > attackerAttractivenessMaximum {
-				attackerAttractivenessMaximum = calculateAttackerAttractiveness(input, techAsset)
-			}
-			if calculateAttackerAttractiveness(input, techAsset) < attackerAttractivenessMinimum {
-				attackerAttractivenessMinimum = calculateAttackerAttractiveness(input, techAsset)
-			}
+type raaCalculator struct {
+	input      *types.Model
+	profile    types.Attractiveness
+	breakdowns map[string]*types.RAABreakdown
+	minimum    float64
+	spread     float64
+}
+
+func newRAACalculator(input *types.Model, profile types.Attractiveness) *raaCalculator {
+	calculator := &raaCalculator{
+		input:      input,
+		profile:    profile,
+		breakdowns: make(map[string]*types.RAABreakdown),
+	}
+
+	// determine (only one time required) the min/max of all
+	// range over them in sorted (hence re-producible) way:
+	keys := make([]string, 0)
+	for k := range input.TechnicalAssets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var attackerAttractivenessMinimum, attackerAttractivenessMaximum float64 = 9223372036854775807, -9223372036854775808
+	for _, key := range keys {
+		breakdown := calculator.calculateAttackerAttractiveness(input.TechnicalAssets[key])
+		calculator.breakdowns[key] = breakdown
+		if breakdown.Score > attackerAttractivenessMaximum {
+			attackerAttractivenessMaximum = breakdown.Score
 		}
-		if !(attackerAttractivenessMinimum < attackerAttractivenessMaximum) {
-			attackerAttractivenessMaximum = attackerAttractivenessMinimum + 1
+		if breakdown.Score < attackerAttractivenessMinimum {
+			attackerAttractivenessMinimum = breakdown.Score
 		}
-		spread = attackerAttractivenessMaximum - attackerAttractivenessMinimum
 	}
+	if !(attackerAttractivenessMinimum < attackerAttractivenessMaximum) {
+		attackerAttractivenessMaximum = attackerAttractivenessMinimum + 1
+	}
+
+	calculator.minimum = attackerAttractivenessMinimum
+	calculator.spread = attackerAttractivenessMaximum - attackerAttractivenessMinimum
+	return calculator
+}
+
+// set the concrete value in relation to the minimum and maximum of all
+func (what *raaCalculator) calculateRelativeAttackerAttractiveness(attractiveness float64) float64 {
 	// calculate the percent value of the value within the defined min/max range
-	value := attractiveness - attackerAttractivenessMinimum
-	percent := value / spread * 100
+	value := attractiveness - what.minimum
+	percent := value / what.spread * 100
 	if percent <= 0 {
 		percent = 1 // since 0 suggests no attacks at all
 	}
@@ -62,15 +87,18 @@ func calculateRelativeAttackerAttractiveness(input *types.Model, attractiveness
 }
 
 // increase the RAA (relative attacker attractiveness) by one third (1/3) of the delta to the highest outgoing neighbour (if positive delta)
-func calculatePivotingNeighbourEffectAdjustment(input *types.Model, techAsset *types.TechnicalAsset) float64 {
+func (what *raaCalculator) calculatePivotingNeighbourEffectAdjustment(techAsset *types.TechnicalAsset) float64 {
 	if techAsset.OutOfScope {
 		return 0
 	}
 	adjustment := 0.0
 	for _, commLink := range techAsset.CommunicationLinks {
-		outgoingNeighbour := input.TechnicalAssets[commLink.TargetId]
+		outgoingNeighbour := what.breakdowns[commLink.TargetId]
+		if outgoingNeighbour == nil {
+			continue
+		}
 		//if outgoingNeighbour.getTrustBoundary() == techAsset.getTrustBoundary() { // same trust boundary
-		delta := calculateRelativeAttackerAttractiveness(input, calculateAttackerAttractiveness(input, outgoingNeighbour)) - calculateRelativeAttackerAttractiveness(input, calculateAttackerAttractiveness(input, techAsset))
+		delta := what.calculateRelativeAttackerAttractiveness(outgoingNeighbour.Score) - what.calculateRelativeAttackerAttractiveness(what.breakdowns[techAsset.Id].Score)
 		if delta > 0 {
 			potentialIncrease := delta / 3
 			//fmt.Println("Positive delta from", techAsset.ID, "to", outgoingNeighbour.ID, "is", delta, "yields to pivoting neighbour effect of an increase of", potentialIncrease)
@@ -85,63 +113,50 @@ func calculatePivotingNeighbourEffectAdjustment(input *types.Model, techAsset *t
 
 // The sum of all CIAs of the asset itself (fibonacci scale) plus the sum of the comm-links' transferred CIAs
 // Multiplied by the quantity values of the data asset for C and I (not A)
-func calculateAttackerAttractiveness(input *types.Model, techAsset *types.TechnicalAsset) float64 {
-	if techAsset.OutOfScope {
-		return 0
+func (what *raaCalculator) calculateAttackerAttractiveness(techAsset *types.TechnicalAsset) *types.RAABreakdown {
+	breakdown := &types.RAABreakdown{
+		TechnologyMultiplier:  1,
+		MultiTenantMultiplier: 1,
 	}
-	var score = 0.0
-	score += techAsset.Confidentiality.AttackerAttractivenessForAsset()
-	score += techAsset.Integrity.AttackerAttractivenessForAsset()
-	score += techAsset.Availability.AttackerAttractivenessForAsset()
-	for _, dataAssetProcessed := range techAsset.DataAssetsProcessed {
-		dataAsset := input.DataAssets[dataAssetProcessed]
-		score += dataAsset.Confidentiality.AttackerAttractivenessForProcessedOrStoredData() * dataAsset.Quantity.QuantityFactor()
-		score += dataAsset.Integrity.AttackerAttractivenessForProcessedOrStoredData() * dataAsset.Quantity.QuantityFactor()
-		score += dataAsset.Availability.AttackerAttractivenessForProcessedOrStoredData()
+
+	if techAsset.OutOfScope {
+		return breakdown
 	}
+
+	profile := what.profile
+	breakdown.AssetScore += types.Fibonacci(profile.Confidentiality.Asset + int(techAsset.Confidentiality))
+	breakdown.AssetScore += types.Fibonacci(profile.Integrity.Asset + int(techAsset.Integrity))
+	breakdown.AssetScore += types.Fibonacci(profile.Availability.Asset + int(techAsset.Availability))
+
 	// NOTE: Assuming all stored data is also processed, this effectively scores stored data twice
-	for _, dataAssetStored := range techAsset.DataAssetsStored {
-		dataAsset := input.DataAssets[dataAssetStored]
-		score += dataAsset.Confidentiality.AttackerAttractivenessForProcessedOrStoredData() * dataAsset.Quantity.QuantityFactor()
-		score += dataAsset.Integrity.AttackerAttractivenessForProcessedOrStoredData() * dataAsset.Quantity.QuantityFactor()
-		score += dataAsset.Availability.AttackerAttractivenessForProcessedOrStoredData()
+	for _, dataAssetIDs := range [][]string{techAsset.DataAssetsProcessed, techAsset.DataAssetsStored} {
+		for _, dataAssetID := range dataAssetIDs {
+			dataAsset := what.input.DataAssets[dataAssetID]
+			breakdown.ProcessedOrStoredDataScore += types.Fibonacci(profile.Confidentiality.ProcessedOrStoredData+int(dataAsset.Confidentiality)) * profile.QuantityFactor(dataAsset.Quantity)
+			breakdown.ProcessedOrStoredDataScore += types.Fibonacci(profile.Integrity.ProcessedOrStoredData+int(dataAsset.Integrity)) * profile.QuantityFactor(dataAsset.Quantity)
+			breakdown.ProcessedOrStoredDataScore += types.Fibonacci(profile.Availability.ProcessedOrStoredData + int(dataAsset.Availability))
+		}
 	}
+
 	// NOTE: To send or receive data effectively is processing that data and it's questionable if the attractiveness increases further
 	for _, dataFlow := range techAsset.CommunicationLinks {
-		for _, dataAssetSent := range dataFlow.DataAssetsSent {
-			dataAsset := input.DataAssets[dataAssetSent]
-			score += dataAsset.Confidentiality.AttackerAttractivenessForInOutTransferredData() * dataAsset.Quantity.QuantityFactor()
-			score += dataAsset.Integrity.AttackerAttractivenessForInOutTransferredData() * dataAsset.Quantity.QuantityFactor()
-			score += dataAsset.Availability.AttackerAttractivenessForInOutTransferredData()
-		}
-
-		for _, dataAssetReceived := range dataFlow.DataAssetsReceived {
-			dataAsset := input.DataAssets[dataAssetReceived]
-			score += dataAsset.Confidentiality.AttackerAttractivenessForInOutTransferredData() * dataAsset.Quantity.QuantityFactor()
-			score += dataAsset.Integrity.AttackerAttractivenessForInOutTransferredData() * dataAsset.Quantity.QuantityFactor()
-			score += dataAsset.Availability.AttackerAttractivenessForInOutTransferredData()
+		for _, dataAssetIDs := range [][]string{dataFlow.DataAssetsSent, dataFlow.DataAssetsReceived} {
+			for _, dataAssetID := range dataAssetIDs {
+				dataAsset := what.input.DataAssets[dataAssetID]
+				breakdown.TransferredDataScore += types.Fibonacci(profile.Confidentiality.TransferredData+int(dataAsset.Confidentiality)) * profile.QuantityFactor(dataAsset.Quantity)
+				breakdown.TransferredDataScore += types.Fibonacci(profile.Integrity.TransferredData+int(dataAsset.Integrity)) * profile.QuantityFactor(dataAsset.Quantity)
+				breakdown.TransferredDataScore += types.Fibonacci(profile.Availability.TransferredData + int(dataAsset.Availability))
+			}
 		}
 	}
 
-	if techAsset.Technologies.GetAttribute(types.LoadBalancer, types.ReverseProxy) {
-		score = score / 5.5
-	} else if techAsset.Technologies.GetAttribute(types.Monitoring) {
-		score = score / 5
-	} else if techAsset.Technologies.GetAttribute(types.ContainerPlatform) {
-		score = score * 5
-	} else if techAsset.Technologies.GetAttribute(types.Vault) {
-		score = score * 2
-	} else if techAsset.Technologies.GetAttribute(types.BuildPipeline, types.SourcecodeRepository, types.ArtifactRegistry) {
-		score = score * 2
-	} else if techAsset.Technologies.GetAttribute(types.IdentityProvider, types.IdentityStoreDatabase, types.IdentityStoreLDAP) {
-		score = score * 2.5
-	} else if techAsset.Type == types.Datastore {
-		score = score * 2
-	}
-
+	breakdown.TechnologyMultiplier, breakdown.TechnologyMultiplierReason = profile.TechnologyMultiplierFor(techAsset)
 	if techAsset.MultiTenant {
-		score = score * 1.5
+		breakdown.MultiTenantMultiplier = profile.MultiTenantMultiplier
 	}
 
-	return score
+	breakdown.Score = (breakdown.AssetScore + breakdown.ProcessedOrStoredDataScore + breakdown.TransferredDataScore) *
+		breakdown.TechnologyMultiplier * breakdown.MultiTenantMultiplier
+
+	return breakdown
 }
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+// the default attractiveness profile has to keep the RAA values of the formerly hard-coded calculation
+func TestApplyRAADefaultAttractivenessOfExampleModel(t *testing.T) {
+	modelInput, err := ReadModelInput("../../demo/example/threagile.yaml", "")
+	assert.NoError(t, err)
+
+	parsedModel, err := ParseModel(&mockConfig{}, modelInput, make(types.RiskRules), make(types.RiskRules))
+	assert.NoError(t, err)
+
+	applyRAA(parsedModel, types.Attractiveness{}, &explainReporter{})
+
+	expected := map[string]float64{
+		"apache-webserver":     60.6120919375654,
+		"backend-admin-client": 1,
+		"backoffice-client":    1,
+		"contract-file-server": 33.2657200811359,
+		"customer-client":      1,
+		"erp-system":           62.062039616154216,
+		"external-dev-client":  1,
+		"git-repo":             31.49087221095335,
+		"identity-provider":    40.999362954246536,
+		"jenkins-build-server": 60.099849550227866,
+		"ldap-auth-server":     51.34381338742393,
+		"load-balancer":        9.952491809545325,
+		"marketing-cms":        22.55383688062901,
+		"sql-database":         100,
+	}
+
+	assert.Equal(t, len(expected), len(parsedModel.TechnicalAssets))
+	for id, raa := range expected {
+		if assert.Contains(t, parsedModel.TechnicalAssets, id) {
+			assert.InDelta(t, raa, parsedModel.TechnicalAssets[id].RAA, 1e-9, id)
+		}
+	}
+}
@@ -19,16 +19,6 @@ type ReadResult struct {
 	CustomRiskRules  types.RiskRules
 }
 
-type explainRiskConfig interface {
-}
-
-type explainRiskReporter interface {
-}
-
-func (what ReadResult) ExplainRisk(cfg explainRiskConfig, risk string, reporter explainRiskReporter) error {
-	return fmt.Errorf("not implemented")
-}
-
 // TODO: consider about splitting this function into smaller ones for better reusability
 
 type configReader interface {
@@ -37,12 +27,14 @@ type configReader interface {
 	GetInteractive() bool
 	GetAppFolder() string
 	GetPluginFolder() string
+	GetScriptRulesFolder() string
 	GetDataFolder() string
 	GetOutputFolder() string
 	GetServerFolder() string
 	GetTempFolder() string
 	GetKeyFolder() string
 	GetInputFile() string
+	GetVariant() string
 	GetImportedInputFile() string
 	GetDataFlowDiagramFilenamePNG() string
 	GetDataAssetDiagramFilenamePNG() string
@@ -72,6 +64,7 @@ type configReader interface {
 	GetAddLegend() bool
 	GetKeepDiagramSourceFiles() bool
 	GetIgnoreOrphanedRiskTracking() bool
+	GetAttractiveness() types.Attractiveness
 	GetThreagileVersion() string
 	GetProgressReporter() types.ProgressReporter
 }
@@ -81,11 +74,14 @@ func ReadAndAnalyzeModel(config configReader, builtinRiskRules types.RiskRules,
 	progressReporter.Infof("Parsing model: %v", config.GetInputFile())
 
 	customRiskRules := LoadCustomRiskRules(config.GetPluginFolder(), config.GetRiskRulePlugins(), progressReporter)
+	builtinRiskRules, customRiskRules, scriptRulesError := LoadScriptRiskRules(config.GetScriptRulesFolder(), builtinRiskRules, customRiskRules, progressReporter)
+	if scriptRulesError != nil {
+		return nil, scriptRulesError
+	}
 
-	modelInput := new(input.Model).Defaults()
-	loadError := modelInput.Load(config.GetInputFile())
+	modelInput, loadError := ReadModelInput(config.GetInputFile(), config.GetVariant())
 	if loadError != nil {
-		return nil, fmt.Errorf("unable to load model yaml: %w", loadError)
+		return nil, loadError
 	}
 
 	result, analysisError := AnalyzeModel(modelInput, config, builtinRiskRules, customRiskRules, progressReporter)
@@ -96,14 +92,33 @@ func ReadAndAnalyzeModel(config configReader, builtinRiskRules types.RiskRules,
 	return result, analysisError
 }
 
+// ReadModelInput loads the model file with its includes merged and the overlays of the variant (if any) applied
+func ReadModelInput(inputFile string, variant string) (*input.Model, error) {
+	modelInput := new(input.Model).Defaults()
+	loadError := modelInput.Load(inputFile)
+	if loadError != nil {
+		return nil, fmt.Errorf("unable to load model yaml: %w", loadError)
+	}
+
+	variantError := modelInput.ApplyVariant(filepath.Dir(inputFile), variant)
+	if variantError != nil {
+		return nil, fmt.Errorf("unable to load model yaml: %w", variantError)
+	}
+
+	return modelInput, nil
+}
+
 func AnalyzeModel(modelInput *input.Model, config configReader, builtinRiskRules types.RiskRules, customRiskRules types.RiskRules, progressReporter types.ProgressReporter) (*ReadResult, error) {
 
 	parsedModel, parseError := ParseModel(config, modelInput, builtinRiskRules, customRiskRules)
 	if parseError != nil {
 		return nil, fmt.Errorf("unable to parse model yaml: %w", parseError)
 	}
+	for _, warning := range parsedModel.Warnings {
+		progressReporter.Warn(warning.Error())
+	}
 
-	introTextRAA := applyRAA(parsedModel, progressReporter)
+	introTextRAA := applyRAA(parsedModel, config.GetAttractiveness(), progressReporter)
 
 	applyRiskGeneration(parsedModel, builtinRiskRules.Merge(customRiskRules), config.GetSkipRiskRules(), progressReporter)
 	err := parsedModel.ApplyWildcardRiskTrackingEvaluation(config.GetIgnoreOrphanedRiskTracking(), progressReporter)
@@ -152,6 +167,10 @@ func applyRiskGeneration(parsedModel *types.Model, rules types.RiskRules,
 			continue
 		}
 
+		for _, risk := range newRisks {
+			risk.SourcePosition = parsedModel.SourcePositionOfRisk(risk)
+		}
+
 		if len(newRisks) > 0 {
 			parsedModel.GeneratedRisksByCategory[id] = newRisks
 		}
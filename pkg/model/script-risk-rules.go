@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/threagile/threagile/pkg/risks"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+// LoadScriptRiskRules loads the script risk rules from scriptRulesDir (if any) and merges them into the given
+// built-in and custom risk rules; the passed-in rule sets are left untouched
+func LoadScriptRiskRules(scriptRulesDir string, builtinRiskRules types.RiskRules, customRiskRules types.RiskRules, reporter types.ProgressReporter) (types.RiskRules, types.RiskRules, error) {
+	if len(scriptRulesDir) == 0 {
+		return builtinRiskRules, customRiskRules, nil
+	}
+
+	reporter.Infof("Loading script risk rules from %q", scriptRulesDir)
+
+	scriptRiskRules, loadError := risks.LoadScriptRiskRules(scriptRulesDir)
+	if loadError != nil {
+		return nil, nil, loadError
+	}
+
+	for id := range scriptRiskRules {
+		reporter.Info("Script risk rule loaded:", id)
+	}
+
+	return risks.ApplyScriptRiskRules(builtinRiskRules, customRiskRules, scriptRiskRules)
+}
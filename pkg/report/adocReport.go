@@ -29,6 +29,7 @@ type adocReport struct {
 	iconsType        string
 	tocDepth         int
 	hideEmptyChapter bool
+	svgDiagrams      bool
 }
 
 func copyFile(source string, destination string) error {
@@ -69,7 +70,7 @@ func fixBasicHtml(inputWithHtml string) string {
 	return result
 }
 
-func NewAdocReport(targetDirectory string, riskRules types.RiskRules, hideEmptyChapter bool) adocReport {
+func NewAdocReport(targetDirectory string, riskRules types.RiskRules, hideEmptyChapter bool, svgDiagrams bool) adocReport {
 	adoc := adocReport{
 		targetDirectory:  filepath.Join(targetDirectory, "adocReport"),
 		iconsType:        "font",
@@ -77,6 +78,7 @@ func NewAdocReport(targetDirectory string, riskRules types.RiskRules, hideEmptyC
 		imagesDir:        filepath.Join(targetDirectory, "adocReport", "images"),
 		riskRules:        riskRules,
 		hideEmptyChapter: hideEmptyChapter,
+		svgDiagrams:      svgDiagrams,
 	}
 	return adoc
 }
@@ -368,6 +370,9 @@ func (adoc adocReport) writeTitleAndPreamble() {
 		reportDate = types.Date{Time: time.Now()}
 	}
 	adoc.writeMainLine(":revdate: " + reportDate.Format("2 January 2006"))
+	if len(adoc.model.Variant) > 0 {
+		adoc.writeMainLine(":revremark: Variant " + adoc.model.Variant)
+	}
 	adoc.writeMainLine("")
 }
 
@@ -926,6 +931,27 @@ For a full high-resolution version of this diagram please refer to the PNG image
 	writeLine(f, "\nimage::"+diagramFilenamePNG+"[]")
 }
 
+// diagramImage returns the image to reference for a diagram: the SVG rendered alongside the PNG if there is one, the PNG otherwise
+func (adoc adocReport) diagramImage(diagramFilenamePNG string, imagePNG string) string {
+	if !adoc.svgDiagrams {
+		return imagePNG
+	}
+
+	diagramFilenameSVG := DiagramFilename(diagramFilenamePNG, DiagramFormatSVG)
+	if _, err := os.Stat(diagramFilenameSVG); err != nil {
+		return imagePNG
+	}
+
+	imageSVG := DiagramFilename(imagePNG, DiagramFormatSVG)
+	err := copyFile(diagramFilenameSVG, filepath.Join(adoc.targetDirectory, imageSVG))
+	if err != nil {
+		log.Println("Could not copy file: »" + diagramFilenameSVG + "«: " + err.Error())
+		return imagePNG
+	}
+
+	return imageSVG
+}
+
 func imageIsWiderThanHigh(diagramFilenamePNG string) bool {
 	/* #nosec diagramFilenamePNG is not tainted (see caller restricting it to image files of model folder only) */
 	imagePath, err := os.Open(diagramFilenamePNG)
@@ -961,7 +987,7 @@ func (adoc adocReport) writeDataFlowDiagram(diagramFilenamePNG string) error {
 	adoc.writeMainLine("<<<")
 	adoc.writeMainLine("include::" + filename + "[leveloffset=+1]")
 
-	adoc.dataFlowDiagram(dfd, "images/data-flow-diagram.png")
+	adoc.dataFlowDiagram(dfd, adoc.diagramImage(diagramFilenamePNG, "images/data-flow-diagram.png"))
 	if landScape {
 		adoc.writeMainLine("[page-layout=portrait]")
 	}
@@ -1278,6 +1304,10 @@ func (adoc adocReport) raa(f *os.File, introTextRAA string) {
 		}
 		writeLine(f, fullLine+">>::")
 		writeLine(f, "  "+technicalAsset.Description)
+		if breakdown := raaBreakdownText(technicalAsset); len(breakdown) > 0 {
+			writeLine(f, "+")
+			writeLine(f, "  [small]#"+breakdown+"#")
+		}
 		writeLine(f, "")
 	}
 }
@@ -1328,7 +1358,7 @@ func (adoc adocReport) writeDataRiskMapping(dataAssetDiagramFilenamePNG string)
 	adoc.writeMainLine("<<<")
 	adoc.writeMainLine("include::" + filename + "[leveloffset=+1]")
 
-	adoc.dataRiskMapping(f, "images/data-asset-diagram.png")
+	adoc.dataRiskMapping(f, adoc.diagramImage(dataAssetDiagramFilenamePNG, "images/data-asset-diagram.png"))
 	if landScape {
 		adoc.writeMainLine("[page-layout=portrait]")
 	}
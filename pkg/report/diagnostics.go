@@ -0,0 +1,121 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/types"
+)
+
+const (
+	DiagnosticsFormatText = "text"
+	DiagnosticsFormatJSON = "json"
+)
+
+func DiagnosticsFormats() []string {
+	return []string{DiagnosticsFormatText, DiagnosticsFormatJSON}
+}
+
+// diagnosticsGroups are the element kinds in the order of the model file sections
+var diagnosticsGroups = []struct {
+	kind  string
+	title string
+}{
+	{types.ModelElement, "Model"},
+	{types.DataAssetElement, "Data assets"},
+	{types.TechnicalAssetElement, "Technical assets"},
+	{types.CommunicationLinkElement, "Communication links"},
+	{types.TrustBoundaryElement, "Trust boundaries"},
+	{types.SharedRuntimeElement, "Shared runtimes"},
+	{types.CustomRiskCategoryElement, "Custom risk categories"},
+	{types.IndividualRiskElement, "Individual risks"},
+	{types.RiskTrackingElement, "Risk tracking"},
+}
+
+func WriteDiagnostics(writer io.Writer, diagnostics types.Diagnostics, format string) error {
+	switch strings.ToLower(format) {
+	case DiagnosticsFormatText, "":
+		return WriteDiagnosticsText(writer, diagnostics)
+
+	case DiagnosticsFormatJSON:
+		return WriteDiagnosticsJSON(writer, diagnostics)
+
+	default:
+		return fmt.Errorf("unknown diagnostics format %q, expected one of %v", format, DiagnosticsFormats())
+	}
+}
+
+func WriteDiagnosticsJSON(writer io.Writer, diagnostics types.Diagnostics) error {
+	if diagnostics == nil {
+		diagnostics = make(types.Diagnostics, 0)
+	}
+
+	jsonBytes, err := json.MarshalIndent(diagnostics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal diagnostics to JSON: %w", err)
+	}
+
+	_, err = fmt.Fprintln(writer, string(jsonBytes))
+	return err
+}
+
+// WriteDiagnosticsText writes the diagnostics grouped by the kind of element, each prefixed with its position like compiler messages
+func WriteDiagnosticsText(writer io.Writer, diagnostics types.Diagnostics) error {
+	text := new(strings.Builder)
+	written := 0
+	for _, group := range diagnosticsGroups {
+		groupDiagnostics := make(types.Diagnostics, 0)
+		for _, diagnostic := range diagnostics {
+			if diagnostic.ElementKind == group.kind {
+				groupDiagnostics = append(groupDiagnostics, diagnostic)
+			}
+		}
+
+		writeDiagnosticsGroup(text, group.title, groupDiagnostics)
+		written += len(groupDiagnostics)
+	}
+
+	if written < len(diagnostics) {
+		known := make(map[string]bool)
+		for _, group := range diagnosticsGroups {
+			known[group.kind] = true
+		}
+
+		others := make(types.Diagnostics, 0)
+		for _, diagnostic := range diagnostics {
+			if !known[diagnostic.ElementKind] {
+				others = append(others, diagnostic)
+			}
+		}
+
+		writeDiagnosticsGroup(text, "Other", others)
+	}
+
+	_, _ = fmt.Fprintf(text, "%d error(s), %d warning(s)\n",
+		diagnostics.Count(types.DiagnosticError),
+		diagnostics.Count(types.DiagnosticWarning))
+
+	_, err := io.WriteString(writer, text.String())
+	return err
+}
+
+func writeDiagnosticsGroup(text *strings.Builder, title string, diagnostics types.Diagnostics) {
+	if len(diagnostics) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(text, "%v (%d):\n", title, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		text.WriteString("  ")
+		if diagnostic.SourcePosition != nil {
+			text.WriteString(diagnostic.SourcePosition.String() + ": ")
+		}
+		_, _ = fmt.Fprintf(text, "%v: %v", diagnostic.Severity, diagnostic.Message)
+		if len(diagnostic.Field) > 0 {
+			_, _ = fmt.Fprintf(text, " [%v]", diagnostic.Field)
+		}
+		text.WriteString("\n")
+	}
+}
@@ -0,0 +1,173 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/model"
+)
+
+const (
+	DiffFormatText     = "text"
+	DiffFormatJSON     = "json"
+	DiffFormatMarkdown = "markdown"
+)
+
+func DiffFormats() []string {
+	return []string{DiffFormatText, DiffFormatJSON, DiffFormatMarkdown}
+}
+
+func WriteDiff(writer io.Writer, diff *model.ModelDiff, format string) error {
+	switch strings.ToLower(format) {
+	case DiffFormatText, "":
+		return WriteDiffText(writer, diff)
+
+	case DiffFormatJSON:
+		return WriteDiffJSON(writer, diff)
+
+	case DiffFormatMarkdown, "md":
+		return WriteDiffMarkdown(writer, diff)
+
+	default:
+		return fmt.Errorf("unknown diff format %q, expected one of %v", format, DiffFormats())
+	}
+}
+
+func WriteDiffJSON(writer io.Writer, diff *model.ModelDiff) error {
+	jsonBytes, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal diff to JSON: %w", err)
+	}
+
+	_, err = fmt.Fprintln(writer, string(jsonBytes))
+	return err
+}
+
+func WriteDiffText(writer io.Writer, diff *model.ModelDiff) error {
+	text := new(strings.Builder)
+	if diff.IsEmpty() {
+		text.WriteString("No differences\n")
+		_, err := io.WriteString(writer, text.String())
+		return err
+	}
+
+	writeRiskDiffText(text, diff.Risks)
+	writeElementDiffText(text, "Technical assets", diff.TechnicalAssets)
+	writeElementDiffText(text, "Communication links", diff.CommunicationLinks)
+	writeElementDiffText(text, "Data assets", diff.DataAssets)
+	writeElementDiffText(text, "Trust boundaries", diff.TrustBoundaries)
+
+	_, err := io.WriteString(writer, text.String())
+	return err
+}
+
+func writeRiskDiffText(text *strings.Builder, risks *model.RiskDiff) {
+	if risks.IsEmpty() {
+		return
+	}
+
+	_, _ = fmt.Fprintf(text, "Risks: %d new, %d resolved, %d changed\n", len(risks.Added), len(risks.Resolved), len(risks.Changed))
+	for _, risk := range risks.Added {
+		_, _ = fmt.Fprintf(text, "  + [%v] %v: %v\n", risk.Severity.Title(), risk.SyntheticId, removeFormattingTags(risk.Title))
+	}
+	for _, risk := range risks.Resolved {
+		_, _ = fmt.Fprintf(text, "  - [%v] %v: %v\n", risk.Severity.Title(), risk.SyntheticId, removeFormattingTags(risk.Title))
+	}
+	for _, risk := range risks.Changed {
+		_, _ = fmt.Fprintf(text, "  ~ %v: %v\n", risk.SyntheticId, riskChangeText(risk))
+	}
+	text.WriteString("\n")
+}
+
+func writeElementDiffText(text *strings.Builder, title string, elements *model.ElementDiff) {
+	if elements.IsEmpty() {
+		return
+	}
+
+	_, _ = fmt.Fprintf(text, "%v: %d added, %d removed, %d changed\n", title, len(elements.Added), len(elements.Removed), len(elements.Changed))
+	for _, id := range elements.Added {
+		_, _ = fmt.Fprintf(text, "  + %v\n", id)
+	}
+	for _, id := range elements.Removed {
+		_, _ = fmt.Fprintf(text, "  - %v\n", id)
+	}
+	for _, element := range elements.Changed {
+		_, _ = fmt.Fprintf(text, "  ~ %v (%v)\n", element.Id, strings.Join(element.Fields, ", "))
+	}
+	text.WriteString("\n")
+}
+
+func WriteDiffMarkdown(writer io.Writer, diff *model.ModelDiff) error {
+	text := new(strings.Builder)
+	text.WriteString("## Threat model changes\n\n")
+	if diff.IsEmpty() {
+		text.WriteString("No differences\n")
+		_, err := io.WriteString(writer, text.String())
+		return err
+	}
+
+	writeRiskDiffMarkdown(text, diff.Risks)
+	writeElementDiffMarkdown(text, "Technical assets", diff.TechnicalAssets)
+	writeElementDiffMarkdown(text, "Communication links", diff.CommunicationLinks)
+	writeElementDiffMarkdown(text, "Data assets", diff.DataAssets)
+	writeElementDiffMarkdown(text, "Trust boundaries", diff.TrustBoundaries)
+
+	_, err := io.WriteString(writer, text.String())
+	return err
+}
+
+func writeRiskDiffMarkdown(text *strings.Builder, risks *model.RiskDiff) {
+	if risks.IsEmpty() {
+		return
+	}
+
+	_, _ = fmt.Fprintf(text, "### Risks\n\n%d new, %d resolved, %d changed\n\n", len(risks.Added), len(risks.Resolved), len(risks.Changed))
+	text.WriteString("| | Severity | Risk | Title |\n")
+	text.WriteString("|---|---|---|---|\n")
+	for _, risk := range risks.Added {
+		_, _ = fmt.Fprintf(text, "| new | %v | `%v` | %v |\n", risk.Severity.Title(), risk.SyntheticId, markdownCell(risk.Title))
+	}
+	for _, risk := range risks.Resolved {
+		_, _ = fmt.Fprintf(text, "| resolved | %v | `%v` | %v |\n", risk.Severity.Title(), risk.SyntheticId, markdownCell(risk.Title))
+	}
+	for _, risk := range risks.Changed {
+		_, _ = fmt.Fprintf(text, "| changed | %v | `%v` | %v |\n", riskChangeText(risk), risk.SyntheticId, markdownCell(risk.Title))
+	}
+	text.WriteString("\n")
+}
+
+func writeElementDiffMarkdown(text *strings.Builder, title string, elements *model.ElementDiff) {
+	if elements.IsEmpty() {
+		return
+	}
+
+	_, _ = fmt.Fprintf(text, "### %v\n\n", title)
+	for _, id := range elements.Added {
+		_, _ = fmt.Fprintf(text, "- added `%v`\n", id)
+	}
+	for _, id := range elements.Removed {
+		_, _ = fmt.Fprintf(text, "- removed `%v`\n", id)
+	}
+	for _, element := range elements.Changed {
+		_, _ = fmt.Fprintf(text, "- changed `%v`: %v\n", element.Id, strings.Join(element.Fields, ", "))
+	}
+	text.WriteString("\n")
+}
+
+func riskChangeText(risk *model.ChangedRisk) string {
+	changes := make([]string, 0)
+	if risk.OldSeverity != risk.NewSeverity {
+		changes = append(changes, risk.OldSeverity.Title()+" → "+risk.NewSeverity.Title())
+	}
+	if risk.OldStatus != risk.NewStatus {
+		changes = append(changes, risk.OldStatus.Title()+" → "+risk.NewStatus.Title())
+	}
+
+	return strings.Join(changes, ", ")
+}
+
+func markdownCell(text string) string {
+	return strings.ReplaceAll(removeFormattingTags(text), "|", "\\|")
+}
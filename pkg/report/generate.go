@@ -17,12 +17,14 @@ type GenerateCommands struct {
 	DataFlowDiagram     bool
 	DataAssetDiagram    bool
 	RisksJSON           bool
+	RisksSARIF          bool
 	TechnicalAssetsJSON bool
 	StatsJSON           bool
 	RisksExcel          bool
 	TagsExcel           bool
 	ReportPDF           bool
 	ReportADOC          bool
+	ReportHTML          bool
 }
 
 func (c *GenerateCommands) Defaults() *GenerateCommands {
@@ -30,12 +32,14 @@ func (c *GenerateCommands) Defaults() *GenerateCommands {
 		DataFlowDiagram:     true,
 		DataAssetDiagram:    true,
 		RisksJSON:           true,
+		RisksSARIF:          true,
 		TechnicalAssetsJSON: true,
 		StatsJSON:           true,
 		RisksExcel:          true,
 		TagsExcel:           true,
 		ReportPDF:           true,
 		ReportADOC:          true,
+		ReportHTML:          true,
 	}
 	return c
 }
@@ -54,9 +58,11 @@ type reportConfigReader interface {
 	GetDataFlowDiagramFilenameDOT() string
 	GetDataAssetDiagramFilenameDOT() string
 	GetReportFilename() string
+	GetHtmlReportFilename() string
 	GetExcelRisksFilename() string
 	GetExcelTagsFilename() string
 	GetJsonRisksFilename() string
+	GetSarifRisksFilename() string
 	GetJsonTechnicalAssetsFilename() string
 	GetJsonStatsFilename() string
 	GetTemplateFilename() string
@@ -71,6 +77,7 @@ type reportConfigReader interface {
 	GetRiskExcelColorText() bool
 
 	GetDiagramDPI() int
+	GetDiagramFormats() []string
 	GetMinGraphvizDPI() int
 	GetMaxGraphvizDPI() int
 
@@ -106,6 +113,20 @@ func Generate(config reportConfigReader, readResult *model.ReadResult, commands
 		}
 	}
 
+	diagramFormats, err := ParseDiagramFormats(config.GetDiagramFormats())
+	if err != nil {
+		return err
+	}
+	if len(diagramFormats) == 0 || ((commands.ReportPDF || commands.ReportADOC) && !contains(diagramFormats, DiagramFormatPNG)) {
+		diagramFormats = append(diagramFormats, DiagramFormatPNG) // as the PDF and ADOC reports embed the PNG images
+	}
+
+	// diagram nodes link to their chapter in the HTML report
+	reportLink := ""
+	if commands.ReportHTML {
+		reportLink = filepath.ToSlash(config.GetHtmlReportFilename())
+	}
+
 	diagramDPI := config.GetDiagramDPI()
 	if diagramDPI < config.GetMinGraphvizDPI() {
 		diagramDPI = config.GetMinGraphvizDPI()
@@ -113,7 +134,8 @@ func Generate(config reportConfigReader, readResult *model.ReadResult, commands
 		diagramDPI = config.GetMaxGraphvizDPI()
 	}
 	// Data-flow Diagram rendering
-	if generateDataFlowDiagram {
+	var dataFlowDiagramSVG, dataAssetDiagramSVG []byte
+	if generateDataFlowDiagram || commands.ReportHTML {
 		gvFile := filepath.Join(config.GetOutputFolder(), config.GetDataFlowDiagramFilenameDOT())
 		if !config.GetKeepDiagramSourceFiles() {
 			tmpFileGV, err := os.CreateTemp(config.GetTempFolder(), config.GetDataFlowDiagramFilenameDOT())
@@ -123,15 +145,25 @@ func Generate(config reportConfigReader, readResult *model.ReadResult, commands
 			gvFile = tmpFileGV.Name()
 			defer func() { _ = os.Remove(gvFile) }()
 		}
-		dotFile, err := WriteDataFlowDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, config.GetAddModelTitle(), config.GetAddLegend(), progressReporter)
+		dotFile, err := WriteDataFlowDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, config.GetAddModelTitle(), config.GetAddLegend(), reportLink, progressReporter)
 		if err != nil {
 			return fmt.Errorf("error while generating data flow diagram: %w", err)
 		}
 
-		err = GenerateDataFlowDiagramGraphvizImage(dotFile, config.GetOutputFolder(),
-			config.GetTempFolder(), config.GetDataFlowDiagramFilenamePNG(), progressReporter, config.GetKeepDiagramSourceFiles())
-		if err != nil {
-			progressReporter.Warn(err)
+		if generateDataFlowDiagram {
+			err = GenerateDataFlowDiagramGraphvizImage(dotFile, config.GetOutputFolder(),
+				config.GetDataFlowDiagramFilenamePNG(), diagramFormats, progressReporter)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+		}
+
+		// the HTML report embeds the diagram as SVG
+		if commands.ReportHTML {
+			dataFlowDiagramSVG, err = renderGraphviz(dotFile.Name(), DiagramFormatSVG)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
 		}
 	}
 	// Data Asset Diagram rendering
@@ -145,15 +177,22 @@ func Generate(config reportConfigReader, readResult *model.ReadResult, commands
 			gvFile = tmpFile.Name()
 			defer func() { _ = os.Remove(gvFile) }()
 		}
-		dotFile, err := WriteDataAssetDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, progressReporter)
+		dotFile, err := WriteDataAssetDiagramGraphvizDOT(readResult.ParsedModel, gvFile, diagramDPI, reportLink, progressReporter)
 		if err != nil {
 			return fmt.Errorf("error while generating data asset diagram: %w", err)
 		}
 		err = GenerateDataAssetDiagramGraphvizImage(dotFile, config.GetOutputFolder(),
-			config.GetTempFolder(), config.GetDataAssetDiagramFilenamePNG(), progressReporter)
+			config.GetDataAssetDiagramFilenamePNG(), diagramFormats, progressReporter)
 		if err != nil {
 			progressReporter.Warn(err)
 		}
+
+		if commands.ReportHTML {
+			dataAssetDiagramSVG, err = renderGraphviz(dotFile.Name(), DiagramFormatSVG)
+			if err != nil {
+				progressReporter.Warn(err)
+			}
+		}
 	}
 
 	// risks as risks json
@@ -165,6 +204,15 @@ func Generate(config reportConfigReader, readResult *model.ReadResult, commands
 		}
 	}
 
+	// risks as sarif
+	if commands.RisksSARIF {
+		progressReporter.Info("Writing risks sarif")
+		err := WriteRisksSARIF(readResult, config.GetInputFile(), config.GetThreagileVersion(), filepath.Join(config.GetOutputFolder(), config.GetSarifRisksFilename()))
+		if err != nil {
+			return fmt.Errorf("error while writing risks sarif: %w", err)
+		}
+	}
+
 	// technical assets json
 	if commands.TechnicalAssetsJSON {
 		progressReporter.Info("Writing technical assets json")
@@ -203,16 +251,10 @@ func Generate(config reportConfigReader, readResult *model.ReadResult, commands
 
 	if commands.ReportPDF {
 		// hash the YAML input file
-		f, err := os.Open(config.GetInputFile())
+		modelHash, err := hashModelFile(config.GetInputFile())
 		if err != nil {
 			return err
 		}
-		defer func() { _ = f.Close() }()
-		hasher := sha256.New()
-		if _, err := io.Copy(hasher, f); err != nil {
-			return err
-		}
-		modelHash := hex.EncodeToString(hasher.Sum(nil))
 		// report PDF
 		progressReporter.Info("Writing report pdf")
 
@@ -238,20 +280,14 @@ func Generate(config reportConfigReader, readResult *model.ReadResult, commands
 
 	if commands.ReportADOC {
 		// hash the YAML input file
-		f, err := os.Open(config.GetInputFile())
+		modelHash, err := hashModelFile(config.GetInputFile())
 		if err != nil {
 			return err
 		}
-		defer func() { _ = f.Close() }()
-		hasher := sha256.New()
-		if _, err := io.Copy(hasher, f); err != nil {
-			return err
-		}
 
-		modelHash := hex.EncodeToString(hasher.Sum(nil))
 		// report ADOC
 		progressReporter.Info("Writing report adoc")
-		adocReporter := NewAdocReport(config.GetOutputFolder(), riskRules, config.GetHideEmptyChapters())
+		adocReporter := NewAdocReport(config.GetOutputFolder(), riskRules, config.GetHideEmptyChapters(), contains(diagramFormats, DiagramFormatSVG))
 		err = adocReporter.WriteReport(readResult.ParsedModel,
 			filepath.Join(config.GetOutputFolder(), config.GetDataFlowDiagramFilenamePNG()),
 			filepath.Join(config.GetOutputFolder(), config.GetDataAssetDiagramFilenamePNG()),
@@ -269,9 +305,49 @@ func Generate(config reportConfigReader, readResult *model.ReadResult, commands
 		}
 	}
 
+	if commands.ReportHTML {
+		// hash the YAML input file
+		modelHash, err := hashModelFile(config.GetInputFile())
+		if err != nil {
+			return err
+		}
+
+		// report HTML
+		progressReporter.Info("Writing report html")
+		htmlReporter := NewHtmlReport(riskRules, config.GetHideEmptyChapters())
+		err = htmlReporter.WriteReport(filepath.Join(config.GetOutputFolder(), config.GetHtmlReportFilename()),
+			readResult.ParsedModel,
+			dataFlowDiagramSVG,
+			dataAssetDiagramSVG,
+			config.GetInputFile(),
+			config.GetSkipRiskRules(),
+			config.GetBuildTimestamp(),
+			config.GetThreagileVersion(),
+			modelHash,
+			readResult.IntroTextRAA,
+			readResult.CustomRiskRules,
+			config.GetReportConfigurationHideChapters())
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func hashModelFile(filename string) (string, error) {
+	f, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
 type progressReporter interface {
 	Info(a ...any)
 	Warn(a ...any)
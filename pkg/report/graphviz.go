@@ -1,6 +1,7 @@
 package report
 
 import (
+	"bytes"
 	"fmt"
 	"hash/fnv"
 	"os"
@@ -14,8 +15,43 @@ import (
 	"github.com/threagile/threagile/pkg/types"
 )
 
+const (
+	DiagramFormatPNG = "png"
+	DiagramFormatSVG = "svg"
+	DiagramFormatPDF = "pdf"
+)
+
+func DiagramFormats() []string {
+	return []string{DiagramFormatPNG, DiagramFormatSVG, DiagramFormatPDF}
+}
+
+// ParseDiagramFormats normalizes the configured diagram formats, dropping duplicates
+func ParseDiagramFormats(values []string) ([]string, error) {
+	formats := make([]string, 0)
+	for _, value := range values {
+		format := strings.ToLower(strings.TrimSpace(value))
+		if len(format) == 0 || contains(formats, format) {
+			continue
+		}
+		if !contains(DiagramFormats(), format) {
+			return nil, fmt.Errorf("unknown diagram format %q, expected one of %v", value, DiagramFormats())
+		}
+		formats = append(formats, format)
+	}
+	return formats, nil
+}
+
+// DiagramFilename is the file name of a diagram in the given format, derived from its configured PNG file name
+func DiagramFilename(diagramFilenamePNG string, format string) string {
+	if format == DiagramFormatPNG {
+		return diagramFilenamePNG
+	}
+	return strings.TrimSuffix(diagramFilenamePNG, filepath.Ext(diagramFilenamePNG)) + "." + format
+}
+
+// WriteDataFlowDiagramGraphvizDOT writes the data-flow diagram; when reportLink is set, nodes link to their chapter in that (HTML) report
 func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
-	diagramFilenameDOT string, dpi int, addModelTitle bool, addLegend bool,
+	diagramFilenameDOT string, dpi int, addModelTitle bool, addLegend bool, reportLink string,
 	progressReporter progressReporter) (*os.File, error) {
 	progressReporter.Info("Writing data flow diagram input")
 
@@ -108,7 +144,7 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 	}
 	modelTitle := ""
 	if addModelTitle {
-		modelTitle = `label="` + parsedModel.Title + `"`
+		modelTitle = `label="` + modelTitleWithVariant(parsedModel) + `"`
 	}
 	dotContent.WriteString(`	graph [ ` + modelTitle + `
 		labelloc=t
@@ -248,7 +284,7 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 	}
 	sort.Sort(types.ByOrderAndIdSort(techAssets))
 	for _, technicalAsset := range techAssets {
-		dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, false))
+		dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, false, reportLink))
 		dotContent.WriteString("\n")
 	}
 
@@ -285,6 +321,7 @@ func WriteDataFlowDiagramGraphvizDOT(parsedModel *types.Model,
 			if !parsedModel.DiagramTweakSuppressEdgeLabels {
 				dotContent.WriteString(` xlabel="` + encode(dataFlow.Protocol.String()) + `" fontcolor="` + determineLabelColor(dataFlow, parsedModel) + `" `)
 			}
+			dotContent.WriteString(` id="` + escapeDOT("diagram-communication-link-"+dataFlow.Id) + `" tooltip="` + escapeDOT(dataFlow.Title+" ("+dataFlow.Protocol.String()+")") + `"`)
 			dotContent.WriteString(" ];\n")
 		}
 	}
@@ -417,55 +454,10 @@ func determineArrowColor(cl *types.CommunicationLink, parsedModel *types.Model)
 	return Black
 }
 
-func GenerateDataFlowDiagramGraphvizImage(dotFile *os.File, targetDir string,
-	tempFolder, dataFlowDiagramFilenamePNG string, progressReporter progressReporter, keepGraphVizDataFile bool) error {
+func GenerateDataFlowDiagramGraphvizImage(dotFile *os.File, targetDir string, dataFlowDiagramFilenamePNG string,
+	formats []string, progressReporter progressReporter) error {
 	progressReporter.Info("Rendering data flow diagram input")
-	// tmp files
-	tmpFileDOT, err := os.CreateTemp(tempFolder, "diagram-*-.gv")
-	if err != nil {
-		return fmt.Errorf("error creating temp file: %w", err)
-	}
-	if !keepGraphVizDataFile {
-		defer func() { _ = os.Remove(tmpFileDOT.Name()) }()
-	}
-
-	tmpFilePNG, err := os.CreateTemp(tempFolder, "diagram-*-.png")
-	if err != nil {
-		return fmt.Errorf("error creating temp file: %w", err)
-	}
-	if !keepGraphVizDataFile {
-		defer func() { _ = os.Remove(tmpFilePNG.Name()) }()
-	}
-
-	// copy into tmp file as input
-	inputDOT, err := os.ReadFile(dotFile.Name())
-	if err != nil {
-		return fmt.Errorf("error reading %s: %w", dotFile.Name(), err)
-	}
-	err = os.WriteFile(tmpFileDOT.Name(), inputDOT, 0600)
-	if err != nil {
-		return fmt.Errorf("error creating %s: %w", tmpFileDOT.Name(), err)
-	}
-
-	// exec
-
-	cmd := exec.Command("dot", "-Tpng", tmpFileDOT.Name(), "-o", tmpFilePNG.Name()) // #nosec G204
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
-	if err != nil {
-		return fmt.Errorf("graph rendering call failed with error: %w", err)
-	}
-	// copy into resulting file
-	inputPNG, err := os.ReadFile(tmpFilePNG.Name())
-	if err != nil {
-		return fmt.Errorf("failed to copy to file %s: %w", tmpFilePNG.Name(), err)
-	}
-	err = os.WriteFile(filepath.Join(targetDir, dataFlowDiagramFilenamePNG), inputPNG, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to create %s: %w", filepath.Join(targetDir, dataFlowDiagramFilenamePNG), err)
-	}
-	return nil
+	return renderGraphvizImages(dotFile.Name(), targetDir, dataFlowDiagramFilenamePNG, formats)
 }
 
 func makeDiagramSameRankNodeTweaks(parsedModel *types.Model) (string, error) {
@@ -516,7 +508,8 @@ func makeDiagramInvisibleConnectionsTweaks(parsedModel *types.Model) (string, er
 	return tweak, nil
 }
 
-func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameDOT string, dpi int,
+// WriteDataAssetDiagramGraphvizDOT writes the data asset diagram; when reportLink is set, nodes link to their chapter in that (HTML) report
+func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameDOT string, dpi int, reportLink string,
 	progressReporter progressReporter) (*os.File, error) {
 	progressReporter.Info("Writing data asset diagram input")
 
@@ -555,7 +548,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 	sort.Sort(types.ByOrderAndIdSort(techAssets))
 	for _, technicalAsset := range techAssets {
 		if len(technicalAsset.DataAssetsStored) > 0 || len(technicalAsset.DataAssetsProcessed) > 0 {
-			dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, true))
+			dotContent.WriteString(makeTechAssetNode(parsedModel, technicalAsset, true, reportLink))
 			dotContent.WriteString("\n")
 		}
 	}
@@ -568,7 +561,7 @@ func WriteDataAssetDiagramGraphvizDOT(parsedModel *types.Model, diagramFilenameD
 
 	sortByDataAssetDataBreachProbabilityAndTitle(parsedModel, dataAssets)
 	for _, dataAsset := range dataAssets {
-		dotContent.WriteString(makeDataAssetNode(parsedModel, dataAsset))
+		dotContent.WriteString(makeDataAssetNode(parsedModel, dataAsset, reportLink))
 		dotContent.WriteString("\n")
 	}
 
@@ -618,7 +611,7 @@ func sortByDataAssetDataBreachProbabilityAndTitle(parsedModel *types.Model, asse
 	})
 }
 
-func makeDataAssetNode(parsedModel *types.Model, dataAsset *types.DataAsset) string {
+func makeDataAssetNode(parsedModel *types.Model, dataAsset *types.DataAsset, reportLink string) string {
 	var color string
 	switch identifiedDataBreachProbabilityStillAtRisk(parsedModel, dataAsset) {
 	case types.Probable:
@@ -633,10 +626,12 @@ func makeDataAssetNode(parsedModel *types.Model, dataAsset *types.DataAsset) str
 	if !isDataBreachPotentialStillAtRisk(parsedModel, dataAsset) {
 		color = "#444444" // since black is too dark here as fill color
 	}
-	return "  " + hash(dataAsset.Id) + ` [ label=<<b>` + encode(dataAsset.Title) + `</b>> penwidth="3.0" style="filled" fillcolor="` + color + `" color="` + color + "\"\n  ]; "
+	return "  " + hash(dataAsset.Id) + ` [ label=<<b>` + encode(dataAsset.Title) + `</b>> penwidth="3.0" style="filled" fillcolor="` + color + `" color="` + color + `"
+	` + makeDiagramNodeAttributes("data-asset", dataAsset.Id, dataAssetTooltip(parsedModel, dataAsset), reportLink) + "\n  ]; "
 }
 
-func makeTechAssetNode(parsedModel *types.Model, technicalAsset *types.TechnicalAsset, simplified bool) string {
+func makeTechAssetNode(parsedModel *types.Model, technicalAsset *types.TechnicalAsset, simplified bool, reportLink string) string {
+	nodeAttributes := makeDiagramNodeAttributes("technical-asset", technicalAsset.Id, technicalAssetTooltip(parsedModel, technicalAsset), reportLink)
 	if simplified {
 		color := rgbHexColorOutOfScope()
 		if !technicalAsset.OutOfScope {
@@ -660,7 +655,8 @@ func makeTechAssetNode(parsedModel *types.Model, technicalAsset *types.Technical
 			}
 		}
 		return "  " + hash(technicalAsset.Id) + ` [ shape="box" style="filled" fillcolor="` + color + `"
-				label=<<b>` + encode(technicalAsset.Title) + `</b>> penwidth="3.0" color="` + color + `" ];
+				label=<<b>` + encode(technicalAsset.Title) + `</b>> penwidth="3.0" color="` + color + `"
+				` + nodeAttributes + ` ];
 				`
 	}
 
@@ -703,7 +699,8 @@ func makeTechAssetNode(parsedModel *types.Model, technicalAsset *types.Technical
 label=<<table border="0" cellborder="` + compartmentBorder + `" cellpadding="2" cellspacing="0"><tr><td><font point-size="15" color="` + DarkBlue + `">` + lineBreak + technicalAsset.Technologies.String() + `</font><br/><font point-size="15" color="` + LightGray + `">` + technicalAsset.Size.String() + `</font></td></tr><tr><td><b><font color="` + determineTechnicalAssetLabelColor(technicalAsset, parsedModel) + `">` + encode(title) + `</font></b><br/></td></tr><tr><td>` + attackerAttractivenessLabel + `</td></tr></table>>
 shape=` + shape + ` style="` + determineShapeBorderLineStyle(technicalAsset) + `,` + determineShapeStyle(technicalAsset) + `" penwidth="` + determineShapeBorderPenWidth(technicalAsset, parsedModel) + `" fillcolor="` + determineShapeFillColor(technicalAsset, parsedModel) + `"
 peripheries=` + strconv.Itoa(determineShapePeripheries(technicalAsset)) + `
-color="` + determineShapeBorderColor(technicalAsset, parsedModel) + "\"\n  ]; "
+color="` + determineShapeBorderColor(technicalAsset, parsedModel) + `"
+` + nodeAttributes + "\n  ]; "
 }
 
 func determineShapeStyle(ta *types.TechnicalAsset) string {
@@ -818,50 +815,39 @@ func determineTechnicalAssetLabelColor(ta *types.TechnicalAsset, model *types.Mo
 	return Black
 }
 
-func GenerateDataAssetDiagramGraphvizImage(dotFile *os.File, targetDir string,
-	tempFolder, dataAssetDiagramFilenamePNG string, progressReporter progressReporter) error { // TODO dedupe with other render...() method here
+func GenerateDataAssetDiagramGraphvizImage(dotFile *os.File, targetDir string, dataAssetDiagramFilenamePNG string,
+	formats []string, progressReporter progressReporter) error {
 	progressReporter.Info("Rendering data asset diagram input")
-	// tmp files
-	tmpFileDOT, err := os.CreateTemp(tempFolder, "diagram-*-.gv")
-	if err != nil {
-		return fmt.Errorf("error creating temp file: %w", err)
-	}
-	defer func() { _ = os.Remove(tmpFileDOT.Name()) }()
+	return renderGraphvizImages(dotFile.Name(), targetDir, dataAssetDiagramFilenamePNG, formats)
+}
 
-	tmpFilePNG, err := os.CreateTemp(tempFolder, "diagram-*-.png")
-	if err != nil {
-		return fmt.Errorf("error creating temp file: %w", err)
-	}
-	defer func() { _ = os.Remove(tmpFilePNG.Name()) }()
+// renderGraphvizImages writes one image per format, named like the PNG file but with the format as extension
+func renderGraphvizImages(dotFilename string, targetDir string, diagramFilenamePNG string, formats []string) error {
+	for _, format := range formats {
+		image, err := renderGraphviz(dotFilename, format)
+		if err != nil {
+			return err
+		}
 
-	// copy into tmp file as input
-	inputDOT, err := os.ReadFile(dotFile.Name())
-	if err != nil {
-		return fmt.Errorf("error reading %s: %w", dotFile.Name(), err)
-	}
-	err = os.WriteFile(tmpFileDOT.Name(), inputDOT, 0600)
-	if err != nil {
-		return fmt.Errorf("error creating %s: %w", tmpFileDOT.Name(), err)
+		imageFilename := filepath.Join(targetDir, DiagramFilename(diagramFilenamePNG, format))
+		err = os.WriteFile(imageFilename, image, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", imageFilename, err)
+		}
 	}
+	return nil
+}
 
-	// exec
-	cmd := exec.Command("dot", "-Tpng", tmpFileDOT.Name(), "-o", tmpFilePNG.Name()) // #nosec G204
-	cmd.Stdout = os.Stdout
+func renderGraphviz(dotFilename string, format string) ([]byte, error) {
+	var image bytes.Buffer
+	cmd := exec.Command("dot", "-T"+format, dotFilename) // #nosec G204
+	cmd.Stdout = &image
 	cmd.Stderr = os.Stderr
-	err = cmd.Run()
-	if err != nil {
-		return fmt.Errorf("graph rendering call failed with error: %w", err)
-	}
-	// copy into resulting file
-	inputPNG, err := os.ReadFile(tmpFilePNG.Name())
-	if err != nil {
-		return fmt.Errorf("failed to copy to file %s: %w", tmpFilePNG.Name(), err)
-	}
-	err = os.WriteFile(filepath.Join(targetDir, dataAssetDiagramFilenamePNG), inputPNG, 0600)
+	err := cmd.Run()
 	if err != nil {
-		return fmt.Errorf("failed to create %s: %w", filepath.Join(targetDir, dataAssetDiagramFilenamePNG), err)
+		return nil, fmt.Errorf("graph rendering call failed with error: %w", err)
 	}
-	return nil
+	return image.Bytes(), nil
 }
 
 func hash(s string) string {
@@ -873,3 +859,58 @@ func hash(s string) string {
 func encode(value string) string {
 	return strings.ReplaceAll(value, "&", "&amp;")
 }
+
+// escapeDOT escapes a value for a double-quoted DOT attribute
+func escapeDOT(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// makeDiagramNodeAttributes gives a node an element id and a tooltip in SVG output and, when a report is written, links it to its chapter there
+func makeDiagramNodeAttributes(kind string, id string, tooltip string, reportLink string) string {
+	attributes := `id="` + escapeDOT("diagram-"+kind+"-"+id) + `" tooltip="` + escapeDOT(tooltip) + `"`
+	if len(reportLink) > 0 {
+		attributes += ` URL="` + escapeDOT(reportLink+"#"+kind+"-"+id) + `" target="_top"`
+	}
+	return attributes
+}
+
+func technicalAssetTooltip(parsedModel *types.Model, technicalAsset *types.TechnicalAsset) string {
+	lines := []string{
+		technicalAsset.Title,
+		fmt.Sprintf("Confidentiality: %v, Integrity: %v, Availability: %v",
+			parsedModel.HighestTechnicalAssetConfidentiality(technicalAsset),
+			parsedModel.HighestIntegrity(technicalAsset),
+			parsedModel.HighestAvailability(technicalAsset)),
+	}
+	if technicalAsset.OutOfScope {
+		return strings.Join(append(lines, "RAA: out of scope"), "\n")
+	}
+
+	// not using GeneratedRisks here as it complains about models without any risks
+	total, stillAtRisk := 0, 0
+	for _, risks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range risks {
+			if risk.MostRelevantTechnicalAssetId != technicalAsset.Id {
+				continue
+			}
+			total++
+			if parsedModel.GetRiskTrackingWithDefault(risk).Status.IsStillAtRisk() {
+				stillAtRisk++
+			}
+		}
+	}
+	lines = append(lines,
+		fmt.Sprintf("RAA: %.0f %%", technicalAsset.RAA),
+		fmt.Sprintf("Risks: %d identified, %d still at risk", total, stillAtRisk))
+	return strings.Join(lines, "\n")
+}
+
+func dataAssetTooltip(parsedModel *types.Model, dataAsset *types.DataAsset) string {
+	return strings.Join([]string{
+		dataAsset.Title,
+		fmt.Sprintf("Confidentiality: %v, Integrity: %v, Availability: %v", dataAsset.Confidentiality, dataAsset.Integrity, dataAsset.Availability),
+		fmt.Sprintf("Data breach probability: %v (still at risk: %v)",
+			parsedModel.IdentifiedDataBreachProbability(dataAsset).Title(),
+			identifiedDataBreachProbabilityStillAtRisk(parsedModel, dataAsset).Title()),
+	}, "\n")
+}
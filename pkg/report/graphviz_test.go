@@ -0,0 +1,78 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeDOT(t *testing.T) {
+	assert.Equal(t, `plain`, escapeDOT(`plain`))
+	assert.Equal(t, `say \"hi\"`, escapeDOT(`say "hi"`))
+	assert.Equal(t, `C:\\temp\\`, escapeDOT(`C:\temp\`))
+	assert.Equal(t, `first\nsecond`, escapeDOT("first\nsecond"))
+	assert.Equal(t, `\\\"`, escapeDOT(`\"`))
+}
+
+func TestMakeDiagramNodeAttributes(t *testing.T) {
+	assert.Equal(t, `id="diagram-technical-asset-some-asset" tooltip="Some \"Asset\"\nRAA: 5 %"`,
+		makeDiagramNodeAttributes("technical-asset", "some-asset", "Some \"Asset\"\nRAA: 5 %", ""))
+	assert.Equal(t, `id="diagram-data-asset-some-data" tooltip="Some Data" URL="report.html#data-asset-some-data" target="_top"`,
+		makeDiagramNodeAttributes("data-asset", "some-data", "Some Data", "report.html"))
+}
+
+func TestWriteDataFlowDiagramGraphvizDOT(t *testing.T) {
+	parsedModel := readExampleModel(t)
+	parsedModel.TechnicalAssets["sql-database"].Title = "Customer \"Contract\" Database\\Backup\nPrimary"
+	parsedModel.TechnicalAssets["sql-database"].RAA = 100
+
+	dotFilename := filepath.Join(t.TempDir(), "data-flow-diagram.gv")
+	_, err := WriteDataFlowDiagramGraphvizDOT(parsedModel, dotFilename, 96, true, false, "report.html", &testProgressReporter{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dot, err := os.ReadFile(dotFilename)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(dot), `id="diagram-technical-asset-sql-database" tooltip="Customer \"Contract\" Database\\Backup\nPrimary\nConfidentiality: strictly-confidential, Integrity: mission-critical, Availability: mission-critical\nRAA: 100 %\nRisks: `)
+	assert.Contains(t, string(dot), `URL="report.html#technical-asset-sql-database" target="_top"`)
+	assert.Contains(t, string(dot), `id="diagram-communication-link-erp-system>database-traffic" tooltip="Database Traffic (jdbc)"`)
+	assert.Contains(t, string(dot), hash("sql-database")+" [")
+
+	// without a report there is nothing to link to
+	_, err = WriteDataFlowDiagramGraphvizDOT(parsedModel, dotFilename, 96, true, false, "", &testProgressReporter{})
+	assert.NoError(t, err)
+
+	dot, err = os.ReadFile(dotFilename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(dot), `id="diagram-technical-asset-sql-database" tooltip="`)
+	assert.NotContains(t, string(dot), `URL=`)
+}
+
+func TestWriteDataAssetDiagramGraphvizDOT(t *testing.T) {
+	parsedModel := readExampleModel(t)
+	parsedModel.DataAssets["customer-accounts"].Title = `Customer "Accounts"`
+
+	dotFilename := filepath.Join(t.TempDir(), "data-asset-diagram.gv")
+	_, err := WriteDataAssetDiagramGraphvizDOT(parsedModel, dotFilename, 96, "report.html", &testProgressReporter{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dot, err := os.ReadFile(dotFilename)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(dot), `id="diagram-data-asset-customer-accounts" tooltip="Customer \"Accounts\"\n`)
+	assert.Contains(t, string(dot), `URL="report.html#data-asset-customer-accounts" target="_top"`)
+	assert.Contains(t, string(dot), `URL="report.html#technical-asset-sql-database" target="_top"`)
+}
+
+type testProgressReporter struct {
+}
+
+func (what *testProgressReporter) Info(_ ...any)  {}
+func (what *testProgressReporter) Warn(_ ...any)  {}
+func (what *testProgressReporter) Error(_ ...any) {}
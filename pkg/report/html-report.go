@@ -0,0 +1,505 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/threagile/threagile/pkg/types"
+)
+
+//go:embed html-report.tmpl
+var htmlReportTemplate string
+
+type htmlReport struct {
+	riskRules        types.RiskRules
+	hideEmptyChapter bool
+}
+
+func NewHtmlReport(riskRules types.RiskRules, hideEmptyChapter bool) htmlReport {
+	return htmlReport{
+		riskRules:        riskRules,
+		hideEmptyChapter: hideEmptyChapter,
+	}
+}
+
+type htmlReportData struct {
+	Model            *types.Model
+	ModelFilename    string
+	ModelHash        string
+	ThreagileVersion string
+	BuildTimestamp   string
+	ExecutionTime    string
+	IntroTextRAA     template.HTML
+	DataFlowDiagram  template.HTML
+	DataAssetDiagram template.HTML
+
+	Severities      []types.RiskSeverity
+	Statuses        []types.RiskStatus
+	SeverityCounts  []*htmlSeverityCount
+	StatusCounts    []int
+	TotalRisks      int
+	StillAtRisk     int
+	Risks           []*htmlRisk
+	Categories      []*htmlCategory
+	TechnicalAssets []*htmlTechnicalAsset
+	RAA             []*htmlTechnicalAsset
+	DataAssets      []*htmlDataAsset
+	STRIDE          []*htmlRiskGroup
+	Functions       []*htmlRiskGroup
+	RiskRules       []*htmlRiskRule
+
+	TechnicalAssetsByTitle []*types.TechnicalAsset
+	DataAssetsByTitle      []*types.DataAsset
+}
+
+type htmlReportChapter struct {
+	Id      string
+	Title   string
+	Content template.HTML
+}
+
+type htmlSeverityCount struct {
+	Severity      types.RiskSeverity
+	Total         int
+	StillAtRisk   int
+	CountByStatus []int
+}
+
+type htmlRisk struct {
+	Risk           *types.Risk
+	Title          template.HTML
+	Category       *types.RiskCategory
+	TechnicalAsset *types.TechnicalAsset
+	Tracking       *types.RiskTracking
+}
+
+type htmlCategory struct {
+	Category        *types.RiskCategory
+	Risks           []*htmlRisk
+	StillAtRisk     int
+	HighestSeverity types.RiskSeverity
+}
+
+type htmlTechnicalAsset struct {
+	Asset           *types.TechnicalAsset
+	Risks           []*htmlRisk
+	StillAtRisk     int
+	HighestSeverity types.RiskSeverity
+	Confidentiality types.Confidentiality
+	Integrity       types.Criticality
+	Availability    types.Criticality
+	RAABreakdown    string
+}
+
+type htmlDataAsset struct {
+	Asset                  *types.DataAsset
+	Probability            types.DataBreachProbability
+	ProbabilityStillAtRisk types.DataBreachProbability
+	Risks                  []*htmlRisk
+}
+
+type htmlRiskGroup struct {
+	Title      string
+	Count      int
+	Categories []*htmlCategory
+}
+
+type htmlRiskRule struct {
+	Category *types.RiskCategory
+	Kind     string
+	Skipped  bool
+}
+
+func (r htmlReport) WriteReport(reportFilename string,
+	model *types.Model,
+	dataFlowDiagramSVG []byte,
+	dataAssetDiagramSVG []byte,
+	modelFilename string,
+	skipRiskRules []string,
+	buildTimestamp string,
+	threagileVersion string,
+	modelHash string,
+	introTextRAA string,
+	customRiskRules types.RiskRules,
+	hideChapters map[ChaptersToShowHide]bool) error {
+	reportTemplate, err := template.New("report").Funcs(template.FuncMap{
+		"basicHtml":       basicHtml,
+		"firstParagraph":  firstParagraph,
+		"severityColor":   htmlSeverityColor,
+		"statusColor":     htmlStatusColor,
+		"colorOutOfScope": func() template.CSS { return template.CSS(rgbHexColorOutOfScope()) },
+		"inc":             func(value int) int { return value + 1 },
+	}).Parse(htmlReportTemplate)
+	if err != nil {
+		return fmt.Errorf("error parsing html report template: %w", err)
+	}
+
+	data := r.collectData(model, dataFlowDiagramSVG, dataAssetDiagramSVG, modelFilename, skipRiskRules, buildTimestamp, threagileVersion, modelHash, introTextRAA, customRiskRules)
+	hasRisks := data.TotalRisks > 0
+	chapters := []struct {
+		id       string
+		title    string
+		template string
+		show     bool
+	}{
+		{"management-summary", "Management Summary", "managementSummary", true},
+		{"risk-mitigation-status", "Risk Mitigation Status", "riskMitigationStatus", true},
+		{"asset-register", "Asset Register", "assetRegister", !hideChapters[AssetRegister]},
+		{"data-flow-diagram", "Data-Flow Diagram", "dataFlowDiagram", len(data.DataFlowDiagram) > 0 || !r.hideEmptyChapter},
+		{"identified-risks", "Identified Risks", "identifiedRisks", hasRisks || !r.hideEmptyChapter},
+		{"stride", "STRIDE Classification of Identified Risks", "stride", hasRisks || !r.hideEmptyChapter},
+		{"assignment-by-function", "Assignment by Function", "assignmentByFunction", hasRisks || !r.hideEmptyChapter},
+		{"raa", "RAA Analysis", "raa", len(data.RAA) > 0 || !r.hideEmptyChapter},
+		{"data-breach-probabilities", "Data Breach Probabilities", "dataBreachProbabilities", len(data.DataAssets) > 0 || !r.hideEmptyChapter},
+		{"risks-by-category", "Risks by Vulnerability Category", "riskCategories", hasRisks || !r.hideEmptyChapter},
+		{"risks-by-technical-asset", "Risks by Technical Asset", "technicalAssets", len(data.TechnicalAssets) > 0 || !r.hideEmptyChapter},
+		{"risk-rules-checked", "Risk Rules Checked by Threagile", "riskRulesChecked", !hideChapters[RiskRulesCheckedByThreagile]},
+	}
+
+	rendered := make([]*htmlReportChapter, 0)
+	for _, chapter := range chapters {
+		if !chapter.show {
+			continue
+		}
+
+		var content bytes.Buffer
+		err = reportTemplate.ExecuteTemplate(&content, chapter.template, data)
+		if err != nil {
+			return fmt.Errorf("error writing html report chapter %q: %w", chapter.title, err)
+		}
+
+		rendered = append(rendered, &htmlReportChapter{
+			Id:      chapter.id,
+			Title:   chapter.title,
+			Content: template.HTML(content.String()), // #nosec G203 -- rendered by html/template
+		})
+	}
+
+	var output bytes.Buffer
+	err = reportTemplate.ExecuteTemplate(&output, "report", struct {
+		*htmlReportData
+		Chapters []*htmlReportChapter
+	}{data, rendered})
+	if err != nil {
+		return fmt.Errorf("error writing html report: %w", err)
+	}
+
+	err = os.WriteFile(reportFilename, output.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", reportFilename, err)
+	}
+
+	return nil
+}
+
+func (r htmlReport) collectData(model *types.Model,
+	dataFlowDiagramSVG []byte,
+	dataAssetDiagramSVG []byte,
+	modelFilename string,
+	skipRiskRules []string,
+	buildTimestamp string,
+	threagileVersion string,
+	modelHash string,
+	introTextRAA string,
+	customRiskRules types.RiskRules) *htmlReportData {
+	data := &htmlReportData{
+		Model:            model,
+		ModelFilename:    modelFilename,
+		ModelHash:        modelHash,
+		ThreagileVersion: threagileVersion,
+		BuildTimestamp:   buildTimestamp,
+		ExecutionTime:    time.Now().Format("20060102150405"),
+		IntroTextRAA:     basicHtml(introTextRAA),
+		DataFlowDiagram:  inlineSVG(dataFlowDiagramSVG),
+		DataAssetDiagram: inlineSVG(dataAssetDiagramSVG),
+		Severities: []types.RiskSeverity{
+			types.CriticalSeverity,
+			types.HighSeverity,
+			types.ElevatedSeverity,
+			types.MediumSeverity,
+			types.LowSeverity,
+		},
+		Statuses: []types.RiskStatus{
+			types.Unchecked,
+			types.InDiscussion,
+			types.Accepted,
+			types.InProgress,
+			types.Mitigated,
+			types.FalsePositive,
+		},
+		TechnicalAssetsByTitle: sortedTechnicalAssetsByTitle(model),
+		DataAssetsByTitle:      sortedDataAssetsByTitle(model),
+	}
+
+	risksByCategory := model.GeneratedRisksByCategoryWithCurrentStatus()
+	risksById := make(map[string]*htmlRisk)
+	for _, category := range model.SortedRiskCategories() {
+		data.Categories = append(data.Categories, r.newHtmlCategory(model, category, model.SortedRisksOfCategory(category), risksById))
+	}
+
+	allRisks := model.AllRisksWithCurrentStatus()
+	types.SortByRiskSeverity(allRisks)
+	for _, risk := range allRisks {
+		if item, ok := risksById[risk.SyntheticId]; ok {
+			data.Risks = append(data.Risks, item)
+		}
+	}
+
+	data.TotalRisks = len(data.Risks)
+	data.StatusCounts = make([]int, len(data.Statuses))
+	for _, severity := range data.Severities {
+		count := &htmlSeverityCount{Severity: severity, CountByStatus: make([]int, len(data.Statuses))}
+		for _, risk := range data.Risks {
+			if risk.Risk.Severity != severity {
+				continue
+			}
+
+			count.Total++
+			if risk.Risk.RiskStatus.IsStillAtRisk() {
+				count.StillAtRisk++
+			}
+
+			for i, status := range data.Statuses {
+				if risk.Risk.RiskStatus == status {
+					count.CountByStatus[i]++
+					data.StatusCounts[i]++
+				}
+			}
+		}
+
+		data.StillAtRisk += count.StillAtRisk
+		data.SeverityCounts = append(data.SeverityCounts, count)
+	}
+
+	for _, technicalAsset := range sortedTechnicalAssetsByRiskSeverityAndTitle(model) {
+		if technicalAsset.OutOfScope {
+			continue
+		}
+
+		data.TechnicalAssets = append(data.TechnicalAssets, r.newHtmlTechnicalAsset(model, technicalAsset, risksById))
+	}
+
+	for _, technicalAsset := range sortedTechnicalAssetsByRAAAndTitle(model) {
+		if technicalAsset.OutOfScope {
+			continue
+		}
+
+		data.RAA = append(data.RAA, r.newHtmlTechnicalAsset(model, technicalAsset, risksById))
+	}
+
+	dataAssets := sortedDataAssetsByTitle(model)
+	sortByDataAssetDataBreachProbabilityAndTitle(model, dataAssets)
+	for _, dataAsset := range dataAssets {
+		item := &htmlDataAsset{
+			Asset:                  dataAsset,
+			Probability:            model.IdentifiedDataBreachProbability(dataAsset),
+			ProbabilityStillAtRisk: identifiedDataBreachProbabilityStillAtRisk(model, dataAsset),
+		}
+
+		breachRisks := model.IdentifiedDataBreachProbabilityRisks(dataAsset)
+		types.SortByRiskSeverity(breachRisks)
+		for _, risk := range breachRisks {
+			if riskItem, ok := risksById[risk.SyntheticId]; ok {
+				item.Risks = append(item.Risks, riskItem)
+			}
+		}
+
+		data.DataAssets = append(data.DataAssets, item)
+	}
+
+	strides := []types.STRIDE{
+		types.Spoofing,
+		types.Tampering,
+		types.Repudiation,
+		types.InformationDisclosure,
+		types.DenialOfService,
+		types.ElevationOfPrivilege,
+	}
+	for _, stride := range strides {
+		data.STRIDE = append(data.STRIDE, r.newHtmlRiskGroup(model, stride.Title(), reduceToSTRIDERisk(model, risksByCategory, stride), risksById))
+	}
+
+	riskFunctions := []types.RiskFunction{
+		types.BusinessSide,
+		types.Architecture,
+		types.Development,
+		types.Operations,
+	}
+	for _, riskFunction := range riskFunctions {
+		data.Functions = append(data.Functions, r.newHtmlRiskGroup(model, riskFunction.Title(), reduceToFunctionRisk(model, risksByCategory, riskFunction), risksById))
+	}
+
+	data.RiskRules = r.riskRulesChecked(model, skipRiskRules, customRiskRules)
+	return data
+}
+
+func (r htmlReport) newHtmlCategory(model *types.Model, category *types.RiskCategory, risks []*types.Risk, risksById map[string]*htmlRisk) *htmlCategory {
+	result := &htmlCategory{
+		Category:        category,
+		StillAtRisk:     len(types.ReduceToOnlyStillAtRisk(risks)),
+		HighestSeverity: types.HighestSeverityStillAtRisk(risks),
+	}
+
+	for _, risk := range risks {
+		item, ok := risksById[risk.SyntheticId]
+		if !ok {
+			item = &htmlRisk{
+				Risk:           risk,
+				Title:          basicHtml(risk.Title),
+				Category:       category,
+				TechnicalAsset: model.TechnicalAssets[risk.MostRelevantTechnicalAssetId],
+				Tracking:       model.GetRiskTracking(risk),
+			}
+			risksById[risk.SyntheticId] = item
+		}
+
+		result.Risks = append(result.Risks, item)
+	}
+
+	return result
+}
+
+func (r htmlReport) newHtmlTechnicalAsset(model *types.Model, technicalAsset *types.TechnicalAsset, risksById map[string]*htmlRisk) *htmlTechnicalAsset {
+	risks := model.GeneratedRisks(technicalAsset)
+	result := &htmlTechnicalAsset{
+		Asset:           technicalAsset,
+		StillAtRisk:     len(types.ReduceToOnlyStillAtRisk(risks)),
+		HighestSeverity: types.HighestSeverityStillAtRisk(risks),
+		Confidentiality: model.HighestTechnicalAssetConfidentiality(technicalAsset),
+		Integrity:       model.HighestIntegrity(technicalAsset),
+		Availability:    model.HighestAvailability(technicalAsset),
+		RAABreakdown:    raaBreakdownText(technicalAsset),
+	}
+
+	for _, risk := range risks {
+		if item, ok := risksById[risk.SyntheticId]; ok {
+			result.Risks = append(result.Risks, item)
+		}
+	}
+
+	return result
+}
+
+func (r htmlReport) newHtmlRiskGroup(model *types.Model, title string, risksByCategory map[string][]*types.Risk, risksById map[string]*htmlRisk) *htmlRiskGroup {
+	categories := make([]*types.RiskCategory, 0)
+	for categoryId := range risksByCategory {
+		if category := model.GetRiskCategory(categoryId); category != nil {
+			categories = append(categories, category)
+		}
+	}
+	model.SortByRiskCategoryHighestContainingRiskSeveritySortStillAtRisk(categories)
+
+	result := &htmlRiskGroup{
+		Title: title,
+		Count: countRisks(risksByCategory),
+	}
+
+	for _, category := range categories {
+		risks := risksByCategory[category.ID]
+		types.SortByRiskSeverity(risks)
+		result.Categories = append(result.Categories, r.newHtmlCategory(model, category, risks, risksById))
+	}
+
+	return result
+}
+
+func (r htmlReport) riskRulesChecked(model *types.Model, skipRiskRules []string, customRiskRules types.RiskRules) []*htmlRiskRule {
+	result := make([]*htmlRiskRule, 0)
+	for _, id := range sortedRiskRuleIds(customRiskRules) {
+		result = append(result, &htmlRiskRule{
+			Category: customRiskRules[id].Category(),
+			Kind:     "Custom Risk Rule",
+			Skipped:  contains(skipRiskRules, id),
+		})
+	}
+
+	individualCategories := append(make([]*types.RiskCategory, 0), model.CustomRiskCategories...)
+	sort.Sort(types.ByRiskCategoryTitleSort(individualCategories))
+	for _, category := range individualCategories {
+		result = append(result, &htmlRiskRule{
+			Category: category,
+			Kind:     "Individual Risk Category",
+		})
+	}
+
+	for _, id := range sortedRiskRuleIds(r.riskRules) {
+		category := r.riskRules[id].Category()
+		result = append(result, &htmlRiskRule{
+			Category: category,
+			Skipped:  contains(skipRiskRules, category.ID),
+		})
+	}
+
+	return result
+}
+
+func sortedRiskRuleIds(rules types.RiskRules) []string {
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func htmlSeverityColor(severity types.RiskSeverity) template.CSS {
+	switch severity {
+	case types.CriticalSeverity:
+		return template.CSS(rgbHexColorCriticalRisk())
+	case types.HighSeverity:
+		return template.CSS(rgbHexColorHighRisk())
+	case types.ElevatedSeverity:
+		return template.CSS(rgbHexColorElevatedRisk())
+	case types.MediumSeverity:
+		return template.CSS(rgbHexColorMediumRisk())
+	default:
+		return template.CSS(rgbHexColorLowRisk())
+	}
+}
+
+func htmlStatusColor(status types.RiskStatus) template.CSS {
+	switch status {
+	case types.InDiscussion:
+		return template.CSS(rgbHexColorRiskStatusInDiscussion())
+	case types.Accepted:
+		return template.CSS(rgbHexColorRiskStatusAccepted())
+	case types.InProgress:
+		return template.CSS(rgbHexColorRiskStatusInProgress())
+	case types.Mitigated:
+		return template.CSS(rgbHexColorRiskStatusMitigated())
+	case types.FalsePositive:
+		return template.CSS(rgbHexColorRiskStatusFalsePositive())
+	default:
+		return template.CSS(RgbHexColorRiskStatusUnchecked())
+	}
+}
+
+var basicHtmlTags = strings.NewReplacer(
+	"&lt;b&gt;", "<b>", "&lt;/b&gt;", "</b>",
+	"&lt;i&gt;", "<i>", "&lt;/i&gt;", "</i>",
+	"&lt;u&gt;", "<u>", "&lt;/u&gt;", "</u>",
+	"&lt;br&gt;", "<br>", "&lt;/br&gt;", "<br>", "&lt;br/&gt;", "<br>",
+)
+
+// basicHtml escapes text from the model but keeps the few formatting tags used in titles and descriptions
+func basicHtml(text string) template.HTML {
+	return template.HTML(basicHtmlTags.Replace(html.EscapeString(text))) // #nosec G203 -- escaped before re-enabling formatting tags
+}
+
+// inlineSVG strips the XML prolog graphviz writes in front of the svg element
+func inlineSVG(svg []byte) template.HTML {
+	start := bytes.Index(svg, []byte("<svg"))
+	if start < 0 {
+		return ""
+	}
+
+	return template.HTML(svg[start:]) // #nosec G203 -- rendered by graphviz from escaped labels
+}
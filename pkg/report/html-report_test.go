@@ -0,0 +1,150 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/risks"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+func TestHtmlReportOfExampleModel(t *testing.T) {
+	parsedModel := readExampleModel(t)
+	parsedModel.TechnicalAssets["apache-webserver"].Description = `<script>alert("x")</script> with <b>bold</b> text`
+
+	report := writeHtmlReport(t, parsedModel, false, nil)
+
+	for _, chapter := range []string{
+		"management-summary",
+		"risk-mitigation-status",
+		"asset-register",
+		"data-flow-diagram",
+		"identified-risks",
+		"stride",
+		"assignment-by-function",
+		"raa",
+		"data-breach-probabilities",
+		"risks-by-category",
+		"risks-by-technical-asset",
+		"risk-rules-checked",
+	} {
+		assert.Contains(t, report, `<section id="`+chapter+`">`)
+		assert.Contains(t, report, `<a href="#`+chapter+`">`)
+	}
+
+	assert.Contains(t, report, `<h3 id="technical-asset-sql-database">`)
+	assert.Contains(t, report, `<h3 id="data-asset-customer-accounts">`)
+	assert.Contains(t, report, `<h3 id="category-something-strange">`)
+	assert.Contains(t, report, `<tr id="risk-something-strange@sql-database"`)
+	assert.Contains(t, report, `<div class="diagram"><svg`)
+
+	assert.Contains(t, report, `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; with <b>bold</b> text`)
+	assert.NotContains(t, report, `<script>alert(`)
+}
+
+func TestHtmlReportHideChapters(t *testing.T) {
+	report := writeHtmlReport(t, readExampleModel(t), false, map[ChaptersToShowHide]bool{
+		AssetRegister:               true,
+		RiskRulesCheckedByThreagile: true,
+	})
+
+	assert.NotContains(t, report, `<section id="asset-register">`)
+	assert.NotContains(t, report, `<section id="risk-rules-checked">`)
+	assert.Contains(t, report, `<section id="identified-risks">`)
+}
+
+func TestHtmlReportHideEmptyChapters(t *testing.T) {
+	emptyModel := &types.Model{
+		Title:                    "Empty",
+		TechnicalAssets:          map[string]*types.TechnicalAsset{},
+		DataAssets:               map[string]*types.DataAsset{},
+		GeneratedRisksByCategory: map[string][]*types.Risk{},
+		RiskTracking:             map[string]*types.RiskTracking{},
+	}
+
+	emptyChapters := []string{"data-flow-diagram", "identified-risks", "stride", "assignment-by-function", "raa", "data-breach-probabilities", "risks-by-category", "risks-by-technical-asset"}
+
+	shown := writeHtmlReport(t, emptyModel, false, nil)
+	for _, chapter := range emptyChapters {
+		assert.Contains(t, shown, `<section id="`+chapter+`">`)
+	}
+
+	hidden := writeHtmlReport(t, emptyModel, true, nil)
+	for _, chapter := range emptyChapters {
+		assert.NotContains(t, hidden, `<section id="`+chapter+`">`)
+	}
+
+	assert.Contains(t, hidden, `<section id="management-summary">`)
+	assert.Contains(t, hidden, `<section id="asset-register">`)
+}
+
+func TestBasicHtml(t *testing.T) {
+	assert.Equal(t, "<b>bold</b>, <i>italic</i>, <u>underlined</u><br>next line<br>",
+		string(basicHtml("<b>bold</b>, <i>italic</i>, <u>underlined</u><br>next line</br>")))
+	assert.Equal(t, `&lt;a href=&#34;x&#34; onclick=&#39;y&#39;&gt;link&lt;/a&gt; &amp;amp;`,
+		string(basicHtml(`<a href="x" onclick='y'>link</a> &amp;`)))
+	assert.Equal(t, "&lt;b onmouseover=x&gt;", string(basicHtml("<b onmouseover=x>")))
+}
+
+func writeHtmlReport(t *testing.T, parsedModel *types.Model, hideEmptyChapter bool, hideChapters map[ChaptersToShowHide]bool) string {
+	filename := filepath.Join(t.TempDir(), "report.html")
+	dataFlowDiagram := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" + `<svg width="10pt" height="10pt"></svg>`)
+	if len(parsedModel.TechnicalAssets) == 0 {
+		dataFlowDiagram = nil
+	}
+
+	err := NewHtmlReport(risks.GetBuiltInRiskRules(), hideEmptyChapter).WriteReport(filename, parsedModel, dataFlowDiagram, nil,
+		"threagile.yaml", nil, "20240102", "1.0.0", "hash", "RAA intro", make(types.RiskRules), hideChapters)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+
+	report, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	return string(report)
+}
+
+// readExampleModel parses the example model and generates its risks with the built-in risk rules
+func readExampleModel(t *testing.T) *types.Model {
+	modelInput, err := model.ReadModelInput("../../demo/example/threagile.yaml", "")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	riskRules := risks.GetBuiltInRiskRules()
+	parsedModel, err := model.ParseModel(&testConfig{}, modelInput, riskRules, make(types.RiskRules))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for id, rule := range riskRules {
+		generatedRisks, riskError := rule.GenerateRisks(parsedModel)
+		assert.NoError(t, riskError, id)
+		if len(generatedRisks) > 0 {
+			parsedModel.GeneratedRisksByCategory[id] = generatedRisks
+		}
+	}
+
+	for _, generatedRisks := range parsedModel.GeneratedRisksByCategory {
+		for _, risk := range generatedRisks {
+			parsedModel.GeneratedRisksBySyntheticId[strings.ToLower(risk.SyntheticId)] = risk
+		}
+	}
+
+	return parsedModel
+}
+
+type testConfig struct {
+}
+
+func (what *testConfig) GetAppFolder() string {
+	return ""
+}
+
+func (what *testConfig) GetTechnologyFilename() string {
+	return ""
+}
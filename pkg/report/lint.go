@@ -0,0 +1,61 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/lint"
+)
+
+const (
+	LintFormatText = "text"
+	LintFormatJSON = "json"
+)
+
+func LintFormats() []string {
+	return []string{LintFormatText, LintFormatJSON}
+}
+
+func WriteLintFindings(writer io.Writer, findings []*lint.Finding, format string) error {
+	switch strings.ToLower(format) {
+	case LintFormatText, "":
+		return WriteLintFindingsText(writer, findings)
+
+	case LintFormatJSON:
+		return WriteLintFindingsJSON(writer, findings)
+
+	default:
+		return fmt.Errorf("unknown lint format %q, expected one of %v", format, LintFormats())
+	}
+}
+
+func WriteLintFindingsJSON(writer io.Writer, findings []*lint.Finding) error {
+	jsonBytes, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lint findings to JSON: %w", err)
+	}
+
+	_, err = fmt.Fprintln(writer, string(jsonBytes))
+	return err
+}
+
+// WriteLintFindingsText writes one line per finding, prefixed with its position like compiler messages
+func WriteLintFindingsText(writer io.Writer, findings []*lint.Finding) error {
+	text := new(strings.Builder)
+	for _, finding := range findings {
+		if finding.SourcePosition != nil {
+			text.WriteString(finding.SourcePosition.String() + ": ")
+		}
+		_, _ = fmt.Fprintf(text, "%v: %v [%v]\n", finding.Severity, finding.Message, finding.RuleId)
+	}
+
+	_, _ = fmt.Fprintf(text, "%d error(s), %d warning(s), %d info(s)\n",
+		lint.CountBySeverity(findings, lint.SeverityError),
+		lint.CountBySeverity(findings, lint.SeverityWarning),
+		lint.CountBySeverity(findings, lint.SeverityInfo))
+
+	_, err := io.WriteString(writer, text.String())
+	return err
+}
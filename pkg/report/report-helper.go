@@ -1,11 +1,22 @@
 package report
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/threagile/threagile/pkg/types"
 )
 
+// modelTitleWithVariant is the title of the model followed by the variant its overlays were applied for
+func modelTitleWithVariant(parsedModel *types.Model) string {
+	if len(parsedModel.Variant) == 0 {
+		return parsedModel.Title
+	}
+
+	return parsedModel.Title + " (" + parsedModel.Variant + ")"
+}
+
 func filteredByRiskStatus(parsedModel *types.Model, status types.RiskStatus) []*types.Risk {
 	filteredRisks := make([]*types.Risk, 0)
 	for _, risks := range parsedModel.GeneratedRisksByCategoryWithCurrentStatus() {
@@ -92,6 +103,28 @@ func sortedTechnicalAssetsByRAAAndTitle(parsedModel *types.Model) []*types.Techn
 	return assets
 }
 
+// raaBreakdownText describes how the attacker attractiveness of the technical asset was composed
+func raaBreakdownText(technicalAsset *types.TechnicalAsset) string {
+	breakdown := technicalAsset.RAABreakdown
+	if breakdown == nil {
+		return ""
+	}
+
+	text := fmt.Sprintf("Score %.0f = (asset %.0f + processed/stored data %.0f + transferred data %.0f)",
+		breakdown.Score, breakdown.AssetScore, breakdown.ProcessedOrStoredDataScore, breakdown.TransferredDataScore)
+	if breakdown.TechnologyMultiplier != 1 {
+		text += fmt.Sprintf(" x %.2f (%v)", breakdown.TechnologyMultiplier, strings.Join(breakdown.TechnologyMultiplierReason, ", "))
+	}
+	if breakdown.MultiTenantMultiplier != 1 {
+		text += fmt.Sprintf(" x %.2f (multi-tenant)", breakdown.MultiTenantMultiplier)
+	}
+	if breakdown.PivotingAdjustment > 0 {
+		text += fmt.Sprintf(", pivoting-factor +%.1f", breakdown.PivotingAdjustment)
+	}
+
+	return text
+}
+
 func sortedKeysOfQuestions(parsedModel *types.Model) []string {
 	keys := make([]string, 0)
 	for k := range parsedModel.Questions {
@@ -163,7 +163,7 @@ func (r *pdfReporter) addBreadcrumb(parsedModel *types.Model) {
 		uni := r.pdf.UnicodeTranslatorFromDescriptor("")
 		r.pdf.SetFont("Helvetica", "", 10)
 		r.pdf.SetTextColor(127, 127, 127)
-		r.pdf.Text(46.7, 24.5, uni(r.currentChapterTitleBreadcrumb+"   -   "+parsedModel.Title))
+		r.pdf.Text(46.7, 24.5, uni(r.currentChapterTitleBreadcrumb+"   -   "+modelTitleWithVariant(parsedModel)))
 	}
 }
 
@@ -198,6 +198,9 @@ func (r *pdfReporter) createCover(parsedModel *types.Model) {
 	}
 	r.pdf.Text(40.7, 145, reportDate.Format("2 January 2006"))
 	r.pdf.Text(40.7, 153, uni(parsedModel.Author.Name))
+	if len(parsedModel.Variant) > 0 {
+		r.pdf.Text(40.7, 161, uni("Variant: "+parsedModel.Variant))
+	}
 	r.pdf.SetFont("Helvetica", "", 10)
 	r.pdf.SetTextColor(80, 80, 80)
 	r.pdf.Text(8.6, 275, parsedModel.Author.Homepage)
@@ -1692,6 +1695,13 @@ func (r *pdfReporter) createRAA(parsedModel *types.Model, introTextRAA string) {
 		strBuilder.WriteString(uni(technicalAsset.Description))
 		html.Write(5, strBuilder.String())
 		strBuilder.Reset()
+		if breakdown := raaBreakdownText(technicalAsset); len(breakdown) > 0 {
+			r.pdf.SetFont("Helvetica", "", fontSizeSmall)
+			r.pdfColorGray()
+			html.Write(5, "<br>"+uni(breakdown))
+			r.pdf.SetFont("Helvetica", "", fontSizeBody)
+			r.pdf.SetTextColor(0, 0, 0)
+		}
 		r.pdf.Link(9, posY, 190, r.pdf.GetY()-posY+4, r.tocLinkIdByAssetId[technicalAsset.Id])
 	}
 
@@ -0,0 +1,348 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchemaUri = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// sarifModelRoot is the base of the model file locations, the directory of the model file
+	sarifModelRoot = "MODELROOT"
+)
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                         `json:"tool"`
+	OriginalUriBaseIds map[string]*sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []*sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	Version        string       `json:"version,omitempty"`
+	InformationUri string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string              `json:"id"`
+	Name                 string              `json:"name"`
+	ShortDescription     sarifMessage        `json:"shortDescription"`
+	FullDescription      sarifMessage        `json:"fullDescription"`
+	Help                 sarifMessage        `json:"help"`
+	HelpUri              string              `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration  `json:"defaultConfiguration"`
+	Properties           sarifRuleProperties `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperties struct {
+	Tags             []string `json:"tags,omitempty"`
+	SecuritySeverity string   `json:"security-severity,omitempty"`
+}
+
+type sarifMessage struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type sarifResult struct {
+	RuleId              string                `json:"ruleId"`
+	RuleIndex           int                   `json:"ruleIndex"`
+	Level               string                `json:"level"`
+	Message             sarifMessage          `json:"message"`
+	Locations           []*sarifLocation      `json:"locations"`
+	PartialFingerprints map[string]string     `json:"partialFingerprints"`
+	Suppressions        []*sarifSuppression   `json:"suppressions,omitempty"`
+	Properties          sarifResultProperties `json:"properties"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation   `json:"physicalLocation"`
+	LogicalLocations []*sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	Uri         string        `json:"uri"`
+	UriBaseId   string        `json:"uriBaseId,omitempty"`
+	Description *sarifMessage `json:"description,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifResultProperties struct {
+	Severity               string `json:"severity"`
+	ExploitationLikelihood string `json:"exploitation_likelihood"`
+	ExploitationImpact     string `json:"exploitation_impact"`
+	RiskStatus             string `json:"risk_status"`
+	DataBreachProbability  string `json:"data_breach_probability"`
+}
+
+// WriteRisksSARIF writes all generated risks as SARIF 2.1.0 log, one rule per risk category and one result per risk
+func WriteRisksSARIF(readResult *model.ReadResult, modelFilename string, version string, filename string) error {
+	sarif := createRisksSARIF(readResult.ParsedModel, modelFilename, version)
+	jsonBytes, err := json.MarshalIndent(sarif, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal risks to SARIF: %w", err)
+	}
+	err = os.WriteFile(filename, jsonBytes, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write risks to SARIF file: %w", err)
+	}
+	return nil
+}
+
+func createRisksSARIF(parsedModel *types.Model, modelFilename string, version string) *sarifLog {
+	modelDirectory, err := filepath.Abs(filepath.Dir(modelFilename))
+	if err != nil {
+		modelDirectory = filepath.Dir(modelFilename)
+	}
+
+	run := &sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "Threagile",
+				Version:        version,
+				InformationUri: "https://threagile.io",
+				Rules:          make([]*sarifRule, 0),
+			},
+		},
+		OriginalUriBaseIds: map[string]*sarifArtifactLocation{
+			sarifModelRoot: {
+				Uri:         strings.TrimSuffix(sarifFileUri(modelDirectory), "/") + "/", // base ids end with a slash
+				Description: &sarifMessage{Text: "The directory of the model file"},
+			},
+		},
+		Results: make([]*sarifResult, 0),
+	}
+
+	for _, category := range parsedModel.SortedRiskCategories() {
+		risks := parsedModel.SortedRisksOfCategory(category)
+		if len(risks) == 0 {
+			continue
+		}
+
+		ruleIndex := len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleFor(category, types.HighestSeverityStillAtRisk(risks)))
+		for _, risk := range risks {
+			run.Results = append(run.Results, sarifResultFor(parsedModel, modelDirectory, modelFilename, category, ruleIndex, risk))
+		}
+	}
+
+	return &sarifLog{
+		Schema:  sarifSchemaUri,
+		Version: sarifVersion,
+		Runs:    []*sarifRun{run},
+	}
+}
+
+func sarifRuleFor(category *types.RiskCategory, severity types.RiskSeverity) *sarifRule {
+	tags := []string{"security", "threat-model", category.STRIDE.Title(), category.Function.Title()}
+	if category.CWE > 0 {
+		tags = append(tags, fmt.Sprintf("external/cwe/cwe-%d", category.CWE))
+	}
+
+	help := new(strings.Builder)
+	if len(category.Mitigation) > 0 {
+		help.WriteString("Mitigation: " + removeFormattingTags(category.Mitigation) + "\n\n")
+	}
+	if len(category.ASVS) > 0 {
+		help.WriteString("ASVS: " + category.ASVS + "\n\n")
+	}
+	if len(category.CheatSheet) > 0 {
+		help.WriteString("Cheat Sheet: " + category.CheatSheet + "\n\n")
+	}
+	if category.CWE > 0 {
+		help.WriteString(fmt.Sprintf("CWE: https://cwe.mitre.org/data/definitions/%d.html\n", category.CWE))
+	}
+
+	helpMarkdown := new(strings.Builder)
+	if len(category.Mitigation) > 0 {
+		helpMarkdown.WriteString("**Mitigation:** " + removeFormattingTags(category.Mitigation) + "\n\n")
+	}
+	if len(category.ASVS) > 0 {
+		helpMarkdown.WriteString("**ASVS:** " + category.ASVS + "\n\n")
+	}
+	if len(category.CheatSheet) > 0 {
+		helpMarkdown.WriteString("**Cheat Sheet:** <" + category.CheatSheet + ">\n\n")
+	}
+	if category.CWE > 0 {
+		helpMarkdown.WriteString(fmt.Sprintf("**CWE:** [CWE-%d](https://cwe.mitre.org/data/definitions/%d.html)\n", category.CWE, category.CWE))
+	}
+
+	return &sarifRule{
+		Id:                   category.ID,
+		Name:                 category.Title,
+		ShortDescription:     sarifMessage{Text: category.Title},
+		FullDescription:      sarifMessage{Text: removeFormattingTags(category.Description)},
+		Help:                 sarifMessage{Text: strings.TrimSpace(help.String()), Markdown: strings.TrimSpace(helpMarkdown.String())},
+		HelpUri:              category.CheatSheet,
+		DefaultConfiguration: sarifConfiguration{Level: sarifLevel(severity)},
+		Properties: sarifRuleProperties{
+			Tags:             tags,
+			SecuritySeverity: sarifSecuritySeverity(severity),
+		},
+	}
+}
+
+func sarifResultFor(parsedModel *types.Model, modelDirectory string, modelFilename string, category *types.RiskCategory, ruleIndex int, risk *types.Risk) *sarifResult {
+	result := &sarifResult{
+		RuleId:    category.ID,
+		RuleIndex: ruleIndex,
+		Level:     sarifLevel(risk.Severity),
+		Message:   sarifMessage{Text: removeFormattingTags(risk.Title)},
+		Locations: []*sarifLocation{sarifLocationFor(parsedModel, modelDirectory, modelFilename, risk)},
+		PartialFingerprints: map[string]string{
+			"threagileSyntheticId/v1": risk.SyntheticId,
+		},
+		Properties: sarifResultProperties{
+			Severity:               risk.Severity.String(),
+			ExploitationLikelihood: risk.ExploitationLikelihood.String(),
+			ExploitationImpact:     risk.ExploitationImpact.String(),
+			RiskStatus:             risk.RiskStatus.String(),
+			DataBreachProbability:  risk.DataBreachProbability.String(),
+		},
+	}
+
+	tracking := parsedModel.GetRiskTracking(risk)
+	if tracking == nil {
+		return result
+	}
+
+	switch tracking.Status {
+	case types.Mitigated, types.FalsePositive, types.Accepted:
+		result.Suppressions = []*sarifSuppression{{Kind: "external", Status: "accepted", Justification: sarifJustification(tracking)}}
+
+	case types.InDiscussion:
+		result.Suppressions = []*sarifSuppression{{Kind: "external", Status: "underReview", Justification: sarifJustification(tracking)}}
+	}
+
+	return result
+}
+
+func sarifJustification(tracking *types.RiskTracking) string {
+	justification := tracking.Status.Title()
+	if len(tracking.Justification) > 0 {
+		justification += ": " + tracking.Justification
+	}
+	return justification
+}
+
+func sarifLevel(severity types.RiskSeverity) string {
+	switch severity {
+	case types.CriticalSeverity, types.HighSeverity:
+		return "error"
+
+	case types.ElevatedSeverity, types.MediumSeverity:
+		return "warning"
+
+	default:
+		return "note"
+	}
+}
+
+// sarifSecuritySeverity is the CVSS like score used by code scanning dashboards to rank results
+func sarifSecuritySeverity(severity types.RiskSeverity) string {
+	return [...]string{"2.0", "5.0", "6.5", "8.0", "9.5"}[severity]
+}
+
+func sarifLocationFor(parsedModel *types.Model, modelDirectory string, modelFilename string, risk *types.Risk) *sarifLocation {
+	var logical *sarifLogicalLocation
+	if link, ok := parsedModel.CommunicationLinks[risk.MostRelevantCommunicationLinkId]; ok {
+		logical = &sarifLogicalLocation{Name: link.Title, FullyQualifiedName: "communication_links/" + link.Id, Kind: "communication_link"}
+	} else if asset, ok := parsedModel.TechnicalAssets[risk.MostRelevantTechnicalAssetId]; ok {
+		logical = &sarifLogicalLocation{Name: asset.Title, FullyQualifiedName: "technical_assets/" + asset.Id, Kind: "technical_asset"}
+	} else if boundary, ok := parsedModel.TrustBoundaries[risk.MostRelevantTrustBoundaryId]; ok {
+		logical = &sarifLogicalLocation{Name: boundary.Title, FullyQualifiedName: "trust_boundaries/" + boundary.Id, Kind: "trust_boundary"}
+	} else if runtime, ok := parsedModel.SharedRuntimes[risk.MostRelevantSharedRuntimeId]; ok {
+		logical = &sarifLogicalLocation{Name: runtime.Title, FullyQualifiedName: "shared_runtimes/" + runtime.Id, Kind: "shared_runtime"}
+	} else if dataAsset, ok := parsedModel.DataAssets[risk.MostRelevantDataAssetId]; ok {
+		logical = &sarifLogicalLocation{Name: dataAsset.Title, FullyQualifiedName: "data_assets/" + dataAsset.Id, Kind: "data_asset"}
+	}
+
+	location := &sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocationFor(modelDirectory, modelFilename),
+		},
+	}
+
+	position := parsedModel.SourcePositionOfRisk(risk)
+	if position != nil {
+		location.PhysicalLocation.ArtifactLocation = sarifArtifactLocationFor(modelDirectory, position.File)
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: position.Line, StartColumn: position.Column}
+	}
+
+	if logical != nil {
+		location.LogicalLocations = []*sarifLogicalLocation{logical}
+	}
+
+	return location
+}
+
+// sarifArtifactLocationFor locates a model file relative to the directory of the model file,
+// so code scanning maps results to the file in the repository rather than to the path it was analyzed at
+func sarifArtifactLocationFor(modelDirectory string, filename string) sarifArtifactLocation {
+	absolute, err := filepath.Abs(filename)
+	if err != nil {
+		return sarifArtifactLocation{Uri: sarifFileUri(filename)}
+	}
+
+	relative, err := filepath.Rel(modelDirectory, absolute)
+	if err != nil {
+		return sarifArtifactLocation{Uri: sarifFileUri(absolute)}
+	}
+
+	return sarifArtifactLocation{Uri: (&url.URL{Path: filepath.ToSlash(relative)}).String(), UriBaseId: sarifModelRoot}
+}
+
+// sarifFileUri is the file URI of an absolute path
+func sarifFileUri(path string) string {
+	uriPath := filepath.ToSlash(path)
+	if !strings.HasPrefix(uriPath, "/") {
+		uriPath = "/" + uriPath
+	}
+
+	return (&url.URL{Scheme: "file", Path: uriPath}).String()
+}
@@ -0,0 +1,115 @@
+package report
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+func TestCreateRisksSARIF(t *testing.T) {
+	modelDirectory := t.TempDir()
+	modelFilename := filepath.Join(modelDirectory, "threagile.yaml")
+
+	parsedModel := &types.Model{
+		TechnicalAssets: map[string]*types.TechnicalAsset{
+			"web-server": {
+				Id:             "web-server",
+				Title:          "Web Server",
+				SourcePosition: &types.SourcePosition{File: filepath.Join(modelDirectory, "includes", "assets.yaml"), Line: 12, Column: 3},
+			},
+			"database": {Id: "database", Title: "Database"},
+		},
+		CustomRiskCategories: []*types.RiskCategory{
+			{ID: "critical-category", Title: "Critical Category", Description: "<b>critical</b> description", Mitigation: "fix it", CWE: 79, STRIDE: types.Tampering, Function: types.Development},
+			{ID: "low-category", Title: "Low Category", Description: "low description"},
+		},
+		GeneratedRisksByCategory: map[string][]*types.Risk{
+			"critical-category": {
+				{CategoryId: "critical-category", SyntheticId: "critical-category@web-server", Title: "Critical at <b>Web Server</b>", Severity: types.CriticalSeverity, MostRelevantTechnicalAssetId: "web-server"},
+				{CategoryId: "critical-category", SyntheticId: "critical-category@database", Title: "Critical at Database", Severity: types.MediumSeverity, MostRelevantTechnicalAssetId: "database"},
+			},
+			"low-category": {
+				{CategoryId: "low-category", SyntheticId: "low-category@database", Title: "Low at Database", Severity: types.LowSeverity, MostRelevantTechnicalAssetId: "database"},
+			},
+		},
+		RiskTracking: map[string]*types.RiskTracking{
+			"critical-category@database": {SyntheticRiskId: "critical-category@database", Status: types.Accepted, Justification: "not reachable"},
+			"low-category@database":      {SyntheticRiskId: "low-category@database", Status: types.InDiscussion},
+		},
+	}
+
+	sarif := createRisksSARIF(parsedModel, modelFilename, "1.2.3")
+
+	assert.Equal(t, "2.1.0", sarif.Version)
+	if !assert.Len(t, sarif.Runs, 1) {
+		return
+	}
+
+	run := sarif.Runs[0]
+	assert.Equal(t, "1.2.3", run.Tool.Driver.Version)
+	assert.Equal(t, sarifFileUri(modelDirectory)+"/", run.OriginalUriBaseIds[sarifModelRoot].Uri)
+
+	// rules by the highest severity still at risk
+	if !assert.Len(t, run.Tool.Driver.Rules, 2) {
+		return
+	}
+
+	criticalRule := run.Tool.Driver.Rules[0]
+	assert.Equal(t, "critical-category", criticalRule.Id)
+	assert.Equal(t, "critical description", criticalRule.FullDescription.Text)
+	assert.Equal(t, "error", criticalRule.DefaultConfiguration.Level)
+	assert.Equal(t, "9.5", criticalRule.Properties.SecuritySeverity)
+	assert.Contains(t, criticalRule.Properties.Tags, "external/cwe/cwe-79")
+	assert.Contains(t, criticalRule.Help.Markdown, "**Mitigation:** fix it")
+	assert.Equal(t, "low-category", run.Tool.Driver.Rules[1].Id)
+	assert.Equal(t, "note", run.Tool.Driver.Rules[1].DefaultConfiguration.Level)
+
+	if !assert.Len(t, run.Results, 3) {
+		return
+	}
+
+	webServerResult := run.Results[0]
+	assert.Equal(t, "critical-category", webServerResult.RuleId)
+	assert.Equal(t, 0, webServerResult.RuleIndex)
+	assert.Equal(t, "error", webServerResult.Level)
+	assert.Equal(t, "Critical at Web Server", webServerResult.Message.Text)
+	assert.Equal(t, "critical-category@web-server", webServerResult.PartialFingerprints["threagileSyntheticId/v1"])
+	assert.Empty(t, webServerResult.Suppressions)
+	assert.Equal(t, sarifArtifactLocation{Uri: "includes/assets.yaml", UriBaseId: sarifModelRoot}, webServerResult.Locations[0].PhysicalLocation.ArtifactLocation)
+	assert.Equal(t, &sarifRegion{StartLine: 12, StartColumn: 3}, webServerResult.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "technical_assets/web-server", webServerResult.Locations[0].LogicalLocations[0].FullyQualifiedName)
+
+	// elements without source position are located at the model file
+	databaseResult := run.Results[1]
+	assert.Equal(t, "warning", databaseResult.Level)
+	assert.Equal(t, sarifArtifactLocation{Uri: "threagile.yaml", UriBaseId: sarifModelRoot}, databaseResult.Locations[0].PhysicalLocation.ArtifactLocation)
+	assert.Nil(t, databaseResult.Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, []*sarifSuppression{{Kind: "external", Status: "accepted", Justification: "Accepted: not reachable"}}, databaseResult.Suppressions)
+	assert.Equal(t, "accepted", databaseResult.Properties.RiskStatus)
+
+	lowResult := run.Results[2]
+	assert.Equal(t, 1, lowResult.RuleIndex)
+	assert.Equal(t, "note", lowResult.Level)
+	assert.Equal(t, []*sarifSuppression{{Kind: "external", Status: "underReview", Justification: "In Discussion"}}, lowResult.Suppressions)
+
+	jsonBytes, err := json.Marshal(sarif)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(jsonBytes), "baselineState")
+}
+
+func TestSarifLevel(t *testing.T) {
+	expected := map[types.RiskSeverity]string{
+		types.CriticalSeverity: "error",
+		types.HighSeverity:     "error",
+		types.ElevatedSeverity: "warning",
+		types.MediumSeverity:   "warning",
+		types.LowSeverity:      "note",
+	}
+
+	for severity, level := range expected {
+		assert.Equal(t, level, sarifLevel(severity), severity.String())
+	}
+}
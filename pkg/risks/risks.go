@@ -2,9 +2,15 @@ package risks
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"github.com/threagile/threagile/pkg/risks/script"
 	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/threagile/threagile/pkg/risks/builtin"
 	"github.com/threagile/threagile/pkg/types"
@@ -87,22 +93,48 @@ func GetScriptRiskRules() (RiskRules, error) {
 }
 
 func (what RiskRules) LoadRiskRules() (RiskRules, error) {
-	fileSystem := ruleScripts
-	walkError := fs.WalkDir(fileSystem, "scripts", func(path string, entry fs.DirEntry, err error) error {
+	return what.loadRiskRules(ruleScripts, "scripts")
+}
+
+// LoadScriptRiskRules loads all user supplied script risk rules (*.yaml) from the given folder
+func LoadScriptRiskRules(folder string) (RiskRules, error) {
+	rules, loadError := make(RiskRules).loadRiskRules(os.DirFS(folder), ".")
+	if loadError != nil {
+		return nil, fmt.Errorf("failed to load script risk rules from %q: %w", folder, loadError)
+	}
+
+	return rules, nil
+}
+
+func (what RiskRules) loadRiskRules(fileSystem fs.FS, root string) (RiskRules, error) {
+	loadErrors := make([]error, 0)
+	filenames := make(map[string]string)
+	walkError := fs.WalkDir(fileSystem, root, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
+		if !entry.IsDir() && !strings.EqualFold(filepath.Ext(path), ".yaml") {
+			return nil
+		}
+
 		newRule := new(script.RiskRule).Init()
 		loadError := newRule.Load(fileSystem, path, entry)
 		if loadError != nil {
-			return loadError
+			loadErrors = append(loadErrors, loadError)
+			return nil
 		}
 
 		if newRule.Category().ID == "" {
 			return nil
 		}
 
+		if previous, ok := filenames[newRule.Category().ID]; ok {
+			loadErrors = append(loadErrors, fmt.Errorf("script risk rule %q in %q is already defined in %q", newRule.Category().ID, path, previous))
+			return nil
+		}
+
+		filenames[newRule.Category().ID] = path
 		what[newRule.Category().ID] = newRule
 		return nil
 	})
@@ -111,5 +143,105 @@ func (what RiskRules) LoadRiskRules() (RiskRules, error) {
 		return nil, walkError
 	}
 
+	if len(loadErrors) > 0 {
+		return nil, errors.Join(loadErrors...)
+	}
+
 	return what, nil
 }
+
+// ApplyScriptRiskRules merges user supplied script risk rules into the given rule sets.
+// A script rule using the ID of a built-in rule has to state explicitly whether it overrides or extends it.
+func ApplyScriptRiskRules(builtinRiskRules types.RiskRules, customRiskRules types.RiskRules, scriptRiskRules RiskRules) (types.RiskRules, types.RiskRules, error) {
+	newBuiltinRiskRules := make(types.RiskRules).Merge(builtinRiskRules)
+	newCustomRiskRules := make(types.RiskRules).Merge(customRiskRules)
+
+	ids := make([]string, 0, len(scriptRiskRules))
+	for id := range scriptRiskRules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		rule := scriptRiskRules[id]
+
+		mode := ""
+		if scriptRule, ok := rule.(*script.RiskRule); ok {
+			mode = scriptRule.Mode()
+		}
+
+		if _, ok := newCustomRiskRules[id]; ok {
+			return nil, nil, fmt.Errorf("script risk rule %q clashes with custom risk rule plugin of the same ID", id)
+		}
+
+		builtinRule, isBuiltin := newBuiltinRiskRules[id]
+		switch {
+		case !isBuiltin && len(mode) > 0:
+			return nil, nil, fmt.Errorf("script risk rule %q is set to %v, but there is no built-in risk rule with this ID", id, mode)
+
+		case !isBuiltin:
+			newCustomRiskRules[id] = rule
+
+		case mode == script.ModeOverride:
+			newBuiltinRiskRules[id] = rule
+
+		case mode == script.ModeExtend:
+			newBuiltinRiskRules[id] = &extendedRiskRule{base: builtinRule, extension: rule}
+
+		default:
+			return nil, nil, fmt.Errorf("script risk rule %q clashes with built-in risk rule: set 'mode' to %q or %q", id, script.ModeOverride, script.ModeExtend)
+		}
+	}
+
+	return newBuiltinRiskRules, newCustomRiskRules, nil
+}
+
+// extendedRiskRule generates the risks of a built-in rule plus those of a script rule extending it
+type extendedRiskRule struct {
+	base      types.RiskRule
+	extension types.RiskRule
+}
+
+func (what *extendedRiskRule) Category() *types.RiskCategory {
+	return what.base.Category()
+}
+
+func (what *extendedRiskRule) SupportedTags() []string {
+	tags := make([]string, 0)
+	tags = append(tags, what.base.SupportedTags()...)
+	for _, tag := range what.extension.SupportedTags() {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+func (what *extendedRiskRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	baseRisks, baseError := what.base.GenerateRisks(parsedModel)
+	if baseError != nil {
+		return nil, baseError
+	}
+
+	extensionRisks, extensionError := what.extension.GenerateRisks(parsedModel)
+	if extensionError != nil {
+		return nil, extensionError
+	}
+
+	ids := make(map[string]bool)
+	for _, risk := range baseRisks {
+		ids[risk.SyntheticId] = true
+	}
+
+	for _, risk := range extensionRisks {
+		if ids[risk.SyntheticId] {
+			continue
+		}
+
+		risk.CategoryId = what.base.Category().ID
+		baseRisks = append(baseRisks, risk)
+	}
+
+	return baseRisks, nil
+}
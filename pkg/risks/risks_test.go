@@ -0,0 +1,106 @@
+package risks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/risks/builtin"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+func writeScriptRiskRule(t *testing.T, folder string, filename string, id string, mode string) {
+	data, readError := ruleScripts.ReadFile("scripts/accidental-secret-leak.yaml")
+	assert.NoError(t, readError)
+
+	text := strings.Replace(string(data), "id: accidental-secret-leak", "id: "+id, 1)
+	if len(mode) > 0 {
+		text = "mode: " + mode + "\n" + text
+	}
+
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, filename), []byte(text), 0600))
+}
+
+func TestLoadScriptRiskRules(t *testing.T) {
+	folder := t.TempDir()
+	writeScriptRiskRule(t, folder, "first.yaml", "first-rule", "")
+	writeScriptRiskRule(t, folder, "second.yaml", "second-rule", "extend")
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "notes.txt"), []byte("not a rule"), 0600))
+
+	rules, err := LoadScriptRiskRules(folder)
+
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Contains(t, rules, "first-rule")
+	assert.Contains(t, rules, "second-rule")
+}
+
+func TestLoadScriptRiskRulesReportsFileAndLine(t *testing.T) {
+	folder := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(folder, "broken.yaml"), []byte("id: broken\nrisk:\n  match: [\n"), 0600))
+
+	_, err := LoadScriptRiskRules(folder)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "broken.yaml:3")
+}
+
+func TestLoadScriptRiskRulesDuplicateId(t *testing.T) {
+	folder := t.TempDir()
+	writeScriptRiskRule(t, folder, "first.yaml", "same-rule", "")
+	writeScriptRiskRule(t, folder, "second.yaml", "same-rule", "")
+
+	_, err := LoadScriptRiskRules(folder)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already defined")
+}
+
+func TestApplyScriptRiskRules(t *testing.T) {
+	builtinRule := builtin.NewMissingVaultRule()
+	builtinRiskRules := types.RiskRules{builtinRule.Category().ID: builtinRule}
+
+	folder := t.TempDir()
+	writeScriptRiskRule(t, folder, "new.yaml", "new-rule", "")
+	writeScriptRiskRule(t, folder, "extend.yaml", builtinRule.Category().ID, "extend")
+	scriptRiskRules, loadError := LoadScriptRiskRules(folder)
+	assert.NoError(t, loadError)
+
+	newBuiltinRiskRules, newCustomRiskRules, err := ApplyScriptRiskRules(builtinRiskRules, make(types.RiskRules), scriptRiskRules)
+
+	assert.NoError(t, err)
+	assert.Contains(t, newCustomRiskRules, "new-rule")
+	assert.IsType(t, &extendedRiskRule{}, newBuiltinRiskRules[builtinRule.Category().ID])
+	assert.Same(t, builtinRule, builtinRiskRules[builtinRule.Category().ID])
+}
+
+func TestApplyScriptRiskRulesClashRequiresMode(t *testing.T) {
+	builtinRule := builtin.NewMissingVaultRule()
+	builtinRiskRules := types.RiskRules{builtinRule.Category().ID: builtinRule}
+
+	folder := t.TempDir()
+	writeScriptRiskRule(t, folder, "clash.yaml", builtinRule.Category().ID, "")
+	scriptRiskRules, loadError := LoadScriptRiskRules(folder)
+	assert.NoError(t, loadError)
+
+	_, _, err := ApplyScriptRiskRules(builtinRiskRules, make(types.RiskRules), scriptRiskRules)
+
+	assert.Error(t, err)
+}
+
+func TestApplyScriptRiskRulesOverride(t *testing.T) {
+	builtinRule := builtin.NewMissingVaultRule()
+	builtinRiskRules := types.RiskRules{builtinRule.Category().ID: builtinRule}
+
+	folder := t.TempDir()
+	writeScriptRiskRule(t, folder, "override.yaml", builtinRule.Category().ID, "override")
+	scriptRiskRules, loadError := LoadScriptRiskRules(folder)
+	assert.NoError(t, loadError)
+
+	newBuiltinRiskRules, _, err := ApplyScriptRiskRules(builtinRiskRules, make(types.RiskRules), scriptRiskRules)
+
+	assert.NoError(t, err)
+	assert.Same(t, scriptRiskRules[builtinRule.Category().ID], newBuiltinRiskRules[builtinRule.Category().ID])
+}
@@ -1,20 +1,33 @@
 package script
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/risks/script/common"
 	"github.com/threagile/threagile/pkg/types"
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	// ModeOverride replaces a built-in risk rule with the same ID
+	ModeOverride = "override"
+
+	// ModeExtend keeps a built-in risk rule with the same ID and adds the risks generated by the script
+	ModeExtend = "extend"
+)
+
 type RiskRule struct {
 	types.RiskRule
 	category      types.RiskCategory
 	supportedTags []string
+	mode          string
 	script        *Script
 }
 
@@ -31,6 +44,7 @@ func (what *RiskRule) ParseFromData(text []byte) (*RiskRule, error) {
 	var rule struct {
 		Category      string         `yaml:"category"`
 		SupportedTags []string       `yaml:"supported-tags"`
+		Mode          string         `yaml:"mode"`
 		Script        map[string]any `yaml:"risk"`
 	}
 
@@ -39,10 +53,23 @@ func (what *RiskRule) ParseFromData(text []byte) (*RiskRule, error) {
 		return nil, ruleError
 	}
 
+	switch strings.ToLower(strings.TrimSpace(rule.Mode)) {
+	case "":
+
+	case ModeOverride:
+		what.mode = ModeOverride
+
+	case ModeExtend:
+		what.mode = ModeExtend
+
+	default:
+		return nil, &keyError{key: "mode", err: fmt.Errorf("unknown mode %q, expected %q or %q", rule.Mode, ModeOverride, ModeExtend)}
+	}
+
 	what.supportedTags = rule.SupportedTags
 	script, scriptError := NewScript(new(input.Strings)).ParseScript(rule.Script)
 	if scriptError != nil {
-		return nil, scriptError
+		return nil, &keyError{key: common.Risk, err: scriptError}
 	}
 
 	what.script = script
@@ -58,6 +85,11 @@ func (what *RiskRule) SupportedTags() []string {
 	return what.supportedTags
 }
 
+// Mode returns how the rule relates to a built-in risk rule with the same ID (ModeOverride, ModeExtend or empty)
+func (what *RiskRule) Mode() string {
+	return what.mode
+}
+
 func (what *RiskRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
 	if what.script == nil {
 		return nil, fmt.Errorf("no script found in risk rule")
@@ -111,8 +143,53 @@ func (what *RiskRule) loadRiskRule(fileSystem fs.FS, filename string) error {
 
 	_, parseError := what.ParseFromData(ruleData)
 	if parseError != nil {
-		return fmt.Errorf("error parsing scripts from %q: %w", scriptFilename, parseError)
+		return fmt.Errorf("error parsing scripts from %v:%v: %w", scriptFilename, errorLine(ruleData, parseError), parseError)
 	}
 
 	return nil
 }
+
+// keyError is an error caused by the value of a top level key of a risk rule file
+type keyError struct {
+	key string
+	err error
+}
+
+func (what *keyError) Error() string {
+	return what.err.Error()
+}
+
+func (what *keyError) Unwrap() error {
+	return what.err
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// errorLine returns the line a parse error refers to: either the line reported by the yaml parser
+// or, for errors in the rule itself, the line of the offending top level key
+func errorLine(data []byte, parseError error) int {
+	match := yamlErrorLine.FindStringSubmatch(parseError.Error())
+	if len(match) > 1 {
+		line, _ := strconv.Atoi(match[1])
+		return line
+	}
+
+	var cause *keyError
+	if !errors.As(parseError, &cause) {
+		return 1
+	}
+
+	var document yaml.Node
+	if yaml.Unmarshal(data, &document) != nil || len(document.Content) == 0 {
+		return 1
+	}
+
+	root := document.Content[0]
+	for n := 0; n+1 < len(root.Content); n += 2 {
+		if strings.EqualFold(root.Content[n].Value, cause.key) {
+			return root.Content[n].Line
+		}
+	}
+
+	return 1
+}
@@ -0,0 +1,480 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+const memoryCheckInterval = 100 * time.Millisecond
+
+// the error of a failed analysis subprocess keeps the last lines of its error output, each up to a maximum length
+const (
+	subprocessErrorLines      = 3
+	subprocessErrorLineLength = 200
+)
+
+// analysisJob is the analysis of a single model file, generating the requested outputs into the output folder
+type analysisJob struct {
+	modelFile        string
+	outputDir        string
+	dpi              int
+	commands         *report.GenerateCommands
+	progressReporter types.ProgressReporter // optional, receives the progress of the analysis in addition to the server log
+	started          func()                 // optional, called once the job got a worker
+}
+
+// analysisRunner runs the analyses of the server on a bounded number of workers, either in-process or
+// (for isolation from memory and/or data leaks of the used third party libs like PDF generation) in a subprocess each
+type analysisRunner struct {
+	config           serverConfigReader
+	builtinRiskRules types.RiskRules
+	customRiskRules  types.RiskRules
+	workers          chan struct{}
+	metrics          *serverMetrics
+}
+
+func newAnalysisRunner(config serverConfigReader, builtinRiskRules types.RiskRules, customRiskRules types.RiskRules, metrics *serverMetrics) *analysisRunner {
+	workers := config.GetServerWorkers()
+	if workers < 1 {
+		workers = 1
+	}
+	return &analysisRunner{
+		config:           config,
+		builtinRiskRules: builtinRiskRules,
+		customRiskRules:  customRiskRules,
+		workers:          make(chan struct{}, workers),
+		metrics:          metrics,
+	}
+}
+
+// run blocks until the job is done, failed, timed out or exceeded its memory limit, or the context is cancelled (e.g. by the client disconnecting)
+func (what *analysisRunner) run(ctx context.Context, job analysisJob) (err error) {
+	select {
+	case what.workers <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("analysis cancelled while waiting for a worker: %w", ctx.Err())
+	}
+
+	if job.started != nil {
+		job.started()
+	}
+
+	start := time.Now()
+	defer func() {
+		what.metrics.observeAnalysis(job.commands, time.Since(start), err)
+	}()
+
+	if timeout := what.config.GetServerJobTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+		defer cancel()
+	}
+
+	// the heap is shared by all analyses running in-process, so only a subprocess can be held to the memory limit
+	if what.config.GetServerSubprocess() || what.memoryLimit() > 0 {
+		defer func() { <-what.workers }()
+		return what.runSubprocess(ctx, job)
+	}
+
+	return what.runInProcess(ctx, job)
+}
+
+func (what *analysisRunner) runInProcess(ctx context.Context, job analysisJob) error {
+	// the analysis writes into a folder of its own, moved into the output folder only when the job wasn't abandoned,
+	// as the caller may remove the output folder as soon as the job returns
+	resultDir, err := os.MkdirTemp(what.config.GetTempFolder(), "threagile-analysis-")
+	if err != nil {
+		return fmt.Errorf("unable to create analysis folder: %w", err)
+	}
+
+	var lock sync.Mutex
+	abandoned := false
+	done := make(chan error, 1)
+	go func() {
+		// an abandoned analysis stops at the next risk rule or report step only, so it keeps its worker until it actually ends
+		defer func() { <-what.workers }()
+		defer func() { _ = os.RemoveAll(resultDir) }()
+
+		resultJob := job
+		resultJob.outputDir = resultDir
+		analyzeError := what.analyze(ctx, resultJob)
+
+		lock.Lock()
+		defer lock.Unlock()
+		if abandoned {
+			return
+		}
+		if analyzeError == nil {
+			analyzeError = moveFolderContent(resultDir, job.outputDir)
+		}
+		done <- analyzeError
+	}()
+
+	select {
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		lock.Lock()
+		defer lock.Unlock()
+		select {
+		case err = <-done: // finished in the meantime
+			return err
+		default:
+			abandoned = true
+			return jobAborted(ctx)
+		}
+	}
+}
+
+func (what *analysisRunner) analyze(ctx context.Context, job analysisJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("analysis failed: %v", r)
+		}
+	}()
+
+	config := &jobConfig{serverConfigReader: what.config, job: job}
+	var progressReporter types.ProgressReporter = DefaultProgressReporter{
+		Verbose:       what.config.GetVerbose(),
+		SuppressError: true,
+	}
+	if job.progressReporter != nil {
+		progressReporter = job.progressReporter
+	}
+
+	modelInput := new(input.Model).Defaults()
+	loadError := modelInput.Load(job.modelFile)
+	if loadError != nil {
+		return fmt.Errorf("unable to load model yaml: %w", loadError)
+	}
+
+	result, err := model.AnalyzeModel(modelInput, config, cancellableRiskRules(ctx, what.builtinRiskRules), cancellableRiskRules(ctx, what.customRiskRules), progressReporter)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range reportSteps(job.commands) {
+		if ctx.Err() != nil {
+			return jobAborted(ctx)
+		}
+
+		err = report.Generate(config, result, step, result.BuiltinRiskRules, progressReporter)
+		if err != nil {
+			return fmt.Errorf("failed to generate reports: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// reportSteps splits the outputs to generate into steps, between which an abandoned analysis stops;
+// the diagrams come first, as the reports embed them
+func reportSteps(commands *report.GenerateCommands) []*report.GenerateCommands {
+	steps := []*report.GenerateCommands{
+		{DataFlowDiagram: commands.DataFlowDiagram, DataAssetDiagram: commands.DataAssetDiagram, ReportHTML: commands.ReportHTML},
+		{RisksJSON: commands.RisksJSON},
+		{RisksSARIF: commands.RisksSARIF},
+		{TechnicalAssetsJSON: commands.TechnicalAssetsJSON},
+		{StatsJSON: commands.StatsJSON},
+		{RisksExcel: commands.RisksExcel},
+		{TagsExcel: commands.TagsExcel},
+		{ReportPDF: commands.ReportPDF},
+		{ReportADOC: commands.ReportADOC},
+	}
+
+	result := make([]*report.GenerateCommands, 0, len(steps))
+	for _, step := range steps {
+		if *step != (report.GenerateCommands{}) {
+			result = append(result, step)
+		}
+	}
+	return result
+}
+
+// cancellableRiskRule skips the risk rule it wraps once the analysis is abandoned
+type cancellableRiskRule struct {
+	types.RiskRule
+	ctx context.Context
+}
+
+func (what *cancellableRiskRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	if what.ctx.Err() != nil {
+		return nil, nil
+	}
+	return what.RiskRule.GenerateRisks(parsedModel)
+}
+
+// cancellableRiskRules wraps the risk rules for an abandoned analysis to stop between them
+func cancellableRiskRules(ctx context.Context, rules types.RiskRules) types.RiskRules {
+	cancellable := make(types.RiskRules, len(rules))
+	for id, rule := range rules {
+		cancellable[id] = &cancellableRiskRule{RiskRule: rule, ctx: ctx}
+	}
+	return cancellable
+}
+
+// moveFolderContent moves the files of the analysis into the output folder, both being within the temp folder
+func moveFolderContent(from string, to string) error {
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return fmt.Errorf("unable to read analysis folder: %w", err)
+	}
+	for _, entry := range entries {
+		err = os.Rename(filepath.Join(from, entry.Name()), filepath.Join(to, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("unable to move analysis result %q: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (what *analysisRunner) runSubprocess(ctx context.Context, job analysisJob) error {
+	configFile, err := what.writeJobConfig(job)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(configFile) }()
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to locate executable: %w", err)
+	}
+
+	// os/exec copies each stream in a goroutine of its own, so they must not share a buffer
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, self, "analyze-model", "--config", configFile) // #nosec G204
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if job.progressReporter != nil {
+		cmd.Stdout = io.MultiWriter(&stdout, &lineWriter{progressReporter: job.progressReporter})
+	}
+	cmd.Env = os.Environ()
+	if what.memoryLimit() > 0 {
+		// makes the garbage collector of the subprocess work harder before the hard limit below is reached
+		cmd.Env = append(cmd.Env, "GOMEMLIMIT="+strconv.Itoa(what.config.GetServerJobMemoryLimit())+"MiB")
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return fmt.Errorf("unable to start analysis: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	memoryLimit := what.memoryLimit()
+	ticker := time.NewTicker(memoryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err = <-done:
+			if ctx.Err() != nil {
+				return jobAborted(ctx)
+			}
+			if err != nil {
+				return subprocessError(err, stderr.String())
+			}
+			if what.config.GetVerbose() && stdout.Len()+stderr.Len() > 0 {
+				fmt.Println("---")
+				fmt.Print(stdout.String())
+				fmt.Print(stderr.String())
+				fmt.Println("---")
+			}
+			return nil
+		case <-ticker.C:
+			if memory, ok := processMemory(cmd.Process.Pid); ok && memoryLimit > 0 && memory > memoryLimit {
+				_ = cmd.Process.Kill()
+				<-done
+				return fmt.Errorf("analysis aborted: memory limit of %v MiB exceeded", what.config.GetServerJobMemoryLimit())
+			}
+		}
+	}
+}
+
+// subprocessError describes a failed analysis by its exit status and the last lines it reported as errors,
+// as the error ends up in the job status and the responses of the api
+func subprocessError(err error, stderr string) error {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(stderr, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		if runes := []rune(line); len(runes) > subprocessErrorLineLength {
+			line = string(runes[:subprocessErrorLineLength]) + "..."
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	if len(lines) > subprocessErrorLines {
+		lines = lines[len(lines)-subprocessErrorLines:]
+	}
+
+	return fmt.Errorf("analysis failed (%w): %v", err, strings.Join(lines, "; "))
+}
+
+// writeJobConfig passes the full server config to the subprocess, adjusted to the job
+func (what *analysisRunner) writeJobConfig(job analysisJob) (string, error) {
+	data, err := json.Marshal(what.config)
+	if err != nil {
+		return "", fmt.Errorf("unable to serialize config: %w", err)
+	}
+
+	// the keys are the ones of the config file
+	values := make(map[string]any)
+	err = json.Unmarshal(data, &values)
+	if err != nil {
+		return "", fmt.Errorf("unable to serialize config: %w", err)
+	}
+
+	values["InputFile"] = job.modelFile
+	values["OutputFolder"] = job.outputDir
+	values["ImportedInputFile"] = ""
+	values["DiagramDPI"] = job.dpi
+	values["ServerMode"] = false
+	values["Interactive"] = false
+	values["FailOn"] = ""
+	if job.progressReporter != nil {
+		values["Verbose"] = true // the progress is streamed from the output of the subprocess
+	}
+	values["SkipDataFlowDiagram"] = !job.commands.DataFlowDiagram
+	values["SkipDataAssetDiagram"] = !job.commands.DataAssetDiagram
+	values["SkipRisksJSON"] = !job.commands.RisksJSON
+	values["SkipRisksSARIF"] = !job.commands.RisksSARIF
+	values["SkipTechnicalAssetsJSON"] = !job.commands.TechnicalAssetsJSON
+	values["SkipStatsJSON"] = !job.commands.StatsJSON
+	values["SkipRisksExcel"] = !job.commands.RisksExcel
+	values["SkipTagsExcel"] = !job.commands.TagsExcel
+	values["SkipReportPDF"] = !job.commands.ReportPDF
+	values["SkipReportADOC"] = !job.commands.ReportADOC
+	values["SkipReportHTML"] = !job.commands.ReportHTML
+
+	data, err = json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("unable to serialize config: %w", err)
+	}
+
+	configFile, err := os.CreateTemp(what.config.GetTempFolder(), "threagile-job-*.json")
+	if err != nil {
+		return "", fmt.Errorf("unable to create job config file: %w", err)
+	}
+	defer func() { _ = configFile.Close() }()
+
+	_, err = configFile.Write(data)
+	if err != nil {
+		_ = os.Remove(configFile.Name())
+		return "", fmt.Errorf("unable to write job config file: %w", err)
+	}
+
+	return configFile.Name(), nil
+}
+
+// analysisCommands are the outputs of a full analysis delivered by the server
+func analysisCommands() *report.GenerateCommands {
+	return &report.GenerateCommands{
+		DataFlowDiagram:     true,
+		DataAssetDiagram:    true,
+		RisksJSON:           true,
+		TechnicalAssetsJSON: true,
+		StatsJSON:           true,
+		RisksExcel:          true,
+		TagsExcel:           true,
+		ReportPDF:           true,
+	}
+}
+
+func (what *analysisRunner) memoryLimit() uint64 {
+	if what.config.GetServerJobMemoryLimit() <= 0 {
+		return 0
+	}
+	return uint64(what.config.GetServerJobMemoryLimit()) * 1024 * 1024
+}
+
+func jobAborted(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("analysis aborted: timeout exceeded")
+	}
+	return fmt.Errorf("analysis aborted: %w", ctx.Err())
+}
+
+// processMemory returns the resident memory of the process, where the operating system tells it (linux)
+func processMemory(pid int) (uint64, bool) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "statm"))
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, false
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return pages * uint64(os.Getpagesize()), true
+}
+
+// lineWriter passes each line written to it as info to the progress reporter
+type lineWriter struct {
+	progressReporter types.ProgressReporter
+	pending          []byte
+}
+
+func (what *lineWriter) Write(data []byte) (int, error) {
+	what.pending = append(what.pending, data...)
+	for {
+		index := bytes.IndexByte(what.pending, '\n')
+		if index < 0 {
+			return len(data), nil
+		}
+		if line := strings.TrimSpace(string(what.pending[:index])); len(line) > 0 {
+			what.progressReporter.Info(line)
+		}
+		what.pending = what.pending[index+1:]
+	}
+}
+
+// jobConfig is the server config with the input and output of the job
+type jobConfig struct {
+	serverConfigReader
+	job analysisJob
+}
+
+func (what *jobConfig) GetInputFile() string {
+	return what.job.modelFile
+}
+
+func (what *jobConfig) GetOutputFolder() string {
+	return what.job.outputDir
+}
+
+func (what *jobConfig) GetImportedInputFile() string {
+	return ""
+}
+
+func (what *jobConfig) GetDiagramDPI() int {
+	return what.job.dpi
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+func TestAnalysisRunnerWorkers(t *testing.T) {
+	config := newTestServerConfig(t)
+	config.workers = 1
+	rule := newBlockingRiskRule()
+	runner := newAnalysisRunner(config, types.RiskRules{"blocking": rule}, nil, newServerMetrics())
+
+	first := newTestAnalysisJob(t, config)
+	firstDone := make(chan error, 1)
+	go func() {
+		firstDone <- runner.run(context.Background(), first)
+	}()
+	<-rule.running
+
+	// the only worker is busy, so the second job waits until its context ends
+	second := newTestAnalysisJob(t, config)
+	started := false
+	second.started = func() { started = true }
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := runner.run(ctx, second)
+	assert.ErrorContains(t, err, "analysis cancelled while waiting for a worker")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, started)
+
+	close(rule.release)
+	assert.NoError(t, <-firstDone)
+	assert.FileExists(t, filepath.Join(first.outputDir, config.GetJsonRisksFilename()))
+	assert.Eventually(t, func() bool { return len(runner.workers) == 0 }, 10*time.Second, 10*time.Millisecond)
+
+	// the released worker takes the next job
+	assert.NoError(t, runner.run(context.Background(), second))
+	assert.True(t, started)
+	assert.FileExists(t, filepath.Join(second.outputDir, config.GetJsonRisksFilename()))
+}
+
+func TestAnalysisRunnerTimeout(t *testing.T) {
+	config := newTestServerConfig(t)
+	config.workers = 1
+	config.jobTimeout = 1
+	rule := newBlockingRiskRule()
+	runner := newAnalysisRunner(config, types.RiskRules{"blocking": rule}, nil, newServerMetrics())
+
+	job := newTestAnalysisJob(t, config)
+	err := runner.run(context.Background(), job)
+	assert.EqualError(t, err, "analysis aborted: timeout exceeded")
+
+	// the abandoned analysis keeps its worker until it ends, but doesn't write into the output folder anymore
+	assert.Len(t, runner.workers, 1)
+	close(rule.release)
+	assert.Eventually(t, func() bool { return len(runner.workers) == 0 }, 10*time.Second, 10*time.Millisecond)
+	assertFolderContent(t, job.outputDir, filepath.Base(job.modelFile))
+	assertFolderContent(t, config.GetTempFolder(), filepath.Base(job.outputDir))
+}
+
+func TestAnalysisRunnerClientDisconnect(t *testing.T) {
+	config := newTestServerConfig(t)
+	rule := newBlockingRiskRule()
+	runner := newAnalysisRunner(config, types.RiskRules{"blocking": rule}, nil, newServerMetrics())
+
+	job := newTestAnalysisJob(t, config)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-rule.running
+		cancel()
+	}()
+	err := runner.run(ctx, job)
+	assert.EqualError(t, err, "analysis aborted: context canceled")
+
+	close(rule.release)
+	assert.Eventually(t, func() bool { return len(runner.workers) == 0 }, 10*time.Second, 10*time.Millisecond)
+	assertFolderContent(t, job.outputDir, filepath.Base(job.modelFile))
+}
+
+func TestReportSteps(t *testing.T) {
+	steps := reportSteps(&report.GenerateCommands{DataAssetDiagram: true, RisksJSON: true, ReportPDF: true, ReportHTML: true})
+	assert.Equal(t, []*report.GenerateCommands{
+		{DataAssetDiagram: true, ReportHTML: true},
+		{RisksJSON: true},
+		{ReportPDF: true},
+	}, steps)
+
+	assert.Empty(t, reportSteps(&report.GenerateCommands{}))
+}
+
+// newTestAnalysisJob writes the test model into an output folder within the temp folder, as the jobs of the server do
+func newTestAnalysisJob(t *testing.T, config *testServerConfig) analysisJob {
+	outputDir, err := os.MkdirTemp(config.GetTempFolder(), "threagile-test-")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	modelFile := filepath.Join(outputDir, config.GetInputFile())
+	modelYaml := testModelYaml[:strings.Index(testModelYaml, "risk_tracking:")] // the tracked risks aren't generated here
+	assert.NoError(t, os.WriteFile(modelFile, []byte(modelYaml), 0600))
+	return analysisJob{
+		modelFile: modelFile,
+		outputDir: outputDir,
+		dpi:       config.GetDiagramDPI(),
+		commands:  &report.GenerateCommands{RisksJSON: true},
+	}
+}
+
+func assertFolderContent(t *testing.T, folder string, expected ...string) {
+	t.Helper()
+	entries, err := os.ReadDir(folder)
+	assert.NoError(t, err)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, expected, names)
+}
+
+// blockingRiskRule blocks the analysis until the test releases it
+type blockingRiskRule struct {
+	running chan struct{}
+	release chan struct{}
+}
+
+func newBlockingRiskRule() *blockingRiskRule {
+	return &blockingRiskRule{running: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (what *blockingRiskRule) Category() *types.RiskCategory {
+	return &types.RiskCategory{ID: "blocking", Title: "Blocking"}
+}
+
+func (what *blockingRiskRule) SupportedTags() []string {
+	return nil
+}
+
+func (what *blockingRiskRule) GenerateRisks(*types.Model) ([]*types.Risk, error) {
+	select {
+	case what.running <- struct{}{}:
+	default:
+	}
+	<-what.release
+	return nil, nil
+}
+
+func TestSubprocessError(t *testing.T) {
+	exitError := errors.New("exit status 1")
+
+	// only the last lines of the error output are kept, shortened
+	err := subprocessError(exitError, "failed to read and analyze model:\n\n  first problem\n  second problem\r\n"+strings.Repeat("x", 300)+"\n")
+	assert.ErrorIs(t, err, exitError)
+	assert.Equal(t, "analysis failed (exit status 1): first problem; second problem; "+strings.Repeat("x", subprocessErrorLineLength)+"...", err.Error())
+
+	err = subprocessError(exitError, " \n")
+	assert.ErrorIs(t, err, exitError)
+	assert.EqualError(t, err, "analysis failed: exit status 1")
+}
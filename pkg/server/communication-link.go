@@ -0,0 +1,333 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type payloadCommunicationLink struct {
+	Title                  string   `yaml:"title" json:"title"`
+	Target                 string   `yaml:"target" json:"target"`
+	Description            string   `yaml:"description" json:"description"`
+	Protocol               string   `yaml:"protocol" json:"protocol"`
+	Authentication         string   `yaml:"authentication" json:"authentication"`
+	Authorization          string   `yaml:"authorization" json:"authorization"`
+	Tags                   []string `yaml:"tags" json:"tags"`
+	VPN                    bool     `yaml:"vpn" json:"vpn"`
+	IpFiltered             bool     `yaml:"ip_filtered" json:"ip_filtered"`
+	Readonly               bool     `yaml:"readonly" json:"readonly"`
+	Usage                  string   `yaml:"usage" json:"usage"`
+	DataAssetsSent         []string `yaml:"data_assets_sent" json:"data_assets_sent"`
+	DataAssetsReceived     []string `yaml:"data_assets_received" json:"data_assets_received"`
+	DiagramTweakWeight     int      `yaml:"diagram_tweak_weight" json:"diagram_tweak_weight"`
+	DiagramTweakConstraint bool     `yaml:"diagram_tweak_constraint" json:"diagram_tweak_constraint"`
+}
+
+func (s *server) getCommunicationLinks(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		_, technicalAsset, ok := findTechnicalAsset(ginContext, modelInput)
+		if ok {
+			ginContext.JSON(http.StatusOK, technicalAsset.CommunicationLinks)
+		}
+	}
+}
+
+func (s *server) getCommunicationLink(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		_, technicalAsset, ok := findTechnicalAsset(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, commLink := range technicalAsset.CommunicationLinks {
+			if matchesCommunicationLinkId(technicalAsset.ID, title, ginContext.Param("communication-link-id")) {
+				ginContext.JSON(http.StatusOK, gin.H{
+					title: commLink,
+				})
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "communication link not found",
+		})
+	}
+}
+
+func (s *server) createNewCommunicationLink(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		techAssetTitle, technicalAsset, ok := findTechnicalAsset(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		payload := payloadCommunicationLink{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		linkId, err := model.CreateDataFlowId(technicalAsset.ID, payload.Title)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+		// different titles may still result in the same id, so check the id for uniqueness
+		for title := range technicalAsset.CommunicationLinks {
+			if matchesCommunicationLinkId(technicalAsset.ID, title, linkId) {
+				ginContext.JSON(http.StatusConflict, gin.H{
+					"error": "communication link with this id already exists",
+				})
+				return
+			}
+		}
+		if !checkTechnicalAssetsExisting(modelInput, []string{payload.Target}) {
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "referenced technical asset does not exist",
+			})
+			return
+		}
+		commLinkInput, ok := populateCommunicationLink(ginContext, payload)
+		if !ok {
+			return
+		}
+		if technicalAsset.CommunicationLinks == nil {
+			technicalAsset.CommunicationLinks = make(map[string]input.CommunicationLink)
+		}
+		technicalAsset.CommunicationLinks[payload.Title] = commLinkInput
+		modelInput.TechnicalAssets[techAssetTitle] = technicalAsset
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Communication Link Creation")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "communication link created",
+				"id":      linkId,
+			})
+		}
+	}
+}
+
+func (s *server) setCommunicationLink(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		_, technicalAsset, ok := findTechnicalAsset(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title := range technicalAsset.CommunicationLinks {
+			if matchesCommunicationLinkId(technicalAsset.ID, title, ginContext.Param("communication-link-id")) {
+				payload := payloadCommunicationLink{}
+				err := ginContext.BindJSON(&payload)
+				if err != nil {
+					log.Println(err)
+					ginContext.JSON(http.StatusBadRequest, gin.H{
+						"error": "unable to parse request payload",
+					})
+					return
+				}
+				oldLinkId, err := model.CreateDataFlowId(technicalAsset.ID, title)
+				if err != nil {
+					handleErrorInServiceCall(err, ginContext)
+					return
+				}
+				newLinkId, err := model.CreateDataFlowId(technicalAsset.ID, payload.Title)
+				if err != nil {
+					handleErrorInServiceCall(err, ginContext)
+					return
+				}
+				for otherTitle := range technicalAsset.CommunicationLinks {
+					if otherTitle != title && matchesCommunicationLinkId(technicalAsset.ID, otherTitle, newLinkId) {
+						ginContext.JSON(http.StatusConflict, gin.H{
+							"error": "communication link with this id already exists",
+						})
+						return
+					}
+				}
+				if !checkTechnicalAssetsExisting(modelInput, []string{payload.Target}) {
+					ginContext.JSON(http.StatusBadRequest, gin.H{
+						"error": "referenced technical asset does not exist",
+					})
+					return
+				}
+				commLinkInput, ok := populateCommunicationLink(ginContext, payload)
+				if !ok {
+					return
+				}
+				// in order to also update the title, remove the link from the map and re-insert it (with new key)
+				delete(technicalAsset.CommunicationLinks, title)
+				technicalAsset.CommunicationLinks[payload.Title] = commLinkInput
+				idChanged := newLinkId != oldLinkId
+				if idChanged { // ID-CHANGE-PROPAGATION
+					renameRiskTrackingElement(&modelInput, oldLinkId, newLinkId)
+					for _, individualRiskCat := range modelInput.CustomRiskCategories {
+						for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+							if individualRiskInstance.MostRelevantCommunicationLink == oldLinkId { // apply the ID change
+								individualRiskInstance.MostRelevantCommunicationLink = newLinkId
+								individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+							}
+						}
+					}
+				}
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Communication Link Update")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":    "communication link updated",
+						"id":         newLinkId,
+						"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "communication link not found",
+		})
+	}
+}
+
+func (s *server) deleteCommunicationLink(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		_, technicalAsset, ok := findTechnicalAsset(ginContext, modelInput)
+		if !ok {
+			return
+		}
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title := range technicalAsset.CommunicationLinks {
+			if matchesCommunicationLinkId(technicalAsset.ID, title, ginContext.Param("communication-link-id")) {
+				linkId, err := model.CreateDataFlowId(technicalAsset.ID, title)
+				if err != nil {
+					handleErrorInServiceCall(err, ginContext)
+					return
+				}
+				// also remove all usages of this communication link !!
+				referencesDeleted := removeRiskTrackingOfElement(&modelInput, linkId)
+				for _, individualRiskCat := range modelInput.CustomRiskCategories {
+					for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+						if individualRiskInstance.MostRelevantCommunicationLink == linkId { // apply the removal
+							referencesDeleted = true
+							individualRiskInstance.MostRelevantCommunicationLink = ""
+							individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+						}
+					}
+				}
+				// remove it itself
+				delete(technicalAsset.CommunicationLinks, title)
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Communication Link Deletion")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":            "communication link deleted",
+						"id":                 linkId,
+						"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "communication link not found",
+		})
+	}
+}
+
+func populateCommunicationLink(ginContext *gin.Context, payload payloadCommunicationLink) (commLinkInput input.CommunicationLink, ok bool) {
+	protocol, err := types.ParseProtocol(payload.Protocol)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return commLinkInput, false
+	}
+	authentication, err := types.ParseAuthentication(payload.Authentication)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return commLinkInput, false
+	}
+	authorization, err := types.ParseAuthorization(payload.Authorization)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return commLinkInput, false
+	}
+	usage, err := types.ParseUsage(payload.Usage)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return commLinkInput, false
+	}
+	commLinkInput = input.CommunicationLink{
+		Target:                 payload.Target,
+		Description:            payload.Description,
+		Protocol:               protocol.String(),
+		Authentication:         authentication.String(),
+		Authorization:          authorization.String(),
+		Tags:                   lowerCaseAndTrim(payload.Tags),
+		VPN:                    payload.VPN,
+		IpFiltered:             payload.IpFiltered,
+		Readonly:               payload.Readonly,
+		Usage:                  usage.String(),
+		DataAssetsSent:         payload.DataAssetsSent,
+		DataAssetsReceived:     payload.DataAssetsReceived,
+		DiagramTweakWeight:     payload.DiagramTweakWeight,
+		DiagramTweakConstraint: payload.DiagramTweakConstraint,
+	}
+	return commLinkInput, true
+}
+
+// findTechnicalAsset looks up the technical asset of the request's path, responding with not found if there is none
+func findTechnicalAsset(ginContext *gin.Context, modelInput input.Model) (title string, technicalAsset input.TechnicalAsset, ok bool) {
+	for title, technicalAsset := range modelInput.TechnicalAssets {
+		if technicalAsset.ID == ginContext.Param("technical-asset-id") {
+			return title, technicalAsset, true
+		}
+	}
+	ginContext.JSON(http.StatusNotFound, gin.H{
+		"error": "technical asset not found",
+	})
+	return title, technicalAsset, false
+}
+
+// matchesCommunicationLinkId accepts both the full link id ("<source-asset-id>><link>") and the part following the source asset id
+func matchesCommunicationLinkId(sourceAssetId string, title string, linkId string) bool {
+	id, err := model.CreateDataFlowId(sourceAssetId, title)
+	if err != nil {
+		return false
+	}
+	return id == linkId || id == sourceAssetId+">"+linkId
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testCommunicationLinkPayload = `{
+	"title": "%v",
+	"target": "%v",
+	"protocol": "jdbc-encrypted",
+	"authentication": "credentials",
+	"authorization": "technical-user",
+	"usage": "business",
+	"data_assets_sent": ["customer-data"]
+}`
+
+func TestCreateCommunicationLink(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+	links := "/models/" + modelId + "/technical-assets/web-server/communication-links"
+
+	response := ts.request(http.MethodPost, links, fmt.Sprintf(testCommunicationLinkPayload, "Replication", "database"), "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "web-server>replication", decodeTestResponse(t, response)["id"])
+	assert.Equal(t, "jdbc-encrypted", ts.readModel(t, key, modelId).TechnicalAssets["Web Server"].CommunicationLinks["Replication"].Protocol)
+
+	// both the full id and the part following the source asset id address the link
+	for _, linkId := range []string{"replication", "web-server>replication"} {
+		response = ts.request(http.MethodGet, links+"/"+linkId, "", "token", token)
+		assert.Equal(t, http.StatusOK, response.Code, linkId)
+		assert.Contains(t, decodeTestResponse(t, response), "Replication")
+	}
+
+	response = ts.request(http.MethodGet, links, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Len(t, decodeTestResponse(t, response), 2)
+
+	// titles resulting in the same id conflict
+	response = ts.request(http.MethodPost, links, fmt.Sprintf(testCommunicationLinkPayload, "replication", "database"), "token", token)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	response = ts.request(http.MethodPost, links, fmt.Sprintf(testCommunicationLinkPayload, "Broken", "unknown"), "token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = ts.request(http.MethodPost, "/models/"+modelId+"/technical-assets/unknown/communication-links", fmt.Sprintf(testCommunicationLinkPayload, "Other", "database"), "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestRenameCommunicationLink(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodPut, "/models/"+modelId+"/technical-assets/web-server/communication-links/database-traffic",
+		fmt.Sprintf(testCommunicationLinkPayload, "Database Queries", "database"), "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, map[string]any{"message": "communication link updated", "id": "web-server>database-queries", "id_changed": true}, decodeTestResponse(t, response))
+
+	modelInput := ts.readModel(t, key, modelId)
+	links := modelInput.TechnicalAssets["Web Server"].CommunicationLinks
+	assert.NotContains(t, links, "Database Traffic")
+	assert.Equal(t, "jdbc-encrypted", links["Database Queries"].Protocol)
+	assert.Contains(t, riskTrackingKeys(modelInput), "unencrypted-communication@web-server>database-queries@web-server@database")
+	assert.NotContains(t, riskTrackingKeys(modelInput), "unencrypted-communication@web-server>database-traffic@web-server@database")
+
+	response = ts.request(http.MethodPut, "/models/"+modelId+"/technical-assets/web-server/communication-links/database-traffic",
+		fmt.Sprintf(testCommunicationLinkPayload, "Database Traffic", "database"), "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestDeleteCommunicationLink(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodDelete, "/models/"+modelId+"/technical-assets/web-server/communication-links/web-server>database-traffic", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, map[string]any{"message": "communication link deleted", "id": "web-server>database-traffic", "references_deleted": true}, decodeTestResponse(t, response))
+
+	modelInput := ts.readModel(t, key, modelId)
+	assert.Empty(t, modelInput.TechnicalAssets["Web Server"].CommunicationLinks)
+	assert.Equal(t, []string{"missing-hardening@database", "missing-vault@*", "unencrypted-asset@database"}, riskTrackingKeys(modelInput))
+
+	response = ts.request(http.MethodDelete, "/models/"+modelId+"/technical-assets/web-server/communication-links/database-traffic", "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
@@ -10,7 +10,6 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -18,6 +17,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/threagile/threagile/pkg/input"
 	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
 	"github.com/threagile/threagile/pkg/risks"
 )
 
@@ -38,7 +38,7 @@ func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []by
 	defer func() {
 		var err error
 		if r := recover(); r != nil {
-			s.errorCount++
+			s.errorCount.Add(1)
 			err = r.(error)
 			log.Println(err)
 			ginContext.JSON(http.StatusBadRequest, gin.H{
@@ -129,10 +129,15 @@ func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []by
 	}
 	defer func() { _ = os.Remove(tmpResultFile.Name()) }()
 
+	commands := &report.GenerateCommands{RisksJSON: true, TechnicalAssetsJSON: true, StatsJSON: true}
 	if dryRun {
-		s.doItViaRuntimeCall(yamlFile, tmpOutputDir, false, false, false, false, false, true, true, true, 40)
+		dpi = 40
 	} else {
-		s.doItViaRuntimeCall(yamlFile, tmpOutputDir, true, true, true, true, true, true, true, true, dpi)
+		commands = analysisCommands()
+	}
+	if !s.runAnalysis(ginContext, yamlFile, tmpOutputDir, commands, dpi) {
+		s.errorCount.Add(1)
+		return yamlContent, false
 	}
 
 	yamlContent, err = os.ReadFile(filepath.Clean(yamlFile))
@@ -172,77 +177,30 @@ func (s *server) execute(ginContext *gin.Context, dryRun bool) (yamlContent []by
 		}
 		ginContext.FileAttachment(tmpResultFile.Name(), "threagile-result.zip")
 	}
-	s.successCount++
+	s.successCount.Add(1)
 	return yamlContent, true
 }
 
-// ultimately to avoid any in-process memory and/or data leaks by the used third party libs like PDF generation: exec and quit
-func (s *server) doItViaRuntimeCall(modelFile string, outputDir string,
-	generateDataFlowDiagram, generateDataAssetDiagram, generateReportPdf, generateRisksExcel, generateTagsExcel, generateRisksJSON, generateTechnicalAssetsJSON, generateStatsJSON bool,
-	dpi int) {
-	// Remember to also add the same args to the exec based sub-process calls!
-	var cmd *exec.Cmd
-	args := []string{"analyze-model",
-		"--model", modelFile,
-		"--output", outputDir,
-		"--execute-model-macro", s.config.GetExecuteModelMacro(),
-		"--custom-risk-rules-plugin", strings.Join(s.config.GetRiskRulePlugins(), ","),
-		"--skip-risk-rules", strings.Join(s.config.GetSkipRiskRules(), ","),
-		"--diagram-dpi", strconv.Itoa(dpi),
-	}
-	if s.config.GetVerbose() {
-		args = append(args, "--verbose")
-	}
-	if s.config.GetIgnoreOrphanedRiskTracking() { // TODO why add all them as arguments, when they are also variables on outer level?
-		args = append(args, "--ignore-orphaned-risk-tracking")
-	}
-	if generateDataFlowDiagram {
-		args = append(args, "--generate-data-flow-diagram")
-	}
-	if generateDataAssetDiagram {
-		args = append(args, "--generate-data-asset-diagram")
-	}
-	if generateReportPdf {
-		args = append(args, "--generate-report-pdf")
-	}
-	if generateRisksExcel {
-		args = append(args, "--generate-risks-excel")
-	}
-	if generateTagsExcel {
-		args = append(args, "--generate-tags-excel")
-	}
-	if generateRisksJSON {
-		args = append(args, "--generate-risks-json")
-	}
-	if generateTechnicalAssetsJSON {
-		args = append(args, "--generate-technical-assets-json")
-	}
-	if generateStatsJSON {
-		args = append(args, "--generate-stats-json")
-	}
-	self, nameError := os.Executable()
-	if nameError != nil {
-		panic(nameError)
-	}
-
-	cmd = exec.Command(self, args...) // #nosec G204
-	out, err := cmd.CombinedOutput()
+// runAnalysis analyzes the model file on one of the workers, generating the requested outputs into the output folder
+func (s *server) runAnalysis(ginContext *gin.Context, modelFile string, outputDir string, commands *report.GenerateCommands, dpi int) bool {
+	err := s.runner.run(ginContext.Request.Context(), analysisJob{
+		modelFile: modelFile,
+		outputDir: outputDir,
+		dpi:       dpi,
+		commands:  commands,
+	})
 	if err != nil {
-		panic(fmt.Errorf("%v", string(out)))
-	} else {
-		if s.config.GetVerbose() && len(out) > 0 {
-			fmt.Println("---")
-			fmt.Print(string(out))
-			fmt.Println("---")
-		}
+		handleErrorInServiceCall(err, ginContext)
+		return false
 	}
+	return true
 }
 
 func (s *server) editModelAnalyze(ginContext *gin.Context) {
 	defer func() {
 		var err error
 		if r := recover(); r != nil {
-			s.errorCount++
+			s.errorCount.Add(1)
 			err = r.(error)
 			log.Println(err)
 			ginContext.JSON(http.StatusBadRequest, gin.H{
@@ -265,7 +223,13 @@ func (s *server) editModelAnalyze(ginContext *gin.Context) {
 		SuppressError: true,
 	}
 	customRiskRules := model.LoadCustomRiskRules(s.config.GetPluginFolder(), s.config.GetRiskRulePlugins(), progressReporter)
-	builtinRiskRules := risks.GetBuiltInRiskRules()
+	builtinRiskRules, customRiskRules, scriptRulesError := model.LoadScriptRiskRules(s.config.GetScriptRulesFolder(), risks.GetBuiltInRiskRules(), customRiskRules, progressReporter)
+	if scriptRulesError != nil {
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Unable to load script risk rules: " + scriptRulesError.Error(),
+		})
+		return
+	}
 
 	result, err := model.AnalyzeModel(&modelInput, s.config, builtinRiskRules, customRiskRules, progressReporter)
 	if err != nil {
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+)
+
+// currentVersion denotes the current model (instead of a history entry) when comparing versions
+const currentVersion = "current"
+
+func (s *server) getHistory(ginContext *gin.Context) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelId, ok := checkModelId(ginContext, ginContext.Param("model-id"))
+	if !ok {
+		return
+	}
+	entries, err := s.models.History(keyIdOfFolder(folderNameOfKey), modelId)
+	if err != nil {
+		handleModelStoreError(err, ginContext, "unable to read history")
+		return
+	}
+	ginContext.JSON(http.StatusOK, entries)
+}
+
+// getHistoryEntry delivers the model yaml as it was before the change of the history entry
+func (s *server) getHistoryEntry(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	yamlBytes, ok := s.readVersion(ginContext, ginContext.Param("model-id"), ginContext.Param("history-id"), key, folderNameOfKey)
+	if ok {
+		ginContext.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.config.GetInputFile()))
+		ginContext.Data(http.StatusOK, "application/x-yaml", yamlBytes)
+	}
+}
+
+// diffHistory compares two versions of the model (history entries or the current model) by their elements and
+// the risks resulting from their analysis
+func (s *server) diffHistory(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	from, to := ginContext.Query("from"), ginContext.DefaultQuery("to", currentVersion)
+	if len(from) == 0 {
+		handleErrorInServiceCall(fmt.Errorf("missing query parameter 'from'"), ginContext)
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	oldResult, ok := s.analyzeVersion(ginContext, from, key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	newResult, ok := s.analyzeVersion(ginContext, to, key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	diff, err := model.DiffModels(oldResult.ParsedModel, newResult.ParsedModel)
+	if err != nil {
+		handleErrorInServiceCall(fmt.Errorf("failed to compare models: %w", err), ginContext)
+		return
+	}
+	ginContext.JSON(http.StatusOK, gin.H{
+		"from": from,
+		"to":   to,
+		"diff": diff,
+	})
+}
+
+// restoreHistoryEntry rolls the model back to the history entry, which is a change recorded in the history itself
+func (s *server) restoreHistoryEntry(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	historyId := ginContext.Param("history-id")
+	yamlBytes, ok := s.readVersion(ginContext, ginContext.Param("model-id"), historyId, key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	modelInput := new(input.Model).Defaults()
+	err := unmarshalModel(yamlBytes, modelInput)
+	if err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to open model",
+		})
+		return
+	}
+	ok = s.writeModel(ginContext, key, folderNameOfKey, modelInput, changeReason("Restore "+historyId, "Restore"))
+	if ok {
+		ginContext.JSON(http.StatusOK, gin.H{
+			"message": "model restored",
+			"id":      historyId,
+		})
+	}
+}
+
+// analyzeVersion analyzes a version of the model, being either a history entry or the current model
+func (s *server) analyzeVersion(ginContext *gin.Context, version string, key []byte, folderNameOfKey string) (*model.ReadResult, bool) {
+	modelId := ginContext.Param("model-id")
+	var modelInput *input.Model
+	if version == currentVersion {
+		currentModel, _, ok := s.readModel(ginContext, modelId, key, folderNameOfKey)
+		if !ok {
+			return nil, false
+		}
+		modelInput = &currentModel
+	} else {
+		yamlBytes, ok := s.readVersion(ginContext, modelId, version, key, folderNameOfKey)
+		if !ok {
+			return nil, false
+		}
+		modelInput = new(input.Model).Defaults()
+		err := unmarshalModel(yamlBytes, modelInput)
+		if err != nil {
+			handleErrorInServiceCall(fmt.Errorf("unable to read history entry %v: %w", version, err), ginContext)
+			return nil, false
+		}
+	}
+	return s.analyzeModelInput(ginContext, modelInput)
+}
+
+// readVersion reads the model yaml of the history entry
+func (s *server) readVersion(ginContext *gin.Context, modelUUID string, historyId string, key []byte, folderNameOfKey string) ([]byte, bool) {
+	modelId, ok := checkModelId(ginContext, modelUUID)
+	if !ok {
+		return nil, false
+	}
+	data, err := s.models.ReadHistory(keyIdOfFolder(folderNameOfKey), modelId, historyId)
+	if err != nil {
+		handleModelStoreError(err, ginContext, "unable to read history")
+		return nil, false
+	}
+	yamlBytes, err := s.decryptModel(key, data)
+	if err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to open model",
+		})
+		return nil, false
+	}
+	return yamlBytes, true
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHistory(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodGet, "/models/"+modelId+"/history", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "[]", response.Body.String())
+
+	response = ts.request(http.MethodDelete, "/models/"+modelId+"/technical-assets/database", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	response = ts.request(http.MethodGet, "/models/"+modelId+"/history", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	entries := ts.history(t, key, modelId)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "Technical Asset Deletion", entries[0].ChangeReason)
+		assert.Contains(t, response.Body.String(), fmt.Sprintf("%q", entries[0].Id))
+
+		// the entry is the model as it was before the change
+		response = ts.request(http.MethodGet, historyPath(modelId, entries[0].Id), "", "token", token)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, testModelYaml, response.Body.String())
+		assert.Equal(t, `attachment; filename="threagile.yaml"`, response.Header().Get("Content-Disposition"))
+	}
+
+	response = ts.request(http.MethodGet, historyPath(modelId, "2024-01-02 03:04:05 Unknown"), "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = ts.request(http.MethodGet, "/models/"+testModelId+"/history", "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// the history belongs to the key of the model
+	otherToken := ts.createToken(t, ts.createKey(t), "")
+	response = ts.request(http.MethodGet, "/models/"+modelId+"/history", "", "token", otherToken)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestDiffHistory(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodDelete, "/models/"+modelId+"/technical-assets/database", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	entries := ts.history(t, key, modelId)
+	if !assert.Len(t, entries, 1) {
+		return
+	}
+	before := entries[0].Id
+
+	response = ts.request(http.MethodGet, "/models/"+modelId+"/history-diff?from="+url.QueryEscape(before), "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	result := decodeTestResponse(t, response)
+	assert.Equal(t, before, result["from"])
+	assert.Equal(t, currentVersion, result["to"])
+	diff := result["diff"].(map[string]any)
+	assert.Equal(t, map[string]any{"removed": []any{"database"}}, diff["technical_assets"])
+	assert.Equal(t, map[string]any{"removed": []any{"web-server>database-traffic"}}, diff["communication_links"])
+	assert.Contains(t, resolvedRiskIds(diff), "sql-nosql-injection@web-server@database@web-server>database-traffic")
+	assert.Contains(t, resolvedRiskIds(diff), "container-baseimage-backdooring@database")
+	assert.NotContains(t, resolvedRiskIds(diff), "container-baseimage-backdooring@web-server")
+
+	// the other way round the risks are added again
+	response = ts.request(http.MethodGet, "/models/"+modelId+"/history-diff?from=current&to="+url.QueryEscape(before), "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	diff = decodeTestResponse(t, response)["diff"].(map[string]any)
+	assert.Equal(t, map[string]any{"added": []any{"database"}}, diff["technical_assets"])
+	assert.NotEmpty(t, diff["risks"].(map[string]any)["added"])
+
+	response = ts.request(http.MethodGet, "/models/"+modelId+"/history-diff?from=current", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	diff = decodeTestResponse(t, response)["diff"].(map[string]any)
+	for _, elements := range []string{"technical_assets", "communication_links", "data_assets", "trust_boundaries", "risks"} {
+		assert.Empty(t, diff[elements], elements)
+	}
+
+	response = ts.request(http.MethodGet, "/models/"+modelId+"/history-diff", "", "token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = ts.request(http.MethodGet, "/models/"+modelId+"/history-diff?from="+url.QueryEscape("2024-01-02 03:04:05 Unknown"), "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestRestoreHistoryEntry(t *testing.T) {
+	ts := newTestServer(t, func(config *testServerConfig) {
+		config.historyToKeep = 2
+	})
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodDelete, "/models/"+modelId+"/technical-assets/database", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	response = ts.request(http.MethodDelete, "/models/"+modelId+"/shared-runtimes/cluster", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	entries := ts.history(t, key, modelId)
+	if !assert.Len(t, entries, 2) {
+		return
+	}
+	original, withoutDatabase := entries[0].Id, entries[1].Id
+
+	response = ts.request(http.MethodPost, historyPath(modelId, original)+"/restore", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, map[string]any{"message": "model restored", "id": original}, decodeTestResponse(t, response))
+
+	modelInput := ts.readModel(t, key, modelId)
+	assert.Contains(t, modelInput.TechnicalAssets, "Database")
+	assert.Contains(t, modelInput.SharedRuntimes, "Cluster")
+
+	// the restore is a change of its own, pruning the oldest entry to keep the configured number of entries
+	entries = ts.history(t, key, modelId)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, withoutDatabase, entries[0].Id)
+		assert.True(t, strings.HasPrefix(entries[1].ChangeReason, "Restore "+original[:10]), entries[1].ChangeReason)
+		assert.NotContains(t, entries[1].ChangeReason, ":")
+
+		// which is the model as it was before the restore
+		response = ts.request(http.MethodGet, historyPath(modelId, entries[1].Id), "", "token", token)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.NotContains(t, response.Body.String(), "Cluster:")
+	}
+
+	// the restored entry is gone now
+	response = ts.request(http.MethodPost, historyPath(modelId, original)+"/restore", "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// restoring an earlier version works the same way
+	response = ts.request(http.MethodPost, historyPath(modelId, withoutDatabase)+"/restore", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	modelInput = ts.readModel(t, key, modelId)
+	assert.NotContains(t, modelInput.TechnicalAssets, "Database")
+	assert.Contains(t, modelInput.SharedRuntimes, "Cluster")
+	assert.Len(t, ts.history(t, key, modelId), 2)
+}
+
+func historyPath(modelId string, historyId string) string {
+	return "/models/" + modelId + "/history/" + url.PathEscape(historyId)
+}
+
+func resolvedRiskIds(diff map[string]any) []string {
+	ids := make([]string, 0)
+	resolved, _ := diff["risks"].(map[string]any)["resolved"].([]any)
+	for _, risk := range resolved {
+		ids = append(ids, risk.(map[string]any)["synthetic_id"].(string))
+	}
+	return ids
+}
@@ -0,0 +1,387 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const defaultJobRetention = time.Hour
+
+type jobState string
+
+const (
+	jobQueued    jobState = "queued"
+	jobRunning   jobState = "running"
+	jobSucceeded jobState = "succeeded"
+	jobFailed    jobState = "failed"
+	jobCancelled jobState = "cancelled"
+)
+
+// jobEvent is a progress message of a job, streamed as server-sent event
+type jobEvent struct {
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+type jobStatus struct {
+	Id        string     `json:"id"`
+	ModelId   string     `json:"model_id"`
+	State     jobState   `json:"state"`
+	Error     string     `json:"error,omitempty"`
+	Created   time.Time  `json:"created"`
+	Started   *time.Time `json:"started,omitempty"`
+	Finished  *time.Time `json:"finished,omitempty"`
+	Artifacts []string   `json:"artifacts,omitempty"`
+}
+
+// backgroundJob is an analysis of a model running independently of the request that created it
+type backgroundJob struct {
+	id              string
+	modelId         string
+	folderNameOfKey string
+	outputDir       string
+	cancel          context.CancelFunc
+
+	lock      sync.Mutex
+	state     jobState
+	err       string
+	created   time.Time
+	started   time.Time
+	finished  time.Time
+	artifacts []string
+	events    []jobEvent
+	changed   chan struct{} // closed (and replaced) on every change to wake up the event streams
+}
+
+func (s *server) createJob(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	if !s.checkObjectCreationThrottler(ginContext, "JOB") {
+		return
+	}
+	dpi, err := strconv.Atoi(ginContext.DefaultQuery("dpi", strconv.Itoa(s.config.GetGraphvizDPI())))
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	modelId := ginContext.Param("model-id")
+	s.lockFolder(folderNameOfKey)
+	_, yamlText, ok := s.readModel(ginContext, modelId, key, folderNameOfKey)
+	s.unlockFolder(folderNameOfKey)
+	if !ok {
+		return
+	}
+	outputDir, err := os.MkdirTemp(s.config.GetTempFolder(), "threagile-job-")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	// the model itself is part of the result as well
+	modelFile := filepath.Join(outputDir, filepath.Base(s.config.GetInputFile()))
+	err = os.WriteFile(modelFile, []byte(yamlText), 0400)
+	if err != nil {
+		_ = os.RemoveAll(outputDir)
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	aJob := &backgroundJob{
+		id:              uuid.New().String(),
+		modelId:         modelId,
+		folderNameOfKey: folderNameOfKey,
+		outputDir:       outputDir,
+		cancel:          cancel,
+		state:           jobQueued,
+		created:         time.Now(),
+		changed:         make(chan struct{}),
+	}
+	s.jobsLock.Lock()
+	s.jobs[aJob.id] = aJob
+	s.jobsLock.Unlock()
+
+	go func() {
+		err := s.runner.run(ctx, analysisJob{
+			modelFile: modelFile,
+			outputDir: outputDir,
+			dpi:       dpi,
+			commands:  s.jobCommands,
+			progressReporter: jobProgressReporter{
+				job: aJob,
+				log: DefaultProgressReporter{Verbose: s.config.GetVerbose(), SuppressError: true},
+			},
+			started: aJob.start,
+		})
+		if err != nil {
+			s.errorCount.Add(1)
+		} else {
+			s.successCount.Add(1)
+		}
+		aJob.finish(ctx, err)
+		time.AfterFunc(s.jobRetention, func() {
+			s.removeJob(aJob.id)
+		})
+	}()
+
+	ginContext.JSON(http.StatusAccepted, gin.H{
+		"message": "job created",
+		"id":      aJob.id,
+	})
+}
+
+func (s *server) getJob(ginContext *gin.Context) {
+	aJob, ok := s.findJob(ginContext)
+	if ok {
+		ginContext.JSON(http.StatusOK, aJob.status())
+	}
+}
+
+// deleteJob cancels the job (if still running) and removes it along with its artifacts
+func (s *server) deleteJob(ginContext *gin.Context) {
+	aJob, ok := s.findJob(ginContext)
+	if ok {
+		s.removeJob(aJob.id)
+		ginContext.JSON(http.StatusOK, gin.H{
+			"message": "job deleted",
+			"id":      aJob.id,
+		})
+	}
+}
+
+// streamJobEvents streams the progress of the job as server-sent events, starting with the ones already reported,
+// and ends with a "state" event once the job is finished
+func (s *server) streamJobEvents(ginContext *gin.Context) {
+	aJob, ok := s.findJob(ginContext)
+	if !ok {
+		return
+	}
+	next := 0
+	ginContext.Stream(func(w io.Writer) bool {
+		events, finished, changed := aJob.eventsSince(next)
+		for _, event := range events {
+			ginContext.SSEvent(event.Level, event)
+		}
+		next += len(events)
+		if finished {
+			ginContext.SSEvent("state", aJob.status())
+			return false
+		}
+		select {
+		case <-changed:
+			return true
+		case <-ginContext.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func (s *server) getJobArtifact(ginContext *gin.Context) {
+	aJob, ok := s.findJob(ginContext)
+	if !ok {
+		return
+	}
+	artifact := ginContext.Param("artifact")
+	for _, name := range aJob.status().Artifacts {
+		if name == artifact {
+			ginContext.FileAttachment(filepath.Join(aJob.outputDir, name), name)
+			return
+		}
+	}
+	ginContext.JSON(http.StatusNotFound, gin.H{
+		"error": "artifact not found",
+	})
+}
+
+// getJobResult delivers all artifacts of the job as zip, like the direct analysis does
+func (s *server) getJobResult(ginContext *gin.Context) {
+	aJob, ok := s.findJob(ginContext)
+	if !ok {
+		return
+	}
+	status := aJob.status()
+	if status.State != jobSucceeded {
+		ginContext.JSON(http.StatusConflict, gin.H{
+			"error": "job not succeeded: " + string(status.State),
+		})
+		return
+	}
+	tmpResultFile, err := os.CreateTemp(s.config.GetTempFolder(), "threagile-result-*.zip")
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	defer func() { _ = os.Remove(tmpResultFile.Name()) }()
+	files := make([]string, 0)
+	for _, name := range status.Artifacts {
+		files = append(files, filepath.Join(aJob.outputDir, name))
+	}
+	err = zipFiles(tmpResultFile.Name(), files)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	ginContext.FileAttachment(tmpResultFile.Name(), "threagile-result.zip")
+}
+
+// findJob finds the job of the id in the request, which needs to belong to the key of the token
+func (s *server) findJob(ginContext *gin.Context) (*backgroundJob, bool) {
+	folderNameOfKey, _, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return nil, false
+	}
+	s.jobsLock.Lock()
+	aJob, exists := s.jobs[ginContext.Param("job-id")]
+	s.jobsLock.Unlock()
+	if !exists || aJob.folderNameOfKey != folderNameOfKey || !tokenAllowsModel(ginContext, aJob.modelId) {
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "job not found",
+		})
+		return nil, false
+	}
+	return aJob, true
+}
+
+func (s *server) removeJob(id string) {
+	s.jobsLock.Lock()
+	aJob, exists := s.jobs[id]
+	delete(s.jobs, id)
+	s.jobsLock.Unlock()
+	if exists {
+		aJob.cancel()
+		err := os.RemoveAll(aJob.outputDir)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+func (what *backgroundJob) start() {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	what.state = jobRunning
+	what.started = time.Now()
+	what.notify()
+}
+
+func (what *backgroundJob) finish(ctx context.Context, err error) {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	what.finished = time.Now()
+	switch {
+	case ctx.Err() != nil:
+		what.state = jobCancelled
+	case err != nil:
+		what.state = jobFailed
+		what.err = strings.TrimSpace(err.Error())
+	default:
+		what.state = jobSucceeded
+		entries, readError := os.ReadDir(what.outputDir)
+		if readError != nil {
+			log.Println(readError)
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() {
+				what.artifacts = append(what.artifacts, entry.Name())
+			}
+		}
+	}
+	what.notify()
+}
+
+func (what *backgroundJob) addEvent(level string, message string) {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	what.events = append(what.events, jobEvent{
+		Level:   level,
+		Message: message,
+		Time:    time.Now(),
+	})
+	what.notify()
+}
+
+// notify wakes up the event streams, needs to be called with the lock held
+func (what *backgroundJob) notify() {
+	close(what.changed)
+	what.changed = make(chan struct{})
+}
+
+func (what *backgroundJob) eventsSince(index int) (events []jobEvent, finished bool, changed <-chan struct{}) {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	events = append(events, what.events[index:]...)
+	finished = what.state == jobSucceeded || what.state == jobFailed || what.state == jobCancelled
+	return events, finished, what.changed
+}
+
+func (what *backgroundJob) status() jobStatus {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	status := jobStatus{
+		Id:        what.id,
+		ModelId:   what.modelId,
+		State:     what.state,
+		Error:     what.err,
+		Created:   what.created,
+		Artifacts: what.artifacts,
+	}
+	if !what.started.IsZero() {
+		started := what.started
+		status.Started = &started
+	}
+	if !what.finished.IsZero() {
+		finished := what.finished
+		status.Finished = &finished
+	}
+	return status
+}
+
+// jobProgressReporter records the progress of the job as events, in addition to logging it like the server does
+type jobProgressReporter struct {
+	job *backgroundJob
+	log DefaultProgressReporter
+}
+
+func (r jobProgressReporter) Info(a ...any) {
+	r.log.Info(a...)
+	r.job.addEvent("info", strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
+}
+
+func (r jobProgressReporter) Warn(a ...any) {
+	r.log.Warn(a...)
+	r.job.addEvent("warn", strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
+}
+
+func (r jobProgressReporter) Error(a ...any) {
+	r.log.Error(a...)
+	r.job.addEvent("error", strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
+}
+
+func (r jobProgressReporter) Infof(format string, a ...any) {
+	r.log.Infof(format, a...)
+	r.job.addEvent("info", fmt.Sprintf(format, a...))
+}
+
+func (r jobProgressReporter) Warnf(format string, a ...any) {
+	r.log.Warnf(format, a...)
+	r.job.addEvent("warn", fmt.Sprintf(format, a...))
+}
+
+func (r jobProgressReporter) Errorf(format string, a ...any) {
+	r.log.Errorf(format, a...)
+	r.job.addEvent("error", fmt.Sprintf(format, a...))
+}
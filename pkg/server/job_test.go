@@ -0,0 +1,181 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/report"
+)
+
+func TestJob(t *testing.T) {
+	ts := newTestServer(t)
+	ts.jobCommands = &report.GenerateCommands{RisksJSON: true, StatsJSON: true}
+	httpServer := httptest.NewServer(ts.router)
+	defer httpServer.Close()
+
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodPost, "/models/"+modelId+"/jobs", "", "token", token)
+	assert.Equal(t, http.StatusAccepted, response.Code, response.Body.String())
+	jobId := decodeTestResponse(t, response)["id"].(string)
+
+	// the stream ends with the state of the job once it is finished
+	events := readTestJobEvents(t, httpServer.URL+"/jobs/"+jobId+"/events", token)
+	if assert.NotEmpty(t, events) {
+		last := events[len(events)-1]
+		assert.Equal(t, "state", last.name)
+		assert.Equal(t, string(jobSucceeded), last.data["state"])
+		assert.Contains(t, events[:len(events)-1], testJobEvent{name: "info", message: "Writing risks json"})
+	}
+
+	// opened later, the stream repeats the events already reported
+	assert.Equal(t, events, readTestJobEvents(t, httpServer.URL+"/jobs/"+jobId+"/events", token))
+
+	response = ts.request(http.MethodGet, "/jobs/"+jobId, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	status := decodeTestResponse(t, response)
+	assert.Equal(t, string(jobSucceeded), status["state"])
+	assert.Equal(t, modelId, status["model_id"])
+	assert.ElementsMatch(t, []any{"risks.json", "stats.json", "threagile.yaml"}, status["artifacts"])
+
+	response = ts.request(http.MethodGet, "/jobs/"+jobId+"/artifacts/risks.json", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"synthetic_id":"unencrypted-asset@database"`)
+	response = ts.request(http.MethodGet, "/jobs/"+jobId+"/artifacts/threagile.yaml", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, testModelYaml, response.Body.String())
+	for _, artifact := range []string{"report.pdf", "..%2Fthreagile.yaml"} {
+		response = ts.request(http.MethodGet, "/jobs/"+jobId+"/artifacts/"+artifact, "", "token", token)
+		assert.Equal(t, http.StatusNotFound, response.Code, artifact)
+	}
+
+	response = ts.request(http.MethodGet, "/jobs/"+jobId+"/result", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/zip", response.Header().Get("Content-Type"))
+
+	// jobs are only visible with a token of their key
+	otherToken := ts.createToken(t, ts.createKey(t), "")
+	response = ts.request(http.MethodGet, "/jobs/"+jobId, "", "token", otherToken)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = ts.request(http.MethodDelete, "/jobs/"+jobId, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = ts.request(http.MethodGet, "/jobs/"+jobId, "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assertFolderContent(t, ts.config.GetTempFolder())
+}
+
+func TestJobRetention(t *testing.T) {
+	ts := newTestServer(t)
+	ts.jobCommands = &report.GenerateCommands{RisksJSON: true}
+	ts.jobRetention = 0
+
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodPost, "/models/"+modelId+"/jobs", "", "token", token)
+	assert.Equal(t, http.StatusAccepted, response.Code, response.Body.String())
+
+	// the finished job is removed along with its artifacts once the retention is over
+	assert.Eventually(t, func() bool {
+		ts.jobsLock.Lock()
+		defer ts.jobsLock.Unlock()
+		return len(ts.jobs) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	assertFolderContent(t, ts.config.GetTempFolder())
+}
+
+func TestJobEventsSince(t *testing.T) {
+	aJob := &backgroundJob{state: jobQueued, changed: make(chan struct{})}
+	events, finished, changed := aJob.eventsSince(0)
+	assert.Empty(t, events)
+	assert.False(t, finished)
+
+	aJob.start()
+	assertClosed(t, changed)
+	aJob.addEvent("info", "first")
+	aJob.addEvent("warn", "second")
+
+	events, finished, changed = aJob.eventsSince(1)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "warn", events[0].Level)
+		assert.Equal(t, "second", events[0].Message)
+	}
+	assert.False(t, finished)
+
+	events, _, _ = aJob.eventsSince(2)
+	assert.Empty(t, events)
+
+	aJob.outputDir = t.TempDir()
+	aJob.finish(context.Background(), nil)
+	assertClosed(t, changed)
+	events, finished, _ = aJob.eventsSince(0)
+	assert.Len(t, events, 2)
+	assert.True(t, finished)
+	assert.Equal(t, jobSucceeded, aJob.status().State)
+}
+
+func assertClosed(t *testing.T, changed <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-changed:
+	default:
+		assert.Fail(t, "channel not closed")
+	}
+}
+
+// testJobEvent is a server-sent event of a job, with the message of progress events and the data of the state event
+type testJobEvent struct {
+	name    string
+	message string
+	data    map[string]any
+}
+
+// readTestJobEvents reads the event stream of the job until the server ends it
+func readTestJobEvents(t *testing.T, url string, token string) []testJobEvent {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	request.Header.Set("token", token)
+	response, err := http.DefaultClient.Do(request)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer func() { _ = response.Body.Close() }()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.True(t, strings.HasPrefix(response.Header.Get("Content-Type"), "text/event-stream"))
+
+	events := make([]testJobEvent, 0)
+	var name string
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimPrefix(line, "event:")
+		case strings.HasPrefix(line, "data:"):
+			var data map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &data))
+			event := testJobEvent{name: name}
+			if name == "state" {
+				event.data = data
+			} else {
+				event.message, _ = data["message"].(string)
+			}
+			events = append(events, event)
+		}
+	}
+	assert.NoError(t, scanner.Err())
+	return events
+}
@@ -0,0 +1,303 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// jsonPatchOperation is a single operation of a JSON Patch (RFC 6902)
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"` // null is a value as well, unlike a missing value
+}
+
+// applyJSONPatch applies the operations of the patch in order to the JSON document, failing as a whole if any of them fails
+func applyJSONPatch(document []byte, patch []byte) ([]byte, error) {
+	var operations []jsonPatchOperation
+	err := json.Unmarshal(patch, &operations)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+
+	var root any
+	err = json.Unmarshal(document, &root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON document: %w", err)
+	}
+
+	for index, operation := range operations {
+		root, err = applyJSONPatchOperation(root, operation)
+		if err != nil {
+			return nil, fmt.Errorf("JSON patch operation %d (%v %v) failed: %w", index, operation.Op, operation.Path, err)
+		}
+	}
+
+	return json.Marshal(root)
+}
+
+func applyJSONPatchOperation(root any, operation jsonPatchOperation) (any, error) {
+	path, err := parseJSONPointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add", "replace", "test":
+		if len(operation.Value) == 0 {
+			return nil, fmt.Errorf("missing value")
+		}
+		var value any
+		err = json.Unmarshal(operation.Value, &value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+		switch operation.Op {
+		case "add":
+			return jsonPointerAdd(root, path, value)
+		case "replace":
+			root, _, err = jsonPointerRemove(root, path)
+			if err != nil {
+				return nil, err
+			}
+			return jsonPointerAdd(root, path, value)
+		default:
+			current, err := jsonPointerGet(root, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, fmt.Errorf("test failed")
+			}
+			return root, nil
+		}
+
+	case "remove":
+		root, _, err = jsonPointerRemove(root, path)
+		return root, err
+
+	case "move", "copy":
+		from, err := parseJSONPointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if operation.Op == "move" {
+			if operation.Path != operation.From && strings.HasPrefix(operation.Path, operation.From+"/") {
+				return nil, fmt.Errorf("unable to move %v into itself", operation.From)
+			}
+			root, value, err = jsonPointerRemove(root, from)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			value, err = jsonPointerGet(root, from)
+			if err != nil {
+				return nil, err
+			}
+			value, err = deepCopyJSON(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return jsonPointerAdd(root, path, value)
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", operation.Op)
+}
+
+// parseJSONPointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens
+func parseJSONPointer(pointer string) ([]string, error) {
+	if len(pointer) == 0 {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func jsonPointerGet(node any, path []string) (any, error) {
+	for _, token := range path {
+		switch container := node.(type) {
+		case map[string]any:
+			child, exists := container[token]
+			if !exists {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			node = child
+		case []any:
+			index, err := jsonArrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = container[index]
+		default:
+			return nil, fmt.Errorf("unable to resolve %q in a scalar value", token)
+		}
+	}
+	return node, nil
+}
+
+// jsonPointerAdd returns the node with the value added, which for arrays may be a different slice
+func jsonPointerAdd(node any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, rest := path[0], path[1:]
+	switch container := node.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			container[token] = value
+			return container, nil
+		}
+		child, exists := container[token]
+		if !exists {
+			return nil, fmt.Errorf("member %q not found", token)
+		}
+		child, err := jsonPointerAdd(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = child
+		return container, nil
+	case []any:
+		if len(rest) == 0 {
+			index := len(container)
+			if token != "-" {
+				var err error
+				index, err = jsonArrayIndex(token, len(container))
+				if err != nil {
+					return nil, err
+				}
+			}
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		}
+		index, err := jsonArrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		container[index], err = jsonPointerAdd(container[index], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		return container, nil
+	}
+	return nil, fmt.Errorf("unable to resolve %q in a scalar value", token)
+}
+
+// jsonPointerRemove returns the node without the value, which for arrays may be a different slice, and the value removed
+func jsonPointerRemove(node any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, node, nil
+	}
+	token, rest := path[0], path[1:]
+	switch container := node.(type) {
+	case map[string]any:
+		child, exists := container[token]
+		if !exists {
+			return nil, nil, fmt.Errorf("member %q not found", token)
+		}
+		if len(rest) == 0 {
+			delete(container, token)
+			return container, child, nil
+		}
+		child, removed, err := jsonPointerRemove(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		container[token] = child
+		return container, removed, nil
+	case []any:
+		index, err := jsonArrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := container[index]
+			return append(container[:index], container[index+1:]...), removed, nil
+		}
+		child, removed, err := jsonPointerRemove(container[index], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		container[index] = child
+		return container, removed, nil
+	}
+	return nil, nil, fmt.Errorf("unable to resolve %q in a scalar value", token)
+}
+
+func jsonArrayIndex(token string, maxIndex int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || strings.Trim(token, "0123456789") != "" || index > maxIndex || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return index, nil
+}
+
+func deepCopyJSON(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result any
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// jsonDocumentOf is the value as its JSON encoding, but with all struct members present (including the omitempty ones
+// with a zero value, like `internet: false` or an empty list), for a patch to be able to test and replace them
+func jsonDocumentOf(value any) any {
+	return jsonDocumentOfValue(reflect.ValueOf(value))
+}
+
+func jsonDocumentOfValue(value reflect.Value) any {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return jsonDocumentOfValue(value.Elem())
+	case reflect.Struct:
+		members := make(map[string]any)
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if len(name) == 0 {
+				name = field.Name
+			}
+			members[name] = jsonDocumentOfValue(value.Field(i))
+		}
+		return members
+	case reflect.Map:
+		members := make(map[string]any)
+		for iterator := value.MapRange(); iterator.Next(); {
+			members[iterator.Key().String()] = jsonDocumentOfValue(iterator.Value())
+		}
+		return members
+	case reflect.Slice, reflect.Array:
+		elements := make([]any, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			elements = append(elements, jsonDocumentOfValue(value.Index(i)))
+		}
+		return elements
+	default:
+		return value.Interface()
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/input"
+)
+
+const testJSONPatchDocument = `{"a": {"b": 1, "c": [1, 2, 3]}, "x~y": {"s/t": true}, "e": []}`
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    string
+		expected string
+	}{
+		{"add member", `[{"op": "add", "path": "/a/d", "value": "new"}]`, `{"a": {"b": 1, "c": [1, 2, 3], "d": "new"}, "x~y": {"s/t": true}, "e": []}`},
+		{"add replaces existing member", `[{"op": "add", "path": "/a/b", "value": {"f": null}}]`, `{"a": {"b": {"f": null}, "c": [1, 2, 3]}, "x~y": {"s/t": true}, "e": []}`},
+		{"add inserts into array", `[{"op": "add", "path": "/a/c/1", "value": 9}]`, `{"a": {"b": 1, "c": [1, 9, 2, 3]}, "x~y": {"s/t": true}, "e": []}`},
+		{"add at end of array", `[{"op": "add", "path": "/a/c/3", "value": 9}]`, `{"a": {"b": 1, "c": [1, 2, 3, 9]}, "x~y": {"s/t": true}, "e": []}`},
+		{"add appends by -", `[{"op": "add", "path": "/a/c/-", "value": 9}, {"op": "add", "path": "/e/-", "value": "first"}]`, `{"a": {"b": 1, "c": [1, 2, 3, 9]}, "x~y": {"s/t": true}, "e": ["first"]}`},
+		{"add null", `[{"op": "add", "path": "/a/d", "value": null}]`, `{"a": {"b": 1, "c": [1, 2, 3], "d": null}, "x~y": {"s/t": true}, "e": []}`},
+		{"add whole document", `[{"op": "add", "path": "", "value": {"z": 1}}]`, `{"z": 1}`},
+		{"remove member", `[{"op": "remove", "path": "/a/b"}]`, `{"a": {"c": [1, 2, 3]}, "x~y": {"s/t": true}, "e": []}`},
+		{"remove array element", `[{"op": "remove", "path": "/a/c/0"}]`, `{"a": {"b": 1, "c": [2, 3]}, "x~y": {"s/t": true}, "e": []}`},
+		{"replace", `[{"op": "replace", "path": "/a/c/2", "value": "three"}]`, `{"a": {"b": 1, "c": [1, 2, "three"]}, "x~y": {"s/t": true}, "e": []}`},
+		{"escaped tokens", `[{"op": "replace", "path": "/x~0y/s~1t", "value": false}]`, `{"a": {"b": 1, "c": [1, 2, 3]}, "x~y": {"s/t": false}, "e": []}`},
+		{"escapes applied once", `[{"op": "add", "path": "/a/~01", "value": 1}]`, `{"a": {"b": 1, "c": [1, 2, 3], "~1": 1}, "x~y": {"s/t": true}, "e": []}`},
+		{"move", `[{"op": "move", "from": "/a/b", "path": "/e/0"}]`, `{"a": {"c": [1, 2, 3]}, "x~y": {"s/t": true}, "e": [1]}`},
+		{"move within array", `[{"op": "move", "from": "/a/c/0", "path": "/a/c/-"}]`, `{"a": {"b": 1, "c": [2, 3, 1]}, "x~y": {"s/t": true}, "e": []}`},
+		{"move to itself", `[{"op": "move", "from": "/a", "path": "/a"}]`, testJSONPatchDocument},
+		{"move to sibling with same prefix", `[{"op": "move", "from": "/a", "path": "/ab"}]`, `{"ab": {"b": 1, "c": [1, 2, 3]}, "x~y": {"s/t": true}, "e": []}`},
+		{"copy", `[{"op": "copy", "from": "/a/c", "path": "/e"}, {"op": "add", "path": "/e/-", "value": 4}]`, `{"a": {"b": 1, "c": [1, 2, 3]}, "x~y": {"s/t": true}, "e": [1, 2, 3, 4]}`},
+		{"test", `[{"op": "test", "path": "/a", "value": {"c": [1, 2, 3], "b": 1}}, {"op": "test", "path": "/x~0y/s~1t", "value": true}, {"op": "test", "path": "/e", "value": []}]`, testJSONPatchDocument},
+		{"operations in order", `[{"op": "add", "path": "/n", "value": 1}, {"op": "test", "path": "/n", "value": 1}, {"op": "remove", "path": "/n"}]`, testJSONPatchDocument},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := applyJSONPatch([]byte(testJSONPatchDocument), []byte(test.patch))
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, test.expected, string(result))
+			}
+		})
+	}
+}
+
+func TestApplyJSONPatchFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		error string
+	}{
+		{"invalid patch", `{"op": "add"}`, "invalid JSON patch"},
+		{"unknown operation", `[{"op": "merge", "path": "/a"}]`, `JSON patch operation 0 (merge /a) failed: unknown operation "merge"`},
+		{"missing value", `[{"op": "add", "path": "/a/d"}]`, "missing value"},
+		{"invalid pointer", `[{"op": "add", "path": "a/d", "value": 1}]`, `invalid JSON pointer "a/d"`},
+		{"add into missing member", `[{"op": "add", "path": "/missing/d", "value": 1}]`, `member "missing" not found`},
+		{"add beyond array", `[{"op": "add", "path": "/a/c/4", "value": 1}]`, `invalid array index "4"`},
+		{"add by leading zero", `[{"op": "add", "path": "/a/c/01", "value": 1}]`, `invalid array index "01"`},
+		{"add into scalar", `[{"op": "add", "path": "/a/b/d", "value": 1}]`, `unable to resolve "d" in a scalar value`},
+		{"remove missing member", `[{"op": "remove", "path": "/a/d"}]`, `member "d" not found`},
+		{"remove by -", `[{"op": "remove", "path": "/a/c/-"}]`, `invalid array index "-"`},
+		{"replace missing member", `[{"op": "replace", "path": "/a/d", "value": 1}]`, `member "d" not found`},
+		{"move into itself", `[{"op": "move", "from": "/a", "path": "/a/c/0"}]`, "unable to move /a into itself"},
+		{"move from missing member", `[{"op": "move", "from": "/a/d", "path": "/e/0"}]`, `member "d" not found`},
+		{"copy from missing member", `[{"op": "copy", "from": "/a/d", "path": "/e/0"}]`, `member "d" not found`},
+		{"test different value", `[{"op": "test", "path": "/a/b", "value": "1"}]`, "test failed"},
+		{"test missing member", `[{"op": "test", "path": "/a/d", "value": null}]`, `member "d" not found`},
+		{"later operation fails", `[{"op": "remove", "path": "/a"}, {"op": "test", "path": "/e", "value": [1]}]`, "JSON patch operation 1 (test /e) failed: test failed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := applyJSONPatch([]byte(testJSONPatchDocument), []byte(test.patch))
+			assert.ErrorContains(t, err, test.error)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestJSONDocumentOf(t *testing.T) {
+	document := jsonDocumentOf(&input.Model{
+		Title: "Some Model",
+		TechnicalAssets: map[string]input.TechnicalAsset{
+			"Web Server": {ID: "web-server"},
+		},
+	}).(map[string]any)
+
+	// the members left out of the JSON encoding for being empty are present
+	assert.Equal(t, "Some Model", document["title"])
+	assert.Equal(t, []any{}, document["tags_available"])
+	assert.Equal(t, map[string]any{}, document["questions"])
+	assert.Nil(t, document["attractiveness"])
+	assert.NotContains(t, document, "Variant")
+
+	webServer := document["technical_assets"].(map[string]any)["Web Server"].(map[string]any)
+	assert.Equal(t, "web-server", webServer["id"])
+	assert.Equal(t, false, webServer["internet"])
+	assert.Equal(t, false, webServer["out_of_scope"])
+	assert.Equal(t, "", webServer["justification_out_of_scope"])
+	assert.Equal(t, []any{}, webServer["tags"])
+	assert.Equal(t, map[string]any{}, webServer["communication_links"])
+	assert.NotContains(t, webServer, "SourcePosition")
+
+	assert.Nil(t, jsonDocumentOf(nil))
+}
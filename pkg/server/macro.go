@@ -0,0 +1,438 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/macros"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+// idle macro sessions are removed after that time
+const macroSessionTimeout = 30 * time.Minute
+
+// macroSession is a model macro (like a wizard) being answered question by question before it is executed on the model
+type macroSession struct {
+	id              string
+	modelId         string
+	folderNameOfKey string
+	macro           macros.Macros
+	parsedModel     *types.Model // the model as it was when the session started, which the questions are based on
+
+	lock     sync.Mutex // the macros aren't safe for concurrent use
+	lastUsed time.Time
+	message  string
+	valid    bool
+}
+
+type payloadMacro struct {
+	Id          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type payloadMacroQuestion struct {
+	Id              string   `json:"id"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description,omitempty"`
+	PossibleAnswers []string `json:"possible_answers,omitempty"`
+	MultiSelect     bool     `json:"multi_select"`
+	DefaultAnswer   string   `json:"default_answer,omitempty"`
+}
+
+type payloadMacroSession struct {
+	Id       string                `json:"id"`
+	ModelId  string                `json:"model_id"`
+	Macro    payloadMacro          `json:"macro"`
+	Question *payloadMacroQuestion `json:"question,omitempty"` // missing once all questions are answered
+	Message  string                `json:"message,omitempty"`  // of the last answer or step back
+	Valid    bool                  `json:"valid"`
+}
+
+type payloadMacroSessionStart struct {
+	Macro string `json:"macro"`
+}
+
+type payloadMacroAnswer struct {
+	QuestionId string   `json:"question_id"` // optional, to detect answering an outdated question
+	Answers    []string `json:"answers"`
+}
+
+func (s *server) listModelMacros(ginContext *gin.Context) {
+	result := make([]payloadMacro, 0)
+	for _, macro := range append(macros.ListBuiltInMacros(), macros.ListCustomMacros()...) {
+		result = append(result, macroPayload(macro))
+	}
+	ginContext.JSON(http.StatusOK, result)
+}
+
+func (s *server) createMacroSession(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	payload := payloadMacroSessionStart{}
+	err := ginContext.BindJSON(&payload)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	macro, err := macros.GetMacroByID(payload.Macro)
+	if err != nil {
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelId, ok := checkModelId(ginContext, ginContext.Param("model-id"))
+	if !ok {
+		return
+	}
+	modelInput, _, ok := s.readModel(ginContext, modelId, key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	result, ok := s.analyzeModelInput(ginContext, &modelInput)
+	if !ok {
+		return
+	}
+	session := &macroSession{
+		id:              uuid.New().String(),
+		modelId:         modelId,
+		folderNameOfKey: folderNameOfKey,
+		macro:           macro,
+		parsedModel:     result.ParsedModel,
+		lastUsed:        time.Now(),
+		valid:           true,
+	}
+	s.macroSessionsLock.Lock()
+	s.housekeepingMacroSessions()
+	s.macroSessions[session.id] = session
+	s.macroSessionsLock.Unlock()
+
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	s.respondMacroSession(ginContext, http.StatusCreated, session)
+}
+
+func (s *server) getMacroSession(ginContext *gin.Context) {
+	session, _, ok := s.findMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	defer session.lock.Unlock()
+	s.respondMacroSession(ginContext, http.StatusOK, session)
+}
+
+// answerMacroQuestion applies the answers to the current question, which are checked against its possible answers
+func (s *server) answerMacroQuestion(ginContext *gin.Context) {
+	session, _, ok := s.findMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	defer session.lock.Unlock()
+	payload := payloadMacroAnswer{}
+	err := ginContext.BindJSON(&payload)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	question, err := nextMacroQuestion(session)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	if question.NoMoreQuestions() {
+		handleErrorInServiceCall(fmt.Errorf("all questions are answered already"), ginContext)
+		return
+	}
+	if len(payload.QuestionId) > 0 && payload.QuestionId != question.ID {
+		ginContext.JSON(http.StatusConflict, gin.H{
+			"error":       "question has been answered in the meantime",
+			"question_id": question.ID,
+		})
+		return
+	}
+	answers, err := checkMacroAnswers(question, payload.Answers)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	err = callMacro(func() (err error) {
+		session.message, session.valid, err = session.macro.ApplyAnswer(question.ID, answers...)
+		return err
+	})
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	s.respondMacroSession(ginContext, http.StatusOK, session)
+}
+
+func (s *server) undoMacroAnswer(ginContext *gin.Context) {
+	session, _, ok := s.findMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	defer session.lock.Unlock()
+	err := callMacro(func() (err error) {
+		session.message, session.valid, err = session.macro.GoBack()
+		return err
+	})
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	s.respondMacroSession(ginContext, http.StatusOK, session)
+}
+
+// getMacroChanges tells the changes executing the macro would apply to the current model
+func (s *server) getMacroChanges(ginContext *gin.Context) {
+	session, key, ok := s.findMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	defer session.lock.Unlock()
+	if !checkMacroAnswered(ginContext, session) {
+		return
+	}
+	s.lockFolder(session.folderNameOfKey)
+	defer s.unlockFolder(session.folderNameOfKey)
+	modelInput, parsedModel, ok := s.readMacroModel(ginContext, session, key)
+	if !ok {
+		return
+	}
+	var changes []string
+	var message string
+	var valid bool
+	err := callMacro(func() (err error) {
+		changes, message, valid, err = session.macro.GetFinalChangeImpact(modelInput, parsedModel)
+		return err
+	})
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	ginContext.JSON(http.StatusOK, gin.H{
+		"changes": changes,
+		"message": message,
+		"valid":   valid,
+	})
+}
+
+// executeMacro applies the macro to the current model, which is a change recorded in the model history, and ends the session
+func (s *server) executeMacro(ginContext *gin.Context) {
+	session, key, ok := s.findMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	defer session.lock.Unlock()
+	if !checkMacroAnswered(ginContext, session) {
+		return
+	}
+	s.lockFolder(session.folderNameOfKey)
+	defer s.unlockFolder(session.folderNameOfKey)
+	modelInput, parsedModel, ok := s.readMacroModel(ginContext, session, key)
+	if !ok {
+		return
+	}
+	var message string
+	var valid bool
+	err := callMacro(func() (err error) {
+		message, valid, err = session.macro.Execute(modelInput, parsedModel)
+		return err
+	})
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	if !valid {
+		handleErrorInServiceCall(fmt.Errorf("macro not executed: %v", message), ginContext)
+		return
+	}
+	ok = s.writeModel(ginContext, key, session.folderNameOfKey, modelInput, changeReason("Macro "+session.macro.GetMacroDetails().ID, "Macro"))
+	if ok {
+		s.removeMacroSession(session.id)
+		ginContext.JSON(http.StatusOK, gin.H{
+			"message": message,
+			"id":      session.modelId,
+		})
+	}
+}
+
+func (s *server) deleteMacroSession(ginContext *gin.Context) {
+	session, _, ok := s.findMacroSession(ginContext)
+	if !ok {
+		return
+	}
+	defer session.lock.Unlock()
+	s.removeMacroSession(session.id)
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "macro session deleted",
+		"id":      session.id,
+	})
+}
+
+// findMacroSession returns the locked session of the model of the request
+func (s *server) findMacroSession(ginContext *gin.Context) (*macroSession, []byte, bool) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return nil, nil, false
+	}
+	modelId, ok := checkModelId(ginContext, ginContext.Param("model-id"))
+	if !ok {
+		return nil, nil, false
+	}
+	s.macroSessionsLock.Lock()
+	s.housekeepingMacroSessions()
+	session, exists := s.macroSessions[ginContext.Param("session-id")]
+	s.macroSessionsLock.Unlock()
+	if !exists || session.folderNameOfKey != folderNameOfKey || session.modelId != modelId {
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "macro session not found",
+		})
+		return nil, nil, false
+	}
+	session.lock.Lock()
+	session.lastUsed = time.Now()
+	return session, key, true
+}
+
+func (s *server) removeMacroSession(id string) {
+	s.macroSessionsLock.Lock()
+	defer s.macroSessionsLock.Unlock()
+	delete(s.macroSessions, id)
+}
+
+// housekeepingMacroSessions removes the idle sessions, the caller holds the lock of the sessions
+func (s *server) housekeepingMacroSessions() {
+	for id, session := range s.macroSessions {
+		if session.lock.TryLock() {
+			if time.Since(session.lastUsed) > macroSessionTimeout {
+				delete(s.macroSessions, id)
+			}
+			session.lock.Unlock()
+		}
+	}
+}
+
+// readMacroModel reads and analyzes the current model, as it may have changed since the session started
+func (s *server) readMacroModel(ginContext *gin.Context, session *macroSession, key []byte) (*input.Model, *types.Model, bool) {
+	modelInput, _, ok := s.readModel(ginContext, session.modelId, key, session.folderNameOfKey)
+	if !ok {
+		return nil, nil, false
+	}
+	result, ok := s.analyzeModelInput(ginContext, &modelInput)
+	if !ok {
+		return nil, nil, false
+	}
+	// the macros add to the maps of the model, which are missing in sparse models
+	if modelInput.DataAssets == nil {
+		modelInput.DataAssets = make(map[string]input.DataAsset)
+	}
+	if modelInput.TechnicalAssets == nil {
+		modelInput.TechnicalAssets = make(map[string]input.TechnicalAsset)
+	}
+	if modelInput.TrustBoundaries == nil {
+		modelInput.TrustBoundaries = make(map[string]input.TrustBoundary)
+	}
+	if modelInput.SharedRuntimes == nil {
+		modelInput.SharedRuntimes = make(map[string]input.SharedRuntime)
+	}
+	if modelInput.RiskTracking == nil {
+		modelInput.RiskTracking = make(map[string]input.RiskTracking)
+	}
+	return &modelInput, result.ParsedModel, true
+}
+
+func (s *server) respondMacroSession(ginContext *gin.Context, status int, session *macroSession) {
+	question, err := nextMacroQuestion(session)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	result := payloadMacroSession{
+		Id:      session.id,
+		ModelId: session.modelId,
+		Macro:   macroPayload(session.macro),
+		Message: session.message,
+		Valid:   session.valid,
+	}
+	if !question.NoMoreQuestions() {
+		result.Question = &payloadMacroQuestion{
+			Id:              question.ID,
+			Title:           question.Title,
+			Description:     question.Description,
+			PossibleAnswers: question.PossibleAnswers,
+			MultiSelect:     question.MultiSelect,
+			DefaultAnswer:   question.DefaultAnswer,
+		}
+	}
+	ginContext.JSON(status, result)
+}
+
+func nextMacroQuestion(session *macroSession) (question macros.MacroQuestion, err error) {
+	err = callMacro(func() (err error) {
+		question, err = session.macro.GetNextQuestion(session.parsedModel)
+		return err
+	})
+	return question, err
+}
+
+// checkMacroAnswered responds with an error unless all questions of the macro are answered
+func checkMacroAnswered(ginContext *gin.Context, session *macroSession) bool {
+	question, err := nextMacroQuestion(session)
+	if err == nil && !question.NoMoreQuestions() {
+		err = fmt.Errorf("question %q is not answered yet", question.ID)
+	}
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return false
+	}
+	return true
+}
+
+// checkMacroAnswers checks the answers like the interactive macro execution does, where no answer means the default one
+func checkMacroAnswers(question macros.MacroQuestion, answers []string) ([]string, error) {
+	if len(answers) == 0 && len(question.DefaultAnswer) > 0 {
+		answers = []string{question.DefaultAnswer}
+	}
+	if !question.MultiSelect && len(answers) != 1 {
+		return nil, fmt.Errorf("question %q takes exactly one answer", question.ID)
+	}
+	if question.IsValueConstrained() {
+		for _, answer := range answers {
+			if !question.IsMatchingValueConstraint(answer) {
+				return nil, fmt.Errorf("answer %q does not match any possible answer of question %q", answer, question.ID)
+			}
+		}
+	}
+	return answers, nil
+}
+
+// callMacro turns a panic of the macro (e.g. due to an unexpected model) into an error
+func callMacro(call func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("macro failed: %v", r)
+		}
+	}()
+	return call()
+}
+
+func macroPayload(macro macros.Macros) payloadMacro {
+	details := macro.GetMacroDetails()
+	return payloadMacro{
+		Id:          details.ID,
+		Title:       details.Title,
+		Description: details.Description,
+	}
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMacroSession(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodPost, "/models/"+modelId+"/macro-sessions", `{"macro": "unknown"}`, "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	session := startTestMacroSession(t, ts, modelId, token, "add-vault")
+	assert.Equal(t, modelId, session.ModelId)
+	assert.Equal(t, "add-vault", session.Macro.Id)
+	assert.True(t, session.Valid)
+	assert.Equal(t, "vault-name", session.Question.Id)
+	sessionPath := "/models/" + modelId + "/macro-sessions/" + session.Id
+
+	// nothing to change before all questions are answered
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		path := sessionPath + "/changes"
+		if method == http.MethodPost {
+			path = sessionPath + "/execute"
+		}
+		response = ts.request(method, path, "", "token", token)
+		assert.Equal(t, http.StatusBadRequest, response.Code, path)
+		assert.Contains(t, response.Body.String(), `question \"vault-name\" is not answered yet`)
+	}
+
+	session = answerTestMacroQuestion(t, ts, sessionPath, token, "vault-name", "HashiCorp Vault")
+	assert.Equal(t, "Answer processed", session.Message)
+	assert.Equal(t, "storage-type", session.Question.Id)
+	assert.Contains(t, session.Question.PossibleAnswers, "Filesystem (local or remote)")
+
+	// the answers must match the question
+	for _, answers := range []string{`["Tape"]`, `["Filesystem (local or remote)", "Service Registry"]`, `[]`} {
+		response = ts.request(http.MethodPost, sessionPath+"/answer", `{"answers": `+answers+`}`, "token", token)
+		assert.Equal(t, http.StatusBadRequest, response.Code, answers)
+	}
+
+	session = answerTestMacroQuestion(t, ts, sessionPath, token, "storage-type", "Filesystem (local or remote)")
+	assert.Equal(t, "authentication-type", session.Question.Id)
+
+	// stepping back asks the previous question again
+	response = ts.request(http.MethodPost, sessionPath+"/back", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	session = decodeTestMacroSession(t, response)
+	assert.Equal(t, "Undo successful", session.Message)
+	assert.Equal(t, "storage-type", session.Question.Id)
+
+	// so an answer to the question asked before is outdated
+	response = ts.request(http.MethodPost, sessionPath+"/answer", `{"question_id": "authentication-type", "answers": ["Certificate"]}`, "token", token)
+	assert.Equal(t, http.StatusConflict, response.Code)
+	assert.Equal(t, map[string]any{"error": "question has been answered in the meantime", "question_id": "storage-type"}, decodeTestResponse(t, response))
+
+	response = ts.request(http.MethodGet, sessionPath, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "storage-type", decodeTestMacroSession(t, response).Question.Id)
+
+	response = ts.request(http.MethodPost, sessionPath+"/back", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "vault-name", decodeTestMacroSession(t, response).Question.Id)
+	response = ts.request(http.MethodPost, sessionPath+"/back", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	session = decodeTestMacroSession(t, response)
+	assert.Equal(t, "Cannot go back further", session.Message)
+	assert.False(t, session.Valid)
+	assert.Equal(t, "vault-name", session.Question.Id)
+
+	// the session belongs to the key and the model it was started for
+	otherModelId := ts.storeModel(t, key, testModelYaml)
+	response = ts.request(http.MethodGet, "/models/"+otherModelId+"/macro-sessions/"+session.Id, "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, map[string]any{"error": "macro session not found"}, decodeTestResponse(t, response))
+	otherToken := ts.createToken(t, ts.createKey(t), "")
+	response = ts.request(http.MethodGet, sessionPath, "", "token", otherToken)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = ts.request(http.MethodDelete, sessionPath, "", "token", otherToken)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = ts.request(http.MethodDelete, sessionPath, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, map[string]any{"message": "macro session deleted", "id": session.Id}, decodeTestResponse(t, response))
+	response = ts.request(http.MethodGet, sessionPath, "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Empty(t, ts.history(t, key, modelId))
+}
+
+func TestMacroSessionTimeout(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+	idle := startTestMacroSession(t, ts, modelId, token, "add-vault")
+	used := startTestMacroSession(t, ts, modelId, token, "pretty-print")
+
+	ts.macroSessionsLock.Lock()
+	ts.macroSessions[idle.Id].lastUsed = time.Now().Add(-macroSessionTimeout - time.Minute)
+	ts.macroSessions[used.Id].lastUsed = time.Now().Add(-macroSessionTimeout + time.Minute)
+	ts.macroSessionsLock.Unlock()
+
+	response := ts.request(http.MethodGet, "/models/"+modelId+"/macro-sessions/"+idle.Id, "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = ts.request(http.MethodGet, "/models/"+modelId+"/macro-sessions/"+used.Id, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// using a session keeps it alive
+	ts.macroSessionsLock.Lock()
+	defer ts.macroSessionsLock.Unlock()
+	assert.Len(t, ts.macroSessions, 1)
+	assert.WithinDuration(t, time.Now(), ts.macroSessions[used.Id].lastUsed, time.Minute)
+}
+
+func TestExecuteMacro(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	session := startTestMacroSession(t, ts, modelId, token, "add-vault")
+	sessionPath := "/models/" + modelId + "/macro-sessions/" + session.Id
+	answerTestMacroQuestion(t, ts, sessionPath, token, "vault-name", "HashiCorp")
+	answerTestMacroQuestion(t, ts, sessionPath, token, "storage-type", "In-Memory (no persistent storage of secrets)")
+	answerTestMacroQuestion(t, ts, sessionPath, token, "authentication-type", "Credentials (username/password, API-key, secret token, etc.)")
+	session = answerTestMacroQuestion(t, ts, sessionPath, token, "multi-tenant")
+	if assert.Equal(t, "clients", session.Question.Id) {
+		assert.Equal(t, []string{"database", "web-server"}, session.Question.PossibleAnswers)
+		assert.True(t, session.Question.MultiSelect)
+	}
+	answerTestMacroQuestion(t, ts, sessionPath, token, "clients", "web-server")
+	session = answerTestMacroQuestion(t, ts, sessionPath, token, "within-trust-boundary")
+	if assert.Equal(t, "selected-trust-boundary", session.Question.Id) {
+		assert.Equal(t, []string{"CREATE NEW TRUST BOUNDARY", "network"}, session.Question.PossibleAnswers)
+	}
+	session = answerTestMacroQuestion(t, ts, sessionPath, token, "selected-trust-boundary", "network")
+	assert.Nil(t, session.Question)
+
+	response := ts.request(http.MethodPost, sessionPath+"/answer", `{"answers": ["No"]}`, "token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "all questions are answered already")
+
+	// the changes are those to the current model, which is left unchanged
+	response = ts.request(http.MethodGet, sessionPath+"/changes", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	changes := decodeTestResponse(t, response)
+	assert.Equal(t, true, changes["valid"])
+	assert.Contains(t, changes["changes"], "adding technical asset (including communication links): hashicorp-vault")
+	assert.Contains(t, changes["changes"], "filling existing trust boundary: network")
+	assert.NotContains(t, ts.readModel(t, key, modelId).TechnicalAssets, "HashiCorp Vault")
+
+	response = ts.request(http.MethodPost, sessionPath+"/execute", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, modelId, decodeTestResponse(t, response)["id"])
+
+	modelInput := ts.readModel(t, key, modelId)
+	if vault, exists := modelInput.TechnicalAssets["HashiCorp Vault"]; assert.True(t, exists) {
+		assert.Equal(t, "hashicorp-vault", vault.ID)
+		assert.Equal(t, []string{"configuration-secrets"}, vault.DataAssetsStored)
+		assert.False(t, vault.MultiTenant)
+	}
+	assert.Contains(t, modelInput.DataAssets, "Configuration Secrets")
+	assert.Contains(t, modelInput.TechnicalAssets["Web Server"].DataAssetsProcessed, "configuration-secrets")
+	assert.Contains(t, modelInput.TrustBoundaries["Network"].TechnicalAssetsInside, "hashicorp-vault")
+	assert.Contains(t, modelInput.TagsAvailable, "hashicorp")
+	if entries := ts.history(t, key, modelId); assert.Len(t, entries, 1) {
+		assert.Equal(t, "Macro add-vault", entries[0].ChangeReason)
+	}
+
+	// the executed macro has ended its session
+	response = ts.request(http.MethodGet, sessionPath, "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func startTestMacroSession(t *testing.T, ts *testServer, modelId string, token string, macro string) payloadMacroSession {
+	response := ts.request(http.MethodPost, "/models/"+modelId+"/macro-sessions", `{"macro": "`+macro+`"}`, "token", token)
+	assert.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	return decodeTestMacroSession(t, response)
+}
+
+// answerTestMacroQuestion answers the question expected to be the current one, where no answers take the default one
+func answerTestMacroQuestion(t *testing.T, ts *testServer, sessionPath string, token string, questionId string, answers ...string) payloadMacroSession {
+	t.Helper()
+	payload, err := json.Marshal(payloadMacroAnswer{QuestionId: questionId, Answers: answers})
+	assert.NoError(t, err)
+	response := ts.request(http.MethodPost, sessionPath+"/answer", string(payload), "token", token)
+	if !assert.Equal(t, http.StatusOK, response.Code, response.Body.String()) {
+		t.FailNow()
+	}
+	return decodeTestMacroSession(t, response)
+}
+
+func decodeTestMacroSession(t *testing.T, response *httptest.ResponseRecorder) payloadMacroSession {
+	session := payloadMacroSession{}
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &session), response.Body.String())
+	return session
+}
@@ -0,0 +1,268 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+const (
+	counterMetric   = "counter"
+	gaugeMetric     = "gauge"
+	histogramMetric = "histogram"
+
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	// the text format escapes just these, unlike strconv.Quote, which would escape e.g. tabs and invalid UTF-8 as well
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+	requestDurationBuckets  = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	analysisDurationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+	riskRuleDurationBuckets = []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+)
+
+// serverMetrics are the metrics of the server, exposed in the Prometheus text format
+type serverMetrics struct {
+	lock             sync.Mutex
+	requests         *metricFamily
+	requestDurations *metricFamily
+	analyses         *metricFamily
+	riskRules        *metricFamily
+	throttled        *metricFamily
+}
+
+// metricFamily is a metric with all its series, which are keyed by their label values
+type metricFamily struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*metricSeries
+}
+
+type metricSeries struct {
+	labelValues  []string
+	value        float64
+	bucketCounts []uint64
+}
+
+func newServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		requests:         newMetricFamily("threagile_http_requests_total", "HTTP requests by route, method and status.", counterMetric, nil, "route", "method", "status"),
+		requestDurations: newMetricFamily("threagile_http_request_duration_seconds", "Duration of the HTTP requests by route and method.", histogramMetric, requestDurationBuckets, "route", "method"),
+		analyses:         newMetricFamily("threagile_analysis_duration_seconds", "Duration of the analyses (once they got a worker) by output and result.", histogramMetric, analysisDurationBuckets, "output", "result"),
+		riskRules:        newMetricFamily("threagile_risk_rule_duration_seconds", "Duration of the risk rule executions by rule (not recorded for analyses in a subprocess).", histogramMetric, riskRuleDurationBuckets, "rule"),
+		throttled:        newMetricFamily("threagile_throttler_rejections_total", "Object creations rejected by the throttler by object type.", counterMetric, nil, "type"),
+	}
+}
+
+func newMetricFamily(name string, help string, kind string, buckets []float64, labelNames ...string) *metricFamily {
+	return &metricFamily{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*metricSeries),
+	}
+}
+
+// middleware records every request by its route (not its path, which would contain ids)
+func (what *serverMetrics) middleware() gin.HandlerFunc {
+	return func(ginContext *gin.Context) {
+		start := time.Now()
+		ginContext.Next()
+		route := ginContext.FullPath()
+		if len(route) == 0 {
+			route = "unmatched"
+		}
+		method := ginContext.Request.Method
+		what.add(what.requests, 1, route, method, strconv.Itoa(ginContext.Writer.Status()))
+		what.observe(what.requestDurations, time.Since(start).Seconds(), route, method)
+	}
+}
+
+func (what *serverMetrics) observeAnalysis(commands *report.GenerateCommands, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	what.observe(what.analyses, duration.Seconds(), analysisOutput(commands), result)
+}
+
+func (what *serverMetrics) throttlerRejected(typeName string) {
+	what.add(what.throttled, 1, strings.ToLower(typeName))
+}
+
+func (what *serverMetrics) add(family *metricFamily, delta float64, labelValues ...string) {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	family.get(labelValues).value += delta
+}
+
+func (what *serverMetrics) observe(family *metricFamily, value float64, labelValues ...string) {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	series := family.get(labelValues)
+	series.value += value
+	series.bucketCounts[len(family.buckets)]++ // the +Inf bucket is the count
+	for i, bucket := range family.buckets {
+		if value <= bucket {
+			series.bucketCounts[i]++
+		}
+	}
+}
+
+// write writes the recorded metrics followed by the scraped ones
+func (what *serverMetrics) write(writer io.Writer, scraped ...*metricFamily) error {
+	what.lock.Lock()
+	defer what.lock.Unlock()
+	families := append([]*metricFamily{what.requests, what.requestDurations, what.analyses, what.riskRules, what.throttled}, scraped...)
+	for _, family := range families {
+		err := family.write(writer)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scrapedMetric creates a metric without labels whose value is read at the time of the scrape
+func scrapedMetric(name string, help string, kind string, value float64) *metricFamily {
+	family := newMetricFamily(name, help, kind, nil)
+	family.get(nil).value = value
+	return family
+}
+
+func (what *metricFamily) get(labelValues []string) *metricSeries {
+	seriesKey := strings.Join(labelValues, "\xff")
+	series, exists := what.series[seriesKey]
+	if !exists {
+		series = &metricSeries{labelValues: labelValues}
+		if what.kind == histogramMetric {
+			series.bucketCounts = make([]uint64, len(what.buckets)+1)
+		}
+		what.series[seriesKey] = series
+	}
+	return series
+}
+
+func (what *metricFamily) write(writer io.Writer) error {
+	var text strings.Builder
+	_, _ = fmt.Fprintf(&text, "# HELP %v %v\n", what.name, helpEscaper.Replace(what.help))
+	_, _ = fmt.Fprintf(&text, "# TYPE %v %v\n", what.name, what.kind)
+
+	keys := make([]string, 0, len(what.series))
+	for seriesKey := range what.series {
+		keys = append(keys, seriesKey)
+	}
+	sort.Strings(keys)
+	for _, seriesKey := range keys {
+		series := what.series[seriesKey]
+		if what.kind != histogramMetric {
+			_, _ = fmt.Fprintf(&text, "%v%v %v\n", what.name, what.labels(series.labelValues), formatMetricValue(series.value))
+			continue
+		}
+		for i, bucket := range what.buckets {
+			labels := what.labels(series.labelValues, "le", formatMetricValue(bucket))
+			_, _ = fmt.Fprintf(&text, "%v_bucket%v %v\n", what.name, labels, series.bucketCounts[i])
+		}
+		count := series.bucketCounts[len(what.buckets)]
+		_, _ = fmt.Fprintf(&text, "%v_bucket%v %v\n", what.name, what.labels(series.labelValues, "le", "+Inf"), count)
+		_, _ = fmt.Fprintf(&text, "%v_sum%v %v\n", what.name, what.labels(series.labelValues), formatMetricValue(series.value))
+		_, _ = fmt.Fprintf(&text, "%v_count%v %v\n", what.name, what.labels(series.labelValues), count)
+	}
+
+	_, err := io.WriteString(writer, text.String())
+	return err
+}
+
+// labels formats the label values of a series along with additional label name/value pairs (like the bucket of a histogram)
+func (what *metricFamily) labels(labelValues []string, additional ...string) string {
+	pairs := make([]string, 0, len(labelValues)+len(additional)/2)
+	for i, value := range labelValues {
+		pairs = append(pairs, what.labelNames[i]+`="`+labelValueEscaper.Replace(value)+`"`)
+	}
+	for i := 0; i+1 < len(additional); i += 2 {
+		pairs = append(pairs, additional[i]+`="`+labelValueEscaper.Replace(additional[i+1])+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatMetricValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// analysisOutput names the output of an analysis by the single output it generates, otherwise it's a full analysis
+// (or a check, which generates just the json files)
+func analysisOutput(commands *report.GenerateCommands) string {
+	outputs := make([]string, 0)
+	for name, generated := range map[string]bool{
+		"data-flow-diagram":     commands.DataFlowDiagram,
+		"data-asset-diagram":    commands.DataAssetDiagram,
+		"risks-json":            commands.RisksJSON,
+		"risks-sarif":           commands.RisksSARIF,
+		"technical-assets-json": commands.TechnicalAssetsJSON,
+		"stats-json":            commands.StatsJSON,
+		"risks-excel":           commands.RisksExcel,
+		"tags-excel":            commands.TagsExcel,
+		"report-pdf":            commands.ReportPDF,
+		"report-adoc":           commands.ReportADOC,
+		"report-html":           commands.ReportHTML,
+	} {
+		if generated {
+			outputs = append(outputs, name)
+		}
+	}
+	switch {
+	case len(outputs) == 1:
+		return outputs[0]
+	case commands.ReportPDF:
+		return "analysis"
+	}
+	return "check"
+}
+
+// timedRiskRule records the execution times of the risk rule it wraps
+type timedRiskRule struct {
+	types.RiskRule
+	id      string
+	metrics *serverMetrics
+}
+
+func (what *timedRiskRule) GenerateRisks(parsedModel *types.Model) ([]*types.Risk, error) {
+	start := time.Now()
+	defer func() {
+		what.metrics.observe(what.metrics.riskRules, time.Since(start).Seconds(), what.id)
+	}()
+	return what.RiskRule.GenerateRisks(parsedModel)
+}
+
+// timedRiskRules wraps the risk rules for their execution times to be recorded
+func timedRiskRules(rules types.RiskRules, metrics *serverMetrics) types.RiskRules {
+	timed := make(types.RiskRules, len(rules))
+	for id, rule := range rules {
+		timed[id] = &timedRiskRule{RiskRule: rule, id: id, metrics: metrics}
+	}
+	return timed
+}
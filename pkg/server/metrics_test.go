@@ -0,0 +1,41 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/threagile/threagile/pkg/report"
+)
+
+func TestMetricsText(t *testing.T) {
+	metrics := newServerMetrics()
+	metrics.add(metrics.requests, 1, "/models/:model-id", "GET", "200")
+	metrics.add(metrics.requests, 2, "/models/:model-id", "GET", "404")
+	metrics.observe(metrics.requestDurations, 0.02, "/models/:model-id", "GET")
+	metrics.observe(metrics.requestDurations, 3, "/models/:model-id", "GET")
+	metrics.observeAnalysis(&report.GenerateCommands{RisksJSON: true}, 1500*time.Millisecond, nil)
+	metrics.observeAnalysis(analysisCommands(), 400*time.Second, errors.New("failed"))
+	metrics.observe(metrics.riskRules, 0.0003, `rule "quoted" \ with`+"\n\tnewline and tab")
+	metrics.throttlerRejected("MODEL")
+
+	var text strings.Builder
+	assert.NoError(t, metrics.write(&text,
+		scrapedMetric("threagile_keys", "Keys on the server.", gaugeMetric, 3),
+		scrapedMetric("threagile_escaped", `Help with \ and`+"\nnewline.", gaugeMetric, math.Inf(1)),
+	))
+
+	golden := filepath.Join("testdata", "metrics.txt")
+	if len(os.Getenv("UPDATE_GOLDEN")) > 0 {
+		assert.NoError(t, os.WriteFile(golden, []byte(text.String()), 0600))
+	}
+	expected, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), text.String())
+}
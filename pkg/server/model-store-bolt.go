@@ -0,0 +1,257 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const boltModelStoreFile = "models.db"
+
+var (
+	boltModelsBucket    = []byte("models")
+	boltHistoryBucket   = []byte("history")
+	boltModelKey        = []byte("model")
+	boltCreatedKey      = []byte("created")
+	boltModifiedKey     = []byte("modified")
+	boltOpenTimeout     = 5 * time.Second
+	boltTimestampFormat = time.RFC3339Nano
+)
+
+// boltModelStore keeps all models in a single embedded key-value database file, where every change (including the
+// history entry it creates) is a transaction. The buckets are models/<key-id>/<model-id>, each holding the model,
+// its timestamps and a history bucket with the entries by id (which sorts them chronologically).
+type boltModelStore struct {
+	db            *bolt.DB
+	historyToKeep int
+}
+
+func openBoltModelStore(filename string, historyToKeep int) (*boltModelStore, error) {
+	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open model store %v: %w", filename, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltModelsBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("unable to initialize model store %v: %w", filename, err)
+	}
+	return &boltModelStore{db: db, historyToKeep: historyToKeep}, nil
+}
+
+func (what *boltModelStore) Keys() ([]string, error) {
+	keyIds := make([]string, 0)
+	err := what.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltModelsBucket).ForEachBucket(func(keyId []byte) error {
+			keyIds = append(keyIds, string(keyId))
+			return nil
+		})
+	})
+	return keyIds, err
+}
+
+func (what *boltModelStore) List(keyId string) ([]StoredModel, error) {
+	models := make([]StoredModel, 0)
+	err := what.db.View(func(tx *bolt.Tx) error {
+		keyBucket := tx.Bucket(boltModelsBucket).Bucket([]byte(keyId))
+		if keyBucket == nil {
+			return nil
+		}
+		return keyBucket.ForEachBucket(func(modelId []byte) error {
+			modelBucket := keyBucket.Bucket(modelId)
+			models = append(models, StoredModel{
+				Id:       string(modelId),
+				Created:  boltTimestamp(modelBucket.Get(boltCreatedKey)),
+				Modified: boltTimestamp(modelBucket.Get(boltModifiedKey)),
+			})
+			return nil
+		})
+	})
+	return models, err
+}
+
+func (what *boltModelStore) Create(keyId string, model StoredModel, data []byte) error {
+	return what.db.Update(func(tx *bolt.Tx) error {
+		keyBucket, err := tx.Bucket(boltModelsBucket).CreateBucketIfNotExists([]byte(keyId))
+		if err != nil {
+			return err
+		}
+		if keyBucket.Bucket([]byte(model.Id)) != nil {
+			return ErrModelExists
+		}
+		modelBucket, err := keyBucket.CreateBucket([]byte(model.Id))
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		created, modified := model.Created, model.Modified
+		if created.IsZero() {
+			created = now
+		}
+		if modified.IsZero() {
+			modified = now
+		}
+		err = modelBucket.Put(boltCreatedKey, []byte(created.Format(boltTimestampFormat)))
+		if err != nil {
+			return err
+		}
+		err = modelBucket.Put(boltModifiedKey, []byte(modified.Format(boltTimestampFormat)))
+		if err != nil {
+			return err
+		}
+		return modelBucket.Put(boltModelKey, data)
+	})
+}
+
+func (what *boltModelStore) Read(keyId string, modelId string) ([]byte, error) {
+	var data []byte
+	err := what.db.View(func(tx *bolt.Tx) error {
+		modelBucket := boltModelBucket(tx, keyId, modelId)
+		if modelBucket == nil {
+			return ErrModelNotFound
+		}
+		// the value is only valid within the transaction
+		data = bytes.Clone(modelBucket.Get(boltModelKey))
+		return nil
+	})
+	return data, err
+}
+
+func (what *boltModelStore) Write(keyId string, modelId string, data []byte, changeReason string) error {
+	return what.db.Update(func(tx *bolt.Tx) error {
+		modelBucket := boltModelBucket(tx, keyId, modelId)
+		if modelBucket == nil {
+			return ErrModelNotFound
+		}
+		historyBucket, err := modelBucket.CreateBucketIfNotExists(boltHistoryBucket)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		err = historyBucket.Put([]byte(newHistoryEntry(now, changeReason).Id), bytes.Clone(modelBucket.Get(boltModelKey)))
+		if err != nil {
+			return err
+		}
+		// the history entries are sorted by their timestamp, so the oldest ones come first
+		count := 0
+		cursor := historyBucket.Cursor()
+		for historyId, _ := cursor.First(); historyId != nil; historyId, _ = cursor.Next() {
+			count++
+		}
+		for historyId, _ := cursor.First(); historyId != nil && count > what.historyToKeep; historyId, _ = cursor.First() {
+			err = cursor.Delete()
+			if err != nil {
+				return err
+			}
+			count--
+		}
+		err = modelBucket.Put(boltModifiedKey, []byte(now.Format(boltTimestampFormat)))
+		if err != nil {
+			return err
+		}
+		return modelBucket.Put(boltModelKey, data)
+	})
+}
+
+func (what *boltModelStore) Delete(keyId string, modelId string) error {
+	return what.db.Update(func(tx *bolt.Tx) error {
+		keyBucket := tx.Bucket(boltModelsBucket).Bucket([]byte(keyId))
+		if keyBucket == nil || keyBucket.Bucket([]byte(modelId)) == nil {
+			return ErrModelNotFound
+		}
+		return keyBucket.DeleteBucket([]byte(modelId))
+	})
+}
+
+func (what *boltModelStore) DeleteKey(keyId string) error {
+	return what.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(boltModelsBucket).Bucket([]byte(keyId)) == nil {
+			return nil
+		}
+		return tx.Bucket(boltModelsBucket).DeleteBucket([]byte(keyId))
+	})
+}
+
+func (what *boltModelStore) History(keyId string, modelId string) ([]HistoryEntry, error) {
+	entries := make([]HistoryEntry, 0)
+	err := what.db.View(func(tx *bolt.Tx) error {
+		modelBucket := boltModelBucket(tx, keyId, modelId)
+		if modelBucket == nil {
+			return ErrModelNotFound
+		}
+		historyBucket := modelBucket.Bucket(boltHistoryBucket)
+		if historyBucket == nil {
+			return nil
+		}
+		return historyBucket.ForEach(func(historyId []byte, _ []byte) error {
+			if entry, ok := parseHistoryEntry(string(historyId)); ok {
+				entries = append(entries, entry)
+			}
+			return nil
+		})
+	})
+	return entries, err
+}
+
+func (what *boltModelStore) ReadHistory(keyId string, modelId string, historyId string) ([]byte, error) {
+	var data []byte
+	err := what.db.View(func(tx *bolt.Tx) error {
+		modelBucket := boltModelBucket(tx, keyId, modelId)
+		if modelBucket == nil {
+			return ErrModelNotFound
+		}
+		historyBucket := modelBucket.Bucket(boltHistoryBucket)
+		if historyBucket == nil {
+			return ErrHistoryEntryNotFound
+		}
+		value := historyBucket.Get([]byte(historyId))
+		if value == nil {
+			return ErrHistoryEntryNotFound
+		}
+		data = bytes.Clone(value)
+		return nil
+	})
+	return data, err
+}
+
+func (what *boltModelStore) WriteHistory(keyId string, modelId string, entry HistoryEntry, data []byte) error {
+	if _, ok := parseHistoryEntry(entry.Id); !ok {
+		return fmt.Errorf("invalid history entry %q", entry.Id)
+	}
+	return what.db.Update(func(tx *bolt.Tx) error {
+		modelBucket := boltModelBucket(tx, keyId, modelId)
+		if modelBucket == nil {
+			return ErrModelNotFound
+		}
+		historyBucket, err := modelBucket.CreateBucketIfNotExists(boltHistoryBucket)
+		if err != nil {
+			return err
+		}
+		return historyBucket.Put([]byte(entry.Id), data)
+	})
+}
+
+func (what *boltModelStore) Close() error {
+	return what.db.Close()
+}
+
+func boltModelBucket(tx *bolt.Tx, keyId string, modelId string) *bolt.Bucket {
+	keyBucket := tx.Bucket(boltModelsBucket).Bucket([]byte(keyId))
+	if keyBucket == nil {
+		return nil
+	}
+	return keyBucket.Bucket([]byte(modelId))
+}
+
+func boltTimestamp(value []byte) time.Time {
+	timestamp, err := time.Parse(boltTimestampFormat, string(value))
+	if err != nil {
+		return time.Time{}
+	}
+	return timestamp
+}
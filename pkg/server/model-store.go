@@ -0,0 +1,465 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ModelStoreFilesystem = "filesystem"
+	ModelStoreBolt       = "bolt"
+
+	historyFolderName      = "history"
+	historyFileSuffix      = ".backup"
+	historyTimestampFormat = "2006-01-02 15:04:05"
+
+	historyNanosecondsFormat = ".000000000"
+	historySequenceLimit     = 1000000
+)
+
+var (
+	ErrModelNotFound        = errors.New("model not found")
+	ErrModelExists          = errors.New("model already exists")
+	ErrHistoryEntryNotFound = errors.New("history entry not found")
+)
+
+// ModelStore persists the models of the server along with their history, grouped by the id of the key they belong to.
+// The models are passed in and out encrypted, so a store never sees the content of a model.
+type ModelStore interface {
+	// Keys lists the ids of the keys having models
+	Keys() ([]string, error)
+	List(keyId string) ([]StoredModel, error)
+	Create(keyId string, model StoredModel, data []byte) error
+	Read(keyId string, modelId string) ([]byte, error)
+	// Write replaces the model, keeping the replaced version as history entry named by the change reason
+	Write(keyId string, modelId string, data []byte, changeReason string) error
+	Delete(keyId string, modelId string) error
+	DeleteKey(keyId string) error
+	// History lists the history entries of the model, oldest first
+	History(keyId string, modelId string) ([]HistoryEntry, error)
+	ReadHistory(keyId string, modelId string, historyId string) ([]byte, error)
+	WriteHistory(keyId string, modelId string, entry HistoryEntry, data []byte) error
+	Close() error
+}
+
+type StoredModel struct {
+	Id       string
+	Created  time.Time
+	Modified time.Time
+}
+
+// HistoryEntry is a version of a model, replaced by the change named by its change reason
+type HistoryEntry struct {
+	Id           string    `json:"id"`
+	Timestamp    time.Time `json:"timestamp"`
+	ChangeReason string    `json:"change_reason"`
+}
+
+type modelStoreConfig interface {
+	GetServerFolder() string
+	GetKeyFolder() string
+	GetInputFile() string
+	GetBackupHistoryFilesToKeep() int
+}
+
+// OpenModelStore opens the store of the given kind (ModelStoreFilesystem or ModelStoreBolt) within the server folder
+func OpenModelStore(kind string, config modelStoreConfig) (ModelStore, error) {
+	switch strings.ToLower(strings.TrimSpace(kind)) {
+	case "", ModelStoreFilesystem:
+		return &fileModelStore{
+			folder:        filepath.Join(config.GetServerFolder(), config.GetKeyFolder()),
+			inputFile:     config.GetInputFile(),
+			historyToKeep: config.GetBackupHistoryFilesToKeep(),
+		}, nil
+	case ModelStoreBolt:
+		return openBoltModelStore(filepath.Join(config.GetServerFolder(), boltModelStoreFile), config.GetBackupHistoryFilesToKeep())
+	}
+	return nil, fmt.Errorf("unknown model store %q (supported: %v, %v)", kind, ModelStoreFilesystem, ModelStoreBolt)
+}
+
+// MigrateModels copies all models along with their history from one store to another, returning the number of models copied
+func MigrateModels(from ModelStore, to ModelStore) (int, error) {
+	keyIds, err := from.Keys()
+	if err != nil {
+		return 0, fmt.Errorf("unable to list keys: %w", err)
+	}
+	count := 0
+	for _, keyId := range keyIds {
+		models, err := from.List(keyId)
+		if err != nil {
+			return count, fmt.Errorf("unable to list models of key %v: %w", keyId, err)
+		}
+		for _, aModel := range models {
+			data, err := from.Read(keyId, aModel.Id)
+			if err != nil {
+				return count, fmt.Errorf("unable to read model %v: %w", aModel.Id, err)
+			}
+			err = to.Create(keyId, aModel, data)
+			if err != nil {
+				return count, fmt.Errorf("unable to write model %v: %w", aModel.Id, err)
+			}
+			entries, err := from.History(keyId, aModel.Id)
+			if err != nil {
+				return count, fmt.Errorf("unable to list history of model %v: %w", aModel.Id, err)
+			}
+			for _, entry := range entries {
+				data, err = from.ReadHistory(keyId, aModel.Id, entry.Id)
+				if err != nil {
+					return count, fmt.Errorf("unable to read history entry %v of model %v: %w", entry.Id, aModel.Id, err)
+				}
+				err = to.WriteHistory(keyId, aModel.Id, entry, data)
+				if err != nil {
+					return count, fmt.Errorf("unable to write history entry %v of model %v: %w", entry.Id, aModel.Id, err)
+				}
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// historySequence tells apart the history entries created within the same nanosecond (or clock tick)
+var historySequence atomic.Uint64
+
+// newHistoryEntry names a history entry by its timestamp to the nanosecond and a sequence number, so entries never
+// overwrite each other and sort chronologically, followed by the change reason
+func newHistoryEntry(timestamp time.Time, changeReason string) HistoryEntry {
+	sequence := historySequence.Add(1) % historySequenceLimit
+	return HistoryEntry{
+		Id:           fmt.Sprintf("%v-%06d %v", timestamp.Format(historyTimestampFormat+historyNanosecondsFormat), sequence, changeReason),
+		Timestamp:    timestamp,
+		ChangeReason: changeReason,
+	}
+}
+
+// parseHistoryEntry reads the ids of newHistoryEntry as well as the ones of older versions, which have neither
+// nanoseconds nor a sequence number ("2006-01-02 15:04:05 <change reason>")
+func parseHistoryEntry(id string) (HistoryEntry, bool) {
+	if len(id) < len(historyTimestampFormat) {
+		return HistoryEntry{}, false
+	}
+	timestamp, err := time.ParseInLocation(historyTimestampFormat, id[:len(historyTimestampFormat)], time.Local)
+	if err != nil {
+		return HistoryEntry{}, false
+	}
+	rest := id[len(historyTimestampFormat):]
+	if strings.HasPrefix(rest, ".") {
+		uniquePart, changeReason, _ := strings.Cut(rest, " ")
+		nanoseconds, sequence, found := strings.Cut(uniquePart[1:], "-")
+		if !found || len(nanoseconds) != len(historyNanosecondsFormat)-1 || len(sequence) == 0 {
+			return HistoryEntry{}, false
+		}
+		nanosecondValue, err := strconv.Atoi(nanoseconds)
+		if err != nil {
+			return HistoryEntry{}, false
+		}
+		if _, err = strconv.Atoi(sequence); err != nil {
+			return HistoryEntry{}, false
+		}
+		timestamp = timestamp.Add(time.Duration(nanosecondValue))
+		rest = changeReason
+	}
+	return HistoryEntry{
+		Id:           id,
+		Timestamp:    timestamp,
+		ChangeReason: strings.TrimSpace(rest),
+	}, true
+}
+
+// fileModelStore is the classic layout of the server folder: <key-folder>/<key-id>/<model-id>/<input-file> with the
+// history as <model-id>/history/<timestamp> <change reason>.backup
+type fileModelStore struct {
+	folder        string
+	inputFile     string
+	historyToKeep int
+}
+
+func (what *fileModelStore) Keys() ([]string, error) {
+	entries, err := os.ReadDir(what.folder)
+	if err != nil {
+		return nil, err
+	}
+	keyIds := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			keyIds = append(keyIds, entry.Name())
+		}
+	}
+	return keyIds, nil
+}
+
+func (what *fileModelStore) List(keyId string) ([]StoredModel, error) {
+	keyFolder, err := what.keyFolder(keyId)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]StoredModel, 0)
+	entries, err := os.ReadDir(keyFolder)
+	if os.IsNotExist(err) {
+		return models, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := uuid.Parse(entry.Name()); err != nil {
+			continue
+		}
+		folderInfo, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		modelInfo, err := os.Stat(filepath.Join(keyFolder, entry.Name(), what.inputFile))
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, StoredModel{
+			Id:       entry.Name(),
+			Created:  folderInfo.ModTime(),
+			Modified: modelInfo.ModTime(),
+		})
+	}
+	return models, nil
+}
+
+func (what *fileModelStore) Create(keyId string, model StoredModel, data []byte) error {
+	modelFolder, err := what.modelFolder(keyId, model.Id)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(modelFolder); err == nil {
+		return ErrModelExists
+	}
+	err = os.MkdirAll(modelFolder, 0700)
+	if err != nil {
+		return err
+	}
+	modelFile := filepath.Join(modelFolder, what.inputFile)
+	err = os.WriteFile(modelFile, data, 0600)
+	if err != nil {
+		return err
+	}
+	if !model.Modified.IsZero() {
+		err = os.Chtimes(modelFile, model.Modified, model.Modified)
+		if err != nil {
+			return err
+		}
+	}
+	if !model.Created.IsZero() {
+		err = os.Chtimes(modelFolder, model.Created, model.Created)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (what *fileModelStore) Read(keyId string, modelId string) ([]byte, error) {
+	modelFolder, err := what.existingModelFolder(keyId, modelId)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Clean(filepath.Join(modelFolder, what.inputFile)))
+}
+
+func (what *fileModelStore) Write(keyId string, modelId string, data []byte, changeReason string) error {
+	modelFolder, err := what.existingModelFolder(keyId, modelId)
+	if err != nil {
+		return err
+	}
+	err = what.backupModelToHistory(modelFolder, changeReason)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(filepath.Join(modelFolder, what.inputFile)), data, 0600)
+}
+
+func (what *fileModelStore) backupModelToHistory(modelFolder string, changeReasonForHistory string) (err error) {
+	historyFolder := filepath.Join(modelFolder, historyFolderName)
+	if _, err := os.Stat(historyFolder); os.IsNotExist(err) {
+		// the model folder tells the creation time of the model, so it must not change by adding the history folder
+		folderInfo, err := os.Stat(modelFolder)
+		if err != nil {
+			return err
+		}
+		err = os.Mkdir(historyFolder, 0700)
+		if err != nil {
+			return err
+		}
+		err = os.Chtimes(modelFolder, folderInfo.ModTime(), folderInfo.ModTime())
+		if err != nil {
+			return err
+		}
+	}
+	inputModel, err := os.ReadFile(filepath.Clean(filepath.Join(modelFolder, what.inputFile)))
+	if err != nil {
+		return err
+	}
+	historyFile := filepath.Join(historyFolder, newHistoryEntry(time.Now(), changeReasonForHistory).Id+historyFileSuffix)
+	err = os.WriteFile(filepath.Clean(historyFile), inputModel, 0400)
+	if err != nil {
+		return err
+	}
+	// now delete any old files if over limit to keep
+	files, err := os.ReadDir(filepath.Clean(historyFolder))
+	if err != nil {
+		return err
+	}
+	if len(files) > what.historyToKeep {
+		requiredToDelete := len(files) - what.historyToKeep
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].Name() < files[j].Name()
+		})
+		for _, file := range files {
+			requiredToDelete--
+			if file.Name() != filepath.Clean(file.Name()) {
+				return fmt.Errorf("weird file name %v", file.Name())
+			}
+			err = os.Remove(filepath.Clean(filepath.Join(historyFolder, file.Name())))
+			if err != nil {
+				return err
+			}
+			if requiredToDelete <= 0 {
+				break
+			}
+		}
+	}
+	return
+}
+
+func (what *fileModelStore) Delete(keyId string, modelId string) error {
+	modelFolder, err := what.existingModelFolder(keyId, modelId)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(modelFolder)
+}
+
+func (what *fileModelStore) DeleteKey(keyId string) error {
+	keyFolder, err := what.keyFolder(keyId)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(keyFolder)
+}
+
+func (what *fileModelStore) History(keyId string, modelId string) ([]HistoryEntry, error) {
+	modelFolder, err := what.existingModelFolder(keyId, modelId)
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(filepath.Join(modelFolder, historyFolderName))
+	if os.IsNotExist(err) {
+		return []HistoryEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]HistoryEntry, 0)
+	for _, file := range files {
+		if !file.Type().IsRegular() || !strings.HasSuffix(file.Name(), historyFileSuffix) {
+			continue
+		}
+		if entry, ok := parseHistoryEntry(strings.TrimSuffix(file.Name(), historyFileSuffix)); ok {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Id < entries[j].Id
+	})
+	return entries, nil
+}
+
+func (what *fileModelStore) ReadHistory(keyId string, modelId string, historyId string) ([]byte, error) {
+	entries, err := what.History(keyId, modelId)
+	if err != nil {
+		return nil, err
+	}
+	// only entries actually listed are read, so the id can't point anywhere else
+	for _, entry := range entries {
+		if entry.Id == historyId {
+			modelFolder, err := what.existingModelFolder(keyId, modelId)
+			if err != nil {
+				return nil, err
+			}
+			return os.ReadFile(filepath.Clean(filepath.Join(modelFolder, historyFolderName, entry.Id+historyFileSuffix)))
+		}
+	}
+	return nil, ErrHistoryEntryNotFound
+}
+
+func (what *fileModelStore) WriteHistory(keyId string, modelId string, entry HistoryEntry, data []byte) error {
+	modelFolder, err := what.existingModelFolder(keyId, modelId)
+	if err != nil {
+		return err
+	}
+	if _, ok := parseHistoryEntry(entry.Id); !ok || entry.Id != filepath.Base(entry.Id) {
+		return fmt.Errorf("invalid history entry %q", entry.Id)
+	}
+	// the model folder tells the creation time of the model, so it must not change by adding the history folder
+	folderInfo, err := os.Stat(modelFolder)
+	if err != nil {
+		return err
+	}
+	historyFolder := filepath.Join(modelFolder, historyFolderName)
+	err = os.MkdirAll(historyFolder, 0700)
+	if err != nil {
+		return err
+	}
+	historyFile := filepath.Join(historyFolder, entry.Id+historyFileSuffix)
+	err = os.WriteFile(filepath.Clean(historyFile), data, 0400)
+	if err != nil {
+		return err
+	}
+	err = os.Chtimes(historyFile, entry.Timestamp, entry.Timestamp)
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(modelFolder, folderInfo.ModTime(), folderInfo.ModTime())
+}
+
+func (what *fileModelStore) Close() error {
+	return nil
+}
+
+func (what *fileModelStore) keyFolder(keyId string) (string, error) {
+	if len(keyId) == 0 || keyId != filepath.Base(keyId) || strings.HasPrefix(keyId, ".") {
+		return "", fmt.Errorf("invalid key id %q", keyId)
+	}
+	return filepath.Join(what.folder, keyId), nil
+}
+
+func (what *fileModelStore) modelFolder(keyId string, modelId string) (string, error) {
+	keyFolder, err := what.keyFolder(keyId)
+	if err != nil {
+		return "", err
+	}
+	parsedId, err := uuid.Parse(modelId)
+	if err != nil || parsedId.String() != modelId {
+		return "", ErrModelNotFound
+	}
+	return filepath.Join(keyFolder, modelId), nil
+}
+
+func (what *fileModelStore) existingModelFolder(keyId string, modelId string) (string, error) {
+	modelFolder, err := what.modelFolder(keyId, modelId)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(modelFolder); os.IsNotExist(err) {
+		return "", ErrModelNotFound
+	}
+	return modelFolder, nil
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelStoreCreateReadWrite(t *testing.T) {
+	forEachModelStore(t, 10, func(t *testing.T, store ModelStore) {
+		created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		assert.NoError(t, store.Create("key", StoredModel{Id: testModelId, Created: created, Modified: created}, []byte("first")))
+		assert.ErrorIs(t, store.Create("key", StoredModel{Id: testModelId}, []byte("again")), ErrModelExists)
+
+		data, err := store.Read("key", testModelId)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", string(data))
+
+		_, err = store.Read("key", "missing")
+		assert.ErrorIs(t, err, ErrModelNotFound)
+		assert.ErrorIs(t, store.Write("key", "missing", []byte("data"), "Change"), ErrModelNotFound)
+
+		// two writes within the same second must not overwrite each other's history entry
+		assert.NoError(t, store.Write("key", testModelId, []byte("second"), "Change"))
+		assert.NoError(t, store.Write("key", testModelId, []byte("third"), "Change"))
+
+		data, err = store.Read("key", testModelId)
+		assert.NoError(t, err)
+		assert.Equal(t, "third", string(data))
+
+		keyIds, err := store.Keys()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key"}, keyIds)
+
+		models, err := store.List("key")
+		assert.NoError(t, err)
+		if assert.Len(t, models, 1) {
+			assert.Equal(t, testModelId, models[0].Id)
+			assert.True(t, models[0].Created.Equal(created))
+			assert.True(t, models[0].Modified.After(created))
+		}
+
+		entries, err := store.History("key", testModelId)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "Change", entries[0].ChangeReason)
+			assert.NotEqual(t, entries[0].Id, entries[1].Id)
+			assertHistoryData(t, store, testModelId, entries[0].Id, "first")
+			assertHistoryData(t, store, testModelId, entries[1].Id, "second")
+		}
+	})
+}
+
+func TestModelStoreHistoryPruning(t *testing.T) {
+	forEachModelStore(t, 2, func(t *testing.T, store ModelStore) {
+		assert.NoError(t, store.Create("key", StoredModel{Id: testModelId}, []byte("version 0")))
+		for _, version := range []string{"version 1", "version 2", "version 3", "version 4"} {
+			assert.NoError(t, store.Write("key", testModelId, []byte(version), "Change to "+version))
+		}
+
+		entries, err := store.History("key", testModelId)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, "Change to version 3", entries[0].ChangeReason)
+			assert.Equal(t, "Change to version 4", entries[1].ChangeReason)
+			assertHistoryData(t, store, testModelId, entries[0].Id, "version 2")
+			assertHistoryData(t, store, testModelId, entries[1].Id, "version 3")
+		}
+	})
+}
+
+func TestModelStoreReadWriteHistory(t *testing.T) {
+	forEachModelStore(t, 10, func(t *testing.T, store ModelStore) {
+		assert.NoError(t, store.Create("key", StoredModel{Id: testModelId}, []byte("current")))
+
+		entries, err := store.History("key", testModelId)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+
+		_, err = store.ReadHistory("key", testModelId, "2024-01-02 03:04:05 Unknown")
+		assert.ErrorIs(t, err, ErrHistoryEntryNotFound)
+		_, err = store.History("key", "missing")
+		assert.ErrorIs(t, err, ErrModelNotFound)
+
+		oldEntry, _ := parseHistoryEntry("2024-01-02 03:04:05 Old Change")
+		newEntry := newHistoryEntry(time.Date(2024, 1, 2, 3, 4, 5, 6, time.Local), "New Change")
+		assert.NoError(t, store.WriteHistory("key", testModelId, newEntry, []byte("new")))
+		assert.NoError(t, store.WriteHistory("key", testModelId, oldEntry, []byte("old")))
+		assert.Error(t, store.WriteHistory("key", testModelId, HistoryEntry{Id: "no timestamp"}, []byte("invalid")))
+
+		entries, err = store.History("key", testModelId)
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, oldEntry, entries[0])
+			assert.Equal(t, newEntry.Id, entries[1].Id)
+			assert.Equal(t, "New Change", entries[1].ChangeReason)
+			assert.True(t, newEntry.Timestamp.Equal(entries[1].Timestamp))
+		}
+		assertHistoryData(t, store, testModelId, oldEntry.Id, "old")
+		assertHistoryData(t, store, testModelId, newEntry.Id, "new")
+	})
+}
+
+func TestModelStoreDelete(t *testing.T) {
+	forEachModelStore(t, 10, func(t *testing.T, store ModelStore) {
+		assert.NoError(t, store.Create("key", StoredModel{Id: testFirstModelId}, []byte("first")))
+		assert.NoError(t, store.Create("key", StoredModel{Id: testSecondModelId}, []byte("second")))
+		assert.NoError(t, store.Create("other-key", StoredModel{Id: testThirdModelId}, []byte("third")))
+		assert.NoError(t, store.Write("key", testFirstModelId, []byte("changed"), "Change"))
+
+		assert.NoError(t, store.Delete("key", testFirstModelId))
+		assert.ErrorIs(t, store.Delete("key", testFirstModelId), ErrModelNotFound)
+		_, err := store.Read("key", testFirstModelId)
+		assert.ErrorIs(t, err, ErrModelNotFound)
+
+		models, err := store.List("key")
+		assert.NoError(t, err)
+		if assert.Len(t, models, 1) {
+			assert.Equal(t, testSecondModelId, models[0].Id)
+		}
+
+		assert.NoError(t, store.DeleteKey("key"))
+		models, err = store.List("key")
+		assert.NoError(t, err)
+		assert.Empty(t, models)
+
+		keyIds, err := store.Keys()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"other-key"}, keyIds)
+	})
+}
+
+func TestMigrateModels(t *testing.T) {
+	for _, kinds := range [][2]string{{ModelStoreFilesystem, ModelStoreBolt}, {ModelStoreBolt, ModelStoreFilesystem}} {
+		t.Run(kinds[0]+" to "+kinds[1], func(t *testing.T) {
+			from := openTestModelStore(t, kinds[0], 10)
+			to := openTestModelStore(t, kinds[1], 10)
+
+			created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+			assert.NoError(t, from.Create("key", StoredModel{Id: testFirstModelId, Created: created, Modified: created}, []byte("first")))
+			assert.NoError(t, from.Write("key", testFirstModelId, []byte("first changed"), "Change"))
+			assert.NoError(t, from.Create("other-key", StoredModel{Id: testSecondModelId}, []byte("second")))
+
+			count, err := MigrateModels(from, to)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, count)
+
+			data, err := to.Read("key", testFirstModelId)
+			assert.NoError(t, err)
+			assert.Equal(t, "first changed", string(data))
+			data, err = to.Read("other-key", testSecondModelId)
+			assert.NoError(t, err)
+			assert.Equal(t, "second", string(data))
+
+			models, err := to.List("key")
+			assert.NoError(t, err)
+			if assert.Len(t, models, 1) {
+				assert.True(t, models[0].Created.Equal(created))
+			}
+
+			fromEntries, err := from.History("key", testFirstModelId)
+			assert.NoError(t, err)
+			toEntries, err := to.History("key", testFirstModelId)
+			assert.NoError(t, err)
+			if assert.Len(t, toEntries, 1) && assert.Len(t, fromEntries, 1) {
+				assert.Equal(t, fromEntries[0].Id, toEntries[0].Id)
+				assertHistoryData(t, to, testFirstModelId, toEntries[0].Id, "first")
+			}
+
+			// the models exist in the target store now
+			_, err = MigrateModels(from, to)
+			assert.ErrorIs(t, err, ErrModelExists)
+		})
+	}
+}
+
+func TestHistoryEntryIds(t *testing.T) {
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.Local)
+	first, second := newHistoryEntry(timestamp, "Some Change"), newHistoryEntry(timestamp, "Some Change")
+	assert.NotEqual(t, first.Id, second.Id)
+	assert.Regexp(t, `^2024-01-02 03:04:05\.000006000-\d{6} Some Change$`, first.Id)
+
+	parsed, ok := parseHistoryEntry(first.Id)
+	assert.True(t, ok)
+	assert.Equal(t, "Some Change", parsed.ChangeReason)
+	assert.True(t, timestamp.Equal(parsed.Timestamp))
+
+	// the ids of earlier versions have neither nanoseconds nor sequence number
+	parsed, ok = parseHistoryEntry("2024-01-02 03:04:05 Some Change")
+	assert.True(t, ok)
+	assert.Equal(t, "Some Change", parsed.ChangeReason)
+	assert.True(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local).Equal(parsed.Timestamp))
+	assert.Less(t, "2024-01-02 03:04:05 Some Change", first.Id)
+
+	for _, invalid := range []string{"", "2024-01-02", "not a timestamp at all", "2024-01-02 03:04:05.123-000001 Change", "2024-01-02 03:04:05.000000000 Change"} {
+		_, ok = parseHistoryEntry(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+const (
+	testFirstModelId  = "00000000-0000-4000-8000-000000000001"
+	testSecondModelId = "00000000-0000-4000-8000-000000000002"
+	testThirdModelId  = "00000000-0000-4000-8000-000000000003"
+)
+
+func assertHistoryData(t *testing.T, store ModelStore, modelId string, historyId string, expected string) {
+	t.Helper()
+	data, err := store.ReadHistory("key", modelId, historyId)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(data))
+}
+
+func forEachModelStore(t *testing.T, historyToKeep int, test func(t *testing.T, store ModelStore)) {
+	for _, kind := range []string{ModelStoreFilesystem, ModelStoreBolt} {
+		t.Run(kind, func(t *testing.T) {
+			test(t, openTestModelStore(t, kind, historyToKeep))
+		})
+	}
+}
+
+func openTestModelStore(t *testing.T, kind string, historyToKeep int) ModelStore {
+	store, err := OpenModelStore(kind, &testModelStoreConfig{serverFolder: t.TempDir(), historyToKeep: historyToKeep})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+type testModelStoreConfig struct {
+	serverFolder  string
+	historyToKeep int
+}
+
+func (what *testModelStoreConfig) GetServerFolder() string {
+	return what.serverFolder
+}
+
+func (what *testModelStoreConfig) GetKeyFolder() string {
+	return "keys"
+}
+
+func (what *testModelStoreConfig) GetInputFile() string {
+	return "threagile.yaml"
+}
+
+func (what *testModelStoreConfig) GetBackupHistoryFilesToKeep() int {
+	return what.historyToKeep
+}
@@ -10,13 +10,17 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
-	"sort"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,6 +31,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
 	"github.com/threagile/threagile/pkg/types"
 	"golang.org/x/crypto/argon2"
 )
@@ -45,16 +50,9 @@ func (s *server) createNewModel(ginContext *gin.Context) {
 	defer s.unlockFolder(folderNameOfKey)
 
 	aUuid := uuid.New().String()
-	err := os.Mkdir(folderNameForModel(folderNameOfKey, aUuid), 0700)
-	if err != nil {
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to create model",
-		})
-		return
-	}
-
 	aYaml := `title: New Threat Model
 threagile_version: ` + s.config.GetThreagileVersion() + `
+schema_version: ` + strconv.Itoa(input.SchemaVersion) + `
 author:
   name: ""
   homepage: ""
@@ -65,7 +63,7 @@ business_overview:
 technical_overview:
   description: ""
   images: []
-business_criticality: ""
+business_criticality: important
 management_summary_comment: ""
 questions: {}
 abuse_cases: {}
@@ -75,16 +73,16 @@ data_assets: {}
 technical_assets: {}
 trust_boundaries: {}
 shared_runtimes: {}
-individual_risk_categories: {}
+custom_risk_categories: []
 risk_tracking: {}
-diagram_tweak_nodesep: ""
-diagram_tweak_ranksep: ""
+diagram_tweak_nodesep: 2
+diagram_tweak_ranksep: 2
 diagram_tweak_edge_layout: ""
 diagram_tweak_suppress_edge_labels: false
 diagram_tweak_invisible_connections_between_assets: []
 diagram_tweak_same_rank_assets: []`
 
-	ok = s.writeModelYAML(ginContext, aYaml, key, folderNameForModel(folderNameOfKey, aUuid), "New Model Creation", true)
+	ok = s.writeModelYAML(ginContext, aYaml, key, folderNameOfKey, aUuid, "New Model Creation", true)
 	if ok {
 		ginContext.JSON(http.StatusCreated, gin.H{
 			"message": "model created",
@@ -109,43 +107,28 @@ func (s *server) listModels(ginContext *gin.Context) { // TODO currently returns
 	defer s.unlockFolder(folderNameOfKey)
 
 	result := make([]payloadModels, 0)
-	modelFolders, err := os.ReadDir(folderNameOfKey)
+	storedModels, err := s.models.List(keyIdOfFolder(folderNameOfKey))
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusNotFound, gin.H{
-			"error": "token not found",
+			"error": "unable to list model",
 		})
 		return
 	}
-	for _, dirEntry := range modelFolders {
-		if dirEntry.IsDir() {
-			modelStat, err := os.Stat(filepath.Join(folderNameOfKey, dirEntry.Name(), s.config.GetInputFile()))
-			if err != nil {
-				log.Println(err)
-				ginContext.JSON(http.StatusNotFound, gin.H{
-					"error": "unable to list model",
-				})
-				return
-			}
-			aModel, _, ok := s.readModel(ginContext, dirEntry.Name(), key, folderNameOfKey)
-			if !ok {
-				return
-			}
-			fileInfo, err := dirEntry.Info()
-			if err != nil {
-				log.Println(err)
-				ginContext.JSON(http.StatusNotFound, gin.H{
-					"error": "unable to get file info",
-				})
-				return
-			}
-			result = append(result, payloadModels{
-				ID:                dirEntry.Name(),
-				Title:             aModel.Title,
-				TimestampCreated:  fileInfo.ModTime(),
-				TimestampModified: modelStat.ModTime(),
-			})
+	for _, storedModel := range storedModels {
+		if !tokenAllowsModel(ginContext, storedModel.Id) {
+			continue
 		}
+		aModel, _, ok := s.readModel(ginContext, storedModel.Id, key, folderNameOfKey)
+		if !ok {
+			return
+		}
+		result = append(result, payloadModels{
+			ID:                storedModel.Id,
+			Title:             aModel.Title,
+			TimestampCreated:  storedModel.Created,
+			TimestampModified: storedModel.Modified,
+		})
 	}
 	ginContext.JSON(http.StatusOK, result)
 }
@@ -157,21 +140,18 @@ func (s *server) deleteModel(ginContext *gin.Context) {
 	}
 	s.lockFolder(folderNameOfKey)
 	defer s.unlockFolder(folderNameOfKey)
-	folder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	modelId, ok := checkModelId(ginContext, ginContext.Param("model-id"))
 	if ok {
-		if folder != filepath.Clean(folder) {
-			ginContext.JSON(http.StatusInternalServerError, gin.H{
-				"error": "model-id is weird",
-			})
+		fileBytes, err := s.models.Read(keyIdOfFolder(folderNameOfKey), modelId)
+		if err == nil && !checkModelVersion(ginContext, fileBytes) {
 			return
 		}
-		err := os.RemoveAll(folder)
+		err = s.models.Delete(keyIdOfFolder(folderNameOfKey), modelId)
 		if err != nil {
-			ginContext.JSON(http.StatusNotFound, gin.H{
-				"error": "model not found",
-			})
+			handleModelStoreError(err, ginContext, "unable to delete model")
 			return
 		}
+		s.modelCount.Add(-1)
 		ginContext.JSON(http.StatusOK, gin.H{
 			"message": "model deleted",
 		})
@@ -339,6 +319,93 @@ func (s *server) getAbuseCases(ginContext *gin.Context) {
 	}
 }
 
+type payloadQuestions map[string]string
+
+func (s *server) setQuestions(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		payload := payloadQuestions{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		modelInput.Questions = payload
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Questions Update")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "model updated",
+			})
+		}
+	}
+}
+
+func (s *server) getQuestions(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		ginContext.JSON(http.StatusOK, aModel.Questions)
+	}
+}
+
+type payloadTags []string
+
+// setTags replaces the available tags; removing a tag still in use fails the model validation
+func (s *server) setTags(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		payload := payloadTags{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		modelInput.TagsAvailable = lowerCaseAndTrim(payload)
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Tags Update")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "model updated",
+			})
+		}
+	}
+}
+
+func (s *server) getTags(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		ginContext.JSON(http.StatusOK, aModel.TagsAvailable)
+	}
+}
+
 type payloadSecurityRequirements map[string]string
 
 func (s *server) setSecurityRequirements(ginContext *gin.Context) {
@@ -725,19 +792,6 @@ func (s *server) populateDataAsset(ginContext *gin.Context, payload payloadDataA
 	return dataAssetInput, true
 }
 
-func (s *server) getTrustBoundaries(ginContext *gin.Context) {
-	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
-	if !ok {
-		return
-	}
-	s.lockFolder(folderNameOfKey)
-	defer s.unlockFolder(folderNameOfKey)
-	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
-	if ok {
-		ginContext.JSON(http.StatusOK, aModel.TrustBoundaries)
-	}
-}
-
 type payloadSharedRuntime struct {
 	Title                  string   `yaml:"title" json:"title"`
 	Id                     string   `yaml:"id" json:"id"`
@@ -972,20 +1026,19 @@ func (s *server) getSharedRuntimes(ginContext *gin.Context) {
 }
 
 func (s *server) readModel(ginContext *gin.Context, modelUUID string, key []byte, folderNameOfKey string) (modelInputResult input.Model, yamlText string, ok bool) {
-	modelFolder, ok := s.checkModelFolder(ginContext, modelUUID, folderNameOfKey)
+	modelId, ok := checkModelId(ginContext, modelUUID)
 	if !ok {
 		return modelInputResult, yamlText, false
 	}
-	cryptoKey := generateKeyFromAlreadyStrongRandomInput(key)
-	block, err := aes.NewCipher(cryptoKey)
+	fileBytes, err := s.models.Read(keyIdOfFolder(folderNameOfKey), modelId)
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
+		handleModelStoreError(err, ginContext, "unable to open model")
 		return modelInputResult, yamlText, false
 	}
-	aesGcm, err := cipher.NewGCM(block)
+	if !checkModelVersion(ginContext, fileBytes) {
+		return modelInputResult, yamlText, false
+	}
+	yamlBytes, err := s.decryptModel(key, fileBytes)
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -993,8 +1046,8 @@ func (s *server) readModel(ginContext *gin.Context, modelUUID string, key []byte
 		})
 		return modelInputResult, yamlText, false
 	}
-
-	fileBytes, err := os.ReadFile(filepath.Clean(filepath.Join(modelFolder, s.config.GetInputFile())))
+	modelInput := new(input.Model).Defaults()
+	err = unmarshalModel(yamlBytes, modelInput)
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -1002,45 +1055,86 @@ func (s *server) readModel(ginContext *gin.Context, modelUUID string, key []byte
 		})
 		return modelInputResult, yamlText, false
 	}
+	return *modelInput, string(yamlBytes), true
+}
+
+// unmarshalModel decodes the yaml of a stored model, migrating models stored by older versions to the current schema version
+func unmarshalModel(yamlBytes []byte, modelInput *input.Model) error {
+	migratedYaml, _, err := input.MigrateModelYaml(yamlBytes)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(migratedYaml, modelInput)
+}
+
+// decryptModel decrypts and decompresses a stored model (or a version of it in the history) into its yaml
+func (s *server) decryptModel(key []byte, fileBytes []byte) ([]byte, error) {
+	cryptoKey := generateKeyFromAlreadyStrongRandomInput(key)
+	block, err := aes.NewCipher(cryptoKey)
+	if err != nil {
+		return nil, err
+	}
+	aesGcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fileBytes) < 12 {
+		return nil, fmt.Errorf("invalid model file")
+	}
 
 	nonce := fileBytes[0:12]
 	ciphertext := fileBytes[12:]
 	plaintext, err := aesGcm.Open(nil, nonce, ciphertext, nil) // #nosec G407 // false positive The nounce is read from file for decryption not encryption
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
+		return nil, err
 	}
 
 	r, err := gzip.NewReader(bytes.NewReader(plaintext))
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
+		return nil, err
 	}
 	buf := new(bytes.Buffer)
 	_, _ = buf.ReadFrom(r)
-	modelInput := new(input.Model).Defaults()
-	yamlBytes := buf.Bytes()
-	err = yaml.Unmarshal(yamlBytes, &modelInput)
+	return buf.Bytes(), nil
+}
+
+// encryptModel compresses and encrypts the yaml of a model (or a version of it in the history) for storing it
+func (s *server) encryptModel(key []byte, yaml []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	_, _ = w.Write(yaml)
+	_ = w.Close()
+	plaintext := b.Bytes()
+	cryptoKey := generateKeyFromAlreadyStrongRandomInput(key)
+	block, err := aes.NewCipher(cryptoKey)
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to open model",
-		})
-		return modelInputResult, yamlText, false
+		return nil, err
 	}
-	return *modelInput, string(yamlBytes), true
+	// Never use more than 2^32 random nonces with a given key because of the risk of a repeat.
+	nonce := make([]byte, 12)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	aesGcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := aesGcm.Seal(nil, nonce, plaintext, nil) // #nosec G407 // The nounce is read from random so it shoul be random each run
+	return append(nonce, ciphertext...), nil
 }
 
 func (s *server) writeModel(ginContext *gin.Context, key []byte, folderNameOfKey string, modelInput *input.Model, changeReasonForHistory string) (ok bool) {
-	modelFolder, ok := s.checkModelFolder(ginContext, ginContext.Param("model-id"), folderNameOfKey)
+	modelId, ok := checkModelId(ginContext, ginContext.Param("model-id"))
 	if ok {
 		modelInput.ThreagileVersion = s.config.GetThreagileVersion()
+		modelInput.SchemaVersion = input.SchemaVersion
+		// never commit a change leaving the model in a state the analysis would reject
+		_, err := model.ParseModel(s.config, modelInput, s.builtinRiskRules, s.customRiskRules)
+		if err != nil {
+			handleErrorInServiceCall(fmt.Errorf("invalid model: %w", err), ginContext)
+			return false
+		}
 		yamlBytes, err := yaml.Marshal(modelInput)
 		if err != nil {
 			log.Println(err)
@@ -1052,27 +1146,53 @@ func (s *server) writeModel(ginContext *gin.Context, key []byte, folderNameOfKey
 		/*
 			yamlBytes = model.ReformatYAML(yamlBytes)
 		*/
-		return s.writeModelYAML(ginContext, string(yamlBytes), key, modelFolder, changeReasonForHistory, false)
+		return s.writeModelYAML(ginContext, string(yamlBytes), key, folderNameOfKey, modelId, changeReasonForHistory, false)
 	}
 	return false
 }
 
-func (s *server) checkModelFolder(ginContext *gin.Context, modelUUID string, folderNameOfKey string) (modelFolder string, ok bool) {
+var changeReasonExpression = regexp.MustCompile(`[^A-Za-z0-9 _.-]+`)
+
+// changeReason makes a client given reason usable as part of the model history file name
+func changeReason(reason string, fallback string) string {
+	reason = strings.TrimSpace(changeReasonExpression.ReplaceAllString(reason, "-"))
+	if len(reason) == 0 {
+		return fallback
+	}
+	if len(reason) > 100 {
+		reason = reason[:100]
+	}
+	return reason
+}
+
+// checkModelId validates the model id of the request, returning it in its canonical form
+func checkModelId(ginContext *gin.Context, modelUUID string) (modelId string, ok bool) {
 	uuidParsed, err := uuid.Parse(modelUUID)
 	if err != nil {
 		ginContext.JSON(http.StatusNotFound, gin.H{
 			"error": "model not found",
 		})
-		return modelFolder, false
+		return modelId, false
 	}
-	modelFolder = folderNameForModel(folderNameOfKey, uuidParsed.String())
-	if _, err := os.Stat(modelFolder); os.IsNotExist(err) {
+	return uuidParsed.String(), true
+}
+
+func handleModelStoreError(err error, ginContext *gin.Context, message string) {
+	switch {
+	case errors.Is(err, ErrModelNotFound):
 		ginContext.JSON(http.StatusNotFound, gin.H{
 			"error": "model not found",
 		})
-		return modelFolder, false
+	case errors.Is(err, ErrHistoryEntryNotFound):
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "history entry not found",
+		})
+	default:
+		log.Println(err)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
 	}
-	return modelFolder, true
 }
 
 func (s *server) getModel(ginContext *gin.Context) {
@@ -1111,14 +1231,17 @@ func (s *server) importModel(ginContext *gin.Context) {
 	s.lockFolder(folderNameOfKey)
 	defer s.unlockFolder(folderNameOfKey)
 
-	aUuid := ginContext.Param("model-id") // UUID is syntactically validated in readModel+checkModelFolder (next line) via uuid.Parse(modelUUID)
+	aUuid, ok := checkModelId(ginContext, ginContext.Param("model-id"))
+	if !ok {
+		return
+	}
 	_, _, ok = s.readModel(ginContext, aUuid, key, folderNameOfKey)
 	if ok {
 		// first analyze it simply by executing the full risk process (just discard the result) to ensure that everything would work
 		yamlContent, ok := s.execute(ginContext, true)
 		if ok {
 			// if we're here, then no problem was raised, so ok to proceed
-			ok = s.writeModelYAML(ginContext, string(yamlContent), key, folderNameForModel(folderNameOfKey, aUuid), "Model Import", false)
+			ok = s.writeModelYAML(ginContext, string(yamlContent), key, folderNameOfKey, aUuid, "Model Import", false)
 			if ok {
 				ginContext.JSON(http.StatusCreated, gin.H{
 					"message": "model imported",
@@ -1128,6 +1251,47 @@ func (s *server) importModel(ginContext *gin.Context) {
 	}
 }
 
+// patchModel applies a JSON Patch (RFC 6902) to the model, addressing it like its JSON representation
+func (s *server) patchModel(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	patch, err := io.ReadAll(ginContext.Request.Body)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if !ok {
+		return
+	}
+	document, err := json.Marshal(jsonDocumentOf(modelInput))
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	document, err = applyJSONPatch(document, patch)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	patchedModel := new(input.Model).Defaults()
+	err = json.Unmarshal(document, patchedModel)
+	if err != nil {
+		handleErrorInServiceCall(fmt.Errorf("invalid model: %w", err), ginContext)
+		return
+	}
+	ok = s.writeModel(ginContext, key, folderNameOfKey, patchedModel, changeReason(ginContext.Query("change_reason"), "Model Patch"))
+	if ok {
+		ginContext.JSON(http.StatusOK, gin.H{
+			"message": "model patched",
+		})
+	}
+}
+
 func (s *server) analyzeModelOnServerDirectly(ginContext *gin.Context) {
 	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
 	if !ok {
@@ -1180,12 +1344,13 @@ func (s *server) analyzeModelOnServerDirectly(ginContext *gin.Context) {
 	defer func() { _ = os.Remove(tmpResultFile.Name()) }()
 
 	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
-
-	s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, true, true, true, true, true, true, true, true, dpi)
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
 		return
 	}
+	if !s.runAnalysis(ginContext, tmpModelFile.Name(), tmpOutputDir, analysisCommands(), dpi) {
+		return
+	}
 	err = os.WriteFile(filepath.Join(tmpOutputDir, s.config.GetInputFile()), []byte(yamlText), 0400)
 	if err != nil {
 		handleErrorInServiceCall(err, ginContext)
@@ -1218,17 +1383,11 @@ func (s *server) analyzeModelOnServerDirectly(ginContext *gin.Context) {
 	ginContext.FileAttachment(tmpResultFile.Name(), "threagile-result.zip")
 }
 
-func (s *server) writeModelYAML(ginContext *gin.Context, yaml string, key []byte, modelFolder string, changeReasonForHistory string, skipBackup bool) (ok bool) {
+func (s *server) writeModelYAML(ginContext *gin.Context, yaml string, key []byte, folderNameOfKey string, modelId string, changeReasonForHistory string, newModel bool) (ok bool) {
 	if s.config.GetVerbose() {
-		fmt.Println("about to write " + strconv.Itoa(len(yaml)) + " bytes of yaml into model folder: " + modelFolder)
+		fmt.Println("about to write " + strconv.Itoa(len(yaml)) + " bytes of yaml into model: " + modelId)
 	}
-	var b bytes.Buffer
-	w := gzip.NewWriter(&b)
-	_, _ = w.Write([]byte(yaml))
-	_ = w.Close()
-	plaintext := b.Bytes()
-	cryptoKey := generateKeyFromAlreadyStrongRandomInput(key)
-	block, err := aes.NewCipher(cryptoKey)
+	data, err := s.encryptModel(key, []byte(yaml))
 	if err != nil {
 		log.Println(err)
 		ginContext.JSON(http.StatusInternalServerError, gin.H{
@@ -1236,48 +1395,56 @@ func (s *server) writeModelYAML(ginContext *gin.Context, yaml string, key []byte
 		})
 		return false
 	}
-	// Never use more than 2^32 random nonces with a given key because of the risk of a repeat.
-	nonce := make([]byte, 12)
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to write model",
-		})
-		return false
-	}
-	aesGcm, err := cipher.NewGCM(block)
-	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to write model",
-		})
-		return false
-	}
-	ciphertext := aesGcm.Seal(nil, nonce, plaintext, nil) // #nosec G407 // The nounce is read from random so it shoul be random each run
-	if !skipBackup {
-		err = s.backupModelToHistory(modelFolder, changeReasonForHistory)
-		if err != nil {
-			log.Println(err)
-			ginContext.JSON(http.StatusInternalServerError, gin.H{
-				"error": "unable to write model",
-			})
+	keyId := keyIdOfFolder(folderNameOfKey)
+	if newModel {
+		err = s.models.Create(keyId, StoredModel{Id: modelId}, data)
+	} else {
+		// the lock of the folder only protects against concurrent changes within this process
+		currentBytes, readError := s.models.Read(keyId, modelId)
+		if readError == nil && !checkModelVersion(ginContext, currentBytes) {
 			return false
 		}
+		err = s.models.Write(keyId, modelId, data, changeReasonForHistory)
 	}
-	f, err := os.Create(filepath.Clean(filepath.Join(modelFolder, s.config.GetInputFile())))
 	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to write model",
-		})
+		handleModelStoreError(err, ginContext, "unable to write model")
 		return false
 	}
-	_, _ = f.Write(nonce)
-	_, _ = f.Write(ciphertext)
-	_ = f.Close()
+	if newModel {
+		s.modelCount.Add(1)
+	}
+	ginContext.Header("ETag", modelVersion(data))
 	return true
 }
 
+// modelVersion is the ETag of the stored (encrypted) model, which changes with every write due to the random nonce
+func modelVersion(fileBytes []byte) string {
+	hash := sha256.Sum256(fileBytes)
+	return `"` + hex.EncodeToString(hash[:]) + `"`
+}
+
+// checkModelVersion tells the current version of the model and, if the request is conditional (If-Match header), fails
+// it with 412 when the model has been changed since the client read it
+func checkModelVersion(ginContext *gin.Context, fileBytes []byte) bool {
+	version := modelVersion(fileBytes)
+	ginContext.Header("ETag", version)
+	ifMatch := ginContext.GetHeader("If-Match")
+	if len(ifMatch) == 0 {
+		return true
+	}
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == version {
+			return true
+		}
+	}
+	ginContext.JSON(http.StatusPreconditionFailed, gin.H{
+		"error": "model has been changed in the meantime",
+		"etag":  version,
+	})
+	return false
+}
+
 func (s *server) lockFolder(folderName string) {
 	s.globalLock.Lock()
 	defer s.globalLock.Unlock()
@@ -1295,54 +1462,6 @@ func (s *server) unlockFolder(folderName string) {
 	}
 }
 
-func (s *server) backupModelToHistory(modelFolder string, changeReasonForHistory string) (err error) {
-	historyFolder := filepath.Join(modelFolder, "history")
-	if _, err := os.Stat(historyFolder); os.IsNotExist(err) {
-		err = os.Mkdir(historyFolder, 0700)
-		if err != nil {
-			return err
-		}
-	}
-	inputModel, err := os.ReadFile(filepath.Clean(filepath.Join(modelFolder, s.config.GetInputFile())))
-	if err != nil {
-		return err
-	}
-	historyFile := filepath.Join(historyFolder, time.Now().Format("2006-01-02 15:04:05")+" "+changeReasonForHistory+".backup")
-	err = os.WriteFile(filepath.Clean(historyFile), inputModel, 0400)
-	if err != nil {
-		return err
-	}
-	// now delete any old files if over limit to keep
-	files, err := os.ReadDir(filepath.Clean(historyFolder))
-	if err != nil {
-		return err
-	}
-	if len(files) > s.config.GetBackupHistoryFilesToKeep() {
-		requiredToDelete := len(files) - s.config.GetBackupHistoryFilesToKeep()
-		sort.Slice(files, func(i, j int) bool {
-			return files[i].Name() < files[j].Name()
-		})
-		for _, file := range files {
-			requiredToDelete--
-			if file.Name() != filepath.Clean(file.Name()) {
-				return fmt.Errorf("weird file name %v", file.Name())
-			}
-			err = os.Remove(filepath.Clean(filepath.Join(historyFolder, file.Name())))
-			if err != nil {
-				return err
-			}
-			if requiredToDelete <= 0 {
-				break
-			}
-		}
-	}
-	return
-}
-
-func folderNameForModel(folderNameOfKey string, uuid string) string {
-	return filepath.Join(folderNameOfKey, uuid)
-}
-
 type argon2Params struct {
 	memory      uint32
 	iterations  uint32
@@ -1372,3 +1491,27 @@ func lowerCaseAndTrim(tags []string) []string {
 	}
 	return tags
 }
+
+func replaceInSlice(values []string, oldValue string, newValue string) []string {
+	for i := range values {
+		if values[i] == oldValue {
+			values[i] = newValue
+		}
+	}
+	return values
+}
+
+func removeFromSlice(values []string, value string) (result []string, removed bool) {
+	if values == nil {
+		return nil, false
+	}
+	result = make([]string, 0, len(values))
+	for _, candidate := range values {
+		if candidate == value {
+			removed = true
+			continue
+		}
+		result = append(result, candidate)
+	}
+	return result, removed
+}
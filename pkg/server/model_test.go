@@ -0,0 +1,123 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchModel(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	// the members left out of the yaml for being empty or false can be tested and replaced like any other
+	response := ts.request(http.MethodPatch, "/models/"+modelId+"?change_reason=Scope", `[
+		{"op": "test", "path": "/technical_assets/Database/internet", "value": false},
+		{"op": "test", "path": "/technical_assets/Database/tags", "value": []},
+		{"op": "replace", "path": "/technical_assets/Database/out_of_scope", "value": true},
+		{"op": "replace", "path": "/technical_assets/Database/justification_out_of_scope", "value": "managed elsewhere"},
+		{"op": "replace", "path": "/technical_assets/Database/redundant", "value": true},
+		{"op": "add", "path": "/technical_assets/Database/tags/-", "value": "aws"},
+		{"op": "add", "path": "/tags_available/-", "value": "aws"},
+		{"op": "copy", "from": "/technical_assets/Web Server/data_assets_processed", "path": "/technical_assets/Web Server/data_assets_stored"},
+		{"op": "remove", "path": "/risk_tracking/missing-vault@*"}
+	]`, "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, map[string]any{"message": "model patched"}, decodeTestResponse(t, response))
+
+	modelInput := ts.readModel(t, key, modelId)
+	database := modelInput.TechnicalAssets["Database"]
+	assert.True(t, database.OutOfScope)
+	assert.Equal(t, "managed elsewhere", database.JustificationOutOfScope)
+	assert.True(t, database.Redundant)
+	assert.Equal(t, []string{"aws"}, database.Tags)
+	assert.Equal(t, []string{"aws"}, modelInput.TagsAvailable)
+	assert.Equal(t, []string{"customer-data"}, modelInput.TechnicalAssets["Web Server"].DataAssetsStored)
+	assert.NotContains(t, modelInput.RiskTracking, "missing-vault@*")
+	if entries := ts.history(t, key, modelId); assert.Len(t, entries, 1) {
+		assert.Equal(t, "Scope", entries[0].ChangeReason)
+	}
+
+	// and set back to their zero value
+	response = ts.request(http.MethodPatch, "/models/"+modelId, `[
+		{"op": "replace", "path": "/technical_assets/Database/out_of_scope", "value": false},
+		{"op": "replace", "path": "/technical_assets/Database/justification_out_of_scope", "value": ""}
+	]`, "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.False(t, ts.readModel(t, key, modelId).TechnicalAssets["Database"].OutOfScope)
+	if entries := ts.history(t, key, modelId); assert.Len(t, entries, 2) {
+		assert.Equal(t, "Model Patch", entries[1].ChangeReason)
+	}
+}
+
+func TestPatchModelAllOrNothing(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	for _, patch := range []string{
+		// a failing operation
+		`[{"op": "replace", "path": "/title", "value": "Changed"}, {"op": "test", "path": "/technical_assets/Database/internet", "value": true}]`,
+		// a patched model not being a model
+		`[{"op": "replace", "path": "/title", "value": "Changed"}, {"op": "replace", "path": "/technical_assets", "value": []}]`,
+		// a patched model failing validation
+		`[{"op": "replace", "path": "/title", "value": "Changed"}, {"op": "remove", "path": "/data_assets/Customer Data"}]`,
+		`not a patch`,
+	} {
+		response := ts.request(http.MethodPatch, "/models/"+modelId, patch, "token", token)
+		assert.Equal(t, http.StatusBadRequest, response.Code, patch)
+	}
+
+	assert.Equal(t, "Test Model", ts.readModel(t, key, modelId).Title)
+	assert.Empty(t, ts.history(t, key, modelId))
+}
+
+func TestModelVersion(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+	titlePatch := `[{"op": "replace", "path": "/title", "value": "Changed"}]`
+
+	response := ts.request(http.MethodGet, "/models/"+modelId, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	firstVersion := response.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{64}"$`, firstVersion)
+
+	// a change based on the current version succeeds and tells the new version
+	response = ts.request(http.MethodPatch, "/models/"+modelId, titlePatch, "token", token, "If-Match", firstVersion)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	secondVersion := response.Header().Get("ETag")
+	assert.NotEqual(t, firstVersion, secondVersion)
+
+	response = ts.request(http.MethodGet, "/models/"+modelId, "", "token", token)
+	assert.Equal(t, secondVersion, response.Header().Get("ETag"))
+
+	// a change based on an outdated version fails without changing anything
+	for _, request := range [][2]string{
+		{http.MethodPatch, `[{"op": "replace", "path": "/title", "value": "Outdated"}]`},
+		{http.MethodPost, fmt.Sprintf(testDatabasePayload, "Cache", "cache")},
+		{http.MethodDelete, ""},
+	} {
+		path := "/models/" + modelId
+		if request[0] == http.MethodPost {
+			path += "/technical-assets"
+		}
+		response = ts.request(request[0], path, request[1], "token", token, "If-Match", firstVersion)
+		assert.Equal(t, http.StatusPreconditionFailed, response.Code, request[0])
+		assert.Equal(t, map[string]any{"error": "model has been changed in the meantime", "etag": secondVersion}, decodeTestResponse(t, response))
+	}
+	assert.Equal(t, "Changed", ts.readModel(t, key, modelId).Title)
+	assert.Len(t, ts.history(t, key, modelId), 1)
+
+	// any of several versions, or any version at all
+	response = ts.request(http.MethodPatch, "/models/"+modelId, titlePatch, "token", token, "If-Match", firstVersion+", "+secondVersion)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	response = ts.request(http.MethodDelete, "/models/"+modelId, "", "token", token, "If-Match", "*")
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+}
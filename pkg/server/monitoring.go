@@ -0,0 +1,105 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthCheck returns nil when the checked part of the server works
+type healthCheck func() error
+
+func (s *server) getMetrics(ginContext *gin.Context) {
+	s.globalLock.Lock()
+	s.housekeepingTokenMaps() // to not count timed-out ones
+	activeTokens := len(s.mapTokenHashToTimeoutStruct)
+	s.globalLock.Unlock()
+
+	ginContext.Header("Content-Type", metricsContentType)
+	ginContext.Status(http.StatusOK)
+	err := s.metrics.write(ginContext.Writer,
+		scrapedMetric("threagile_active_tokens", "Tokens not yet timed out.", gaugeMetric, float64(activeTokens)),
+		scrapedMetric("threagile_keys", "Keys on the server.", gaugeMetric, float64(s.keyCount.Load())),
+		scrapedMetric("threagile_models", "Models on the server.", gaugeMetric, float64(s.modelCount.Load())),
+		scrapedMetric("threagile_analyses_succeeded_total", "Analyses requested directly or as job that succeeded.", counterMetric, float64(s.successCount.Load())),
+		scrapedMetric("threagile_analyses_failed_total", "Analyses requested directly or as job that failed.", counterMetric, float64(s.errorCount.Load())),
+	)
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// healthz tells whether the server is able to work at all, i.e. to store models and to use its temp folder
+func (s *server) healthz(ginContext *gin.Context) {
+	s.respondHealth(ginContext, map[string]healthCheck{
+		"storage": s.checkStorage,
+		"temp":    s.checkTempFolder,
+	})
+}
+
+// readyz tells whether the server is able to serve all requests, which includes rendering the diagrams by Graphviz
+func (s *server) readyz(ginContext *gin.Context) {
+	s.respondHealth(ginContext, map[string]healthCheck{
+		"storage":  s.checkStorage,
+		"temp":     s.checkTempFolder,
+		"graphviz": checkGraphviz,
+	})
+}
+
+func (s *server) respondHealth(ginContext *gin.Context, checks map[string]healthCheck) {
+	status, results := http.StatusOK, make(map[string]string)
+	for name, check := range checks {
+		results[name] = "ok"
+		if err := check(); err != nil {
+			log.Printf("health check %v failed: %v", name, err)
+			results[name] = err.Error()
+			status = http.StatusServiceUnavailable
+		}
+	}
+	message := "ok"
+	if status != http.StatusOK {
+		message = "unavailable"
+	}
+	ginContext.JSON(status, gin.H{
+		"status": message,
+		"checks": results,
+	})
+}
+
+func (s *server) checkStorage() error {
+	keyFolder := filepath.Join(s.config.GetServerFolder(), s.config.GetKeyFolder())
+	info, err := os.Stat(keyFolder)
+	if err != nil {
+		return fmt.Errorf("key folder not accessible: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("key folder %v is no directory", keyFolder)
+	}
+	_, err = s.models.Keys()
+	if err != nil {
+		return fmt.Errorf("model store not accessible: %w", err)
+	}
+	return nil
+}
+
+func (s *server) checkTempFolder() error {
+	tempFile, err := os.CreateTemp(s.config.GetTempFolder(), "threagile-health-*")
+	if err != nil {
+		return fmt.Errorf("temp folder not writable: %w", err)
+	}
+	_ = tempFile.Close()
+	return os.Remove(tempFile.Name())
+}
+
+func checkGraphviz() error {
+	_, err := exec.LookPath("dot")
+	if err != nil {
+		return fmt.Errorf("graphviz not available: %w", err)
+	}
+	return nil
+}
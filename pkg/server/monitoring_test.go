@@ -0,0 +1,102 @@
+package server
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMetrics(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	ts.createToken(t, key, "")
+	ts.request(http.MethodGet, "/models/"+testModelId, "")
+	ts.request(http.MethodGet, "/unknown", "")
+
+	response := ts.request(http.MethodGet, "/metrics", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, metricsContentType, response.Header().Get("Content-Type"))
+	body := response.Body.String()
+
+	// requests are recorded by their route instead of their path
+	assert.Contains(t, body, `threagile_http_requests_total{route="/auth/keys",method="POST",status="201"} 1`)
+	assert.Contains(t, body, `threagile_http_requests_total{route="/models/:model-id",method="GET",status="404"} 1`)
+	assert.Contains(t, body, `threagile_http_requests_total{route="unmatched",method="GET",status="404"} 1`)
+	assert.Contains(t, body, `threagile_http_request_duration_seconds_count{route="/auth/tokens",method="POST"} 1`)
+	assert.NotContains(t, body, testModelId)
+
+	assert.Contains(t, body, "# TYPE threagile_active_tokens gauge\nthreagile_active_tokens 1\n")
+	assert.Contains(t, body, "# TYPE threagile_keys gauge\nthreagile_keys 1\n")
+	assert.Contains(t, body, "# TYPE threagile_models gauge\nthreagile_models 0\n")
+	assert.Contains(t, body, "# TYPE threagile_analyses_failed_total counter\nthreagile_analyses_failed_total 0\n")
+}
+
+func TestHealth(t *testing.T) {
+	ts := newTestServer(t)
+	bin := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(bin, "dot"), []byte("#!/bin/sh\n"), 0700))
+	t.Setenv("PATH", bin)
+
+	for _, path := range []string{"/healthz", "/readyz"} {
+		response := ts.request(http.MethodGet, path, "")
+		assert.Equal(t, http.StatusOK, response.Code, path)
+		result := decodeTestResponse(t, response)
+		assert.Equal(t, "ok", result["status"], path)
+		for name, check := range result["checks"].(map[string]any) {
+			assert.Equal(t, "ok", check, name)
+		}
+	}
+	assertFolderContent(t, ts.config.GetTempFolder())
+}
+
+func TestHealthFailures(t *testing.T) {
+	// graphviz is only needed to be ready
+	ts := newTestServer(t)
+	t.Setenv("PATH", t.TempDir())
+	response := ts.request(http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = ts.request(http.MethodGet, "/readyz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	result := decodeTestResponse(t, response)
+	assert.Equal(t, "unavailable", result["status"])
+	checks := result["checks"].(map[string]any)
+	assert.Equal(t, "ok", checks["storage"])
+	assert.Contains(t, checks["graphviz"], "graphviz not available")
+
+	// the temp folder is needed for any analysis
+	ts = newTestServer(t)
+	assert.NoError(t, os.RemoveAll(ts.config.GetTempFolder()))
+	response = ts.request(http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	checks = decodeTestResponse(t, response)["checks"].(map[string]any)
+	assert.Equal(t, "ok", checks["storage"])
+	assert.Contains(t, checks["temp"], "temp folder not writable")
+
+	// the key folder holds the models
+	ts = newTestServer(t)
+	keyFolder := filepath.Join(ts.config.GetServerFolder(), ts.config.GetKeyFolder())
+	assert.NoError(t, os.RemoveAll(keyFolder))
+	assert.NoError(t, os.WriteFile(keyFolder, []byte("no folder"), 0600))
+	response = ts.request(http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	checks = decodeTestResponse(t, response)["checks"].(map[string]any)
+	assert.Equal(t, "key folder "+keyFolder+" is no directory", checks["storage"])
+	assert.Equal(t, "ok", checks["temp"])
+
+	assert.NoError(t, os.Remove(keyFolder))
+	response = ts.request(http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Contains(t, decodeTestResponse(t, response)["checks"].(map[string]any)["storage"], "key folder not accessible")
+
+	// as well as the model store itself
+	ts = newTestServer(t, func(config *testServerConfig) {
+		config.modelStore = ModelStoreBolt
+	})
+	assert.NoError(t, ts.models.Close())
+	response = ts.request(http.MethodGet, "/healthz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Contains(t, decodeTestResponse(t, response)["checks"].(map[string]any)["storage"], "model store not accessible")
+}
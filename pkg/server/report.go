@@ -13,6 +13,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/report"
 )
 
 type responseType int
@@ -103,53 +105,30 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 	}
 	defer func() { _ = os.RemoveAll(tmpOutputDir) }()
 	err = os.WriteFile(tmpModelFile.Name(), []byte(yamlText), 0400)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return
+	}
+	if !s.runAnalysis(ginContext, tmpModelFile.Name(), tmpOutputDir, responseCommands(responseType), dpi) {
+		return
+	}
 	switch responseType {
 	case dataFlowDiagram:
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, true, false, false, false, false, false, false, false, dpi)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return
-		}
 		ginContext.File(filepath.Clean(filepath.Join(tmpOutputDir, s.config.GetDataFlowDiagramFilenamePNG())))
 
 	case dataAssetDiagram:
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, true, false, false, false, false, false, false, dpi)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return
-		}
 		ginContext.File(filepath.Clean(filepath.Join(tmpOutputDir, s.config.GetDataAssetDiagramFilenamePNG())))
 
 	case reportPDF:
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, true, false, false, false, false, false, dpi)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return
-		}
 		ginContext.FileAttachment(filepath.Clean(filepath.Join(tmpOutputDir, s.config.GetReportFilename())), s.config.GetReportFilename())
 
 	case risksExcel:
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, true, false, false, false, false, dpi)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return
-		}
 		ginContext.FileAttachment(filepath.Clean(filepath.Join(tmpOutputDir, s.config.GetExcelRisksFilename())), s.config.GetExcelRisksFilename())
 
 	case tagsExcel:
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, true, false, false, false, dpi)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return
-		}
 		ginContext.FileAttachment(filepath.Clean(filepath.Join(tmpOutputDir, s.config.GetExcelTagsFilename())), s.config.GetExcelTagsFilename())
 
 	case risksJSON:
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, false, false, dpi)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return
-		}
 		jsonData, err := os.ReadFile(filepath.Clean(filepath.Join(tmpOutputDir, s.config.GetJsonRisksFilename())))
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
@@ -158,11 +137,6 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 		ginContext.Data(http.StatusOK, "application/json", jsonData) // stream directly with JSON content-type in response instead of file download
 
 	case technicalAssetsJSON:
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, true, true, false, dpi)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return
-		}
 		jsonData, err := os.ReadFile(filepath.Clean(filepath.Join(tmpOutputDir, s.config.GetJsonTechnicalAssetsFilename())))
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
@@ -171,11 +145,6 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 		ginContext.Data(http.StatusOK, "application/json", jsonData) // stream directly with JSON content-type in response instead of file download
 
 	case statsJSON:
-		s.doItViaRuntimeCall(tmpModelFile.Name(), tmpOutputDir, false, false, false, false, false, false, false, true, dpi)
-		if err != nil {
-			handleErrorInServiceCall(err, ginContext)
-			return
-		}
 		jsonData, err := os.ReadFile(filepath.Clean(filepath.Join(tmpOutputDir, s.config.GetJsonStatsFilename())))
 		if err != nil {
 			handleErrorInServiceCall(err, ginContext)
@@ -184,3 +153,26 @@ func (s *server) streamResponse(ginContext *gin.Context, responseType responseTy
 		ginContext.Data(http.StatusOK, "application/json", jsonData) // stream directly with JSON content-type in response instead of file download
 	}
 }
+
+// responseCommands are the outputs to generate for the response
+func responseCommands(responseType responseType) *report.GenerateCommands {
+	switch responseType {
+	case dataFlowDiagram:
+		return &report.GenerateCommands{DataFlowDiagram: true}
+	case dataAssetDiagram:
+		return &report.GenerateCommands{DataAssetDiagram: true}
+	case reportPDF:
+		return &report.GenerateCommands{ReportPDF: true}
+	case risksExcel:
+		return &report.GenerateCommands{RisksExcel: true}
+	case tagsExcel:
+		return &report.GenerateCommands{TagsExcel: true}
+	case risksJSON:
+		return &report.GenerateCommands{RisksJSON: true}
+	case technicalAssetsJSON:
+		return &report.GenerateCommands{TechnicalAssetsJSON: true}
+	case statsJSON:
+		return &report.GenerateCommands{StatsJSON: true}
+	}
+	return &report.GenerateCommands{}
+}
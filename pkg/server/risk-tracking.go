@@ -0,0 +1,329 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type payloadRiskTracking struct {
+	Status        string `yaml:"status" json:"status"`
+	Justification string `yaml:"justification" json:"justification"`
+	Ticket        string `yaml:"ticket" json:"ticket"`
+	Date          string `yaml:"date" json:"date"`
+	CheckedBy     string `yaml:"checked_by" json:"checked_by"`
+}
+
+// payloadRiskTrackingBulk applies the same tracking to several (possibly wildcard) synthetic risk ids at once
+type payloadRiskTrackingBulk struct {
+	ChangeReason string              `yaml:"change_reason" json:"change_reason"`
+	SyntheticIds []string            `yaml:"synthetic_ids" json:"synthetic_ids"`
+	RiskTracking payloadRiskTracking `yaml:"risk_tracking" json:"risk_tracking"`
+}
+
+type payloadUncheckedRisk struct {
+	Risk         *types.Risk         `yaml:"risk" json:"risk"`
+	RiskTracking *types.RiskTracking `yaml:"risk_tracking,omitempty" json:"risk_tracking,omitempty"`
+}
+
+func (s *server) getRiskTrackings(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	aModel, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		ginContext.JSON(http.StatusOK, aModel.RiskTracking)
+	}
+}
+
+// getRiskTracking returns the tracking entry of the id; for the id of a risk only tracked via a wildcard entry the tracking
+// resulting from the wildcard evaluation is returned
+func (s *server) getRiskTracking(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		syntheticId := strings.TrimSpace(ginContext.Param("synthetic-id"))
+		if trackingKey, found := findRiskTrackingKey(modelInput, syntheticId); found {
+			ginContext.JSON(http.StatusOK, gin.H{
+				trackingKey: modelInput.RiskTracking[trackingKey],
+			})
+			return
+		}
+		result, ok := s.analyzeModelInput(ginContext, &modelInput)
+		if !ok {
+			return
+		}
+		for _, risk := range result.ParsedModel.FindRisks(syntheticId) {
+			if riskTracking := result.ParsedModel.GetRiskTracking(risk); riskTracking != nil {
+				ginContext.JSON(http.StatusOK, gin.H{
+					risk.SyntheticId: riskTracking,
+				})
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "risk tracking not found",
+		})
+	}
+}
+
+func (s *server) setRiskTracking(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		payload := payloadRiskTracking{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		riskTrackingInput, ok := populateRiskTracking(ginContext, payload)
+		if !ok {
+			return
+		}
+		syntheticId := strings.TrimSpace(ginContext.Param("synthetic-id"))
+		if trackingKey, found := findRiskTrackingKey(modelInput, syntheticId); found {
+			syntheticId = trackingKey
+		}
+		if modelInput.RiskTracking == nil {
+			modelInput.RiskTracking = make(map[string]input.RiskTracking)
+		}
+		modelInput.RiskTracking[syntheticId] = riskTrackingInput
+		// only the analysis tells if the (wildcard) id matches any risk
+		if _, ok = s.analyzeModelInput(ginContext, &modelInput); !ok {
+			return
+		}
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, changeReason(ginContext.Query("change_reason"), "Risk Tracking Update"))
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "risk tracking updated",
+				"id":      syntheticId,
+			})
+		}
+	}
+}
+
+func (s *server) deleteRiskTracking(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		trackingKey, found := findRiskTrackingKey(modelInput, strings.TrimSpace(ginContext.Param("synthetic-id")))
+		if !found {
+			ginContext.JSON(http.StatusNotFound, gin.H{
+				"error": "risk tracking not found",
+			})
+			return
+		}
+		delete(modelInput.RiskTracking, trackingKey)
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, changeReason(ginContext.Query("change_reason"), "Risk Tracking Deletion"))
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "risk tracking deleted",
+				"id":      trackingKey,
+			})
+		}
+	}
+}
+
+// setRiskTrackings transitions several risks at once, recording the change reason in the model history
+func (s *server) setRiskTrackings(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		payload := payloadRiskTrackingBulk{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		if len(payload.SyntheticIds) == 0 {
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "no synthetic risk ids given",
+			})
+			return
+		}
+		riskTrackingInput, ok := populateRiskTracking(ginContext, payload.RiskTracking)
+		if !ok {
+			return
+		}
+		if modelInput.RiskTracking == nil {
+			modelInput.RiskTracking = make(map[string]input.RiskTracking)
+		}
+		ids := make([]string, 0)
+		for _, syntheticId := range payload.SyntheticIds {
+			syntheticId = strings.TrimSpace(syntheticId)
+			if trackingKey, found := findRiskTrackingKey(modelInput, syntheticId); found {
+				syntheticId = trackingKey
+			}
+			modelInput.RiskTracking[syntheticId] = riskTrackingInput
+			ids = append(ids, syntheticId)
+		}
+		if _, ok = s.analyzeModelInput(ginContext, &modelInput); !ok {
+			return
+		}
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, changeReason(payload.ChangeReason, "Risk Tracking Bulk Update"))
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "risk tracking updated",
+				"ids":     ids,
+			})
+		}
+	}
+}
+
+// deleteRiskTrackings removes the tracking of all ids given as (repeated) synthetic-id query parameter
+func (s *server) deleteRiskTrackings(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		syntheticIds := ginContext.QueryArray("synthetic-id")
+		if len(syntheticIds) == 0 {
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "no synthetic risk ids given",
+			})
+			return
+		}
+		ids := make([]string, 0)
+		for _, syntheticId := range syntheticIds {
+			trackingKey, found := findRiskTrackingKey(modelInput, strings.TrimSpace(syntheticId))
+			if !found {
+				ginContext.JSON(http.StatusNotFound, gin.H{
+					"error": fmt.Sprintf("risk tracking not found: %v", syntheticId),
+				})
+				return
+			}
+			delete(modelInput.RiskTracking, trackingKey)
+			ids = append(ids, trackingKey)
+		}
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, changeReason(ginContext.Query("change_reason"), "Risk Tracking Bulk Deletion"))
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "risk tracking deleted",
+				"ids":     ids,
+			})
+		}
+	}
+}
+
+// getUncheckedRisks lists the identified risks not yet checked, along with their tracking (if any)
+func (s *server) getUncheckedRisks(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		result, ok := s.analyzeModelInput(ginContext, &modelInput)
+		if !ok {
+			return
+		}
+		uncheckedRisks := make([]*types.Risk, 0)
+		for _, risk := range result.ParsedModel.AllRisksWithCurrentStatus() {
+			if risk.RiskStatus == types.Unchecked {
+				uncheckedRisks = append(uncheckedRisks, risk)
+			}
+		}
+		types.SortByRiskSeverity(uncheckedRisks)
+		payload := make([]payloadUncheckedRisk, 0, len(uncheckedRisks))
+		for _, risk := range uncheckedRisks {
+			payload = append(payload, payloadUncheckedRisk{
+				Risk:         risk,
+				RiskTracking: result.ParsedModel.GetRiskTracking(risk),
+			})
+		}
+		ginContext.JSON(http.StatusOK, payload)
+	}
+}
+
+func populateRiskTracking(ginContext *gin.Context, payload payloadRiskTracking) (riskTrackingInput input.RiskTracking, ok bool) {
+	status, err := types.ParseRiskStatus(payload.Status)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return riskTrackingInput, false
+	}
+	date := time.Now().Format("2006-01-02")
+	if len(payload.Date) > 0 {
+		parsedDate, err := time.Parse("2006-01-02", payload.Date)
+		if err != nil {
+			handleErrorInServiceCall(fmt.Errorf("unable to parse 'date' of risk tracking (expected format: '2006-01-02'): %v", payload.Date), ginContext)
+			return riskTrackingInput, false
+		}
+		date = parsedDate.Format("2006-01-02")
+	}
+	riskTrackingInput = input.RiskTracking{
+		Status:        status.String(),
+		Justification: payload.Justification,
+		Ticket:        payload.Ticket,
+		Date:          date,
+		CheckedBy:     payload.CheckedBy,
+	}
+	return riskTrackingInput, true
+}
+
+// analyzeModelInput runs the risk analysis, which (unlike the plain model validation) also checks the risk tracking
+func (s *server) analyzeModelInput(ginContext *gin.Context, modelInput *input.Model) (result *model.ReadResult, ok bool) {
+	progressReporter := DefaultProgressReporter{
+		Verbose:       s.config.GetVerbose(),
+		SuppressError: true,
+	}
+	result, err := model.AnalyzeModel(modelInput, s.config, s.builtinRiskRules, s.customRiskRules, progressReporter)
+	if err != nil {
+		handleErrorInServiceCall(fmt.Errorf("invalid model: %w", err), ginContext)
+		return nil, false
+	}
+	return result, true
+}
+
+// findRiskTrackingKey finds the risk tracking entry of the (possibly wildcard) synthetic risk id, ignoring the case like the analysis does
+func findRiskTrackingKey(modelInput input.Model, syntheticId string) (trackingKey string, found bool) {
+	for trackingKey = range modelInput.RiskTracking {
+		if strings.EqualFold(strings.TrimSpace(trackingKey), syntheticId) {
+			return trackingKey, true
+		}
+	}
+	return "", false
+}
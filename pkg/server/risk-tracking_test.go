@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRiskTrackingModelYaml tracks the risks of the base images by wildcard
+const testRiskTrackingModelYaml = testModelYaml + `  container-baseimage-backdooring@*:
+    status: accepted
+    justification: vendor images
+`
+
+func TestGetRiskTrackingByWildcard(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testRiskTrackingModelYaml)
+	riskTracking := "/models/" + modelId + "/risk-tracking/"
+
+	// the entry itself, ignoring the case
+	response := ts.request(http.MethodGet, riskTracking+url.PathEscape("Container-Baseimage-Backdooring@*"), "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "vendor images", decodeTestResponse(t, response)["container-baseimage-backdooring@*"].(map[string]any)["justification"])
+
+	// a risk tracked by the wildcard entry only
+	response = ts.request(http.MethodGet, riskTracking+"container-baseimage-backdooring@database", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	tracking := decodeTestResponse(t, response)["container-baseimage-backdooring@database"].(map[string]any)
+	assert.Equal(t, "accepted", tracking["status"])
+	assert.Equal(t, "vendor images", tracking["justification"])
+
+	response = ts.request(http.MethodGet, riskTracking+"missing-cloud-hardening@cluster", "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestGetUncheckedRisks(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testRiskTrackingModelYaml+`  missing-cloud-hardening@cluster:
+    status: unchecked
+    justification: to be discussed
+`)
+
+	response := ts.request(http.MethodGet, "/models/"+modelId+"/unchecked-risks", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var uncheckedRisks []payloadUncheckedRisk
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &uncheckedRisks))
+
+	trackingBySyntheticId := make(map[string]string)
+	for _, uncheckedRisk := range uncheckedRisks {
+		trackingBySyntheticId[uncheckedRisk.Risk.SyntheticId] = ""
+		if uncheckedRisk.RiskTracking != nil {
+			trackingBySyntheticId[uncheckedRisk.Risk.SyntheticId] = uncheckedRisk.RiskTracking.Justification
+		}
+	}
+	// the risks accepted by the wildcard are checked, the one explicitly unchecked comes with its tracking
+	assert.Equal(t, map[string]string{
+		"sql-nosql-injection@web-server@database@web-server>database-traffic":            "",
+		"unguarded-access-from-internet@database@web-server@web-server>database-traffic": "",
+		"missing-cloud-hardening@cluster":                                                "to be discussed",
+		"missing-cloud-hardening@network":                                                "",
+	}, trackingBySyntheticId)
+
+	// most severe first
+	for i := 1; i < len(uncheckedRisks); i++ {
+		assert.GreaterOrEqual(t, uncheckedRisks[i-1].Risk.Severity, uncheckedRisks[i].Risk.Severity)
+	}
+}
+
+func TestSetRiskTrackings(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testRiskTrackingModelYaml)
+	riskTracking := "/models/" + modelId + "/risk-tracking"
+
+	response := ts.request(http.MethodPut, riskTracking, `{
+		"change_reason": "Review: sprint 42",
+		"synthetic_ids": ["missing-cloud-hardening@cluster", "Container-Baseimage-Backdooring@*"],
+		"risk_tracking": {"status": "mitigated", "ticket": "SEC-42", "date": "2024-03-04"}
+	}`, "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, []any{"missing-cloud-hardening@cluster", "container-baseimage-backdooring@*"}, decodeTestResponse(t, response)["ids"])
+
+	modelInput := ts.readModel(t, key, modelId)
+	for _, syntheticId := range []string{"missing-cloud-hardening@cluster", "container-baseimage-backdooring@*"} {
+		assert.Equal(t, "mitigated", modelInput.RiskTracking[syntheticId].Status, syntheticId)
+		assert.Equal(t, "SEC-42", modelInput.RiskTracking[syntheticId].Ticket, syntheticId)
+		assert.Equal(t, "2024-03-04", modelInput.RiskTracking[syntheticId].Date, syntheticId)
+	}
+	assert.Equal(t, "Review- sprint 42", lastChangeReason(t, ts, key, modelId))
+
+	// all or nothing: an id matching no risk rejects the whole transition
+	response = ts.request(http.MethodPut, riskTracking, `{
+		"synthetic_ids": ["missing-cloud-hardening@network", "unknown-category@database"],
+		"risk_tracking": {"status": "accepted"}
+	}`, "token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.NotContains(t, ts.readModel(t, key, modelId).RiskTracking, "missing-cloud-hardening@network")
+
+	response = ts.request(http.MethodPut, riskTracking, `{"synthetic_ids": [], "risk_tracking": {"status": "accepted"}}`, "token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = ts.request(http.MethodPut, riskTracking, `{"synthetic_ids": ["missing-cloud-hardening@network"], "risk_tracking": {"status": "unknown"}}`, "token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestDeleteRiskTrackings(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testRiskTrackingModelYaml)
+	riskTracking := "/models/" + modelId + "/risk-tracking"
+
+	// all or nothing: an unknown id keeps all
+	response := ts.request(http.MethodDelete, riskTracking+"?synthetic-id=missing-vault@*&synthetic-id=unknown@database", "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Contains(t, ts.readModel(t, key, modelId).RiskTracking, "missing-vault@*")
+
+	response = ts.request(http.MethodDelete, riskTracking+"?synthetic-id=Missing-Vault@*&synthetic-id=container-baseimage-backdooring@*&change_reason=Cleanup", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, []any{"missing-vault@*", "container-baseimage-backdooring@*"}, decodeTestResponse(t, response)["ids"])
+
+	modelInput := ts.readModel(t, key, modelId)
+	assert.NotContains(t, modelInput.RiskTracking, "missing-vault@*")
+	assert.NotContains(t, modelInput.RiskTracking, "container-baseimage-backdooring@*")
+	assert.Contains(t, modelInput.RiskTracking, "unencrypted-asset@database")
+	assert.Equal(t, "Cleanup", lastChangeReason(t, ts, key, modelId))
+
+	response = ts.request(http.MethodDelete, riskTracking, "", "token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestChangeReason(t *testing.T) {
+	assert.Equal(t, "Fallback", changeReason("", "Fallback"))
+	assert.Equal(t, "Review- sprint 42", changeReason("Review: sprint 42", "Fallback"))
+	assert.Equal(t, "a-b-c.d_e", changeReason("a/b\\c.d_e", "Fallback"))
+	assert.Len(t, changeReason(strings.Repeat("x", 200), "Fallback"), 100)
+}
+
+// lastChangeReason tells the reason of the latest change of the model
+func lastChangeReason(t *testing.T, ts *testServer, key string, modelId string) string {
+	entries := ts.history(t, key, modelId)
+	if !assert.NotEmpty(t, entries) {
+		return ""
+	}
+	return entries[len(entries)-1].ChangeReason
+}
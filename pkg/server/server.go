@@ -5,6 +5,7 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package server
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,10 +15,13 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/threagile/threagile/pkg/model"
+	"github.com/threagile/threagile/pkg/report"
 	"github.com/threagile/threagile/pkg/types"
 )
 
@@ -27,71 +31,160 @@ type serverConfigReader interface {
 	GetInteractive() bool
 	GetAppFolder() string
 	GetPluginFolder() string
+	GetScriptRulesFolder() string
 	GetDataFolder() string
 	GetOutputFolder() string
 	GetServerFolder() string
 	GetTempFolder() string
 	GetKeyFolder() string
 	GetInputFile() string
+	GetVariant() string
 	GetImportedInputFile() string
 	GetDataFlowDiagramFilenamePNG() string
 	GetDataAssetDiagramFilenamePNG() string
 	GetDataFlowDiagramFilenameDOT() string
 	GetDataAssetDiagramFilenameDOT() string
 	GetReportFilename() string
+	GetHtmlReportFilename() string
 	GetExcelRisksFilename() string
 	GetRiskExcelConfigHideColumns() []string
 	GetRiskExcelConfigSortByColumns() []string
 	GetRiskExcelConfigWidthOfColumns() map[string]float64
+	GetRiskExcelWrapText() bool
+	GetRiskExcelShrinkColumnsToFit() bool
+	GetRiskExcelColorText() bool
 	GetExcelTagsFilename() string
 	GetJsonRisksFilename() string
+	GetSarifRisksFilename() string
 	GetJsonTechnicalAssetsFilename() string
 	GetJsonStatsFilename() string
 	GetTemplateFilename() string
+	GetReportLogoImagePath() string
 	GetTechnologyFilename() string
 	GetRiskRulePlugins() []string
 	GetSkipRiskRules() []string
 	GetExecuteModelMacro() string
 	GetServerMode() bool
 	GetDiagramDPI() int
+	GetDiagramFormats() []string
 	GetServerPort() int
 	GetGraphvizDPI() int
+	GetMinGraphvizDPI() int
 	GetMaxGraphvizDPI() int
 	GetBackupHistoryFilesToKeep() int
+	GetServerWorkers() int
+	GetServerJobTimeout() int
+	GetServerJobMemoryLimit() int
+	GetServerSubprocess() bool
+	GetServerModelStore() string
+	GetServerPersistTokens() bool
 	GetAddModelTitle() bool
 	GetAddLegend() bool
 	GetKeepDiagramSourceFiles() bool
 	GetIgnoreOrphanedRiskTracking() bool
+	GetReportConfigurationHideChapters() map[report.ChaptersToShowHide]bool
+	GetHideEmptyChapters() bool
+	GetAttractiveness() types.Attractiveness
 	GetThreagileVersion() string
 	GetProgressReporter() types.ProgressReporter
 }
 
 type server struct {
 	config                         serverConfigReader
-	successCount                   int
-	errorCount                     int
+	successCount                   atomic.Int64
+	errorCount                     atomic.Int64
+	keyCount                       atomic.Int64
+	modelCount                     atomic.Int64
+	metrics                        *serverMetrics
 	globalLock                     sync.Mutex
 	throttlerLock                  sync.Mutex
 	createdObjectsThrottler        map[string][]int64
-	mapTokenHashToTimeoutStruct    map[string]timeoutStruct
+	mapTokenHashToTimeoutStruct    map[string]*timeoutStruct
 	mapFolderNameToTokenHash       map[string]string
 	extremeShortTimeoutsForTesting bool
 	locksByFolderName              map[string]*sync.Mutex
 	builtinRiskRules               types.RiskRules
 	customRiskRules                types.RiskRules
+	scriptRiskRuleIds              map[string]bool // of the rules loaded from the script rules folder
+	runner                         *analysisRunner
+	models                         ModelStore
+	jobsLock                       sync.Mutex
+	jobs                           map[string]*backgroundJob
+	jobCommands                    *report.GenerateCommands // the outputs of a job
+	jobRetention                   time.Duration            // finished jobs are kept that long for their artifacts to be downloaded
+	macroSessionsLock              sync.Mutex
+	macroSessions                  map[string]*macroSession
 }
 
-func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) {
+func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) error {
+	s, err := newServer(config, builtinRiskRules)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.models.Close() }()
+
+	fmt.Println("Threagile is running...")
+	return s.newRouter(gin.Default()).Run(":" + strconv.Itoa(s.config.GetServerPort())) // listen and serve on 0.0.0.0:8080 or whatever port was specified
+}
+
+// newServer loads the risk rules, opens the model store and restores the tokens, the caller closes s.models
+func newServer(config serverConfigReader, builtinRiskRules types.RiskRules) (*server, error) {
 	s := &server{
 		config:                         config,
 		createdObjectsThrottler:        make(map[string][]int64),
-		mapTokenHashToTimeoutStruct:    make(map[string]timeoutStruct),
+		mapTokenHashToTimeoutStruct:    make(map[string]*timeoutStruct),
 		mapFolderNameToTokenHash:       make(map[string]string),
 		extremeShortTimeoutsForTesting: false,
 		locksByFolderName:              make(map[string]*sync.Mutex),
 		builtinRiskRules:               builtinRiskRules,
+		jobs:                           make(map[string]*backgroundJob),
+		jobCommands:                    analysisCommands(),
+		jobRetention:                   defaultJobRetention,
+		macroSessions:                  make(map[string]*macroSession),
+		metrics:                        newServerMetrics(),
+	}
+	s.customRiskRules = model.LoadCustomRiskRules(s.config.GetPluginFolder(), s.config.GetRiskRulePlugins(), config.GetProgressReporter())
+	builtinWithScriptRiskRules, customWithScriptRiskRules, scriptRulesError := model.LoadScriptRiskRules(s.config.GetScriptRulesFolder(), s.builtinRiskRules, s.customRiskRules, config.GetProgressReporter())
+	if scriptRulesError != nil {
+		return nil, fmt.Errorf("unable to load script risk rules: %w", scriptRulesError)
+	}
+	s.scriptRiskRuleIds = make(map[string]bool)
+	for _, rules := range []types.RiskRules{builtinWithScriptRiskRules, customWithScriptRiskRules} {
+		for id, rule := range rules {
+			// any rule other than the one before loading the scripts is a new, overriding or extending script rule
+			if rule != s.builtinRiskRules[id] && rule != s.customRiskRules[id] {
+				s.scriptRiskRuleIds[id] = true
+			}
+		}
 	}
-	router := gin.Default()
+	// the rules record their execution times, which is only possible for the analyses run in-process
+	s.builtinRiskRules, s.customRiskRules = timedRiskRules(builtinWithScriptRiskRules, s.metrics), timedRiskRules(customWithScriptRiskRules, s.metrics)
+	s.runner = newAnalysisRunner(s.config, s.builtinRiskRules, s.customRiskRules, s.metrics)
+
+	models, modelStoreError := OpenModelStore(s.config.GetServerModelStore(), s.config)
+	if modelStoreError != nil {
+		return nil, fmt.Errorf("unable to open model store: %w", modelStoreError)
+	}
+	s.models = models
+
+	tokensError := s.loadTokens()
+	if tokensError != nil {
+		_ = models.Close()
+		return nil, fmt.Errorf("unable to load tokens: %w", tokensError)
+	}
+
+	countError := s.countKeysAndModels()
+	if countError != nil {
+		_ = models.Close()
+		return nil, fmt.Errorf("unable to count models: %w", countError)
+	}
+
+	return s, nil
+}
+
+// newRouter registers the routes of the server at the given router
+func (s *server) newRouter(router *gin.Engine) *gin.Engine {
+	router.Use(s.metrics.middleware())
 	router.LoadHTMLGlob(filepath.Join(s.config.GetServerFolder(), "static", "*.html")) // <==
 	router.GET("/", func(c *gin.Context) {
 		c.HTML(http.StatusOK, "index.html", gin.H{})
@@ -133,7 +226,7 @@ func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) {
 	})
 	router.GET("/meta/version", func(c *gin.Context) {
 		c.JSON(200, gin.H{
-			"version":         config.GetThreagileVersion(),
+			"version":         s.config.GetThreagileVersion(),
 			"build_timestamp": s.config.GetBuildTimestamp(),
 		})
 	})
@@ -150,7 +243,7 @@ func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) {
 			"encryption":                   arrayOfStringValues(types.EncryptionStyleValues()),
 			"data_format":                  arrayOfStringValues(types.DataFormatValues()),
 			"protocol":                     arrayOfStringValues(types.ProtocolValues()),
-			"technical_asset_technology":   arrayOfStringValues(types.TechnicalAssetTechnologyValues(config)),
+			"technical_asset_technology":   arrayOfStringValues(types.TechnicalAssetTechnologyValues(s.config)),
 			"technical_asset_machine":      arrayOfStringValues(types.TechnicalAssetMachineValues()),
 			"trust_boundary_type":          arrayOfStringValues(types.TrustBoundaryTypeValues()),
 			"data_breach_probability":      arrayOfStringValues(types.DataBreachProbabilityValues()),
@@ -163,11 +256,15 @@ func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) {
 		})
 	})
 
-	// TODO router.GET("/meta/risk-rules", listRiskRules)
-	// TODO router.GET("/meta/model-macros", listModelMacros)
+	router.GET("/meta/risk-rules", s.listRiskRules)
+	router.GET("/meta/model-macros", s.listModelMacros)
 
 	router.GET("/meta/stats", s.stats)
 
+	router.GET("/metrics", s.getMetrics)
+	router.GET("/healthz", s.healthz)
+	router.GET("/readyz", s.readyz)
+
 	router.POST("/edit-model/analyze", s.editModelAnalyze)
 
 	router.POST("/direct/analyze", s.analyze)
@@ -176,6 +273,7 @@ func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) {
 
 	router.POST("/auth/keys", s.createKey)
 	router.DELETE("/auth/keys", s.deleteKey)
+	router.POST("/auth/keys/rotate", s.rotateKey)
 	router.POST("/auth/tokens", s.createToken)
 	router.DELETE("/auth/tokens", s.deleteToken)
 
@@ -184,6 +282,7 @@ func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) {
 	router.DELETE("/models/:model-id", s.deleteModel)
 	router.GET("/models/:model-id", s.getModel)
 	router.PUT("/models/:model-id", s.importModel)
+	router.PATCH("/models/:model-id", s.patchModel)
 	router.GET("/models/:model-id/data-flow-diagram", s.streamDataFlowDiagram)
 	router.GET("/models/:model-id/data-asset-diagram", s.streamDataAssetDiagram)
 	router.GET("/models/:model-id/report-pdf", s.streamReportPDF)
@@ -193,19 +292,26 @@ func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) {
 	router.GET("/models/:model-id/technical-assets", s.streamTechnicalAssetsJSON)
 	router.GET("/models/:model-id/stats", s.streamStatsJSON)
 	router.GET("/models/:model-id/analysis", s.analyzeModelOnServerDirectly)
+	router.POST("/models/:model-id/jobs", s.createJob)
+
+	router.GET("/jobs/:job-id", s.getJob)
+	router.DELETE("/jobs/:job-id", s.deleteJob)
+	router.GET("/jobs/:job-id/events", s.streamJobEvents)
+	router.GET("/jobs/:job-id/artifacts/:artifact", s.getJobArtifact)
+	router.GET("/jobs/:job-id/result", s.getJobResult)
 
 	router.GET("/models/:model-id/cover", s.getCover)
 	router.PUT("/models/:model-id/cover", s.setCover)
 	router.GET("/models/:model-id/overview", s.getOverview)
 	router.PUT("/models/:model-id/overview", s.setOverview)
-	//router.GET("/models/:model-id/questions", getQuestions)
-	//router.PUT("/models/:model-id/questions", setQuestions)
+	router.GET("/models/:model-id/questions", s.getQuestions)
+	router.PUT("/models/:model-id/questions", s.setQuestions)
 	router.GET("/models/:model-id/abuse-cases", s.getAbuseCases)
 	router.PUT("/models/:model-id/abuse-cases", s.setAbuseCases)
 	router.GET("/models/:model-id/security-requirements", s.getSecurityRequirements)
 	router.PUT("/models/:model-id/security-requirements", s.setSecurityRequirements)
-	//router.GET("/models/:model-id/tags", getTags)
-	//router.PUT("/models/:model-id/tags", setTags)
+	router.GET("/models/:model-id/tags", s.getTags)
+	router.PUT("/models/:model-id/tags", s.setTags)
 
 	router.GET("/models/:model-id/data-assets", s.getDataAssets)
 	router.POST("/models/:model-id/data-assets", s.createNewDataAsset)
@@ -213,11 +319,44 @@ func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) {
 	router.PUT("/models/:model-id/data-assets/:data-asset-id", s.setDataAsset)
 	router.DELETE("/models/:model-id/data-assets/:data-asset-id", s.deleteDataAsset)
 
+	// listing the technical assets is served by the analysis result (see streamTechnicalAssetsJSON above)
+	router.POST("/models/:model-id/technical-assets", s.createNewTechnicalAsset)
+	router.GET("/models/:model-id/technical-assets/:technical-asset-id", s.getTechnicalAsset)
+	router.PUT("/models/:model-id/technical-assets/:technical-asset-id", s.setTechnicalAsset)
+	router.DELETE("/models/:model-id/technical-assets/:technical-asset-id", s.deleteTechnicalAsset)
+
+	router.GET("/models/:model-id/technical-assets/:technical-asset-id/communication-links", s.getCommunicationLinks)
+	router.POST("/models/:model-id/technical-assets/:technical-asset-id/communication-links", s.createNewCommunicationLink)
+	router.GET("/models/:model-id/technical-assets/:technical-asset-id/communication-links/:communication-link-id", s.getCommunicationLink)
+	router.PUT("/models/:model-id/technical-assets/:technical-asset-id/communication-links/:communication-link-id", s.setCommunicationLink)
+	router.DELETE("/models/:model-id/technical-assets/:technical-asset-id/communication-links/:communication-link-id", s.deleteCommunicationLink)
+
 	router.GET("/models/:model-id/trust-boundaries", s.getTrustBoundaries)
-	//	router.POST("/models/:model-id/trust-boundaries", createNewTrustBoundary)
-	//	router.GET("/models/:model-id/trust-boundaries/:trust-boundary-id", getTrustBoundary)
-	//	router.PUT("/models/:model-id/trust-boundaries/:trust-boundary-id", setTrustBoundary)
-	//	router.DELETE("/models/:model-id/trust-boundaries/:trust-boundary-id", deleteTrustBoundary)
+	router.POST("/models/:model-id/trust-boundaries", s.createNewTrustBoundary)
+	router.GET("/models/:model-id/trust-boundaries/:trust-boundary-id", s.getTrustBoundary)
+	router.PUT("/models/:model-id/trust-boundaries/:trust-boundary-id", s.setTrustBoundary)
+	router.DELETE("/models/:model-id/trust-boundaries/:trust-boundary-id", s.deleteTrustBoundary)
+
+	router.GET("/models/:model-id/risk-tracking", s.getRiskTrackings)
+	router.PUT("/models/:model-id/risk-tracking", s.setRiskTrackings)
+	router.DELETE("/models/:model-id/risk-tracking", s.deleteRiskTrackings)
+	router.GET("/models/:model-id/risk-tracking/:synthetic-id", s.getRiskTracking)
+	router.PUT("/models/:model-id/risk-tracking/:synthetic-id", s.setRiskTracking)
+	router.DELETE("/models/:model-id/risk-tracking/:synthetic-id", s.deleteRiskTracking)
+	router.GET("/models/:model-id/unchecked-risks", s.getUncheckedRisks)
+
+	router.GET("/models/:model-id/history", s.getHistory)
+	router.GET("/models/:model-id/history/:history-id", s.getHistoryEntry)
+	router.POST("/models/:model-id/history/:history-id/restore", s.restoreHistoryEntry)
+	router.GET("/models/:model-id/history-diff", s.diffHistory)
+
+	router.POST("/models/:model-id/macro-sessions", s.createMacroSession)
+	router.GET("/models/:model-id/macro-sessions/:session-id", s.getMacroSession)
+	router.DELETE("/models/:model-id/macro-sessions/:session-id", s.deleteMacroSession)
+	router.POST("/models/:model-id/macro-sessions/:session-id/answer", s.answerMacroQuestion)
+	router.POST("/models/:model-id/macro-sessions/:session-id/back", s.undoMacroAnswer)
+	router.GET("/models/:model-id/macro-sessions/:session-id/changes", s.getMacroChanges)
+	router.POST("/models/:model-id/macro-sessions/:session-id/execute", s.executeMacro)
 
 	router.GET("/models/:model-id/shared-runtimes", s.getSharedRuntimes)
 	router.POST("/models/:model-id/shared-runtimes", s.createNewSharedRuntime)
@@ -225,10 +364,7 @@ func RunServer(config serverConfigReader, builtinRiskRules types.RiskRules) {
 	router.PUT("/models/:model-id/shared-runtimes/:shared-runtime-id", s.setSharedRuntime)
 	router.DELETE("/models/:model-id/shared-runtimes/:shared-runtime-id", s.deleteSharedRuntime)
 
-	s.customRiskRules = model.LoadCustomRiskRules(s.config.GetPluginFolder(), s.config.GetRiskRulePlugins(), config.GetProgressReporter())
-
-	fmt.Println("Threagile is running...")
-	_ = router.Run(":" + strconv.Itoa(s.config.GetServerPort())) // listen and serve on 0.0.0.0:8080 or whatever port was specified
+	return router
 }
 
 func (s *server) exampleFile(ginContext *gin.Context) {
@@ -297,52 +433,80 @@ func arrayOfStringValues(values []types.TypeEnum) []string {
 	return result
 }
 
+type payloadRiskRule struct {
+	*types.RiskCategory
+	Source        string   `json:"source"` // builtin, custom (plugin) or script
+	SupportedTags []string `json:"supported_tags"`
+}
+
+// listRiskRules lists the categories of all risk rules the server runs, where built-in ones overridden or extended by
+// a script from the script rules folder count as script
+func (s *server) listRiskRules(ginContext *gin.Context) {
+	result := make([]payloadRiskRule, 0, len(s.builtinRiskRules)+len(s.customRiskRules))
+	for source, rules := range map[string]types.RiskRules{"builtin": s.builtinRiskRules, "custom": s.customRiskRules} {
+		for id, rule := range rules {
+			ruleSource := source
+			if s.scriptRiskRuleIds[id] {
+				ruleSource = "script"
+			}
+			supportedTags := make([]string, 0)
+			for _, tag := range rule.SupportedTags() {
+				supportedTags = append(supportedTags, strings.ToLower(tag))
+			}
+			sort.Strings(supportedTags)
+			result = append(result, payloadRiskRule{
+				RiskCategory:  rule.Category(),
+				Source:        ruleSource,
+				SupportedTags: supportedTags,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	ginContext.JSON(http.StatusOK, result)
+}
+
 func (s *server) stats(ginContext *gin.Context) {
+	ginContext.JSON(http.StatusOK, gin.H{
+		"key_count":     s.keyCount.Load(),
+		"model_count":   s.modelCount.Load(),
+		"success_count": s.successCount.Load(),
+		"error_count":   s.errorCount.Load(),
+	})
+}
+
+// countKeysAndModels counts the keys and models once at startup, from then on the counts follow their creation and deletion
+func (s *server) countKeysAndModels() error {
 	keyCount, modelCount := 0, 0
 	keyFolders, err := os.ReadDir(filepath.Join(s.config.GetServerFolder(), s.config.GetKeyFolder()))
-	if err != nil {
-		log.Println(err)
-		ginContext.JSON(http.StatusInternalServerError, gin.H{
-			"error": "unable to collect stats",
-		})
-		return
+	if err != nil && !os.IsNotExist(err) {
+		return err
 	}
 	for _, keyFolder := range keyFolders {
 		if len(keyFolder.Name()) == 128 { // it's a sha512 token hash probably, so count it as token folder for the stats
 			keyCount++
-			if keyFolder.Name() != filepath.Clean(keyFolder.Name()) {
-				ginContext.JSON(http.StatusInternalServerError, gin.H{
-					"error": "weird file path",
-				})
-				return
-			}
-			modelFolders, err := os.ReadDir(filepath.Join(s.config.GetServerFolder(), s.config.GetKeyFolder(), keyFolder.Name()))
+			models, err := s.models.List(keyFolder.Name())
 			if err != nil {
-				log.Println(err)
-				ginContext.JSON(http.StatusInternalServerError, gin.H{
-					"error": "unable to collect stats",
-				})
-				return
-			}
-			for _, modelFolder := range modelFolders {
-				if len(modelFolder.Name()) == 36 { // it's a uuid model folder probably, so count it as model folder for the stats
-					modelCount++
-				}
+				return err
 			}
+			modelCount += len(models)
 		}
 	}
-	// TODO collect and deliver more stats (old model count?) and health info
-	ginContext.JSON(http.StatusOK, gin.H{
-		"key_count":     keyCount,
-		"model_count":   modelCount,
-		"success_count": s.successCount,
-		"error_count":   s.errorCount,
-	})
+	s.keyCount.Store(int64(keyCount))
+	s.modelCount.Store(int64(modelCount))
+	return nil
 }
 
 func handleErrorInServiceCall(err error, ginContext *gin.Context) {
 	log.Println(err)
-	ginContext.JSON(http.StatusBadRequest, gin.H{
+	body := gin.H{
 		"error": strings.TrimSpace(err.Error()),
-	})
+	}
+	// the problems found in a model are listed for the client to show them at the elements
+	var diagnostics types.Diagnostics
+	if errors.As(err, &diagnostics) {
+		body["diagnostics"] = diagnostics
+	}
+	ginContext.JSON(http.StatusBadRequest, body)
 }
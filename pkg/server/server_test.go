@@ -0,0 +1,353 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/risks"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+// testModelYaml has an asset of each kind, referenced by all the places that can reference it
+const testModelYaml = `title: Test Model
+date: "2024-01-02"
+business_criticality: important
+data_assets:
+  Customer Data:
+    id: customer-data
+    usage: business
+    quantity: many
+    confidentiality: confidential
+    integrity: critical
+    availability: operational
+technical_assets:
+  Web Server:
+    id: web-server
+    type: process
+    usage: business
+    size: application
+    technology: web-server
+    machine: container
+    internet: true
+    encryption: none
+    confidentiality: internal
+    integrity: important
+    availability: important
+    data_assets_processed:
+      - customer-data
+    communication_links:
+      Database Traffic:
+        target: database
+        protocol: jdbc
+        authentication: credentials
+        authorization: technical-user
+        usage: business
+        data_assets_sent:
+          - customer-data
+  Database:
+    id: database
+    type: datastore
+    usage: business
+    size: component
+    technology: database
+    machine: container
+    encryption: none
+    confidentiality: confidential
+    integrity: critical
+    availability: critical
+    data_assets_processed:
+      - customer-data
+    data_assets_stored:
+      - customer-data
+trust_boundaries:
+  Network:
+    id: network
+    type: network-cloud-security-group
+    technical_assets_inside:
+      - web-server
+      - database
+shared_runtimes:
+  Cluster:
+    id: cluster
+    technical_assets_running:
+      - web-server
+      - database
+risk_tracking:
+  unencrypted-asset@database:
+    status: accepted
+    justification: internal only
+  missing-hardening@database:
+    status: mitigated
+  unencrypted-communication@web-server>database-traffic@web-server@database:
+    status: in-progress
+  missing-vault@*:
+    status: accepted
+`
+
+// testModelId is a valid model id, which is not stored unless a test does so
+const testModelId = "00000000-0000-4000-8000-000000000000"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestNewServerFailures(t *testing.T) {
+	config := newTestServerConfig(t)
+	config.modelStore = "unknown"
+	_, err := newServer(config, risks.GetBuiltInRiskRules())
+	assert.ErrorContains(t, err, `unable to open model store: unknown model store "unknown"`)
+
+	config = newTestServerConfig(t)
+	config.persistTokens = true
+	assert.NoError(t, os.WriteFile(filepath.Join(config.serverFolder, tokensFilename), []byte("not json"), 0600))
+	_, err = newServer(config, risks.GetBuiltInRiskRules())
+	assert.ErrorContains(t, err, "unable to load tokens")
+
+	config = newTestServerConfig(t)
+	config.scriptRulesFolder = filepath.Join(config.serverFolder, "missing")
+	_, err = newServer(config, risks.GetBuiltInRiskRules())
+	assert.ErrorContains(t, err, "unable to load script risk rules")
+}
+
+// testServer serves the routes of a server working in temporary folders
+type testServer struct {
+	*server
+	router *gin.Engine
+}
+
+func newTestServer(t *testing.T, configure ...func(config *testServerConfig)) *testServer {
+	config := newTestServerConfig(t)
+	for _, apply := range configure {
+		apply(config)
+	}
+	s, err := newServer(config, risks.GetBuiltInRiskRules())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { _ = s.models.Close() })
+	return &testServer{server: s, router: s.newRouter(gin.New())}
+}
+
+// request sends the request with the given header names and values to the server
+func (what *testServer) request(method string, path string, body string, headers ...string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if len(body) > 0 {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		request.Header.Set(headers[i], headers[i+1])
+	}
+	recorder := httptest.NewRecorder()
+	what.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (what *testServer) createKey(t *testing.T) string {
+	response := what.request(http.MethodPost, "/auth/keys", "")
+	assert.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	return decodeTestResponse(t, response)["key"].(string)
+}
+
+// createToken creates a token of the key, restricted by the payload (unless empty)
+func (what *testServer) createToken(t *testing.T, key string, payload string) string {
+	response := what.request(http.MethodPost, "/auth/tokens", payload, "key", key)
+	assert.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	return decodeTestResponse(t, response)["token"].(string)
+}
+
+// storeModel adds the model to the store directly, bypassing the validation of the api
+func (what *testServer) storeModel(t *testing.T, key string, modelYaml string) string {
+	keyBytes, err := base64.RawURLEncoding.DecodeString(key)
+	assert.NoError(t, err)
+	data, err := what.encryptModel(keyBytes, []byte(modelYaml))
+	assert.NoError(t, err)
+	modelId := uuid.New().String()
+	assert.NoError(t, what.models.Create(keyIdOfFolder(what.folderNameFromKey(keyBytes)), StoredModel{Id: modelId}, data))
+	what.modelCount.Add(1)
+	return modelId
+}
+
+// readModel reads the model from the store directly
+func (what *testServer) readModel(t *testing.T, key string, modelId string) *input.Model {
+	keyBytes, err := base64.RawURLEncoding.DecodeString(key)
+	assert.NoError(t, err)
+	data, err := what.models.Read(keyIdOfFolder(what.folderNameFromKey(keyBytes)), modelId)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	yamlBytes, err := what.decryptModel(keyBytes, data)
+	assert.NoError(t, err)
+	modelInput := new(input.Model).Defaults()
+	assert.NoError(t, yaml.Unmarshal(yamlBytes, modelInput))
+	return modelInput
+}
+
+// history lists the history entries of the model in the store, oldest first
+func (what *testServer) history(t *testing.T, key string, modelId string) []HistoryEntry {
+	keyBytes, err := base64.RawURLEncoding.DecodeString(key)
+	assert.NoError(t, err)
+	entries, err := what.models.History(keyIdOfFolder(what.folderNameFromKey(keyBytes)), modelId)
+	assert.NoError(t, err)
+	return entries
+}
+
+// riskTrackingKeys lists the synthetic risk ids the model tracks risks by
+func riskTrackingKeys(modelInput *input.Model) []string {
+	keys := make([]string, 0, len(modelInput.RiskTracking))
+	for key := range modelInput.RiskTracking {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func decodeTestResponse(t *testing.T, response *httptest.ResponseRecorder) map[string]any {
+	result := make(map[string]any)
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &result), response.Body.String())
+	return result
+}
+
+type testServerConfig struct {
+	serverFolder      string
+	scriptRulesFolder string
+	modelStore        string
+	persistTokens     bool
+	historyToKeep     int
+	workers           int
+	jobTimeout        int
+	jobMemoryLimit    int
+	subprocess        bool
+}
+
+// newTestServerConfig creates the folders of the server, with placeholders of the pages it serves
+func newTestServerConfig(t *testing.T) *testServerConfig {
+	serverFolder := t.TempDir()
+	for _, folder := range []string{"static", "keys", "temp", "app"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(serverFolder, folder), 0700))
+	}
+	for _, page := range []string{"index.html", "edit-model.html"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(serverFolder, "static", page), []byte("<html></html>"), 0600))
+	}
+	return &testServerConfig{
+		serverFolder:  serverFolder,
+		modelStore:    ModelStoreFilesystem,
+		historyToKeep: 50,
+		workers:       2,
+		jobTimeout:    60,
+	}
+}
+
+func (what *testServerConfig) GetBuildTimestamp() string             { return "" }
+func (what *testServerConfig) GetVerbose() bool                      { return false }
+func (what *testServerConfig) GetInteractive() bool                  { return false }
+func (what *testServerConfig) GetAppFolder() string                  { return filepath.Join(what.serverFolder, "app") }
+func (what *testServerConfig) GetPluginFolder() string               { return "" }
+func (what *testServerConfig) GetScriptRulesFolder() string          { return what.scriptRulesFolder }
+func (what *testServerConfig) GetDataFolder() string                 { return "" }
+func (what *testServerConfig) GetOutputFolder() string               { return "" }
+func (what *testServerConfig) GetServerFolder() string               { return what.serverFolder }
+func (what *testServerConfig) GetTempFolder() string                 { return filepath.Join(what.serverFolder, "temp") }
+func (what *testServerConfig) GetKeyFolder() string                  { return "keys" }
+func (what *testServerConfig) GetInputFile() string                  { return "threagile.yaml" }
+func (what *testServerConfig) GetVariant() string                    { return "" }
+func (what *testServerConfig) GetImportedInputFile() string          { return "" }
+func (what *testServerConfig) GetDataFlowDiagramFilenamePNG() string { return "data-flow-diagram.png" }
+func (what *testServerConfig) GetDataAssetDiagramFilenamePNG() string {
+	return "data-asset-diagram.png"
+}
+func (what *testServerConfig) GetDataFlowDiagramFilenameDOT() string     { return "data-flow-diagram.gv" }
+func (what *testServerConfig) GetDataAssetDiagramFilenameDOT() string    { return "data-asset-diagram.gv" }
+func (what *testServerConfig) GetReportFilename() string                 { return "report.pdf" }
+func (what *testServerConfig) GetHtmlReportFilename() string             { return "report.html" }
+func (what *testServerConfig) GetExcelRisksFilename() string             { return "risks.xlsx" }
+func (what *testServerConfig) GetRiskExcelConfigHideColumns() []string   { return nil }
+func (what *testServerConfig) GetRiskExcelConfigSortByColumns() []string { return nil }
+func (what *testServerConfig) GetRiskExcelWrapText() bool                { return false }
+func (what *testServerConfig) GetRiskExcelShrinkColumnsToFit() bool      { return true }
+func (what *testServerConfig) GetRiskExcelColorText() bool               { return true }
+func (what *testServerConfig) GetExcelTagsFilename() string              { return "tags.xlsx" }
+func (what *testServerConfig) GetJsonRisksFilename() string              { return "risks.json" }
+func (what *testServerConfig) GetSarifRisksFilename() string             { return "risks.sarif" }
+func (what *testServerConfig) GetJsonTechnicalAssetsFilename() string    { return "technical-assets.json" }
+func (what *testServerConfig) GetJsonStatsFilename() string              { return "stats.json" }
+func (what *testServerConfig) GetTemplateFilename() string               { return "background.pdf" }
+func (what *testServerConfig) GetReportLogoImagePath() string            { return "report/threagile-logo.png" }
+func (what *testServerConfig) GetTechnologyFilename() string             { return "" }
+func (what *testServerConfig) GetRiskRulePlugins() []string              { return nil }
+func (what *testServerConfig) GetSkipRiskRules() []string                { return nil }
+func (what *testServerConfig) GetExecuteModelMacro() string              { return "" }
+func (what *testServerConfig) GetServerMode() bool                       { return true }
+func (what *testServerConfig) GetDiagramDPI() int                        { return 96 }
+func (what *testServerConfig) GetDiagramFormats() []string               { return []string{report.DiagramFormatPNG} }
+func (what *testServerConfig) GetServerPort() int                        { return 8080 }
+func (what *testServerConfig) GetGraphvizDPI() int                       { return 120 }
+func (what *testServerConfig) GetMinGraphvizDPI() int                    { return 20 }
+func (what *testServerConfig) GetMaxGraphvizDPI() int                    { return 300 }
+func (what *testServerConfig) GetBackupHistoryFilesToKeep() int          { return what.historyToKeep }
+func (what *testServerConfig) GetServerWorkers() int                     { return what.workers }
+func (what *testServerConfig) GetServerJobTimeout() int                  { return what.jobTimeout }
+func (what *testServerConfig) GetServerJobMemoryLimit() int              { return what.jobMemoryLimit }
+func (what *testServerConfig) GetServerSubprocess() bool                 { return what.subprocess }
+func (what *testServerConfig) GetServerModelStore() string               { return what.modelStore }
+func (what *testServerConfig) GetServerPersistTokens() bool              { return what.persistTokens }
+func (what *testServerConfig) GetAddModelTitle() bool                    { return false }
+func (what *testServerConfig) GetAddLegend() bool                        { return false }
+func (what *testServerConfig) GetKeepDiagramSourceFiles() bool           { return false }
+func (what *testServerConfig) GetIgnoreOrphanedRiskTracking() bool       { return false }
+func (what *testServerConfig) GetHideEmptyChapters() bool                { return false }
+func (what *testServerConfig) GetAttractiveness() types.Attractiveness   { return types.Attractiveness{} }
+func (what *testServerConfig) GetThreagileVersion() string               { return "1.0.0" }
+func (what *testServerConfig) GetProgressReporter() types.ProgressReporter {
+	return &testProgressReporter{}
+}
+
+func (what *testServerConfig) GetRiskExcelConfigWidthOfColumns() map[string]float64 {
+	return nil
+}
+
+func (what *testServerConfig) GetReportConfigurationHideChapters() map[report.ChaptersToShowHide]bool {
+	return nil
+}
+
+type testProgressReporter struct {
+}
+
+func (what *testProgressReporter) Info(_ ...any)             {}
+func (what *testProgressReporter) Warn(_ ...any)             {}
+func (what *testProgressReporter) Error(_ ...any)            {}
+func (what *testProgressReporter) Infof(_ string, _ ...any)  {}
+func (what *testProgressReporter) Warnf(_ string, _ ...any)  {}
+func (what *testProgressReporter) Errorf(_ string, _ ...any) {}
+
+func TestServerServesModels(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodGet, "/models", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"id":"`+modelId+`","title":"Test Model"`)
+
+	// the test model must pass the validation of every change
+	response = ts.request(http.MethodPut, "/models/"+modelId+"/tags", `["some-tag"]`, "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, []string{"some-tag"}, ts.readModel(t, key, modelId).TagsAvailable)
+
+	response = ts.request(http.MethodGet, "/models", "", "token", "unknown")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
@@ -0,0 +1,426 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/threagile/threagile/pkg/input"
+	"github.com/threagile/threagile/pkg/types"
+)
+
+type payloadTechnicalAsset struct {
+	Title                   string   `yaml:"title" json:"title"`
+	Id                      string   `yaml:"id" json:"id"`
+	Description             string   `yaml:"description" json:"description"`
+	Type                    string   `yaml:"type" json:"type"`
+	Usage                   string   `yaml:"usage" json:"usage"`
+	UsedAsClientByHuman     bool     `yaml:"used_as_client_by_human" json:"used_as_client_by_human"`
+	OutOfScope              bool     `yaml:"out_of_scope" json:"out_of_scope"`
+	JustificationOutOfScope string   `yaml:"justification_out_of_scope" json:"justification_out_of_scope"`
+	Size                    string   `yaml:"size" json:"size"`
+	Technology              string   `yaml:"technology" json:"technology"`
+	Technologies            []string `yaml:"technologies" json:"technologies"`
+	Tags                    []string `yaml:"tags" json:"tags"`
+	Internet                bool     `yaml:"internet" json:"internet"`
+	Machine                 string   `yaml:"machine" json:"machine"`
+	Encryption              string   `yaml:"encryption" json:"encryption"`
+	Owner                   string   `yaml:"owner" json:"owner"`
+	Confidentiality         string   `yaml:"confidentiality" json:"confidentiality"`
+	Integrity               string   `yaml:"integrity" json:"integrity"`
+	Availability            string   `yaml:"availability" json:"availability"`
+	JustificationCiaRating  string   `yaml:"justification_cia_rating" json:"justification_cia_rating"`
+	MultiTenant             bool     `yaml:"multi_tenant" json:"multi_tenant"`
+	Redundant               bool     `yaml:"redundant" json:"redundant"`
+	CustomDevelopedParts    bool     `yaml:"custom_developed_parts" json:"custom_developed_parts"`
+	DataAssetsProcessed     []string `yaml:"data_assets_processed" json:"data_assets_processed"`
+	DataAssetsStored        []string `yaml:"data_assets_stored" json:"data_assets_stored"`
+	DataFormatsAccepted     []string `yaml:"data_formats_accepted" json:"data_formats_accepted"`
+	DiagramTweakOrder       int      `yaml:"diagram_tweak_order" json:"diagram_tweak_order"`
+}
+
+func (s *server) getTechnicalAsset(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, technicalAsset := range modelInput.TechnicalAssets {
+			if technicalAsset.ID == ginContext.Param("technical-asset-id") {
+				ginContext.JSON(http.StatusOK, gin.H{
+					title: technicalAsset,
+				})
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "technical asset not found",
+		})
+	}
+}
+
+func (s *server) createNewTechnicalAsset(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		payload := payloadTechnicalAsset{}
+		err := ginContext.BindJSON(&payload)
+		if err != nil {
+			log.Println(err)
+			ginContext.JSON(http.StatusBadRequest, gin.H{
+				"error": "unable to parse request payload",
+			})
+			return
+		}
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		if _, exists := modelInput.TechnicalAssets[payload.Title]; exists {
+			ginContext.JSON(http.StatusConflict, gin.H{
+				"error": "technical asset with this title already exists",
+			})
+			return
+		}
+		// but later it will in memory keyed by its "id", so do this uniqueness check also
+		for _, asset := range modelInput.TechnicalAssets {
+			if asset.ID == payload.Id {
+				ginContext.JSON(http.StatusConflict, gin.H{
+					"error": "technical asset with this id already exists",
+				})
+				return
+			}
+		}
+		technicalAssetInput, ok := populateTechnicalAsset(ginContext, payload)
+		if !ok {
+			return
+		}
+		if modelInput.TechnicalAssets == nil {
+			modelInput.TechnicalAssets = make(map[string]input.TechnicalAsset)
+		}
+		modelInput.TechnicalAssets[payload.Title] = technicalAssetInput
+		ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Technical Asset Creation")
+		if ok {
+			ginContext.JSON(http.StatusOK, gin.H{
+				"message": "technical asset created",
+				"id":      technicalAssetInput.ID,
+			})
+		}
+	}
+}
+
+func (s *server) setTechnicalAsset(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, technicalAsset := range modelInput.TechnicalAssets {
+			if technicalAsset.ID == ginContext.Param("technical-asset-id") {
+				payload := payloadTechnicalAsset{}
+				err := ginContext.BindJSON(&payload)
+				if err != nil {
+					log.Println(err)
+					ginContext.JSON(http.StatusBadRequest, gin.H{
+						"error": "unable to parse request payload",
+					})
+					return
+				}
+				if _, exists := modelInput.TechnicalAssets[payload.Title]; exists && payload.Title != title {
+					ginContext.JSON(http.StatusConflict, gin.H{
+						"error": "technical asset with this title already exists",
+					})
+					return
+				}
+				technicalAssetInput, ok := populateTechnicalAsset(ginContext, payload)
+				if !ok {
+					return
+				}
+				// the communication links are maintained via their own endpoints
+				technicalAssetInput.CommunicationLinks = technicalAsset.CommunicationLinks
+				// in order to also update the title, remove the asset from the map and re-insert it (with new key)
+				delete(modelInput.TechnicalAssets, title)
+				modelInput.TechnicalAssets[payload.Title] = technicalAssetInput
+				idChanged := technicalAssetInput.ID != technicalAsset.ID
+				if idChanged { // ID-CHANGE-PROPAGATION
+					renameTechnicalAssetReferences(&modelInput, technicalAsset.ID, technicalAssetInput.ID)
+				}
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Technical Asset Update")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":    "technical asset updated",
+						"id":         technicalAssetInput.ID,
+						"id_changed": idChanged, // in order to signal to clients, that other model parts might've received updates as well and should be reloaded
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "technical asset not found",
+		})
+	}
+}
+
+func (s *server) deleteTechnicalAsset(ginContext *gin.Context) {
+	folderNameOfKey, key, ok := s.checkTokenToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	s.lockFolder(folderNameOfKey)
+	defer s.unlockFolder(folderNameOfKey)
+	modelInput, _, ok := s.readModel(ginContext, ginContext.Param("model-id"), key, folderNameOfKey)
+	if ok {
+		// yes, here keyed by title in YAML for better readability in the YAML file itself
+		for title, technicalAsset := range modelInput.TechnicalAssets {
+			if technicalAsset.ID == ginContext.Param("technical-asset-id") {
+				// remove it itself (including its outgoing communication links) and all usages of it !!
+				delete(modelInput.TechnicalAssets, title)
+				referencesDeleted := removeTechnicalAssetReferences(&modelInput, technicalAsset.ID)
+				ok = s.writeModel(ginContext, key, folderNameOfKey, &modelInput, "Technical Asset Deletion")
+				if ok {
+					ginContext.JSON(http.StatusOK, gin.H{
+						"message":            "technical asset deleted",
+						"id":                 technicalAsset.ID,
+						"references_deleted": referencesDeleted, // in order to signal to clients, that other model parts might've been deleted as well
+					})
+				}
+				return
+			}
+		}
+		ginContext.JSON(http.StatusNotFound, gin.H{
+			"error": "technical asset not found",
+		})
+	}
+}
+
+func populateTechnicalAsset(ginContext *gin.Context, payload payloadTechnicalAsset) (technicalAssetInput input.TechnicalAsset, ok bool) {
+	technicalAssetType, err := types.ParseTechnicalAssetType(payload.Type)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return technicalAssetInput, false
+	}
+	usage, err := types.ParseUsage(payload.Usage)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return technicalAssetInput, false
+	}
+	size, err := types.ParseTechnicalAssetSize(payload.Size)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return technicalAssetInput, false
+	}
+	machine, err := types.ParseTechnicalAssetMachine(payload.Machine)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return technicalAssetInput, false
+	}
+	encryption, err := types.ParseEncryptionStyle(payload.Encryption)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return technicalAssetInput, false
+	}
+	confidentiality, err := types.ParseConfidentiality(payload.Confidentiality)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return technicalAssetInput, false
+	}
+	integrity, err := types.ParseCriticality(payload.Integrity)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return technicalAssetInput, false
+	}
+	availability, err := types.ParseCriticality(payload.Availability)
+	if err != nil {
+		handleErrorInServiceCall(err, ginContext)
+		return technicalAssetInput, false
+	}
+	dataFormatsAccepted := make([]string, 0)
+	for _, value := range payload.DataFormatsAccepted {
+		dataFormat, err := types.ParseDataFormat(value)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return technicalAssetInput, false
+		}
+		dataFormatsAccepted = append(dataFormatsAccepted, dataFormat.String())
+	}
+	// the technologies and referenced data assets are checked when the model gets validated before writing it
+	technicalAssetInput = input.TechnicalAsset{
+		ID:                      payload.Id,
+		Description:             payload.Description,
+		Type:                    technicalAssetType.String(),
+		Usage:                   usage.String(),
+		UsedAsClientByHuman:     payload.UsedAsClientByHuman,
+		OutOfScope:              payload.OutOfScope,
+		JustificationOutOfScope: payload.JustificationOutOfScope,
+		Size:                    size.String(),
+		Technology:              payload.Technology,
+		Technologies:            payload.Technologies,
+		Tags:                    lowerCaseAndTrim(payload.Tags),
+		Internet:                payload.Internet,
+		Machine:                 machine.String(),
+		Encryption:              encryption.String(),
+		Owner:                   payload.Owner,
+		Confidentiality:         confidentiality.String(),
+		Integrity:               integrity.String(),
+		Availability:            availability.String(),
+		JustificationCiaRating:  payload.JustificationCiaRating,
+		MultiTenant:             payload.MultiTenant,
+		Redundant:               payload.Redundant,
+		CustomDevelopedParts:    payload.CustomDevelopedParts,
+		DataAssetsProcessed:     payload.DataAssetsProcessed,
+		DataAssetsStored:        payload.DataAssetsStored,
+		DataFormatsAccepted:     dataFormatsAccepted,
+		DiagramTweakOrder:       payload.DiagramTweakOrder,
+	}
+	return technicalAssetInput, true
+}
+
+// renameTechnicalAssetReferences points all usages of the technical asset to its new id
+func renameTechnicalAssetReferences(modelInput *input.Model, oldId string, newId string) {
+	for techAssetTitle, techAsset := range modelInput.TechnicalAssets {
+		for title, commLink := range techAsset.CommunicationLinks {
+			if commLink.Target == oldId {
+				commLink.Target = newId
+				modelInput.TechnicalAssets[techAssetTitle].CommunicationLinks[title] = commLink
+			}
+		}
+	}
+	for title, trustBoundary := range modelInput.TrustBoundaries {
+		trustBoundary.TechnicalAssetsInside = replaceInSlice(trustBoundary.TechnicalAssetsInside, oldId, newId)
+		modelInput.TrustBoundaries[title] = trustBoundary
+	}
+	for title, sharedRuntime := range modelInput.SharedRuntimes {
+		sharedRuntime.TechnicalAssetsRunning = replaceInSlice(sharedRuntime.TechnicalAssetsRunning, oldId, newId)
+		modelInput.SharedRuntimes[title] = sharedRuntime
+	}
+	for _, individualRiskCat := range modelInput.CustomRiskCategories {
+		for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+			if individualRiskInstance.MostRelevantTechnicalAsset == oldId {
+				individualRiskInstance.MostRelevantTechnicalAsset = newId
+			}
+			// communication link ids are prefixed by the id of their source asset
+			if strings.HasPrefix(individualRiskInstance.MostRelevantCommunicationLink, oldId+">") {
+				individualRiskInstance.MostRelevantCommunicationLink = newId + strings.TrimPrefix(individualRiskInstance.MostRelevantCommunicationLink, oldId)
+			}
+			individualRiskInstance.DataBreachTechnicalAssets = replaceInSlice(individualRiskInstance.DataBreachTechnicalAssets, oldId, newId)
+			individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+		}
+	}
+	renameRiskTrackingElement(modelInput, oldId, newId)
+	for i, invisibleConnection := range modelInput.DiagramTweakInvisibleConnectionsBetweenAssets {
+		modelInput.DiagramTweakInvisibleConnectionsBetweenAssets[i] = strings.Join(replaceInSlice(strings.Split(invisibleConnection, ":"), oldId, newId), ":")
+	}
+	for i, sameRank := range modelInput.DiagramTweakSameRankAssets {
+		modelInput.DiagramTweakSameRankAssets[i] = strings.Join(replaceInSlice(strings.Split(sameRank, ":"), oldId, newId), ":")
+	}
+}
+
+// removeTechnicalAssetReferences removes all usages of the (already deleted) technical asset, including the links targeting it
+func removeTechnicalAssetReferences(modelInput *input.Model, id string) (referencesDeleted bool) {
+	for _, techAsset := range modelInput.TechnicalAssets {
+		for title, commLink := range techAsset.CommunicationLinks {
+			if commLink.Target == id {
+				referencesDeleted = true
+				delete(techAsset.CommunicationLinks, title)
+			}
+		}
+	}
+	for title, trustBoundary := range modelInput.TrustBoundaries {
+		var removed bool
+		trustBoundary.TechnicalAssetsInside, removed = removeFromSlice(trustBoundary.TechnicalAssetsInside, id)
+		referencesDeleted = referencesDeleted || removed
+		modelInput.TrustBoundaries[title] = trustBoundary
+	}
+	for title, sharedRuntime := range modelInput.SharedRuntimes {
+		var removed bool
+		sharedRuntime.TechnicalAssetsRunning, removed = removeFromSlice(sharedRuntime.TechnicalAssetsRunning, id)
+		referencesDeleted = referencesDeleted || removed
+		modelInput.SharedRuntimes[title] = sharedRuntime
+	}
+	for _, individualRiskCat := range modelInput.CustomRiskCategories {
+		for individualRiskInstanceTitle, individualRiskInstance := range individualRiskCat.RisksIdentified {
+			if individualRiskInstance.MostRelevantTechnicalAsset == id {
+				referencesDeleted = true
+				individualRiskInstance.MostRelevantTechnicalAsset = ""
+			}
+			if strings.HasPrefix(individualRiskInstance.MostRelevantCommunicationLink, id+">") {
+				referencesDeleted = true
+				individualRiskInstance.MostRelevantCommunicationLink = ""
+			}
+			var removed bool
+			individualRiskInstance.DataBreachTechnicalAssets, removed = removeFromSlice(individualRiskInstance.DataBreachTechnicalAssets, id)
+			referencesDeleted = referencesDeleted || removed
+			individualRiskCat.RisksIdentified[individualRiskInstanceTitle] = individualRiskInstance
+		}
+	}
+	if removeRiskTrackingOfElement(modelInput, id) {
+		referencesDeleted = true
+	}
+	invisibleConnections := make([]string, 0)
+	for _, invisibleConnection := range modelInput.DiagramTweakInvisibleConnectionsBetweenAssets {
+		if _, removed := removeFromSlice(strings.Split(invisibleConnection, ":"), id); removed {
+			referencesDeleted = true
+			continue
+		}
+		invisibleConnections = append(invisibleConnections, invisibleConnection)
+	}
+	modelInput.DiagramTweakInvisibleConnectionsBetweenAssets = invisibleConnections
+	sameRanks := make([]string, 0)
+	for _, sameRank := range modelInput.DiagramTweakSameRankAssets {
+		assetIds, removed := removeFromSlice(strings.Split(sameRank, ":"), id)
+		referencesDeleted = referencesDeleted || removed
+		if len(assetIds) > 1 {
+			sameRanks = append(sameRanks, strings.Join(assetIds, ":"))
+		}
+	}
+	modelInput.DiagramTweakSameRankAssets = sameRanks
+	return referencesDeleted
+}
+
+// renameRiskTrackingElement renames an element of the model within the synthetic risk ids (category@element@...) the
+// risks are tracked by, the communication links being elements as well as prefixed by the id of their source asset
+func renameRiskTrackingElement(modelInput *input.Model, oldId string, newId string) {
+	for syntheticRiskId, riskTracking := range modelInput.RiskTracking {
+		parts := strings.Split(syntheticRiskId, "@")
+		for i := 1; i < len(parts); i++ {
+			if parts[i] == oldId {
+				parts[i] = newId
+			} else if strings.HasPrefix(parts[i], oldId+">") {
+				parts[i] = newId + strings.TrimPrefix(parts[i], oldId)
+			}
+		}
+		if renamedId := strings.Join(parts, "@"); renamedId != syntheticRiskId {
+			delete(modelInput.RiskTracking, syntheticRiskId)
+			modelInput.RiskTracking[renamedId] = riskTracking
+		}
+	}
+}
+
+// removeRiskTrackingOfElement removes the tracking of the risks of an element of the model (including the communication
+// links of a technical asset), as the risks are gone along with the element
+func removeRiskTrackingOfElement(modelInput *input.Model, id string) (removed bool) {
+	for syntheticRiskId := range modelInput.RiskTracking {
+		parts := strings.Split(syntheticRiskId, "@")
+		for i := 1; i < len(parts); i++ {
+			if parts[i] == id || strings.HasPrefix(parts[i], id+">") {
+				delete(modelInput.RiskTracking, syntheticRiskId)
+				removed = true
+				break
+			}
+		}
+	}
+	return removed
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDatabasePayload = `{
+	"title": "%v",
+	"id": "%v",
+	"type": "datastore",
+	"usage": "business",
+	"size": "component",
+	"technology": "database",
+	"machine": "container",
+	"encryption": "none",
+	"confidentiality": "confidential",
+	"integrity": "critical",
+	"availability": "critical",
+	"data_assets_processed": ["customer-data"],
+	"data_assets_stored": ["customer-data"]
+}`
+
+func TestCreateTechnicalAsset(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodPost, "/models/"+modelId+"/technical-assets", fmt.Sprintf(testDatabasePayload, "Cache", "cache"), "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "cache", decodeTestResponse(t, response)["id"])
+
+	cache, exists := ts.readModel(t, key, modelId).TechnicalAssets["Cache"]
+	if assert.True(t, exists) {
+		assert.Equal(t, "cache", cache.ID)
+		assert.Equal(t, []string{"customer-data"}, cache.DataAssetsStored)
+	}
+
+	response = ts.request(http.MethodGet, "/models/"+modelId+"/technical-assets/cache", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, decodeTestResponse(t, response), "Cache")
+
+	// neither title nor id may be taken already
+	response = ts.request(http.MethodPost, "/models/"+modelId+"/technical-assets", fmt.Sprintf(testDatabasePayload, "Cache", "other-cache"), "token", token)
+	assert.Equal(t, http.StatusConflict, response.Code)
+	response = ts.request(http.MethodPost, "/models/"+modelId+"/technical-assets", fmt.Sprintf(testDatabasePayload, "Other Cache", "cache"), "token", token)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	// references are checked by the validation of the model
+	response = ts.request(http.MethodPost, "/models/"+modelId+"/technical-assets",
+		`{"title": "Broken", "id": "broken", "type": "process", "usage": "business", "size": "service", "technology": "web-server", "machine": "virtual", "encryption": "none", "confidentiality": "public", "integrity": "operational", "availability": "operational", "data_assets_processed": ["unknown-data"]}`,
+		"token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.NotContains(t, ts.readModel(t, key, modelId).TechnicalAssets, "Broken")
+}
+
+func TestRenameTechnicalAsset(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodPut, "/models/"+modelId+"/technical-assets/database", fmt.Sprintf(testDatabasePayload, "Main Database", "main-database"), "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, map[string]any{"message": "technical asset updated", "id": "main-database", "id_changed": true}, decodeTestResponse(t, response))
+
+	modelInput := ts.readModel(t, key, modelId)
+	assert.NotContains(t, modelInput.TechnicalAssets, "Database")
+	assert.Equal(t, "main-database", modelInput.TechnicalAssets["Main Database"].ID)
+	assert.Equal(t, "main-database", modelInput.TechnicalAssets["Web Server"].CommunicationLinks["Database Traffic"].Target)
+	assert.Equal(t, []string{"web-server", "main-database"}, modelInput.TrustBoundaries["Network"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"web-server", "main-database"}, modelInput.SharedRuntimes["Cluster"].TechnicalAssetsRunning)
+
+	// the risks of the asset stay tracked
+	assert.ElementsMatch(t, []string{
+		"unencrypted-asset@main-database",
+		"missing-hardening@main-database",
+		"unencrypted-communication@web-server>database-traffic@web-server@main-database",
+		"missing-vault@*",
+	}, riskTrackingKeys(modelInput))
+	assert.Equal(t, "internal only", modelInput.RiskTracking["unencrypted-asset@main-database"].Justification)
+
+	// renaming the source of a link renames the link id as well
+	response = ts.request(http.MethodPut, "/models/"+modelId+"/technical-assets/web-server",
+		`{"title": "Web Server", "id": "frontend", "type": "process", "usage": "business", "size": "application", "technology": "web-server", "machine": "container", "internet": true, "encryption": "none", "confidentiality": "internal", "integrity": "important", "availability": "important", "data_assets_processed": ["customer-data"]}`,
+		"token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Contains(t, riskTrackingKeys(ts.readModel(t, key, modelId)), "unencrypted-communication@frontend>database-traffic@frontend@main-database")
+
+	response = ts.request(http.MethodPut, "/models/"+modelId+"/technical-assets/unknown", fmt.Sprintf(testDatabasePayload, "Unknown", "unknown"), "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestDeleteTechnicalAsset(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodDelete, "/models/"+modelId+"/technical-assets/database", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, map[string]any{"message": "technical asset deleted", "id": "database", "references_deleted": true}, decodeTestResponse(t, response))
+
+	modelInput := ts.readModel(t, key, modelId)
+	assert.NotContains(t, modelInput.TechnicalAssets, "Database")
+	assert.Empty(t, modelInput.TechnicalAssets["Web Server"].CommunicationLinks)
+	assert.Equal(t, []string{"web-server"}, modelInput.TrustBoundaries["Network"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"web-server"}, modelInput.SharedRuntimes["Cluster"].TechnicalAssetsRunning)
+	assert.Equal(t, []string{"missing-vault@*"}, riskTrackingKeys(modelInput))
+
+	response = ts.request(http.MethodDelete, "/models/"+modelId+"/technical-assets/database", "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const tokensFilename = "tokens.json"
+
+// persistedToken is a token as kept across server restarts, which is as safe as keeping it in memory only: neither the
+// hash of the token nor the random value it is xor-ed with reveal the token or its key
+type persistedToken struct {
+	Hash     string   `json:"hash"`
+	XorRand  []byte   `json:"xor_rand"`
+	KeyId    string   `json:"key_id"`
+	Created  int64    `json:"created"`
+	ReadOnly bool     `json:"read_only,omitempty"`
+	ModelIds []string `json:"model_ids,omitempty"`
+}
+
+func (s *server) tokensFile() string {
+	return filepath.Join(s.config.GetServerFolder(), tokensFilename)
+}
+
+// persistTokens writes the tokens (if they are to be kept across restarts), the caller holds the global lock
+func (s *server) persistTokens() {
+	if !s.config.GetServerPersistTokens() {
+		return
+	}
+	tokens := make([]persistedToken, 0, len(s.mapTokenHashToTimeoutStruct))
+	for tokenHash, val := range s.mapTokenHashToTimeoutStruct {
+		tokens = append(tokens, persistedToken{
+			Hash:     tokenHash,
+			XorRand:  val.xorRand,
+			KeyId:    keyIdOfFolder(val.folderName),
+			Created:  val.createdNanoTime,
+			ReadOnly: val.readOnly,
+			ModelIds: val.modelIds,
+		})
+	}
+	data, err := json.Marshal(tokens)
+	if err == nil {
+		// written to a temp file first to never leave a partially written file
+		err = os.WriteFile(s.tokensFile()+".tmp", data, 0600)
+	}
+	if err == nil {
+		err = os.Rename(s.tokensFile()+".tmp", s.tokensFile())
+	}
+	if err != nil {
+		log.Println("unable to persist tokens: " + err.Error())
+	}
+}
+
+// loadTokens restores the tokens persisted by a previous run of the server, where the restart counts as access
+func (s *server) loadTokens() error {
+	if !s.config.GetServerPersistTokens() {
+		return nil
+	}
+	data, err := os.ReadFile(s.tokensFile())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read tokens: %w", err)
+	}
+	var tokens []persistedToken
+	err = json.Unmarshal(data, &tokens)
+	if err != nil {
+		return fmt.Errorf("unable to parse tokens %v: %w", s.tokensFile(), err)
+	}
+
+	s.globalLock.Lock()
+	defer s.globalLock.Unlock()
+	now := time.Now().UnixNano()
+	for _, token := range tokens {
+		if len(token.XorRand) != keySize || token.KeyId != filepath.Base(filepath.Clean(token.KeyId)) {
+			continue
+		}
+		folderName := filepath.Join(s.config.GetServerFolder(), s.config.GetKeyFolder(), token.KeyId)
+		if _, err := os.Stat(folderName); err != nil {
+			continue // the key has been deleted in the meantime
+		}
+		val := &timeoutStruct{
+			xorRand:              token.XorRand,
+			createdNanoTime:      token.Created,
+			lastAccessedNanoTime: now,
+			folderName:           folderName,
+			readOnly:             token.ReadOnly,
+			modelIds:             token.ModelIds,
+		}
+		s.mapTokenHashToTimeoutStruct[token.Hash] = val
+		if !val.scoped() {
+			s.mapFolderNameToTokenHash[folderName] = token.Hash
+		}
+	}
+	s.housekeepingTokenMaps() // to remove the ones timed out while the server was down
+	s.persistTokens()
+	return nil
+}
@@ -12,10 +12,12 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 const keySize = 32
@@ -27,6 +29,33 @@ type keyHeader struct {
 type timeoutStruct struct {
 	xorRand                               []byte
 	createdNanoTime, lastAccessedNanoTime int64
+	folderName                            string   // of the key the token belongs to
+	readOnly                              bool     // the token can't change models
+	modelIds                              []string // the token is restricted to these models (unless empty)
+}
+
+// scoped tokens are restricted in some way, unlike the one full-access token of a key
+func (what *timeoutStruct) scoped() bool {
+	return what.readOnly || len(what.modelIds) > 0
+}
+
+func (what *timeoutStruct) allowsModel(modelId string) bool {
+	return len(what.modelIds) == 0 || slices.Contains(what.modelIds, modelId)
+}
+
+// payloadToken restricts the token to be created, which by default has full access to all models of the key
+type payloadToken struct {
+	ReadOnly bool     `json:"read_only"`
+	ModelIds []string `json:"model_ids"`
+}
+
+// tokenContextKey is the key of the token of the request within the gin context
+const tokenContextKey = "token"
+
+// readingRoutes are the routes a read-only token may use despite their method, as they don't change any model
+var readingRoutes = map[string]bool{
+	"POST /models/:model-id/jobs": true,
+	"DELETE /jobs/:job-id":        true,
 }
 
 func (s *server) createKey(ginContext *gin.Context) {
@@ -54,6 +83,7 @@ func (s *server) createKey(ginContext *gin.Context) {
 		})
 		return
 	}
+	s.keyCount.Add(1)
 	ginContext.JSON(http.StatusCreated, gin.H{
 		"key": base64.RawURLEncoding.EncodeToString(keyBytesArr[:]),
 	})
@@ -96,6 +126,7 @@ func (s *server) checkObjectCreationThrottler(ginContext *gin.Context, typeName
 		s.createdObjectsThrottler[keyHash] = append(s.createdObjectsThrottler[keyHash], now)
 		return true
 	}
+	s.metrics.throttlerRejected(typeName)
 	ginContext.JSON(http.StatusTooManyRequests, gin.H{
 		"error": "object creation throttling exceeded (denial-of-service protection): please wait some time and try again",
 	})
@@ -109,7 +140,19 @@ func (s *server) deleteKey(ginContext *gin.Context) {
 	}
 	s.globalLock.Lock()
 	defer s.globalLock.Unlock()
-	err := os.RemoveAll(folderName)
+	models, err := s.models.List(keyIdOfFolder(folderName))
+	if err == nil {
+		err = s.models.DeleteKey(keyIdOfFolder(folderName))
+	}
+	if err != nil {
+		log.Println("error during key delete: " + err.Error())
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to delete key",
+		})
+		return
+	}
+	s.modelCount.Add(-int64(len(models)))
+	err = os.RemoveAll(folderName)
 	if err != nil {
 		log.Println("error during key delete: " + err.Error())
 		ginContext.JSON(http.StatusNotFound, gin.H{
@@ -117,21 +160,137 @@ func (s *server) deleteKey(ginContext *gin.Context) {
 		})
 		return
 	}
+	s.keyCount.Add(-1)
+	s.deleteTokensOfFolder(folderName)
 	ginContext.JSON(http.StatusOK, gin.H{
 		"message": "key deleted",
 	})
 }
 
+// rotateKey re-encrypts all models of the key (including their history) under a new key, which replaces the old one
+func (s *server) rotateKey(ginContext *gin.Context) {
+	folderName, key, ok := s.checkKeyToFolderName(ginContext)
+	if !ok {
+		return
+	}
+	// the tokens are invalidated first, as requests waiting for the folder must not find the key afterward
+	s.globalLock.Lock()
+	s.deleteTokensOfFolder(folderName)
+	s.globalLock.Unlock()
+	s.lockFolder(folderName)
+	defer s.unlockFolder(folderName)
+
+	newKey := make([]byte, keySize)
+	n, err := rand.Read(newKey)
+	if n != keySize || err != nil {
+		log.Println(err)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to create key",
+		})
+		return
+	}
+	newFolderName := s.folderNameFromKey(newKey)
+	err = os.MkdirAll(newFolderName, 0700)
+	if err == nil {
+		err = s.reencryptModels(keyIdOfFolder(folderName), key, keyIdOfFolder(newFolderName), newKey)
+	}
+	if err != nil {
+		log.Println("error during key rotation: " + err.Error())
+		_ = s.models.DeleteKey(keyIdOfFolder(newFolderName))
+		_ = os.RemoveAll(newFolderName)
+		ginContext.JSON(http.StatusInternalServerError, gin.H{
+			"error": "unable to rotate key",
+		})
+		return
+	}
+
+	err = s.models.DeleteKey(keyIdOfFolder(folderName))
+	if err == nil {
+		err = os.RemoveAll(folderName)
+	}
+	if err != nil {
+		// the models are available under the new key nevertheless
+		log.Println("error during key rotation: unable to delete old key: " + err.Error())
+	}
+	ginContext.JSON(http.StatusOK, gin.H{
+		"message": "key rotated",
+		"key":     base64.RawURLEncoding.EncodeToString(newKey),
+	})
+}
+
+// reencryptModels copies all models of the key along with their history to the new key
+func (s *server) reencryptModels(keyId string, key []byte, newKeyId string, newKey []byte) error {
+	reencrypt := func(data []byte) ([]byte, error) {
+		yamlBytes, err := s.decryptModel(key, data)
+		if err != nil {
+			return nil, err
+		}
+		return s.encryptModel(newKey, yamlBytes)
+	}
+
+	models, err := s.models.List(keyId)
+	if err != nil {
+		return err
+	}
+	for _, storedModel := range models {
+		data, err := s.models.Read(keyId, storedModel.Id)
+		if err == nil {
+			data, err = reencrypt(data)
+		}
+		if err == nil {
+			err = s.models.Create(newKeyId, storedModel, data)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to re-encrypt model %v: %w", storedModel.Id, err)
+		}
+		entries, err := s.models.History(keyId, storedModel.Id)
+		if err != nil {
+			return fmt.Errorf("unable to list history of model %v: %w", storedModel.Id, err)
+		}
+		for _, entry := range entries {
+			data, err = s.models.ReadHistory(keyId, storedModel.Id, entry.Id)
+			if err == nil {
+				data, err = reencrypt(data)
+			}
+			if err == nil {
+				err = s.models.WriteHistory(newKeyId, storedModel.Id, entry, data)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to re-encrypt history entry %v of model %v: %w", entry.Id, storedModel.Id, err)
+			}
+		}
+	}
+	return nil
+}
+
 func (s *server) createToken(ginContext *gin.Context) {
 	folderName, key, ok := s.checkKeyToFolderName(ginContext)
 	if !ok {
 		return
 	}
+	payload := payloadToken{}
+	if ginContext.Request.ContentLength != 0 {
+		err := ginContext.ShouldBindJSON(&payload)
+		if err != nil {
+			handleErrorInServiceCall(err, ginContext)
+			return
+		}
+	}
+	modelIds := make([]string, 0, len(payload.ModelIds))
+	for _, modelId := range payload.ModelIds {
+		parsedId, err := uuid.Parse(strings.TrimSpace(modelId))
+		if err != nil {
+			handleErrorInServiceCall(fmt.Errorf("invalid model id %q: %w", modelId, err), ginContext)
+			return
+		}
+		modelIds = append(modelIds, parsedId.String())
+	}
 	s.globalLock.Lock()
 	defer s.globalLock.Unlock()
-	if tokenHash, exists := s.mapFolderNameToTokenHash[folderName]; exists {
-		// invalidate previous token
-		delete(s.mapTokenHashToTimeoutStruct, tokenHash)
+	scope := timeoutStruct{folderName: folderName, readOnly: payload.ReadOnly, modelIds: modelIds}
+	if tokenHash, exists := s.mapFolderNameToTokenHash[folderName]; exists && !scope.scoped() {
+		// invalidate previous token (scoped ones are additional tokens)
+		s.deleteTokenHashFromMaps(tokenHash)
 	}
 	// create a strong random 256 bit value (used to xor)
 	xorBytesArr := make([]byte, keySize)
@@ -147,14 +306,17 @@ func (s *server) createToken(ginContext *gin.Context) {
 	token := xor(key, xorBytesArr)
 	tokenHash := hashSHA256(token)
 	s.housekeepingTokenMaps()
-	s.mapTokenHashToTimeoutStruct[tokenHash] = timeoutStruct{
-		xorRand:              xorBytesArr,
-		createdNanoTime:      now,
-		lastAccessedNanoTime: now,
+	scope.xorRand = xorBytesArr
+	scope.createdNanoTime, scope.lastAccessedNanoTime = now, now
+	s.mapTokenHashToTimeoutStruct[tokenHash] = &scope
+	if !scope.scoped() {
+		s.mapFolderNameToTokenHash[folderName] = tokenHash
 	}
-	s.mapFolderNameToTokenHash[folderName] = tokenHash
+	s.persistTokens()
 	ginContext.JSON(http.StatusCreated, gin.H{
-		"token": base64.RawURLEncoding.EncodeToString(token[:]),
+		"token":     base64.RawURLEncoding.EncodeToString(token[:]),
+		"read_only": scope.readOnly,
+		"model_ids": scope.modelIds,
 	})
 }
 
@@ -183,6 +345,7 @@ func (s *server) deleteToken(ginContext *gin.Context) {
 	s.globalLock.Lock()
 	defer s.globalLock.Unlock()
 	s.deleteTokenHashFromMaps(hashSHA256(token))
+	s.persistTokens()
 	ginContext.JSON(http.StatusOK, gin.H{
 		"message": "token deleted",
 	})
@@ -253,6 +416,10 @@ func (s *server) checkTokenToFolderName(ginContext *gin.Context) (folderNameOfKe
 			return folderNameOfKey, key, false
 		}
 		timeoutStruct.lastAccessedNanoTime = time.Now().UnixNano()
+		if !checkTokenScope(ginContext, timeoutStruct) {
+			return folderNameOfKey, key, false
+		}
+		ginContext.Set(tokenContextKey, timeoutStruct)
 		return folderNameOfKey, key, true
 	} else {
 		ginContext.JSON(http.StatusNotFound, gin.H{
@@ -262,13 +429,60 @@ func (s *server) checkTokenToFolderName(ginContext *gin.Context) (folderNameOfKe
 	}
 }
 
+// checkTokenScope fails the request with 403 when the token is read-only and the request would change a model, or when
+// the token is restricted to models not including the one requested
+func checkTokenScope(ginContext *gin.Context, token *timeoutStruct) bool {
+	method := ginContext.Request.Method
+	route := method + " " + ginContext.FullPath()
+	if token.readOnly && method != http.MethodGet && method != http.MethodHead && !readingRoutes[route] {
+		ginContext.JSON(http.StatusForbidden, gin.H{
+			"error": "token is read-only",
+		})
+		return false
+	}
+	if len(token.modelIds) == 0 {
+		return true
+	}
+	allowed := false
+	if modelId := ginContext.Param("model-id"); len(modelId) > 0 {
+		parsedId, err := uuid.Parse(modelId)
+		allowed = err == nil && token.allowsModel(parsedId.String())
+	} else {
+		// these filter by the models of the token themselves (see tokenAllowsModel)
+		allowed = route == "GET /models" || strings.HasPrefix(ginContext.FullPath(), "/jobs/")
+	}
+	if !allowed {
+		ginContext.JSON(http.StatusForbidden, gin.H{
+			"error": "token is not valid for this model",
+		})
+	}
+	return allowed
+}
+
+// tokenAllowsModel tells whether the token of the request (see checkTokenToFolderName) has access to the model
+func tokenAllowsModel(ginContext *gin.Context, modelId string) bool {
+	token, exists := ginContext.Get(tokenContextKey)
+	return !exists || token.(*timeoutStruct).allowsModel(modelId)
+}
+
 func (s *server) folderNameFromKey(key []byte) string {
 	sha512Hash := hashSHA256(key)
 	return filepath.Join(s.config.GetServerFolder(), s.config.GetKeyFolder(), sha512Hash)
 }
 
+// keyIdOfFolder identifies the key in the model store
+func keyIdOfFolder(folderNameOfKey string) string {
+	return filepath.Base(folderNameOfKey)
+}
+
 func (s *server) housekeepingTokenMaps() {
 	now := time.Now().UnixNano()
+	count := len(s.mapTokenHashToTimeoutStruct)
+	defer func() {
+		if len(s.mapTokenHashToTimeoutStruct) != count {
+			s.persistTokens()
+		}
+	}()
 	for tokenHash, val := range s.mapTokenHashToTimeoutStruct {
 		if s.extremeShortTimeoutsForTesting {
 			// remove all elements older than 1 minute (= 60000000000 ns) soft
@@ -287,6 +501,16 @@ func (s *server) housekeepingTokenMaps() {
 	}
 }
 
+// deleteTokensOfFolder invalidates all tokens of the key
+func (s *server) deleteTokensOfFolder(folderName string) {
+	for tokenHash, val := range s.mapTokenHashToTimeoutStruct {
+		if val.folderName == folderName {
+			s.deleteTokenHashFromMaps(tokenHash)
+		}
+	}
+	s.persistTokens()
+}
+
 func (s *server) deleteTokenHashFromMaps(tokenHash string) {
 	delete(s.mapTokenHashToTimeoutStruct, tokenHash)
 	for folderName, check := range s.mapFolderNameToTokenHash {
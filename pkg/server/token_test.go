@@ -0,0 +1,269 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/threagile/threagile/pkg/report"
+	"github.com/threagile/threagile/pkg/risks"
+)
+
+func TestReadOnlyToken(t *testing.T) {
+	ts := newTestServer(t)
+	ts.jobCommands = &report.GenerateCommands{RisksJSON: true}
+	key := ts.createKey(t)
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodPost, "/auth/tokens", `{"read_only": true}`, "key", key)
+	assert.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	result := decodeTestResponse(t, response)
+	assert.Equal(t, true, result["read_only"])
+	assert.Equal(t, []any{}, result["model_ids"])
+	token := result["token"].(string)
+
+	response = ts.request(http.MethodGet, "/models/"+modelId, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = ts.request(http.MethodGet, "/models", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	for _, request := range [][3]string{
+		{http.MethodPost, "/models", ""},
+		{http.MethodPut, "/models/" + modelId, testModelYaml},
+		{http.MethodPatch, "/models/" + modelId, `[{"op": "replace", "path": "/title", "value": "Changed"}]`},
+		{http.MethodDelete, "/models/" + modelId, ""},
+		{http.MethodPost, "/models/" + modelId + "/technical-assets", fmt.Sprintf(testDatabasePayload, "Cache", "cache")},
+		{http.MethodDelete, "/models/" + modelId + "/technical-assets/database", ""},
+	} {
+		response = ts.request(request[0], request[1], request[2], "token", token)
+		assert.Equal(t, http.StatusForbidden, response.Code, request[0]+" "+request[1])
+		assert.Equal(t, map[string]any{"error": "token is read-only"}, decodeTestResponse(t, response))
+	}
+	assert.Equal(t, "Test Model", ts.readModel(t, key, modelId).Title)
+	assert.Empty(t, ts.history(t, key, modelId))
+
+	// analysing a model doesn't change it, neither does removing the analysis
+	response = ts.request(http.MethodPost, "/models/"+modelId+"/jobs", "", "token", token)
+	assert.Equal(t, http.StatusAccepted, response.Code, response.Body.String())
+	jobId := decodeTestResponse(t, response)["id"].(string)
+	waitForTestJob(t, ts, jobId, token)
+	response = ts.request(http.MethodDelete, "/jobs/"+jobId, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	// the read-only token is an additional one
+	fullToken := ts.createToken(t, key, "")
+	response = ts.request(http.MethodGet, "/models/"+modelId, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = ts.request(http.MethodPatch, "/models/"+modelId, `[{"op": "replace", "path": "/title", "value": "Changed"}]`, "token", fullToken)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+}
+
+func TestModelScopedToken(t *testing.T) {
+	ts := newTestServer(t)
+	ts.jobCommands = &report.GenerateCommands{RisksJSON: true}
+	key := ts.createKey(t)
+	fullToken := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+	otherModelId := ts.storeModel(t, key, testModelYaml)
+
+	response := ts.request(http.MethodPost, "/auth/tokens", `{"model_ids": [" `+modelId+` "]}`, "key", key)
+	assert.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	result := decodeTestResponse(t, response)
+	assert.Equal(t, false, result["read_only"])
+	assert.Equal(t, []any{modelId}, result["model_ids"])
+	token := result["token"].(string)
+
+	response = ts.request(http.MethodGet, "/models/"+modelId, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = ts.request(http.MethodPatch, "/models/"+modelId, `[{"op": "replace", "path": "/title", "value": "Changed"}]`, "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	for _, request := range [][2]string{
+		{http.MethodGet, "/models/" + otherModelId},
+		{http.MethodGet, "/models/" + otherModelId + "/history"},
+		{http.MethodDelete, "/models/" + otherModelId},
+		{http.MethodPost, "/models/" + otherModelId + "/jobs"},
+		{http.MethodGet, "/models/not-a-uuid"},
+		{http.MethodPost, "/models"},
+	} {
+		response = ts.request(request[0], request[1], "", "token", token)
+		assert.Equal(t, http.StatusForbidden, response.Code, request[0]+" "+request[1])
+		assert.Equal(t, map[string]any{"error": "token is not valid for this model"}, decodeTestResponse(t, response))
+	}
+	assert.Equal(t, "Test Model", ts.readModel(t, key, otherModelId).Title)
+
+	// the list of models only contains the models of the token
+	response = ts.request(http.MethodGet, "/models", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var models []payloadModels
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &models))
+	if assert.Len(t, models, 1) {
+		assert.Equal(t, modelId, models[0].ID)
+		assert.Equal(t, "Changed", models[0].Title)
+	}
+	response = ts.request(http.MethodGet, "/models", "", "token", fullToken)
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &models))
+	assert.Len(t, models, 2)
+
+	// as do the jobs, where a job of another model is not found at all
+	response = ts.request(http.MethodPost, "/models/"+otherModelId+"/jobs", "", "token", fullToken)
+	assert.Equal(t, http.StatusAccepted, response.Code, response.Body.String())
+	otherJobId := decodeTestResponse(t, response)["id"].(string)
+	response = ts.request(http.MethodPost, "/models/"+modelId+"/jobs", "", "token", token)
+	assert.Equal(t, http.StatusAccepted, response.Code, response.Body.String())
+	jobId := decodeTestResponse(t, response)["id"].(string)
+	waitForTestJob(t, ts, otherJobId, fullToken)
+	waitForTestJob(t, ts, jobId, token)
+
+	for _, path := range []string{"/jobs/" + otherJobId, "/jobs/" + otherJobId + "/events", "/jobs/" + otherJobId + "/artifacts/risks.json"} {
+		response = ts.request(http.MethodGet, path, "", "token", token)
+		assert.Equal(t, http.StatusNotFound, response.Code, path)
+		assert.Equal(t, map[string]any{"error": "job not found"}, decodeTestResponse(t, response))
+	}
+	response = ts.request(http.MethodDelete, "/jobs/"+otherJobId, "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = ts.request(http.MethodGet, "/jobs/"+otherJobId, "", "token", fullToken)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = ts.request(http.MethodGet, "/jobs/"+jobId, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// the model ids are validated when creating the token
+	response = ts.request(http.MethodPost, "/auth/tokens", `{"model_ids": ["not-a-uuid"]}`, "key", key)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestRotateKey(t *testing.T) {
+	ts := newTestServer(t, func(config *testServerConfig) {
+		config.persistTokens = true
+	})
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	readOnlyToken := ts.createToken(t, key, `{"read_only": true}`)
+	modelId := ts.storeModel(t, key, testModelYaml)
+	response := ts.request(http.MethodDelete, "/models/"+modelId+"/technical-assets/database", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	entries := ts.history(t, key, modelId)
+	if !assert.Len(t, entries, 1) {
+		return
+	}
+
+	response = ts.request(http.MethodPost, "/auth/keys/rotate", "", "key", key)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	result := decodeTestResponse(t, response)
+	assert.Equal(t, "key rotated", result["message"])
+	newKey := result["key"].(string)
+	assert.NotEqual(t, key, newKey)
+
+	// the old key and its tokens are gone
+	for _, oldToken := range []string{token, readOnlyToken} {
+		response = ts.request(http.MethodGet, "/models/"+modelId, "", "token", oldToken)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	}
+	response = ts.request(http.MethodPost, "/auth/tokens", "", "key", key)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	keyBytes, err := base64.RawURLEncoding.DecodeString(key)
+	assert.NoError(t, err)
+	assert.NoDirExists(t, ts.folderNameFromKey(keyBytes))
+	assert.Empty(t, ts.mapTokenHashToTimeoutStruct)
+	persisted, err := os.ReadFile(ts.tokensFile())
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(persisted))
+
+	// while the model and its history are readable with the new key
+	newToken := ts.createToken(t, newKey, "")
+	assert.NotContains(t, ts.readModel(t, newKey, modelId).TechnicalAssets, "Database")
+	if newEntries := ts.history(t, newKey, modelId); assert.Len(t, newEntries, 1) {
+		assert.Equal(t, entries[0], newEntries[0])
+	}
+	response = ts.request(http.MethodGet, historyPath(modelId, entries[0].Id), "", "token", newToken)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, testModelYaml, response.Body.String())
+	response = ts.request(http.MethodGet, "/models", "", "token", newToken)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), modelId)
+}
+
+func TestLoadTokens(t *testing.T) {
+	ts := newTestServer(t, func(config *testServerConfig) {
+		config.persistTokens = true
+	})
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	readOnlyToken := ts.createToken(t, key, `{"read_only": true}`)
+	expiredToken := ts.createToken(t, key, `{"read_only": true}`)
+	deletedKey := ts.createKey(t)
+	tokenOfDeletedKey := ts.createToken(t, deletedKey, "")
+	modelId := ts.storeModel(t, key, testModelYaml)
+
+	// while the server is down, a key is deleted and a token times out
+	deletedKeyBytes, err := base64.RawURLEncoding.DecodeString(deletedKey)
+	assert.NoError(t, err)
+	assert.NoError(t, os.RemoveAll(ts.folderNameFromKey(deletedKeyBytes)))
+	data, err := os.ReadFile(ts.tokensFile())
+	assert.NoError(t, err)
+	var tokens []persistedToken
+	assert.NoError(t, json.Unmarshal(data, &tokens))
+	assert.Len(t, tokens, 4)
+	for i := range tokens {
+		if tokens[i].Hash == testTokenHash(t, expiredToken) {
+			tokens[i].Created = time.Now().Add(-11 * time.Hour).UnixNano()
+		}
+	}
+	tokens = append(tokens, persistedToken{Hash: "invalid", XorRand: []byte("too short"), KeyId: tokens[0].KeyId})
+	data, err = json.Marshal(tokens)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(ts.tokensFile(), data, 0600))
+
+	s, err := newServer(ts.config, risks.GetBuiltInRiskRules())
+	if !assert.NoError(t, err) {
+		return
+	}
+	t.Cleanup(func() { _ = s.models.Close() })
+	restarted := &testServer{server: s, router: s.newRouter(gin.New())}
+
+	assert.Len(t, restarted.mapTokenHashToTimeoutStruct, 2)
+	assert.Len(t, restarted.mapFolderNameToTokenHash, 1)
+	data, err = os.ReadFile(restarted.tokensFile())
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &tokens))
+	assert.Len(t, tokens, 2)
+
+	// the remaining tokens keep their scope
+	response := restarted.request(http.MethodGet, "/models/"+modelId, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = restarted.request(http.MethodGet, "/models/"+modelId, "", "token", readOnlyToken)
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = restarted.request(http.MethodDelete, "/models/"+modelId, "", "token", readOnlyToken)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	for _, discarded := range []string{expiredToken, tokenOfDeletedKey} {
+		response = restarted.request(http.MethodGet, "/models", "", "token", discarded)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	}
+
+	// creating the full-access token again replaces the restored one
+	restarted.createToken(t, key, "")
+	response = restarted.request(http.MethodGet, "/models/"+modelId, "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// waitForTestJob waits for the job to be finished, so it doesn't write into folders being removed
+func waitForTestJob(t *testing.T, ts *testServer, jobId string, token string) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		response := ts.request(http.MethodGet, "/jobs/"+jobId, "", "token", token)
+		state := decodeTestResponse(t, response)["state"]
+		return state != string(jobQueued) && state != string(jobRunning)
+	}, 30*time.Second, 20*time.Millisecond)
+}
+
+func testTokenHash(t *testing.T, token string) string {
+	tokenBytes, err := base64.RawURLEncoding.DecodeString(token)
+	assert.NoError(t, err)
+	return hashSHA256(tokenBytes)
+}
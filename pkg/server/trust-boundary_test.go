@@ -0,0 +1,102 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testTrustBoundaryModelYaml tracks a risk naming the trust boundary
+const testTrustBoundaryModelYaml = testModelYaml + `  some-category@web-server@network:
+    status: accepted
+`
+
+const testTrustBoundaryPayload = `{
+	"title": "%v",
+	"id": "%v",
+	"type": "network-cloud-provider",
+	"trust_boundaries_nested": [%v]
+}`
+
+func TestCreateTrustBoundary(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testTrustBoundaryModelYaml)
+	trustBoundaries := "/models/" + modelId + "/trust-boundaries"
+
+	response := ts.request(http.MethodPost, trustBoundaries, fmt.Sprintf(testTrustBoundaryPayload, "Cloud", "cloud", `"network"`), "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "cloud", decodeTestResponse(t, response)["id"])
+	assert.Equal(t, []string{"network"}, ts.readModel(t, key, modelId).TrustBoundaries["Cloud"].TrustBoundariesNested)
+
+	response = ts.request(http.MethodGet, trustBoundaries+"/cloud", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, decodeTestResponse(t, response), "Cloud")
+
+	response = ts.request(http.MethodGet, trustBoundaries, "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Len(t, decodeTestResponse(t, response), 2)
+
+	response = ts.request(http.MethodPost, trustBoundaries, fmt.Sprintf(testTrustBoundaryPayload, "Cloud", "other-cloud", ""), "token", token)
+	assert.Equal(t, http.StatusConflict, response.Code)
+	response = ts.request(http.MethodPost, trustBoundaries, fmt.Sprintf(testTrustBoundaryPayload, "Other Cloud", "cloud", ""), "token", token)
+	assert.Equal(t, http.StatusConflict, response.Code)
+	response = ts.request(http.MethodPost, trustBoundaries, fmt.Sprintf(testTrustBoundaryPayload, "Other Cloud", "other-cloud", `"unknown"`), "token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, "referenced trust boundary does not exist", decodeTestResponse(t, response)["error"])
+}
+
+func TestRenameTrustBoundary(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testTrustBoundaryModelYaml)
+	trustBoundaries := "/models/" + modelId + "/trust-boundaries"
+
+	response := ts.request(http.MethodPost, trustBoundaries, fmt.Sprintf(testTrustBoundaryPayload, "Cloud", "cloud", `"network"`), "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	response = ts.request(http.MethodPut, trustBoundaries+"/network",
+		`{"title": "Private Network", "id": "private-network", "type": "network-cloud-security-group", "technical_assets_inside": ["web-server", "database"]}`, "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, map[string]any{"message": "trust boundary updated", "id": "private-network", "id_changed": true}, decodeTestResponse(t, response))
+
+	modelInput := ts.readModel(t, key, modelId)
+	assert.NotContains(t, modelInput.TrustBoundaries, "Network")
+	assert.Equal(t, []string{"web-server", "database"}, modelInput.TrustBoundaries["Private Network"].TechnicalAssetsInside)
+	assert.Equal(t, []string{"private-network"}, modelInput.TrustBoundaries["Cloud"].TrustBoundariesNested)
+	assert.Contains(t, riskTrackingKeys(modelInput), "some-category@web-server@private-network")
+
+	response = ts.request(http.MethodPut, trustBoundaries+"/cloud", fmt.Sprintf(testTrustBoundaryPayload, "Cloud", "cloud", `"unknown"`), "token", token)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = ts.request(http.MethodPut, trustBoundaries+"/unknown", fmt.Sprintf(testTrustBoundaryPayload, "Unknown", "unknown", ""), "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestDeleteTrustBoundary(t *testing.T) {
+	ts := newTestServer(t)
+	key := ts.createKey(t)
+	token := ts.createToken(t, key, "")
+	modelId := ts.storeModel(t, key, testTrustBoundaryModelYaml)
+	trustBoundaries := "/models/" + modelId + "/trust-boundaries"
+
+	response := ts.request(http.MethodPost, trustBoundaries, fmt.Sprintf(testTrustBoundaryPayload, "Cloud", "cloud", `"network"`), "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	response = ts.request(http.MethodDelete, trustBoundaries+"/network", "", "token", token)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, map[string]any{"message": "trust boundary deleted", "id": "network", "references_deleted": true}, decodeTestResponse(t, response))
+
+	modelInput := ts.readModel(t, key, modelId)
+	assert.NotContains(t, modelInput.TrustBoundaries, "Network")
+	assert.Empty(t, modelInput.TrustBoundaries["Cloud"].TrustBoundariesNested)
+	assert.NotContains(t, riskTrackingKeys(modelInput), "some-category@web-server@network")
+	// the assets stay, just outside any trust boundary
+	assert.Contains(t, modelInput.TechnicalAssets, "Database")
+
+	response = ts.request(http.MethodDelete, trustBoundaries+"/network", "", "token", token)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
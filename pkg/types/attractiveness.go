@@ -0,0 +1,146 @@
+package types
+
+// Attractiveness is the weighting profile of the RAA (relative attacker attractiveness) calculation.
+// Zero values keep the built-in defaults.
+type Attractiveness struct {
+	Quantity              int                    `json:"quantity,omitempty" yaml:"quantity"` // fibonacci sequence base index
+	Confidentiality       AttackerFocus          `json:"confidentiality" yaml:"confidentiality"`
+	Integrity             AttackerFocus          `json:"integrity" yaml:"integrity"`
+	Availability          AttackerFocus          `json:"availability" yaml:"availability"`
+	TechnologyMultipliers []TechnologyMultiplier `json:"technology_multipliers,omitempty" yaml:"technology_multipliers"`
+	DatastoreMultiplier   float64                `json:"datastore_multiplier,omitempty" yaml:"datastore_multiplier"`
+	MultiTenantMultiplier float64                `json:"multi_tenant_multiplier,omitempty" yaml:"multi_tenant_multiplier"`
+}
+
+type AttackerFocus struct {
+	Asset                 int // fibonacci sequence base index
+	ProcessedOrStoredData int // fibonacci sequence base index
+	TransferredData       int // fibonacci sequence base index
+}
+
+// TechnologyMultiplier is applied to the score of technical assets having any of the technology attributes
+type TechnologyMultiplier struct {
+	Attributes []string `json:"attributes,omitempty" yaml:"attributes"`
+	Multiplier float64  `json:"multiplier,omitempty" yaml:"multiplier"`
+}
+
+// RAABreakdown records how the attacker attractiveness of a technical asset was composed
+type RAABreakdown struct {
+	AssetScore                 float64  `json:"asset_score" yaml:"asset_score"`
+	ProcessedOrStoredDataScore float64  `json:"processed_or_stored_data_score" yaml:"processed_or_stored_data_score"`
+	TransferredDataScore       float64  `json:"transferred_data_score" yaml:"transferred_data_score"`
+	TechnologyMultiplier       float64  `json:"technology_multiplier" yaml:"technology_multiplier"`
+	TechnologyMultiplierReason []string `json:"technology_multiplier_reason,omitempty" yaml:"technology_multiplier_reason,omitempty"`
+	MultiTenantMultiplier      float64  `json:"multi_tenant_multiplier" yaml:"multi_tenant_multiplier"`
+	Score                      float64  `json:"score" yaml:"score"`
+	PivotingAdjustment         float64  `json:"pivoting_adjustment" yaml:"pivoting_adjustment"`
+}
+
+func DefaultAttractiveness() Attractiveness {
+	return Attractiveness{
+		Quantity: 1,
+		Confidentiality: AttackerFocus{
+			Asset:                 5,
+			ProcessedOrStoredData: 4,
+			TransferredData:       2,
+		},
+		Integrity: AttackerFocus{
+			Asset:                 4,
+			ProcessedOrStoredData: 3,
+			TransferredData:       2,
+		},
+		Availability: AttackerFocus{
+			Asset:                 4,
+			ProcessedOrStoredData: 3,
+			TransferredData:       2,
+		},
+		// the first matching entry wins
+		TechnologyMultipliers: []TechnologyMultiplier{
+			{Attributes: []string{LoadBalancer, ReverseProxy}, Multiplier: 1 / 5.5},
+			{Attributes: []string{Monitoring}, Multiplier: 1 / 5.0},
+			{Attributes: []string{ContainerPlatform}, Multiplier: 5},
+			{Attributes: []string{Vault}, Multiplier: 2},
+			{Attributes: []string{BuildPipeline, SourcecodeRepository, ArtifactRegistry}, Multiplier: 2},
+			{Attributes: []string{IdentityProvider, IdentityStoreDatabase, IdentityStoreLDAP}, Multiplier: 2.5},
+		},
+		DatastoreMultiplier:   2,
+		MultiTenantMultiplier: 1.5,
+	}
+}
+
+// Merge returns a copy of the profile with all non-zero values of other applied;
+// technology multipliers of other are checked before the existing ones
+func (what Attractiveness) Merge(other Attractiveness) Attractiveness {
+	result := what
+	if other.Quantity != 0 {
+		result.Quantity = other.Quantity
+	}
+
+	result.Confidentiality = what.Confidentiality.merge(other.Confidentiality)
+	result.Integrity = what.Integrity.merge(other.Integrity)
+	result.Availability = what.Availability.merge(other.Availability)
+
+	result.TechnologyMultipliers = make([]TechnologyMultiplier, 0, len(other.TechnologyMultipliers)+len(what.TechnologyMultipliers))
+	result.TechnologyMultipliers = append(result.TechnologyMultipliers, other.TechnologyMultipliers...)
+	result.TechnologyMultipliers = append(result.TechnologyMultipliers, what.TechnologyMultipliers...)
+
+	if other.DatastoreMultiplier != 0 {
+		result.DatastoreMultiplier = other.DatastoreMultiplier
+	}
+
+	if other.MultiTenantMultiplier != 0 {
+		result.MultiTenantMultiplier = other.MultiTenantMultiplier
+	}
+
+	return result
+}
+
+func (what AttackerFocus) merge(other AttackerFocus) AttackerFocus {
+	result := what
+	if other.Asset != 0 {
+		result.Asset = other.Asset
+	}
+
+	if other.ProcessedOrStoredData != 0 {
+		result.ProcessedOrStoredData = other.ProcessedOrStoredData
+	}
+
+	if other.TransferredData != 0 {
+		result.TransferredData = other.TransferredData
+	}
+
+	return result
+}
+
+func (what Attractiveness) QuantityFactor(quantity Quantity) float64 {
+	return Fibonacci(what.Quantity + int(quantity))
+}
+
+// TechnologyMultiplierFor returns the multiplier for the technical asset and the attributes (or type) causing it
+func (what Attractiveness) TechnologyMultiplierFor(techAsset *TechnicalAsset) (float64, []string) {
+	for _, candidate := range what.TechnologyMultipliers {
+		if len(candidate.Attributes) == 0 {
+			continue
+		}
+
+		if techAsset.Technologies.GetAttribute(candidate.Attributes[0], candidate.Attributes[1:]...) {
+			return candidate.Multiplier, candidate.Attributes
+		}
+	}
+
+	if techAsset.Type == Datastore && what.DatastoreMultiplier != 0 {
+		return what.DatastoreMultiplier, []string{Datastore.String()}
+	}
+
+	return 1, nil
+}
+
+// Fibonacci returns the n-th value of the sequence 1, 2, 3, 5, 8, 13, ... (starting at n = 1)
+func Fibonacci(n int) float64 {
+	previous, current := 1.0, 1.0
+	for i := 0; i < n; i++ {
+		previous, current = current, previous+current
+	}
+
+	return previous
+}
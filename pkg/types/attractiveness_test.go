@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFibonacci(t *testing.T) {
+	expected := []float64{1, 1, 2, 3, 5, 8, 13, 21, 34}
+	for n, value := range expected {
+		assert.Equal(t, value, Fibonacci(n), "n = %d", n)
+	}
+}
+
+func TestAttractivenessMerge(t *testing.T) {
+	defaults := DefaultAttractiveness()
+	merged := defaults.Merge(Attractiveness{
+		Confidentiality:       AttackerFocus{Asset: 7},
+		TechnologyMultipliers: []TechnologyMultiplier{{Attributes: []string{Vault}, Multiplier: 10}},
+		MultiTenantMultiplier: 3,
+	})
+
+	assert.Equal(t, 7, merged.Confidentiality.Asset)
+	assert.Equal(t, defaults.Confidentiality.ProcessedOrStoredData, merged.Confidentiality.ProcessedOrStoredData)
+	assert.Equal(t, defaults.Integrity, merged.Integrity)
+	assert.Equal(t, defaults.Quantity, merged.Quantity)
+	assert.Equal(t, defaults.DatastoreMultiplier, merged.DatastoreMultiplier)
+	assert.Equal(t, 3.0, merged.MultiTenantMultiplier)
+	assert.Equal(t, len(defaults.TechnologyMultipliers)+1, len(merged.TechnologyMultipliers))
+	assert.Equal(t, 10.0, merged.TechnologyMultipliers[0].Multiplier)
+}
+
+func TestAttractivenessTechnologyMultiplierFor(t *testing.T) {
+	profile := DefaultAttractiveness()
+
+	datastore := &TechnicalAsset{Type: Datastore}
+	multiplier, reason := profile.TechnologyMultiplierFor(datastore)
+	assert.Equal(t, 2.0, multiplier)
+	assert.Equal(t, []string{Datastore.String()}, reason)
+
+	process := &TechnicalAsset{Type: Process}
+	multiplier, reason = profile.TechnologyMultiplierFor(process)
+	assert.Equal(t, 1.0, multiplier)
+	assert.Nil(t, reason)
+}
@@ -39,7 +39,6 @@ func ParseAuthorization(value string) (authorization Authorization, err error) {
 }
 
 func (what Authorization) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return AuthorizationTypeDescription[what].Name
 }
 
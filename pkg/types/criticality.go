@@ -41,7 +41,6 @@ var CriticalityTypeDescription = [...]TypeDescription{
 }
 
 func (what Criticality) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return CriticalityTypeDescription[what].Name
 }
 
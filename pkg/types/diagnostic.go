@@ -0,0 +1,114 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type DiagnosticSeverity string
+
+const (
+	DiagnosticError   DiagnosticSeverity = "error"
+	DiagnosticWarning DiagnosticSeverity = "warning"
+)
+
+// element kinds of diagnostics, named like the model file sections the elements are defined in
+const (
+	ModelElement              = "model"
+	DataAssetElement          = "data_asset"
+	TechnicalAssetElement     = "technical_asset"
+	CommunicationLinkElement  = "communication_link"
+	TrustBoundaryElement      = "trust_boundary"
+	SharedRuntimeElement      = "shared_runtime"
+	CustomRiskCategoryElement = "custom_risk_category"
+	IndividualRiskElement     = "individual_risk"
+	RiskTrackingElement       = "risk_tracking"
+)
+
+// Diagnostic is a problem of a single model element found while parsing the model
+type Diagnostic struct {
+	Severity       DiagnosticSeverity `json:"severity" yaml:"severity"`
+	ElementKind    string             `json:"element_kind" yaml:"element_kind"`
+	ElementId      string             `json:"element_id,omitempty" yaml:"element_id,omitempty"`
+	Field          string             `json:"field,omitempty" yaml:"field,omitempty"`
+	Message        string             `json:"message" yaml:"message"`
+	SourcePosition *SourcePosition    `json:"source_position,omitempty" yaml:"source_position,omitempty"`
+}
+
+func (what *Diagnostic) Error() string {
+	if what.SourcePosition == nil {
+		return what.Message
+	}
+
+	return what.SourcePosition.String() + ": " + what.Message
+}
+
+// Unwrap makes the position of the diagnostic available as SourceError
+func (what *Diagnostic) Unwrap() error {
+	if what.SourcePosition == nil {
+		return nil
+	}
+
+	return &SourceError{Position: *what.SourcePosition, Err: fmt.Errorf("%v", what.Message)}
+}
+
+// Diagnostics are all problems found in a model, which is an error as long as any of them is one
+type Diagnostics []*Diagnostic
+
+func (what Diagnostics) Error() string {
+	lines := make([]string, len(what))
+	for i, diagnostic := range what {
+		lines[i] = diagnostic.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (what Diagnostics) Unwrap() []error {
+	errs := make([]error, len(what))
+	for i, diagnostic := range what {
+		errs[i] = diagnostic
+	}
+
+	return errs
+}
+
+func (what Diagnostics) Count(severity DiagnosticSeverity) int {
+	count := 0
+	for _, diagnostic := range what {
+		if diagnostic.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Sort orders the diagnostics by their position in the model, then by element and message
+func (what Diagnostics) Sort() {
+	sort.SliceStable(what, func(i, j int) bool {
+		left, right := what[i].SourcePosition, what[j].SourcePosition
+		if left != nil && right != nil {
+			if left.File != right.File {
+				return left.File < right.File
+			}
+
+			if left.Line != right.Line {
+				return left.Line < right.Line
+			}
+		} else if left != nil || right != nil {
+			return left != nil
+		}
+
+		if what[i].ElementKind != what[j].ElementKind {
+			return what[i].ElementKind < what[j].ElementKind
+		}
+
+		if what[i].ElementId != what[j].ElementId {
+			return what[i].ElementId < what[j].ElementId
+		}
+
+		return what[i].Message < what[j].Message
+	})
+}
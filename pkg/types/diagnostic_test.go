@@ -0,0 +1,45 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnosticsError(t *testing.T) {
+	diagnostics := Diagnostics{
+		{Severity: DiagnosticError, ElementKind: ModelElement, Message: "unknown 'date'"},
+		{Severity: DiagnosticError, ElementKind: DataAssetElement, ElementId: "some-data", Message: "unknown 'usage'", SourcePosition: &SourcePosition{File: "model.yaml", Line: 3, Column: 3}},
+	}
+
+	err := fmt.Errorf("unable to parse model yaml: %w", diagnostics)
+	assert.Equal(t, "unable to parse model yaml: unknown 'date'\nmodel.yaml:3:3: unknown 'usage'", err.Error())
+
+	var found Diagnostics
+	assert.ErrorAs(t, err, &found)
+	assert.Len(t, found, 2)
+
+	var sourceError *SourceError
+	assert.ErrorAs(t, err, &sourceError)
+	assert.Equal(t, SourcePosition{File: "model.yaml", Line: 3, Column: 3}, sourceError.Position)
+}
+
+func TestDiagnosticsSort(t *testing.T) {
+	diagnostics := Diagnostics{
+		{Severity: DiagnosticError, ElementKind: ModelElement, Message: "unknown 'date'"},
+		{Severity: DiagnosticWarning, ElementKind: TechnicalAssetElement, ElementId: "b", Message: "second", SourcePosition: &SourcePosition{File: "model.yaml", Line: 9}},
+		{Severity: DiagnosticError, ElementKind: TechnicalAssetElement, ElementId: "a", Message: "first", SourcePosition: &SourcePosition{File: "model.yaml", Line: 9}},
+		{Severity: DiagnosticError, ElementKind: DataAssetElement, ElementId: "c", Message: "earlier", SourcePosition: &SourcePosition{File: "model.yaml", Line: 2}},
+	}
+
+	diagnostics.Sort()
+
+	messages := make([]string, len(diagnostics))
+	for i, diagnostic := range diagnostics {
+		messages[i] = diagnostic.Message
+	}
+	assert.Equal(t, []string{"earlier", "first", "second", "unknown 'date'"}, messages)
+	assert.Equal(t, 3, diagnostics.Count(DiagnosticError))
+	assert.Equal(t, 1, diagnostics.Count(DiagnosticWarning))
+}
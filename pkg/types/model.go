@@ -20,6 +20,7 @@ type Model struct {
 	ThreagileVersion                              string                        `yaml:"threagile_version,omitempty" json:"threagile_version,omitempty"`
 	Includes                                      []string                      `yaml:"includes,omitempty" json:"includes,omitempty"`
 	Title                                         string                        `json:"title,omitempty" yaml:"title,omitempty"`
+	Variant                                       string                        `json:"variant,omitempty" yaml:"variant,omitempty"`
 	Author                                        *Author                       `json:"author,omitempty" yaml:"author,omitempty"`
 	Contributors                                  []*Author                     `yaml:"contributors,omitempty" json:"contributors,omitempty"`
 	Date                                          Date                          `json:"date,omitempty" yaml:"date,omitempty"`
@@ -39,6 +40,7 @@ type Model struct {
 	CustomRiskCategories                          RiskCategories                `json:"custom_risk_categories,omitempty" yaml:"custom_risk_categories,omitempty"`
 	BuiltInRiskCategories                         RiskCategories                `json:"built_in_risk_categories,omitempty" yaml:"built_in_risk_categories,omitempty"`
 	RiskTracking                                  map[string]*RiskTracking      `json:"risk_tracking,omitempty" yaml:"risk_tracking,omitempty"`
+	Attractiveness                                *Attractiveness               `json:"attractiveness,omitempty" yaml:"attractiveness,omitempty"`
 	CommunicationLinks                            map[string]*CommunicationLink `json:"communication_links,omitempty" yaml:"communication_links,omitempty"`
 	AllSupportedTags                              map[string]bool               `json:"all_supported_tags,omitempty" yaml:"all_supported_tags,omitempty"`
 	DiagramTweakNodesep                           int                           `json:"diagram_tweak_nodesep,omitempty" yaml:"diagram_tweak_nodesep,omitempty"`
@@ -54,6 +56,9 @@ type Model struct {
 	DirectContainingTrustBoundaryMappedByTechnicalAssetId map[string]*TrustBoundary       `json:"direct_containing_trust_boundary_mapped_by_technical_asset_id,omitempty" yaml:"direct_containing_trust_boundary_mapped_by_technical_asset_id,omitempty"`
 	GeneratedRisksByCategory                              map[string][]*Risk              `json:"generated_risks_by_category,omitempty" yaml:"generated_risks_by_category,omitempty"`
 	GeneratedRisksBySyntheticId                           map[string]*Risk                `json:"generated_risks_by_synthetic_id,omitempty" yaml:"generated_risks_by_synthetic_id,omitempty"`
+
+	// the problems found while parsing the model, which don't prevent analyzing it
+	Warnings Diagnostics `json:"-" yaml:"-"`
 }
 
 type ProgressReporter interface {
@@ -122,6 +127,8 @@ func (model *Model) ApplyWildcardRiskTrackingEvaluation(ignoreOrphanedRiskTracki
 					Ticket:          riskTracking.Ticket,
 					Status:          riskTracking.Status,
 					Date:            riskTracking.Date,
+					SourcePosition:  riskTracking.SourcePosition,
+					TrackingKey:     syntheticRiskIdPattern,
 				}
 
 				progressReporter.Infof("  => %v", syntheticRiskId)
@@ -132,7 +139,7 @@ func (model *Model) ApplyWildcardRiskTrackingEvaluation(ignoreOrphanedRiskTracki
 			if ignoreOrphanedRiskTracking {
 				progressReporter.Warnf("Wildcard risk tracking does not match any risk id: %v", syntheticRiskIdPattern)
 			} else {
-				return fmt.Errorf("wildcard risk tracking does not match any risk id: %v", syntheticRiskIdPattern)
+				return riskTracking.SourcePosition.Errorf("wildcard risk tracking does not match any risk id: %v", syntheticRiskIdPattern)
 			}
 		}
 	}
@@ -142,19 +149,11 @@ func (model *Model) ApplyWildcardRiskTrackingEvaluation(ignoreOrphanedRiskTracki
 func (model *Model) CheckRiskTracking(ignoreOrphanedRiskTracking bool, progressReporter ProgressReporter) error {
 	progressReporter.Info("Checking risk tracking")
 	for _, tracking := range model.RiskTracking {
-		foundSome := false
-		var matchingRiskIdExpression = regexp.MustCompile(strings.ReplaceAll(regexp.QuoteMeta(tracking.SyntheticRiskId), `\*`, `[^@]+`))
-		for syntheticRiskId := range model.GeneratedRisksBySyntheticId {
-			if matchingRiskIdExpression.Match([]byte(syntheticRiskId)) {
-				foundSome = true
-			}
-		}
-
-		if !foundSome {
+		if !model.RiskTrackingMatchesAnyRisk(tracking.SyntheticRiskId) {
 			if ignoreOrphanedRiskTracking {
 				progressReporter.Infof("Risk tracking references unknown risk (risk id not found): %v", tracking.SyntheticRiskId)
 			} else {
-				return fmt.Errorf("Risk tracking references unknown risk (risk id not found) - you might want to use the option -ignore-orphaned-risk-tracking: %v"+
+				return tracking.SourcePosition.Errorf("Risk tracking references unknown risk (risk id not found) - you might want to use the option -ignore-orphaned-risk-tracking: %v"+
 					"\n\nNOTE: For risk tracking each risk-id needs to be defined (the string with the @ sign in it). "+
 					"These unique risk IDs are visible in the PDF report (the small grey string under each risk), "+
 					"the Excel (column \"ID\"), as well as the JSON responses. Some risk IDs have only one @ sign in them, "+
@@ -171,6 +170,47 @@ func (model *Model) CheckRiskTracking(ignoreOrphanedRiskTracking bool, progressR
 	return nil
 }
 
+// RiskTrackingMatchesAnyRisk tells if the (possibly wildcard) synthetic risk id of a risk tracking entry matches a generated risk
+func (model *Model) RiskTrackingMatchesAnyRisk(syntheticRiskIdPattern string) bool {
+	var matchingRiskIdExpression = regexp.MustCompile(strings.ReplaceAll(regexp.QuoteMeta(syntheticRiskIdPattern), `\*`, `[^@]+`))
+	for syntheticRiskId := range model.GeneratedRisksBySyntheticId {
+		if matchingRiskIdExpression.Match([]byte(syntheticRiskId)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SourcePositionOfRisk returns where the risk or else its most relevant model element is defined
+func (model *Model) SourcePositionOfRisk(risk *Risk) *SourcePosition {
+	if risk.SourcePosition != nil {
+		return risk.SourcePosition
+	}
+
+	if link, ok := model.CommunicationLinks[risk.MostRelevantCommunicationLinkId]; ok && link.SourcePosition != nil {
+		return link.SourcePosition
+	}
+
+	if asset, ok := model.TechnicalAssets[risk.MostRelevantTechnicalAssetId]; ok && asset.SourcePosition != nil {
+		return asset.SourcePosition
+	}
+
+	if boundary, ok := model.TrustBoundaries[risk.MostRelevantTrustBoundaryId]; ok && boundary.SourcePosition != nil {
+		return boundary.SourcePosition
+	}
+
+	if runtime, ok := model.SharedRuntimes[risk.MostRelevantSharedRuntimeId]; ok && runtime.SourcePosition != nil {
+		return runtime.SourcePosition
+	}
+
+	if asset, ok := model.DataAssets[risk.MostRelevantDataAssetId]; ok && asset.SourcePosition != nil {
+		return asset.SourcePosition
+	}
+
+	return nil
+}
+
 func (model *Model) CheckTagExists(referencedTag, where string) error {
 	if !slices.Contains(model.TagsAvailable, referencedTag) {
 		return fmt.Errorf("missing referenced tag in overall tag list at %v: %v", where, referencedTag)
@@ -511,6 +551,25 @@ func (model *Model) GetRiskCategory(categoryID string) *RiskCategory {
 	return nil
 }
 
+// FindRisks returns the generated risks whose synthetic id matches the given id,
+// where '*' may be used as a wildcard for a single @-delimited part (as in risk tracking)
+func (model *Model) FindRisks(syntheticRiskIdPattern string) []*Risk {
+	var matchingRiskIdExpression = regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(strings.ToLower(strings.TrimSpace(syntheticRiskIdPattern))), `\*`, `[^@]+`) + "$")
+
+	result := make([]*Risk, 0)
+	for syntheticRiskId, risk := range model.GeneratedRisksBySyntheticId {
+		if matchingRiskIdExpression.MatchString(syntheticRiskId) {
+			result = append(result, risk)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SyntheticId < result[j].SyntheticId
+	})
+
+	return result
+}
+
 func (model *Model) AllRisks() []*Risk {
 	result := make([]*Risk, 0)
 	for _, risks := range model.GeneratedRisksByCategory {
@@ -877,3 +936,11 @@ func (model *Model) GeneratedRisksByCategoryWithCurrentStatus() map[string][]*Ri
 	}
 	return generatedRisksByCategoryWithCurrentStatus
 }
+
+func (model *Model) AllRisksWithCurrentStatus() []*Risk {
+	result := make([]*Risk, 0)
+	for _, risks := range model.GeneratedRisksByCategoryWithCurrentStatus() {
+		result = append(result, risks...)
+	}
+	return result
+}
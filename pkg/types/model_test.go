@@ -0,0 +1,57 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type FindRisksTest struct {
+	pattern  string
+	expected []string
+}
+
+func TestFindRisks(t *testing.T) {
+	model := &Model{
+		GeneratedRisksBySyntheticId: map[string]*Risk{
+			"unencrypted-asset@web-server":              {SyntheticId: "unencrypted-asset@web-server"},
+			"unencrypted-asset@database":                {SyntheticId: "unencrypted-asset@database"},
+			"missing-waf@web-server@internet":           {SyntheticId: "missing-waf@web-server@internet"},
+			"unencrypted-communication@web-server@db>x": {SyntheticId: "unencrypted-communication@web-server@db>x"},
+		},
+	}
+
+	testCases := map[string]FindRisksTest{
+		"exact": {
+			pattern:  "unencrypted-asset@database",
+			expected: []string{"unencrypted-asset@database"},
+		},
+		"case insensitive": {
+			pattern:  "Unencrypted-Asset@Database",
+			expected: []string{"unencrypted-asset@database"},
+		},
+		"wildcard": {
+			pattern:  "unencrypted-asset@*",
+			expected: []string{"unencrypted-asset@database", "unencrypted-asset@web-server"},
+		},
+		"wildcard does not span parts": {
+			pattern:  "missing-waf@*",
+			expected: []string{},
+		},
+		"no prefix match": {
+			pattern:  "unencrypted",
+			expected: []string{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ids := make([]string, 0)
+			for _, risk := range model.FindRisks(testCase.pattern) {
+				ids = append(ids, risk.SyntheticId)
+			}
+
+			assert.Equal(t, testCase.expected, ids)
+		})
+	}
+}
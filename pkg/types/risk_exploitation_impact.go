@@ -42,7 +42,6 @@ func ParseRiskExploitationImpact(value string) (riskExploitationImpact RiskExplo
 }
 
 func (what RiskExploitationImpact) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return RiskExploitationImpactTypeDescription[what].Name
 }
 
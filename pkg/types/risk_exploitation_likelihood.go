@@ -42,7 +42,6 @@ func ParseRiskExploitationLikelihood(value string) (riskExploitationLikelihood R
 }
 
 func (what RiskExploitationLikelihood) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return RiskExploitationLikelihoodTypeDescription[what].Name
 }
 
@@ -48,7 +48,6 @@ func ParseRiskFunction(value string) (riskFunction RiskFunction, err error) {
 }
 
 func (what RiskFunction) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return RiskFunctionTypeDescription[what].Name
 }
 
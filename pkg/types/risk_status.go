@@ -54,7 +54,6 @@ func ParseRiskStatus(value string) (riskStatus RiskStatus, err error) {
 }
 
 func (what RiskStatus) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return RiskStatusTypeDescription[what].Name
 }
 
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RiskThreshold selects the risks still at risk with at least the given severity,
+// optionally limited to some risk statuses (e.g. "high" or "elevated:unchecked,in-discussion")
+type RiskThreshold struct {
+	Severity RiskSeverity
+	Statuses []RiskStatus
+}
+
+func ParseRiskThreshold(value string) (*RiskThreshold, error) {
+	severityText, statusesText, hasStatuses := strings.Cut(strings.TrimSpace(value), ":")
+	if len(strings.TrimSpace(severityText)) == 0 {
+		return nil, fmt.Errorf("missing risk severity in threshold %q", value)
+	}
+
+	severity, severityError := ParseRiskSeverity(strings.TrimSpace(severityText))
+	if severityError != nil {
+		return nil, fmt.Errorf("invalid risk threshold %q: %w", value, severityError)
+	}
+
+	threshold := &RiskThreshold{Severity: severity}
+	if !hasStatuses {
+		return threshold, nil
+	}
+
+	for _, statusText := range strings.Split(statusesText, ",") {
+		status, statusError := ParseRiskStatus(strings.TrimSpace(statusText))
+		if statusError != nil {
+			return nil, fmt.Errorf("invalid risk threshold %q: %w", value, statusError)
+		}
+
+		if !status.IsStillAtRisk() {
+			return nil, fmt.Errorf("invalid risk threshold %q: risk status %q is not at risk", value, status.String())
+		}
+
+		threshold.Statuses = append(threshold.Statuses, status)
+	}
+
+	return threshold, nil
+}
+
+func (what *RiskThreshold) String() string {
+	if len(what.Statuses) == 0 {
+		return what.Severity.String()
+	}
+
+	statuses := make([]string, 0, len(what.Statuses))
+	for _, status := range what.Statuses {
+		statuses = append(statuses, status.String())
+	}
+
+	return what.Severity.String() + ":" + strings.Join(statuses, ",")
+}
+
+// Exceeding returns the risks still at risk reaching the threshold, sorted by severity
+func (what *RiskThreshold) Exceeding(risks []*Risk) []*Risk {
+	result := make([]*Risk, 0)
+	for _, risk := range ReduceToOnlyStillAtRisk(risks) {
+		if risk.Severity >= what.Severity && what.matchesStatus(risk.RiskStatus) {
+			result = append(result, risk)
+		}
+	}
+
+	SortByRiskSeverity(result)
+	return result
+}
+
+func (what *RiskThreshold) matchesStatus(status RiskStatus) bool {
+	if len(what.Statuses) == 0 {
+		return true
+	}
+
+	for _, candidate := range what.Statuses {
+		if candidate == status {
+			return true
+		}
+	}
+
+	return false
+}
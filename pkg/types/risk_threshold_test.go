@@ -0,0 +1,39 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRiskThreshold(t *testing.T) {
+	threshold, err := ParseRiskThreshold("high")
+	assert.NoError(t, err)
+	assert.Equal(t, &RiskThreshold{Severity: HighSeverity}, threshold)
+
+	threshold, err = ParseRiskThreshold("Elevated:unchecked, in-discussion")
+	assert.NoError(t, err)
+	assert.Equal(t, &RiskThreshold{Severity: ElevatedSeverity, Statuses: []RiskStatus{Unchecked, InDiscussion}}, threshold)
+	assert.Equal(t, "elevated:unchecked,in-discussion", threshold.String())
+
+	for _, invalid := range []string{"", ":unchecked", "severe", "high:unknown", "high:mitigated"} {
+		_, err = ParseRiskThreshold(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRiskThresholdExceeding(t *testing.T) {
+	risks := []*Risk{
+		{SyntheticId: "low", Severity: LowSeverity},
+		{SyntheticId: "high-unchecked", Severity: HighSeverity},
+		{SyntheticId: "high-mitigated", Severity: HighSeverity, RiskStatus: Mitigated},
+		{SyntheticId: "critical-accepted", Severity: CriticalSeverity, RiskStatus: Accepted},
+		{SyntheticId: "elevated-unchecked", Severity: ElevatedSeverity},
+	}
+
+	threshold := &RiskThreshold{Severity: HighSeverity}
+	assert.Equal(t, []*Risk{risks[3], risks[1]}, threshold.Exceeding(risks))
+
+	threshold = &RiskThreshold{Severity: ElevatedSeverity, Statuses: []RiskStatus{Unchecked}}
+	assert.Equal(t, []*Risk{risks[1], risks[4]}, threshold.Exceeding(risks))
+}
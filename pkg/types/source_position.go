@@ -0,0 +1,62 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SourcePosition is the location of a model element in the model file (or one of its includes)
+type SourcePosition struct {
+	File   string `json:"file,omitempty" yaml:"file,omitempty"`
+	Line   int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Column int    `json:"column,omitempty" yaml:"column,omitempty"`
+}
+
+func (what *SourcePosition) String() string {
+	if what == nil {
+		return ""
+	}
+
+	if what.Line <= 0 {
+		return what.File
+	}
+
+	if what.Column <= 0 {
+		return fmt.Sprintf("%v:%d", what.File, what.Line)
+	}
+
+	return fmt.Sprintf("%v:%d:%d", what.File, what.Line, what.Column)
+}
+
+// Wrap attaches the position to the error unless it already carries one
+func (what *SourcePosition) Wrap(err error) error {
+	if err == nil || what == nil {
+		return err
+	}
+
+	var sourceError *SourceError
+	if errors.As(err, &sourceError) {
+		return err
+	}
+
+	return &SourceError{Position: *what, Err: err}
+}
+
+// Errorf is fmt.Errorf with the position attached
+func (what *SourcePosition) Errorf(format string, args ...any) error {
+	return what.Wrap(fmt.Errorf(format, args...))
+}
+
+// SourceError is an error caused by the model element at Position
+type SourceError struct {
+	Position SourcePosition
+	Err      error
+}
+
+func (what *SourceError) Error() string {
+	return what.Position.String() + ": " + what.Err.Error()
+}
+
+func (what *SourceError) Unwrap() error {
+	return what.Err
+}
@@ -0,0 +1,49 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourcePositionString(t *testing.T) {
+	assert.Equal(t, "", (*SourcePosition)(nil).String())
+	assert.Equal(t, "model.yaml", (&SourcePosition{File: "model.yaml"}).String())
+	assert.Equal(t, "model.yaml:12", (&SourcePosition{File: "model.yaml", Line: 12}).String())
+	assert.Equal(t, "model.yaml:12:5", (&SourcePosition{File: "model.yaml", Line: 12, Column: 5}).String())
+}
+
+func TestSourcePositionWrap(t *testing.T) {
+	cause := errors.New("broken")
+
+	assert.Nil(t, (&SourcePosition{File: "model.yaml"}).Wrap(nil))
+	assert.Equal(t, cause, (*SourcePosition)(nil).Wrap(cause))
+
+	inner := &SourcePosition{File: "include.yaml", Line: 3, Column: 1}
+	outer := &SourcePosition{File: "model.yaml", Line: 7, Column: 1}
+	err := outer.Wrap(inner.Wrap(cause))
+
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "include.yaml:3:1: broken", err.Error())
+}
+
+func TestSourcePositionNotInReports(t *testing.T) {
+	// the positions hold local paths, which the published reports must not contain
+	position := &SourcePosition{File: "/home/user/model/threagile.yaml", Line: 12, Column: 5}
+	for _, element := range []any{
+		&Risk{SyntheticId: "some-risk@web-server", SourcePosition: position},
+		&TechnicalAsset{Id: "web-server", SourcePosition: position},
+		&CommunicationLink{Id: "web-server>database", SourcePosition: position},
+		&DataAsset{Id: "customer-data", SourcePosition: position},
+		&TrustBoundary{Id: "network", SourcePosition: position},
+		&SharedRuntime{Id: "cluster", SourcePosition: position},
+		&RiskTracking{SyntheticRiskId: "some-risk@web-server", SourcePosition: position},
+	} {
+		data, err := json.Marshal(element)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(data), position.File)
+		assert.NotContains(t, string(data), "source_position")
+	}
+}
@@ -54,7 +54,6 @@ func ParseSTRIDE(value string) (stride STRIDE, err error) {
 }
 
 func (what STRIDE) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return StrideTypeDescription[what].Name
 }
 
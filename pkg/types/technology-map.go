@@ -129,6 +129,17 @@ func (what TechnologyMap) GetAll(names ...string) ([]*Technology, error) {
 	return technologies, nil
 }
 
+// HasAttribute tells if any of the technologies has the attribute, be it set or not
+func (what TechnologyMap) HasAttribute(name string) bool {
+	for _, technology := range what {
+		if _, ok := technology.Attributes[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (what TechnologyMap) PropagateAttributes() {
 	technologyList := make([]Technology, 0)
 	for name, value := range what {
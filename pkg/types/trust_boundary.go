@@ -12,6 +12,7 @@ type TrustBoundary struct {
 	Tags                  []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
 	TechnicalAssetsInside []string          `json:"technical_assets_inside,omitempty" yaml:"technical_assets_inside,omitempty"`
 	TrustBoundariesNested []string          `json:"trust_boundaries_nested,omitempty" yaml:"trust_boundaries_nested,omitempty"`
+	SourcePosition        *SourcePosition   `json:"-" yaml:"-"`
 }
 
 func (what TrustBoundary) IsTaggedWithAny(tags ...string) bool {
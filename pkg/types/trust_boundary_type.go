@@ -53,7 +53,6 @@ func ParseTrustBoundary(value string) (trustBoundary TrustBoundaryType, err erro
 }
 
 func (what TrustBoundaryType) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	return TrustBoundaryTypeDescription[what].Name
 }
 
@@ -42,7 +42,6 @@ var UsageTypeDescription = [...]TypeDescription{
 }
 
 func (what Usage) String() string {
-	// NOTE: maintain list also in schema.json for validation in IDEs
 	//return [...]string{"business", "devops"}[what]
 	return UsageTypeDescription[what].Name
 }
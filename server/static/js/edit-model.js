@@ -232,9 +232,6 @@ $(document).ready(function() {
       },
       'security_requirements': {
         addCaption: 'Add security requirement'
-      },
-      'individual_risk_categories': {
-        addCaption: 'Add individual risk category'
       }
     };
     projectEditor.generateEditor(hiddenProperties, extendableProperties);